@@ -1,21 +1,41 @@
 package handlers
 
 import (
-	"log"
+	"context"
+	"log/slog"
+	"os"
 
 	"encore.dev/config"
 	service "github.com/asheet-bhaskar/billing-service/app/services"
+	"github.com/asheet-bhaskar/billing-service/app/workflows"
 	"github.com/asheet-bhaskar/billing-service/db"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/mail"
+	"github.com/asheet-bhaskar/billing-service/pkg/ratelimit"
 	"github.com/asheet-bhaskar/billing-service/worker"
 	"go.temporal.io/sdk/client"
+	temporallog "go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/workflow"
 )
 
 // encore:service
 type APIService struct {
-	Bill     service.BillService
-	Customer service.CustomerService
-	Currency service.CurrencyService
+	Bill         service.BillService
+	Customer     service.CustomerService
+	Currency     service.CurrencyService
+	Subscription service.SubscriptionService
+	Catalog      service.CatalogService
+	Usage        service.UsageService
+	ExchangeRate service.ExchangeRateService
+	Ledger       service.LedgerService
+	Audit        service.AuditService
+	Webhook      service.WebhookService
+	Projection   service.ProjectionService
+	Notification service.NotificationService
+	APIKey       service.APIKeyService
+	RateLimiter  ratelimit.Limiter
+	RateLimits   map[string]ratelimit.Limit
 }
 
 type Config struct {
@@ -26,30 +46,120 @@ type Config struct {
 	DBPassword             config.String
 	DBName                 config.String
 	DBSchemaMigrationsPath config.String
+	ExchangeRatesPath      config.String
+	SMTPAddr               config.String
+	SMTPUsername           config.String
+	SMTPPassword           config.String
+	MailFrom               config.String
+	BootstrapAPIKeyHash    config.String
+	// RateLimitStore keeps the rate limit buckets in "memory", per
+	// instance, or in "postgres", shared by all instances.
+	RateLimitStore    config.String
+	DefaultRateLimit  RateLimitConfig
+	WorkflowRateLimit RateLimitConfig
+	UsageRateLimit    RateLimitConfig
+	BulkRateLimit     RateLimitConfig
+}
+
+// RateLimitConfig is the rate limit of every api key on a class of endpoints,
+// RequestsPerMinute on average with bursts of up to Burst requests. A zero
+// Burst disables the limit.
+type RateLimitConfig struct {
+	RequestsPerMinute config.Int
+	Burst             config.Int
+}
+
+func (rc RateLimitConfig) Limit() ratelimit.Limit {
+	return ratelimit.PerMinute(rc.RequestsPerMinute(), rc.Burst())
 }
 
 var appConfig = config.Load[Config]()
 
 func initAPIService() (*APIService, error) {
-	dbClient, _ := db.InitDBClient(appConfig.DBHost(), appConfig.DBPort(), appConfig.DBUser(), appConfig.DBUser(), appConfig.DBName(), appConfig.DBSchemaMigrationsPath())
+	slog.SetDefault(logging.New(os.Stdout, slog.LevelInfo))
+
+	dbClient, err := db.InitDBClient(appConfig.DBHost(), appConfig.DBPort(), appConfig.DBUser(), appConfig.DBPassword(), appConfig.DBName(), appConfig.DBSchemaMigrationsPath())
+	if err != nil {
+		return nil, err
+	}
+
 	BillRepo := repository.NewBillRepository(dbClient.DB)
 	CustomerRepo := repository.NewCustomerRepository(dbClient.DB)
 	CurrencyRepo := repository.NewCurrencyRepository(dbClient.DB)
+	SubscriptionRepo := repository.NewSubscriptionRepository(dbClient.DB)
+	CatalogRepo := repository.NewCatalogRepository(dbClient.DB)
+	UsageRepo := repository.NewUsageRepository(dbClient.DB)
+	ExchangeRateRepo := repository.NewExchangeRateRepository(dbClient.DB)
+	LedgerRepo := repository.NewLedgerRepository(dbClient.DB)
+	AuditRepo := repository.NewAuditRepository(dbClient.DB)
+	WebhookRepo := repository.NewWebhookRepository(dbClient.DB)
+	ProjectionRepo := repository.NewProjectionRepository(dbClient.DB)
+	NotificationRepo := repository.NewNotificationRepository(dbClient.DB)
+	APIKeyRepo := repository.NewAPIKeyRepository(dbClient.DB)
+	var rateLimiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
+	if appConfig.RateLimitStore() == "postgres" {
+		rateLimiter = repository.NewRateLimitRepository(dbClient.DB)
+	}
 	temporalClient, err := client.NewClient(client.Options{
-		HostPort:  appConfig.TemporalHostPort(),
-		Namespace: "default",
+		HostPort:           appConfig.TemporalHostPort(),
+		Namespace:          "default",
+		ContextPropagators: []workflow.ContextPropagator{workflows.NewTenantPropagator()},
+		Logger:             temporallog.NewStructuredLogger(slog.Default()),
 	})
 
 	if err != nil {
-		log.Fatal("Failed to initiate temporal client")
+		slog.Error("failed to initiate temporal client", "host_port", appConfig.TemporalHostPort(), "error", err)
+		return nil, err
+	}
+
+	var exchangeRateProvider service.ExchangeRateProvider
+	if appConfig.ExchangeRatesPath() != "" {
+		exchangeRateProvider = service.NewFileExchangeRateProvider(appConfig.ExchangeRatesPath())
+	}
+	exchangeRateService := service.NewExchangeRateService(ExchangeRateRepo, exchangeRateProvider)
+
+	if exchangeRateProvider != nil {
+		_, err = exchangeRateService.Sync(context.Background())
+		if err != nil {
+			slog.Error("failed to sync exchange rates", "error", err)
+		}
+	}
+
+	ledgerService := service.NewLedgerService(LedgerRepo)
+	auditService := service.NewAuditService(AuditRepo)
+	webhookService := service.NewWebhookService(WebhookRepo, temporalClient)
+	events := &topicPublisher{}
+	billService := service.NewBillService(BillRepo, CurrencyRepo, CustomerRepo, CatalogRepo, exchangeRateService, ledgerService, auditService, webhookService, events, temporalClient)
+	var mailTransport mail.Transport = &mail.LogTransport{}
+	if appConfig.SMTPAddr() != "" {
+		mailTransport = mail.NewSMTPTransport(appConfig.SMTPAddr(), appConfig.SMTPUsername(), appConfig.SMTPPassword())
 	}
+	notificationService := service.NewNotificationService(NotificationRepo, CurrencyRepo, billService, mailTransport, appConfig.MailFrom())
+	usageService := service.NewUsageService(UsageRepo, BillRepo, CustomerRepo, CatalogRepo, billService)
 
-	log.Println("starting temporal worker")
-	go worker.Start(temporalClient)
+	slog.Info("starting temporal worker")
+	go worker.Start(temporalClient, billService, usageService)
 
 	return &APIService{
-		Bill:     service.NewBillService(BillRepo, CurrencyRepo, CustomerRepo, temporalClient),
-		Customer: service.NewCustomerService(CustomerRepo),
-		Currency: service.NewCurrencyService(CurrencyRepo),
+		Bill:         billService,
+		Customer:     service.NewCustomerService(CustomerRepo, CurrencyRepo, BillRepo, LedgerRepo, auditService, events),
+		Currency:     service.NewCurrencyService(CurrencyRepo, auditService, events),
+		Subscription: service.NewSubscriptionService(SubscriptionRepo, CurrencyRepo, CustomerRepo, CatalogRepo, billService, temporalClient),
+		Catalog:      service.NewCatalogService(CatalogRepo, CurrencyRepo),
+		Usage:        usageService,
+		ExchangeRate: exchangeRateService,
+		Ledger:       ledgerService,
+		Audit:        auditService,
+		Webhook:      webhookService,
+		Projection:   service.NewProjectionService(ProjectionRepo),
+		Notification: notificationService,
+		APIKey:       service.NewAPIKeyService(APIKeyRepo, auditService, appConfig.BootstrapAPIKeyHash()),
+		RateLimiter:  rateLimiter,
+		RateLimits: map[string]ratelimit.Limit{
+			RateLimitClassDefault:  appConfig.DefaultRateLimit.Limit(),
+			RateLimitClassWorkflow: appConfig.WorkflowRateLimit.Limit(),
+			RateLimitClassUsage:    appConfig.UsageRateLimit.Limit(),
+			RateLimitClassBulk:     appConfig.BulkRateLimit.Limit(),
+		},
 	}, nil
 }
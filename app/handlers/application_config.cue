@@ -5,6 +5,17 @@ DBUser:       "billing_service"
 DBPassword:   "billing_service"
 DBName:       "billing_service"
 DBSchemaMigrationsPath: "db/migrations"
+ExchangeRatesPath: ""
+SMTPAddr: "localhost:1025"
+SMTPUsername: ""
+SMTPPassword: ""
+MailFrom: "Billing <billing@localhost>"
+BootstrapAPIKeyHash: ""
+RateLimitStore: "memory"
+DefaultRateLimit: {RequestsPerMinute: 600, Burst: 100}
+WorkflowRateLimit: {RequestsPerMinute: 120, Burst: 20}
+UsageRateLimit: {RequestsPerMinute: 1200, Burst: 200}
+BulkRateLimit: {RequestsPerMinute: 6, Burst: 2}
 
 
 if #Meta.Environment.Name == "test" {
@@ -14,4 +25,5 @@ if #Meta.Environment.Name == "test" {
     DBPassword:   "billing_service_test"
     DBName:       "billing_service_test"
     DBSchemaMigrationsPath: "migrations"
+    SMTPAddr: ""
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+	"encore.dev/middleware"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+)
+
+// AuditContextMiddleware passes the principal of the auth handler with its
+// tenant and the request id of the X-Request-ID header, or the trace id, to
+// the service layer, which records them with every change made by the
+// request. Repositories only see the data of the tenant, and every line
+// logged for the request carries its id, endpoint, tenant and api key.
+//
+//encore:middleware target=all
+func (bs *APIService) AuditContextMiddleware(req middleware.Request, next middleware.Next) middleware.Response {
+	data := req.Data()
+	ctx := req.Context()
+
+	requestID := data.Headers.Get("X-Request-ID")
+	if requestID == "" && data.Trace != nil {
+		requestID = data.Trace.TraceID
+	}
+	ctx = logging.With(ctx, "request_id", requestID, "endpoint", data.Endpoint)
+
+	if principal, ok := auth.Data().(*models.Principal); ok {
+		ctx = service.WithPrincipal(ctx, principal)
+		ctx = tenancy.WithTenant(ctx, principal.TenantID)
+		ctx = logging.With(ctx, "tenant_id", principal.TenantID, "api_key_id", principal.APIKeyID)
+	}
+
+	return next(req.WithContext(service.WithRequestID(ctx, requestID)))
+}
+
+// encore:api auth method=GET path=/audit-events
+func (bs *APIService) ListAuditEventsHandler(ctx context.Context, request *models.ListAuditEventsRequest) (*models.AuditEventList, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid list audit events request")
+		return &models.AuditEventList{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid list audit events request, entity_type must be bill, line_item, customer, currency or api_key",
+		}
+	}
+
+	events, err := bs.Audit.List(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing audit events", "entity_type", request.EntityType, "error", err)
+		return &models.AuditEventList{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list audit events",
+		}
+	}
+
+	return events, nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	"github.com/stretchr/testify/suite"
+)
+
+type auditHandlerTestSuite struct {
+	suite.Suite
+	auditServiceMock *service.AuditServiceMock
+	apiService       *APIService
+}
+
+func (suite *auditHandlerTestSuite) SetupTest() {
+	suite.auditServiceMock = new(service.AuditServiceMock)
+	suite.apiService = &APIService{
+		Audit: suite.auditServiceMock,
+	}
+}
+
+func (suite *auditHandlerTestSuite) Test_ListAuditEventsHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.ListAuditEventsRequest{EntityType: models.LineItemEntity, EntityID: "item id"}
+	list := &models.AuditEventList{Events: []*models.AuditEvent{{ID: "event id", Action: models.LineItemRemoved}}, Total: 1}
+
+	suite.auditServiceMock.On("List", ctx, request).Return(list, nil)
+
+	events, err := suite.apiService.ListAuditEventsHandler(ctx, request)
+	suite.Nil(err)
+	suite.Equal(list, events)
+}
+
+func (suite *auditHandlerTestSuite) Test_ListAuditEventsHandlerFailsWithoutEntityType() {
+	_, err := suite.apiService.ListAuditEventsHandler(context.Background(), &models.ListAuditEventsRequest{EntityID: "item id"})
+	suite.NotNil(err)
+}
+
+func TestAuditHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(auditHandlerTestSuite))
+}
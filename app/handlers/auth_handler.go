@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// AuthHandler resolves the api key of the Authorization: Bearer header to the
+// principal of the request. Endpoints marked auth refuse requests without a
+// valid key.
+//
+//encore:authhandler
+func (bs *APIService) AuthHandler(ctx context.Context, token string) (auth.UID, *models.Principal, error) {
+	principal, err := bs.APIKey.Authenticate(ctx, token)
+
+	if errors.Is(err, ce.InvalidAPIKeyError) {
+		return "", nil, apiError(err, "invalid api key")
+	}
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while authenticating api key", "error", err)
+		return "", nil, &errs.Error{
+			Code:    errs.Unavailable,
+			Message: "failed to authenticate api key",
+		}
+	}
+
+	return auth.UID(principal.APIKeyID), principal, nil
+}
+
+// encore:api auth method=POST path=/api-keys
+func (bs *APIService) IssueAPIKeyHandler(ctx context.Context, request *models.APIKeyRequest) (*models.IssuedAPIKey, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid api key request")
+		return &models.IssuedAPIKey{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid api key request, name is required and at most 100 characters, role must be viewer, biller or finance_admin and tenant_id is a lower case slug",
+		}
+	}
+
+	issued, err := bs.APIKey.Issue(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to issue api key", "error", err)
+		return &models.IssuedAPIKey{}, apiError(err, "failed to issue api key")
+	}
+
+	return issued, nil
+}
+
+// encore:api auth method=GET path=/api-keys
+func (bs *APIService) ListAPIKeysHandler(ctx context.Context) (*models.APIKeys, error) {
+	apiKeys, err := bs.APIKey.List(ctx)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list api keys", "error", err)
+		return &models.APIKeys{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list api keys",
+		}
+	}
+
+	return apiKeys, nil
+}
+
+// encore:api auth method=DELETE path=/api-keys/:id
+func (bs *APIService) RevokeAPIKeyHandler(ctx context.Context, id string) (*models.APIKey, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid api key id")
+		return &models.APIKey{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid api key id",
+		}
+	}
+
+	apiKey, err := bs.APIKey.Revoke(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while revoking api key", "api_key_id", id, "error", err)
+		return &models.APIKey{}, apiError(err, "failed to revoke api key")
+	}
+
+	return apiKey, nil
+}
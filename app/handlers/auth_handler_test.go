@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/stretchr/testify/suite"
+)
+
+type authHandlerTestSuite struct {
+	suite.Suite
+	apiKeyServiceMock *service.APIKeyServiceMock
+	apiService        *APIService
+}
+
+func (suite *authHandlerTestSuite) SetupTest() {
+	suite.apiKeyServiceMock = new(service.APIKeyServiceMock)
+	suite.apiService = &APIService{
+		APIKey: suite.apiKeyServiceMock,
+	}
+}
+
+func (suite *authHandlerTestSuite) Test_AuthHandlerResolvesPrincipal() {
+	ctx := context.Background()
+	principal := &models.Principal{APIKeyID: "key id", Name: "billing cron"}
+
+	suite.apiKeyServiceMock.On("Authenticate", ctx, "bsk_key").Return(principal, nil)
+
+	uid, data, err := suite.apiService.AuthHandler(ctx, "bsk_key")
+	suite.Nil(err)
+	suite.Equal(auth.UID("key id"), uid)
+	suite.Equal(principal, data)
+}
+
+func (suite *authHandlerTestSuite) Test_AuthHandlerRefusesInvalidKey() {
+	ctx := context.Background()
+
+	suite.apiKeyServiceMock.On("Authenticate", ctx, "bsk_revoked").Return(&models.Principal{}, ce.InvalidAPIKeyError)
+
+	_, _, err := suite.apiService.AuthHandler(ctx, "bsk_revoked")
+	suite.Equal(errs.Unauthenticated, errs.Code(err))
+}
+
+func (suite *authHandlerTestSuite) Test_AuthHandlerFailsWhenKeysCannotBeChecked() {
+	ctx := context.Background()
+
+	suite.apiKeyServiceMock.On("Authenticate", ctx, "bsk_key").Return(&models.Principal{}, errors.New("test error"))
+
+	_, _, err := suite.apiService.AuthHandler(ctx, "bsk_key")
+	suite.Equal(errs.Unavailable, errs.Code(err))
+}
+
+func (suite *authHandlerTestSuite) Test_IssueAPIKeyHandlerFailsWithoutName() {
+	_, err := suite.apiService.IssueAPIKeyHandler(context.Background(), &models.APIKeyRequest{Name: " "})
+	suite.NotNil(err)
+}
+
+func (suite *authHandlerTestSuite) Test_RevokeAPIKeyHandlerFailsForUnknownKey() {
+	ctx := context.Background()
+
+	suite.apiKeyServiceMock.On("Revoke", ctx, "key id").Return(&models.APIKey{}, ce.APIKeyNotFoundError)
+
+	_, err := suite.apiService.RevokeAPIKeyHandler(ctx, "key id")
+	suite.Equal(errs.NotFound, errs.Code(err))
+}
+
+func TestAuthHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(authHandlerTestSuite))
+}
@@ -2,18 +2,16 @@ package handlers
 
 import (
 	"context"
-	"fmt"
-	"log"
 
 	"encore.dev/beta/errs"
 	"github.com/asheet-bhaskar/billing-service/app/models"
-	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
 )
 
-// encore:api method=GET path=/bills/:id
+// encore:api auth method=GET path=/bills/:id
 func (bs *APIService) GetBillHandler(ctx context.Context, id string) (*models.Bill, error) {
 	if id == "" {
-		log.Println("invalid bill id")
+		logging.From(ctx).Warn("invalid bill id")
 		return &models.Bill{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid bill id",
@@ -21,29 +19,18 @@ func (bs *APIService) GetBillHandler(ctx context.Context, id string) (*models.Bi
 	}
 	bill, err := bs.Bill.GetByID(ctx, id)
 
-	if err == ce.BillNotFoundError {
-		log.Printf("bill not found for id %s\n", id)
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.NotFound,
-			Message: "bill not found",
-		}
-	}
-
 	if err != nil {
-		log.Printf("error occurred while fetching bill for is %s\n", id)
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to find bill",
-		}
+		logging.From(ctx).Error("error occurred while fetching bill", "bill_id", id, "error", err)
+		return &models.Bill{}, apiError(err, "failed to find bill")
 	}
 
 	return bill, nil
 }
 
-// encore:api  method=POST path=/bills
+// encore:api auth method=POST path=/bills
 func (bs *APIService) CreateBillHandler(ctx context.Context, request *models.BillRequest) (*models.Bill, error) {
 	if !request.IsValid() {
-		log.Println("invalid bill request")
+		logging.From(ctx).Warn("invalid bill request")
 		return &models.Bill{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid bill request",
@@ -52,45 +39,18 @@ func (bs *APIService) CreateBillHandler(ctx context.Context, request *models.Bil
 
 	bill, err := bs.Bill.Create(ctx, request)
 
-	if err == ce.CustomerNotFoundError {
-		log.Printf("customer not found for id, %s", request.CustomerID)
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: fmt.Sprintf("customer not found for id, %s", request.CustomerID),
-		}
-	}
-
-	if err == ce.CurrencyNotFoundError {
-		log.Printf("currency not found for code, %s", request.CurrencyCode)
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: fmt.Sprintf("currency not found for code, %s", request.CurrencyCode),
-		}
-	}
-
-	if err == ce.BillAlreadyExistError {
-		log.Println("bill already exists")
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "bill already exists",
-		}
-	}
-
 	if err != nil {
-		log.Println("failed to create bill")
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to create bill",
-		}
+		logging.From(ctx).Error("failed to create bill", "error", err)
+		return &models.Bill{}, apiError(err, "failed to create bill")
 	}
 
 	return bill, nil
 }
 
-//encore:api method=POST path=/bills/items
+//encore:api auth method=POST path=/bills/items
 func (bs *APIService) AddLineItemsHandler(ctx context.Context, request models.AddLineItemrequest) (*models.LineItem, error) {
 	if !request.IsValid() {
-		log.Println("invalid line item")
+		logging.From(ctx).Warn("invalid line item")
 		return &models.LineItem{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid line item",
@@ -99,37 +59,18 @@ func (bs *APIService) AddLineItemsHandler(ctx context.Context, request models.Ad
 
 	item, err := bs.Bill.AddLineItems(ctx, request.ToLineItem())
 
-	if err == ce.BillNotFoundError {
-		log.Println("bill not found")
-		return item, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: "bill not found",
-		}
-	}
-
-	if err == ce.BillClosedError {
-		log.Println("bill closed already")
-		return item, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: "bill closed already",
-		}
-	}
-
 	if err != nil {
-		log.Println("failed to add line item")
-		return item, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to add line item",
-		}
+		logging.From(ctx).Error("failed to add line item", "error", err)
+		return item, apiError(err, "failed to add line item")
 	}
 
 	return item, nil
 }
 
-//encore:api method=PUT path=/bills/:billID/items/:itemID
+//encore:api auth method=PUT path=/bills/:billID/items/:itemID
 func (bs *APIService) RemoveLineItemsHandler(ctx context.Context, billID string, itemID string) (*models.LineItem, error) {
 	if billID == "" || itemID == "" {
-		log.Println("invalid bill id or item id")
+		logging.From(ctx).Warn("invalid bill id or item id")
 		return &models.LineItem{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid bill idor item id",
@@ -138,105 +79,86 @@ func (bs *APIService) RemoveLineItemsHandler(ctx context.Context, billID string,
 
 	item, err := bs.Bill.RemoveLineItems(ctx, billID, itemID)
 
-	if err == ce.LineItemAlreadyRemovedError {
-		log.Println("line item aleady removed")
-		return item, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: "line item aleady removed",
-		}
+	if err != nil {
+		logging.From(ctx).Error("failed to remove line item", "error", err)
+		return item, apiError(err, "failed to remove line item")
 	}
 
-	if err == ce.BillNotFoundError {
-		log.Println("bill not found")
-		return item, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: "bill not found",
-		}
-	}
+	return item, nil
+}
 
-	if err == ce.BillClosedError {
-		log.Println("bill closed already")
-		return item, &errs.Error{
+// encore:api auth method=GET path=/bills/:id/invoice
+func (bs *APIService) GetInvoiceHandler(ctx context.Context, id string, request *models.InvoiceRequest) (*models.Invoice, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid bill id")
+		return &models.Invoice{}, &errs.Error{
 			Code:    errs.InvalidArgument,
-			Message: "bill closed already",
+			Message: "invalid bill id",
 		}
 	}
+	invoice, err := bs.Bill.Invoice(ctx, id, request.Currency)
 
 	if err != nil {
-		log.Println("failed to remove line item")
-		return item, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to remove line item",
-		}
+		logging.From(ctx).Error("error occurred while fetching bill", "bill_id", id, "error", err)
+		return &models.Invoice{}, apiError(err, "failed to find bill")
 	}
 
-	return item, nil
+	return invoice, nil
 }
 
-// encore:api method=GET path=/bills/:id/invoice
-func (bs *APIService) GetInvoiceHandler(ctx context.Context, id string) (*models.Invoice, error) {
+// encore:api auth method=PUT path=/bills/:id/close
+func (bs *APIService) CloseBillHandler(ctx context.Context, id string) (*models.Bill, error) {
 	if id == "" {
-		log.Println("invalid bill id")
-		return &models.Invoice{}, &errs.Error{
+		logging.From(ctx).Warn("invalid bill id")
+		return &models.Bill{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid bill id",
 		}
 	}
-	invoice, err := bs.Bill.Invoice(ctx, id)
+	bill, err := bs.Bill.Close(ctx, id)
 
-	if err == ce.BillNotFoundError {
-		log.Printf("bill not found for id %s\n", id)
-		return &models.Invoice{}, &errs.Error{
-			Code:    errs.InvalidArgument,
-			Message: "bill not found",
-		}
+	if err != nil {
+		logging.From(ctx).Error("error occurred while closing bill", "bill_id", id, "error", err)
+		return &models.Bill{}, apiError(err, "failed to close bill")
 	}
 
-	if err == ce.CurrencyNotFoundError {
-		log.Printf("currency not found for id %s\n", id)
-		return &models.Invoice{}, &errs.Error{
+	return bill, nil
+}
+
+// encore:api auth method=POST path=/bills/:id/payments
+func (bs *APIService) RecordPaymentHandler(ctx context.Context, id string, request *models.PaymentRequest) (*models.JournalEntry, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid payment request")
+		return &models.JournalEntry{}, &errs.Error{
 			Code:    errs.InvalidArgument,
-			Message: "currency not found",
+			Message: "invalid payment request",
 		}
 	}
 
+	entry, err := bs.Bill.RecordPayment(ctx, id, request)
 	if err != nil {
-		log.Printf("error occurred while fetching bill for is %s\n", id)
-		return &models.Invoice{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to find bill",
-		}
+		logging.From(ctx).Error("failed to record payment for bill", "bill_id", id, "error", err)
+		return &models.JournalEntry{}, apiError(err, "failed to record payment")
 	}
 
-	return invoice, nil
+	return entry, nil
 }
 
-// encore:api method=PUT path=/bills/:id/close
-func (bs *APIService) CloseBillHandler(ctx context.Context, id string) (*models.Bill, error) {
-	if id == "" {
-		log.Println("invalid bill id")
-		return &models.Bill{}, &errs.Error{
+// encore:api auth method=POST path=/bills/:id/credits
+func (bs *APIService) CreditBillHandler(ctx context.Context, id string, request *models.CreditRequest) (*models.JournalEntry, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid credit request")
+		return &models.JournalEntry{}, &errs.Error{
 			Code:    errs.InvalidArgument,
-			Message: "invalid bill id",
-		}
-	}
-	bill, err := bs.Bill.Close(ctx, id)
-
-	if err == ce.BillNotFoundError {
-		log.Printf("bill not found for id %s\n", id)
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.NotFound,
-			Message: "bill not found",
+			Message: "invalid credit request",
 		}
 	}
 
+	entry, err := bs.Bill.Credit(ctx, id, request)
 	if err != nil {
-		log.Printf("error occurred while closing bill for is %s\n", id)
-		return &models.Bill{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to find bill",
-		}
+		logging.From(ctx).Error("failed to credit bill", "bill_id", id, "error", err)
+		return &models.JournalEntry{}, apiError(err, "failed to record credit")
 	}
 
-	return bill, nil
+	return entry, nil
 }
@@ -263,16 +263,16 @@ func (suite *billHandlerTestSuite) Test_GetInvoiceHandlerSucceeds() {
 		LineItems:   []models.LineItem{},
 	}
 
-	suite.billServiceMock.On("Invoice", ctx, id).Return(invoice, nil)
+	suite.billServiceMock.On("Invoice", ctx, id, "").Return(invoice, nil)
 
-	_, err := suite.apiService.GetInvoiceHandler(ctx, id)
+	_, err := suite.apiService.GetInvoiceHandler(ctx, id, &models.InvoiceRequest{})
 	suite.Nil(err)
 }
 
 func (suite *billHandlerTestSuite) Test_GetInvoiceHandlerFailsWhenIDIsInvalid() {
 	ctx := context.Background()
 
-	_, err := suite.apiService.GetInvoiceHandler(ctx, "")
+	_, err := suite.apiService.GetInvoiceHandler(ctx, "", &models.InvoiceRequest{})
 	suite.NotNil(err)
 }
 
@@ -280,9 +280,9 @@ func (suite *billHandlerTestSuite) Test_GetInvoiceHandlerFailsWhenBillIsNotFound
 	ctx := context.Background()
 	id := utils.GetNewUUID()
 
-	suite.billServiceMock.On("Invoice", ctx, id).Return(&models.Invoice{}, ce.BillNotFoundError)
+	suite.billServiceMock.On("Invoice", ctx, id, "").Return(&models.Invoice{}, ce.BillNotFoundError)
 
-	_, err := suite.apiService.GetInvoiceHandler(ctx, id)
+	_, err := suite.apiService.GetInvoiceHandler(ctx, id, &models.InvoiceRequest{})
 	suite.NotNil(err)
 }
 
@@ -291,9 +291,19 @@ func (suite *billHandlerTestSuite) Test_GetInvoiceHandlerFailsWhenUnknownErrorOc
 	id := utils.GetNewUUID()
 
 	testError := errors.New("test error")
-	suite.billServiceMock.On("Invoice", ctx, id).Return(&models.Invoice{}, testError)
+	suite.billServiceMock.On("Invoice", ctx, id, "").Return(&models.Invoice{}, testError)
 
-	_, err := suite.apiService.GetInvoiceHandler(ctx, id)
+	_, err := suite.apiService.GetInvoiceHandler(ctx, id, &models.InvoiceRequest{})
+	suite.NotNil(err)
+}
+
+func (suite *billHandlerTestSuite) Test_GetInvoiceHandlerFailsWhenExchangeRateIsNotFound() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.billServiceMock.On("Invoice", ctx, id, "EUR").Return(&models.Invoice{}, ce.ExchangeRateNotFoundError)
+
+	_, err := suite.apiService.GetInvoiceHandler(ctx, id, &models.InvoiceRequest{Currency: "EUR"})
 	suite.NotNil(err)
 }
 
@@ -446,6 +456,46 @@ func (suite *billHandlerTestSuite) Test_RemoveLineItemHandlerFailWhenUnknownErro
 	suite.NotNil(err)
 }
 
+func (suite *billHandlerTestSuite) Test_RecordPaymentHandlerSucceeds() {
+	ctx := context.Background()
+	billID := utils.GetNewUUID()
+	request := &models.PaymentRequest{Amount: 10, Reference: "txn-01"}
+	entry := &models.JournalEntry{ID: utils.GetNewUUID(), Kind: models.JournalPayment, BillID: billID}
+
+	suite.billServiceMock.On("RecordPayment", ctx, billID, request).Return(entry, nil)
+
+	recorded, err := suite.apiService.RecordPaymentHandler(ctx, billID, request)
+	suite.Nil(err)
+	suite.Equal(entry, recorded)
+}
+
+func (suite *billHandlerTestSuite) Test_RecordPaymentHandlerFailsWhenAmountIsInvalid() {
+	_, err := suite.apiService.RecordPaymentHandler(context.Background(), utils.GetNewUUID(), &models.PaymentRequest{Amount: 0})
+	suite.NotNil(err)
+}
+
+func (suite *billHandlerTestSuite) Test_RecordPaymentHandlerFailsWhenAmountExceedsBalanceDue() {
+	ctx := context.Background()
+	billID := utils.GetNewUUID()
+	request := &models.PaymentRequest{Amount: 10}
+
+	suite.billServiceMock.On("RecordPayment", ctx, billID, request).Return(&models.JournalEntry{}, ce.AmountExceedsBalanceDueError)
+
+	_, err := suite.apiService.RecordPaymentHandler(ctx, billID, request)
+	suite.NotNil(err)
+}
+
+func (suite *billHandlerTestSuite) Test_CreditBillHandlerFailsWhenBillIsOpen() {
+	ctx := context.Background()
+	billID := utils.GetNewUUID()
+	request := &models.CreditRequest{Amount: 10, Reason: "goodwill"}
+
+	suite.billServiceMock.On("Credit", ctx, billID, request).Return(&models.JournalEntry{}, ce.BillNotClosedError)
+
+	_, err := suite.apiService.CreditBillHandler(ctx, billID, request)
+	suite.NotNil(err)
+}
+
 func TestBillHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(billHandlerTestSuite))
 }
@@ -0,0 +1,269 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// encore:api auth method=POST path=/products
+func (bs *APIService) CreateProductHandler(ctx context.Context, request *models.CreateProductRequest) (*models.Product, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid product request")
+		return &models.Product{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid product request",
+		}
+	}
+
+	product, err := bs.Catalog.CreateProduct(ctx, request.ToProduct())
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create product", "error", err)
+		return &models.Product{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to create product",
+		}
+	}
+
+	return product, nil
+}
+
+// encore:api auth method=GET path=/products/:id
+func (bs *APIService) GetProductHandler(ctx context.Context, id string) (*models.Product, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid product id")
+		return &models.Product{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid product id",
+		}
+	}
+	product, err := bs.Catalog.GetProduct(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching product", "product_id", id, "error", err)
+		return &models.Product{}, apiError(err, "failed to get product")
+	}
+
+	return product, nil
+}
+
+// encore:api auth method=GET path=/products
+func (bs *APIService) ListProductsHandler(ctx context.Context, request *models.ListProductsRequest) (*models.ProductList, error) {
+	products, err := bs.Catalog.ListProducts(ctx, request.IncludeArchived)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list products", "error", err)
+		return &models.ProductList{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list products",
+		}
+	}
+
+	return &models.ProductList{Products: products}, nil
+}
+
+// encore:api auth method=PUT path=/products/:id
+func (bs *APIService) UpdateProductHandler(ctx context.Context, id string, request *models.UpdateProductRequest) (*models.Product, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid product request")
+		return &models.Product{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid product request",
+		}
+	}
+	product, err := bs.Catalog.UpdateProduct(ctx, id, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating product", "product_id", id, "error", err)
+		return &models.Product{}, apiError(err, "failed to update product")
+	}
+
+	return product, nil
+}
+
+// encore:api auth method=PUT path=/products/:id/archive
+func (bs *APIService) ArchiveProductHandler(ctx context.Context, id string) (*models.Product, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid product id")
+		return &models.Product{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid product id",
+		}
+	}
+	product, err := bs.Catalog.ArchiveProduct(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving product", "product_id", id, "error", err)
+		return &models.Product{}, apiError(err, "failed to archive product")
+	}
+
+	return product, nil
+}
+
+// encore:api auth method=POST path=/plans
+func (bs *APIService) CreatePlanHandler(ctx context.Context, request *models.CreatePlanRequest) (*models.Plan, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid plan request")
+		return &models.Plan{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid plan request",
+		}
+	}
+
+	plan, err := bs.Catalog.CreatePlan(ctx, request.ToPlan())
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create plan", "error", err)
+		return &models.Plan{}, apiError(err, "failed to create plan")
+	}
+
+	return plan, nil
+}
+
+// encore:api auth method=GET path=/plans/:id
+func (bs *APIService) GetPlanHandler(ctx context.Context, id string) (*models.Plan, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid plan id")
+		return &models.Plan{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid plan id",
+		}
+	}
+	plan, err := bs.Catalog.GetPlan(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching plan", "plan_id", id, "error", err)
+		return &models.Plan{}, apiError(err, "failed to get plan")
+	}
+
+	return plan, nil
+}
+
+// encore:api auth method=GET path=/products/:id/plans
+func (bs *APIService) ListPlansHandler(ctx context.Context, id string) (*models.PlanList, error) {
+	plans, err := bs.Catalog.ListPlans(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list plans for product", "product_id", id, "error", err)
+		return &models.PlanList{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list plans",
+		}
+	}
+
+	return &models.PlanList{Plans: plans}, nil
+}
+
+// encore:api auth method=PUT path=/plans/:id
+func (bs *APIService) UpdatePlanHandler(ctx context.Context, id string, request *models.UpdatePlanRequest) (*models.Plan, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid plan request")
+		return &models.Plan{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid plan request",
+		}
+	}
+	plan, err := bs.Catalog.UpdatePlan(ctx, id, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating plan", "plan_id", id, "error", err)
+		return &models.Plan{}, apiError(err, "failed to update plan")
+	}
+
+	return plan, nil
+}
+
+// encore:api auth method=PUT path=/plans/:id/archive
+func (bs *APIService) ArchivePlanHandler(ctx context.Context, id string) (*models.Plan, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid plan id")
+		return &models.Plan{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid plan id",
+		}
+	}
+	plan, err := bs.Catalog.ArchivePlan(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving plan", "plan_id", id, "error", err)
+		return &models.Plan{}, apiError(err, "failed to archive plan")
+	}
+
+	return plan, nil
+}
+
+// encore:api auth method=POST path=/prices
+func (bs *APIService) CreatePriceHandler(ctx context.Context, request *models.CreatePriceRequest) (*models.Price, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid price request")
+		return &models.Price{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid price request",
+		}
+	}
+
+	price, err := bs.Catalog.CreatePrice(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create price", "error", err)
+		return &models.Price{}, apiError(err, "failed to create price")
+	}
+
+	return price, nil
+}
+
+// encore:api auth method=GET path=/prices/:id
+func (bs *APIService) GetPriceHandler(ctx context.Context, id string) (*models.Price, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid price id")
+		return &models.Price{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid price id",
+		}
+	}
+	price, err := bs.Catalog.GetPrice(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching price", "price_id", id, "error", err)
+		return &models.Price{}, apiError(err, "failed to get price")
+	}
+
+	return price, nil
+}
+
+// encore:api auth method=GET path=/plans/:id/prices
+func (bs *APIService) ListPricesHandler(ctx context.Context, id string) (*models.PriceList, error) {
+	prices, err := bs.Catalog.ListPrices(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list prices for plan", "plan_id", id, "error", err)
+		return &models.PriceList{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list prices",
+		}
+	}
+
+	return &models.PriceList{Prices: prices}, nil
+}
+
+// encore:api auth method=PUT path=/prices/:id/archive
+func (bs *APIService) ArchivePriceHandler(ctx context.Context, id string) (*models.Price, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid price id")
+		return &models.Price{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid price id",
+		}
+	}
+	price, err := bs.Catalog.ArchivePrice(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving price", "price_id", id, "error", err)
+		return &models.Price{}, apiError(err, "failed to archive price")
+	}
+
+	return price, nil
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type catalogHandlerTestSuite struct {
+	suite.Suite
+	catalogServiceMock *service.CatalogServiceMock
+	apiService         *APIService
+}
+
+func (suite *catalogHandlerTestSuite) SetupTest() {
+	suite.catalogServiceMock = new(service.CatalogServiceMock)
+	suite.apiService = &APIService{
+		Catalog: suite.catalogServiceMock,
+	}
+}
+
+func (suite *catalogHandlerTestSuite) Test_CreateProductHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.CreateProductRequest{Name: "API"}
+
+	suite.catalogServiceMock.On("CreateProduct", ctx, mock.Anything).Return(&models.Product{ID: utils.GetNewUUID(), Name: "API", Active: true}, nil)
+
+	_, err := suite.apiService.CreateProductHandler(ctx, request)
+	suite.Nil(err)
+}
+
+func (suite *catalogHandlerTestSuite) Test_CreateProductHandlerFailsWhenRequestIsInvalid() {
+	ctx := context.Background()
+
+	_, err := suite.apiService.CreateProductHandler(ctx, &models.CreateProductRequest{})
+	suite.NotNil(err)
+}
+
+func (suite *catalogHandlerTestSuite) Test_GetProductHandlerFailsWhenProductIsNotFound() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.catalogServiceMock.On("GetProduct", ctx, id).Return(&models.Product{}, ce.ProductNotFoundError)
+
+	_, err := suite.apiService.GetProductHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *catalogHandlerTestSuite) Test_ListProductsHandlerSucceeds() {
+	ctx := context.Background()
+	products := []*models.Product{{ID: utils.GetNewUUID(), Name: "API", Active: true}}
+
+	suite.catalogServiceMock.On("ListProducts", ctx, false).Return(products, nil)
+
+	list, err := suite.apiService.ListProductsHandler(ctx, &models.ListProductsRequest{})
+	suite.Nil(err)
+	suite.Equal(1, len(list.Products))
+}
+
+func (suite *catalogHandlerTestSuite) Test_CreatePlanHandlerFailsWhenProductIsArchived() {
+	ctx := context.Background()
+	request := &models.CreatePlanRequest{
+		ProductID:       utils.GetNewUUID(),
+		Name:            "pro",
+		BillingInterval: models.MonthlyInterval,
+	}
+
+	suite.catalogServiceMock.On("CreatePlan", ctx, mock.Anything).Return(&models.Plan{}, ce.ProductArchivedError)
+
+	_, err := suite.apiService.CreatePlanHandler(ctx, request)
+	suite.NotNil(err)
+}
+
+func (suite *catalogHandlerTestSuite) Test_CreatePriceHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.CreatePriceRequest{
+		PlanID:       utils.GetNewUUID(),
+		CurrencyCode: "USD",
+		UnitAmount:   9.99,
+	}
+
+	suite.catalogServiceMock.On("CreatePrice", ctx, request).Return(&models.Price{ID: utils.GetNewUUID()}, nil)
+
+	_, err := suite.apiService.CreatePriceHandler(ctx, request)
+	suite.Nil(err)
+}
+
+func (suite *catalogHandlerTestSuite) Test_ArchivePriceHandlerFailsWhenUnknownErrorOccured() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.catalogServiceMock.On("ArchivePrice", ctx, id).Return(&models.Price{}, errors.New("test error"))
+
+	_, err := suite.apiService.ArchivePriceHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func TestCatalogHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(catalogHandlerTestSuite))
+}
@@ -2,17 +2,16 @@ package handlers
 
 import (
 	"context"
-	"log"
 
 	"encore.dev/beta/errs"
 	"github.com/asheet-bhaskar/billing-service/app/models"
-	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
 )
 
-// encore:api method=GET path=/currencies/:id
+// encore:api auth method=GET path=/currencies/:id
 func (bs *APIService) GetCurrencyHandler(ctx context.Context, id string) (*models.Currency, error) {
 	if id == "" {
-		log.Println("invalid currency id")
+		logging.From(ctx).Warn("invalid currency id")
 		return &models.Currency{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid currency id",
@@ -20,29 +19,18 @@ func (bs *APIService) GetCurrencyHandler(ctx context.Context, id string) (*model
 	}
 	currency, err := bs.Currency.GetByID(ctx, id)
 
-	if err == ce.CurrencyNotFoundError {
-		log.Printf("currency not found for id %s\n", id)
-		return &models.Currency{}, &errs.Error{
-			Code:    errs.NotFound,
-			Message: "currency not found",
-		}
-	}
-
 	if err != nil {
-		log.Printf("error occurred while fetching currency for id %s\n", id)
-		return &models.Currency{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to get currency",
-		}
+		logging.From(ctx).Error("error occurred while fetching currency", "currency_id", id, "error", err)
+		return &models.Currency{}, apiError(err, "failed to get currency")
 	}
 
 	return currency, nil
 }
 
-// encore:api  method=POST path=/currencies
+// encore:api auth method=POST path=/currencies
 func (bs *APIService) CreateCurrencyHandler(ctx context.Context, request *models.CreateCurrencyRequest) (*models.Currency, error) {
 	if !request.IsValid() {
-		log.Println("invalid currency request")
+		logging.From(ctx).Warn("invalid currency request")
 		return &models.Currency{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid currency request",
@@ -51,21 +39,72 @@ func (bs *APIService) CreateCurrencyHandler(ctx context.Context, request *models
 
 	currency, err := bs.Currency.Create(ctx, request.ToCurrency())
 
-	if err == ce.CurrencyAlreadyExistError {
-		log.Println("currency already exists")
-		return &models.Currency{}, &errs.Error{
+	if err != nil {
+		logging.From(ctx).Error("failed to create currency", "error", err)
+		return &models.Currency{}, apiError(err, "failed to create currency")
+	}
+
+	return currency, nil
+}
+
+// encore:api auth method=GET path=/currencies
+func (bs *APIService) ListCurrenciesHandler(ctx context.Context, request *models.ListCurrenciesRequest) (*models.CurrencyList, error) {
+	currencies, err := bs.Currency.List(ctx, request.IncludeInactive)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list currencies", "error", err)
+		return &models.CurrencyList{}, &errs.Error{
 			Code:    errs.Unknown,
-			Message: "currency already exists",
+			Message: "failed to list currencies",
 		}
 	}
 
-	if err != nil {
-		log.Println("failed to create currency")
+	return &models.CurrencyList{Currencies: currencies}, nil
+}
+
+// encore:api auth method=PUT path=/currencies/:id
+func (bs *APIService) UpdateCurrencyHandler(ctx context.Context, id string, request *models.UpdateCurrencyRequest) (*models.Currency, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid currency update request")
 		return &models.Currency{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to create currency",
+			Code:    errs.InvalidArgument,
+			Message: "invalid currency update request",
 		}
 	}
 
+	currency, err := bs.Currency.Update(ctx, id, request)
+	return currencyResponse(ctx, id, currency, err, "failed to update currency")
+}
+
+// encore:api auth method=PUT path=/currencies/:id/activate
+func (bs *APIService) ActivateCurrencyHandler(ctx context.Context, id string) (*models.Currency, error) {
+	currency, err := bs.Currency.Activate(ctx, id)
+	return currencyResponse(ctx, id, currency, err, "failed to activate currency")
+}
+
+// encore:api auth method=PUT path=/currencies/:id/deactivate
+func (bs *APIService) DeactivateCurrencyHandler(ctx context.Context, id string) (*models.Currency, error) {
+	currency, err := bs.Currency.Deactivate(ctx, id)
+	return currencyResponse(ctx, id, currency, err, "failed to deactivate currency")
+}
+
+// encore:api auth method=DELETE path=/currencies/:id
+func (bs *APIService) DeleteCurrencyHandler(ctx context.Context, id string) error {
+	err := bs.Currency.Delete(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while deleting currency", "currency_id", id, "error", err)
+		return apiError(err, "failed to delete currency")
+	}
+
+	return nil
+}
+
+func currencyResponse(ctx context.Context, id string, currency *models.Currency, err error, message string) (*models.Currency, error) {
+	if err != nil {
+		logging.From(ctx).Error(message, "currency_id", id, "error", err)
+		return &models.Currency{}, apiError(err, message)
+	}
+
 	return currency, nil
 }
@@ -180,6 +180,68 @@ func (suite *currencyHandlerTestSuite) Test_GetCustoemrHandlerFailsWhenUnknownEr
 	suite.NotNil(err)
 }
 
+func (suite *currencyHandlerTestSuite) Test_CreateReturnErrorWhenCodeIsNotISO() {
+	ctx := context.Background()
+	currencyRequest := &models.CreateCurrencyRequest{
+		Code:   "XYZ",
+		Name:   "Unknown",
+		Symbol: "X",
+	}
+
+	suite.currencyServiceMock.On("Create", ctx, mock.Anything).Return(&models.Currency{}, ce.ISOCurrencyNotFoundError)
+
+	_, err := suite.apiService.CreateCurrencyHandler(ctx, currencyRequest)
+	suite.NotNil(err)
+}
+
+func (suite *currencyHandlerTestSuite) Test_ListCurrenciesHandlerSucceeds() {
+	ctx := context.Background()
+	currencies := []*models.Currency{{ID: utils.GetNewUUID(), Code: "USD", Active: true}}
+
+	suite.currencyServiceMock.On("List", ctx, true).Return(currencies, nil)
+
+	list, err := suite.apiService.ListCurrenciesHandler(ctx, &models.ListCurrenciesRequest{IncludeInactive: true})
+	suite.Nil(err)
+	suite.Equal(currencies, list.Currencies)
+}
+
+func (suite *currencyHandlerTestSuite) Test_UpdateCurrencyHandlerFailsWhenRequestIsInvalid() {
+	ctx := context.Background()
+
+	_, err := suite.apiService.UpdateCurrencyHandler(ctx, utils.GetNewUUID(), &models.UpdateCurrencyRequest{})
+	suite.NotNil(err)
+}
+
+func (suite *currencyHandlerTestSuite) Test_DeactivateCurrencyHandlerFailsWhenCurrencyNotFound() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.currencyServiceMock.On("Deactivate", ctx, id).Return(&models.Currency{}, ce.CurrencyNotFoundError)
+
+	_, err := suite.apiService.DeactivateCurrencyHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *currencyHandlerTestSuite) Test_DeleteCurrencyHandlerFailsWhenCurrencyIsInUse() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.currencyServiceMock.On("Delete", ctx, id).Return(ce.CurrencyInUseError)
+
+	err := suite.apiService.DeleteCurrencyHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *currencyHandlerTestSuite) Test_DeleteCurrencyHandlerSucceeds() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.currencyServiceMock.On("Delete", ctx, id).Return(nil)
+
+	err := suite.apiService.DeleteCurrencyHandler(ctx, id)
+	suite.Nil(err)
+}
+
 func TestCurrencyHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(currencyHandlerTestSuite))
 }
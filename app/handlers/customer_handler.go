@@ -2,17 +2,20 @@ package handlers
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"net/http"
+	"time"
 
+	"encore.dev"
 	"encore.dev/beta/errs"
 	"github.com/asheet-bhaskar/billing-service/app/models"
-	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
 )
 
-// encore:api method=GET path=/customers/:id
+// encore:api auth method=GET path=/customers/:id
 func (bs *APIService) GetCustomerHandler(ctx context.Context, id string) (*models.Customer, error) {
 	if id == "" {
-		log.Println("invalid customer id")
+		logging.From(ctx).Warn("invalid customer id")
 		return &models.Customer{}, &errs.Error{
 			Code:    errs.InvalidArgument,
 			Message: "invalid customer id",
@@ -20,52 +23,179 @@ func (bs *APIService) GetCustomerHandler(ctx context.Context, id string) (*model
 	}
 	customer, err := bs.Customer.GetByID(ctx, id)
 
-	if err == ce.CustomerNotFoundError {
-		log.Printf("customer not found for id %s\n", id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching customer", "customer_id", id, "error", err)
+		return &models.Customer{}, apiError(err, "failed to get customer")
+	}
+
+	return customer, nil
+}
+
+// encore:api auth method=POST path=/customers
+func (bs *APIService) CreateCustomerHandler(ctx context.Context, request *models.CreateCustomerRequest) (*models.Customer, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid customer request")
 		return &models.Customer{}, &errs.Error{
-			Code:    errs.NotFound,
-			Message: "customer not found",
+			Code:    errs.InvalidArgument,
+			Message: "invalid customer request",
 		}
 	}
 
+	customer, err := bs.Customer.Create(ctx, request.ToCustomer())
+
 	if err != nil {
-		log.Printf("error occurred while fetching customer for id %s\n", id)
-		return &models.Customer{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to get customer",
-		}
+		logging.From(ctx).Error("failed to create customer", "error", err)
+		return &models.Customer{}, apiError(err, "failed to create customer")
 	}
 
 	return customer, nil
 }
 
-// encore:api  method=POST path=/customers
-func (bs *APIService) CreateCustomerHandler(ctx context.Context, request *models.CreateCustomerRequest) (*models.Customer, error) {
+// encore:api auth method=GET path=/customers
+func (bs *APIService) ListCustomersHandler(ctx context.Context, request *models.ListCustomersRequest) (*models.CustomerList, error) {
 	if !request.IsValid() {
-		log.Println("invalid customer request")
+		logging.From(ctx).Warn("invalid list customers request")
+		return &models.CustomerList{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: fmt.Sprintf("invalid list customers request, limit must be between 0 and %d", models.MaxPageSize),
+		}
+	}
+
+	customers, err := bs.Customer.List(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list customers", "error", err)
+		return &models.CustomerList{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list customers",
+		}
+	}
+
+	return customers, nil
+}
+
+// encore:api auth method=PUT path=/customers/:id
+func (bs *APIService) UpdateCustomerHandler(ctx context.Context, id string, request *models.UpdateCustomerRequest) (*models.Customer, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid customer update request")
 		return &models.Customer{}, &errs.Error{
 			Code:    errs.InvalidArgument,
-			Message: "invalid customer request",
+			Message: "invalid customer update request",
 		}
 	}
 
-	customer, err := bs.Customer.Create(ctx, request.ToCustomer())
+	customer, err := bs.Customer.Update(ctx, id, request)
 
-	if err == ce.CustomerAlreadyExistError {
-		log.Println("customer already exists")
+	return customerResponse(ctx, id, customer, err, "failed to update customer")
+}
+
+// encore:api auth method=PUT path=/customers/:id/archive
+func (bs *APIService) ArchiveCustomerHandler(ctx context.Context, id string) (*models.Customer, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid customer id")
 		return &models.Customer{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "customer already exists",
+			Code:    errs.InvalidArgument,
+			Message: "invalid customer id",
 		}
 	}
 
-	if err != nil {
-		log.Println("failed to create customer")
+	customer, err := bs.Customer.Archive(ctx, id)
+	return customerResponse(ctx, id, customer, err, "failed to archive customer")
+}
+
+// encore:api auth method=DELETE path=/customers/:id
+func (bs *APIService) DeleteCustomerHandler(ctx context.Context, id string) (*models.Customer, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid customer id")
 		return &models.Customer{}, &errs.Error{
-			Code:    errs.Unknown,
-			Message: "failed to create customer",
+			Code:    errs.InvalidArgument,
+			Message: "invalid customer id",
+		}
+	}
+
+	customer, err := bs.Customer.Delete(ctx, id)
+
+	return customerResponse(ctx, id, customer, err, "failed to delete customer")
+}
+
+// encore:api auth method=POST path=/customers/:id/purge
+func (bs *APIService) PurgeCustomerHandler(ctx context.Context, id string, request *models.PurgeRequest) (*models.Purge, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid purge request")
+		return &models.Purge{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid purge request, reason and requester are required",
 		}
 	}
 
+	purge, err := bs.Customer.Purge(ctx, id, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while purging customer", "customer_id", id, "error", err)
+		return &models.Purge{}, apiError(err, "failed to purge customer")
+	}
+
+	return purge, nil
+}
+
+// encore:api auth method=GET path=/customers/:id/statement
+func (bs *APIService) GetCustomerStatementHandler(ctx context.Context, id string, request *models.StatementRequest) (*models.Statement, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid statement request")
+		return &models.Statement{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid statement request, from must not be after to",
+		}
+	}
+
+	statement, err := bs.Customer.Statement(ctx, id, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while building statement of customer", "customer_id", id, "error", err)
+		return &models.Statement{}, apiError(err, "failed to get statement")
+	}
+
+	return statement, nil
+}
+
+// PrintCustomerStatementHandler renders the statement as plain text for
+// sending to the customer. It takes the from and to query parameters of the
+// statement endpoint as RFC 3339 timestamps.
+//
+// encore:api auth raw method=GET path=/customers/:id/statement/print
+func (bs *APIService) PrintCustomerStatementHandler(w http.ResponseWriter, req *http.Request) {
+	id := encore.CurrentRequest().PathParams.Get("id")
+
+	request := &models.StatementRequest{}
+	for name, value := range map[string]*time.Time{"from": &request.From, "to": &request.To} {
+		if param := req.URL.Query().Get(name); param != "" {
+			parsed, err := time.Parse(time.RFC3339, param)
+			if err != nil {
+				errs.HTTPError(w, &errs.Error{
+					Code:    errs.InvalidArgument,
+					Message: fmt.Sprintf("invalid %s, expected an RFC 3339 timestamp", name),
+				})
+				return
+			}
+			*value = parsed
+		}
+	}
+
+	statement, err := bs.GetCustomerStatementHandler(req.Context(), id, request)
+	if err != nil {
+		errs.HTTPError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Write([]byte(statement.Render()))
+}
+
+func customerResponse(ctx context.Context, id string, customer *models.Customer, err error, message string) (*models.Customer, error) {
+	if err != nil {
+		logging.From(ctx).Error(message, "customer_id", id, "error", err)
+		return &models.Customer{}, apiError(err, message)
+	}
+
 	return customer, nil
 }
@@ -185,6 +185,110 @@ func (suite *customerHandlerTestSuite) Test_GetCustoemrHandlerFailsWhenUnknownEr
 	suite.NotNil(err)
 }
 
+func (suite *customerHandlerTestSuite) Test_ListCustomersHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.ListCustomersRequest{Query: "john", Limit: 10}
+	list := &models.CustomerList{Customers: []*models.Customer{{ID: utils.GetNewUUID(), Email: "john.jacobs@mail.com"}}, Total: 1}
+
+	suite.customerServiceMock.On("List", ctx, request).Return(list, nil)
+
+	response, err := suite.apiService.ListCustomersHandler(ctx, request)
+	suite.Nil(err)
+	suite.Equal(list, response)
+}
+
+func (suite *customerHandlerTestSuite) Test_ListCustomersHandlerFailsWhenLimitIsTooLarge() {
+	ctx := context.Background()
+
+	_, err := suite.apiService.ListCustomersHandler(ctx, &models.ListCustomersRequest{Limit: models.MaxPageSize + 1})
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_UpdateCustomerHandlerFailsWhenEmailIsTaken() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+	request := &models.UpdateCustomerRequest{Email: "jane@mail.com"}
+
+	suite.customerServiceMock.On("Update", ctx, id, request).Return(&models.Customer{}, ce.CustomerAlreadyExistError)
+
+	_, err := suite.apiService.UpdateCustomerHandler(ctx, id, request)
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_ArchiveCustomerHandlerFailsWhenCustomerNotFound() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.customerServiceMock.On("Archive", ctx, id).Return(&models.Customer{}, ce.CustomerNotFoundError)
+
+	_, err := suite.apiService.ArchiveCustomerHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_DeleteCustomerHandlerFailsWhenSubscriptionsAreActive() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.customerServiceMock.On("Delete", ctx, id).Return(&models.Customer{}, ce.CustomerHasActiveSubscriptionsError)
+
+	_, err := suite.apiService.DeleteCustomerHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_PurgeCustomerHandlerFailsWhenRequestIsInvalid() {
+	ctx := context.Background()
+
+	_, err := suite.apiService.PurgeCustomerHandler(ctx, utils.GetNewUUID(), &models.PurgeRequest{Reason: "gdpr request"})
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_PurgeCustomerHandlerFailsWhenCustomerHasClosedBills() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+	request := &models.PurgeRequest{Reason: "gdpr request", RequestedBy: "ops@mail.com"}
+
+	suite.customerServiceMock.On("Purge", ctx, id, request).Return(&models.Purge{}, ce.CustomerHasClosedBillsError)
+
+	_, err := suite.apiService.PurgeCustomerHandler(ctx, id, request)
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_CreateCustomerHandlerFailsWhenDefaultCurrencyIsUnknown() {
+	ctx := context.Background()
+	request := &models.CreateCustomerRequest{
+		FirstName:       "John",
+		LastName:        "Jacobs",
+		Email:           "john.jacobs@mail.com",
+		DefaultCurrency: "XYZ",
+		PaymentTerms:    models.Net30,
+	}
+
+	suite.customerServiceMock.On("Create", ctx, mock.Anything).Return(&models.Customer{}, ce.CurrencyNotFoundError)
+
+	_, err := suite.apiService.CreateCustomerHandler(ctx, request)
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_GetCustomerStatementHandlerFailsWhenWindowIsInvalid() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	_, err := suite.apiService.GetCustomerStatementHandler(ctx, utils.GetNewUUID(), &models.StatementRequest{From: now, To: now.Add(-time.Hour)})
+	suite.NotNil(err)
+}
+
+func (suite *customerHandlerTestSuite) Test_GetCustomerStatementHandlerSucceeds() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+	request := &models.StatementRequest{}
+
+	suite.customerServiceMock.On("Statement", ctx, id, request).Return(&models.Statement{CustomerID: id}, nil)
+
+	statement, err := suite.apiService.GetCustomerStatementHandler(ctx, id, request)
+	suite.Nil(err)
+	suite.Equal(id, statement.CustomerID)
+}
+
 func TestCustomerHandlerTestSuite(t *testing.T) {
 	suite.Run(t, new(customerHandlerTestSuite))
 }
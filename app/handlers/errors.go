@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+
+	"encore.dev/beta/errs"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+)
+
+// errorCodes is the api error code of every code of the domain errors.
+var errorCodes = map[ce.Code]errs.ErrCode{
+	ce.NotFound:           errs.NotFound,
+	ce.AlreadyExists:      errs.AlreadyExists,
+	ce.InvalidArgument:    errs.InvalidArgument,
+	ce.FailedPrecondition: errs.FailedPrecondition,
+	ce.Unauthenticated:    errs.Unauthenticated,
+	ce.PermissionDenied:   errs.PermissionDenied,
+	ce.Unavailable:        errs.Unavailable,
+	ce.Internal:           errs.Internal,
+}
+
+// ErrorDetails names the domain error a request failed with and the entity
+// it is about. Reason is stable, clients match on it rather than on the
+// message.
+type ErrorDetails struct {
+	Reason string
+	Entity string
+	ID     string
+}
+
+func (ErrorDetails) ErrDetails() {}
+
+// apiError returns the api error of err. Domain errors keep their message,
+// any other error is a failure of the service, answered errs.Unknown with
+// message so nothing of it leaks.
+func apiError(err error, message string) error {
+	var domainErr *ce.Error
+	if !errors.As(err, &domainErr) {
+		return &errs.Error{
+			Code:    errs.Unknown,
+			Message: message,
+		}
+	}
+
+	code, ok := errorCodes[domainErr.Code]
+	if !ok {
+		code = errs.Unknown
+	}
+
+	return &errs.Error{
+		Code:    code,
+		Message: domainErr.Error(),
+		Details: ErrorDetails{
+			Reason: domainErr.Reason,
+			Entity: domainErr.Entity,
+			ID:     domainErr.ID,
+		},
+	}
+}
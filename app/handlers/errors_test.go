@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"encore.dev/beta/errs"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/stretchr/testify/suite"
+)
+
+type errorsTestSuite struct {
+	suite.Suite
+}
+
+func (suite *errorsTestSuite) Test_APIErrorMapsDomainErrors() {
+	err := apiError(fmt.Errorf("closing bill: %w", ce.BillClosedError.WithID("bill-01")), "failed to close bill")
+
+	suite.Equal(errs.FailedPrecondition, errs.Code(err))
+	suite.Equal("Bill is closed, bill-01", err.(*errs.Error).Message)
+	suite.Equal(ErrorDetails{Reason: "bill_closed", Entity: "bill", ID: "bill-01"}, err.(*errs.Error).Details)
+}
+
+func (suite *errorsTestSuite) Test_APIErrorHidesOtherErrors() {
+	err := apiError(errors.New("connection refused"), "failed to close bill")
+
+	suite.Equal(errs.Unknown, errs.Code(err))
+	suite.Equal("failed to close bill", err.(*errs.Error).Message)
+	suite.Nil(err.(*errs.Error).Details)
+}
+
+func (suite *errorsTestSuite) Test_EveryCodeHasAnAPICode() {
+	for _, code := range []ce.Code{ce.NotFound, ce.AlreadyExists, ce.InvalidArgument, ce.FailedPrecondition,
+		ce.Unauthenticated, ce.PermissionDenied, ce.Unavailable, ce.Internal} {
+		_, ok := errorCodes[code]
+		suite.True(ok, "code %s has no api code", code)
+	}
+}
+
+func TestErrorsTestSuite(t *testing.T) {
+	suite.Run(t, new(errorsTestSuite))
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+// Topics of the billing domain events. Topic names never change once
+// deployed.
+var (
+	BillEvents = pubsub.NewTopic[*models.BillEvent]("bill-events", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	LineItemEvents = pubsub.NewTopic[*models.LineItemEvent]("line-item-events", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	CustomerEvents = pubsub.NewTopic[*models.CustomerEvent]("customer-events", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+
+	CurrencyEvents = pubsub.NewTopic[*models.CurrencyEvent]("currency-events", pubsub.TopicConfig{
+		DeliveryGuarantee: pubsub.AtLeastOnce,
+	})
+)
+
+// topicPublisher publishes domain events on the Pub/Sub topics.
+type topicPublisher struct{}
+
+func (tp *topicPublisher) PublishBill(ctx context.Context, eventType string, bill *models.Bill) {
+	_, err := BillEvents.Publish(ctx, &models.BillEvent{
+		ID:         utils.GetNewUUID(),
+		Type:       eventType,
+		Bill:       bill,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		logging.From(ctx).Error("failed to publish event of bill", "event_type", eventType, "bill_id", bill.ID, "error", err)
+	}
+}
+
+func (tp *topicPublisher) PublishLineItem(ctx context.Context, eventType string, lineItem *models.LineItem) {
+	_, err := LineItemEvents.Publish(ctx, &models.LineItemEvent{
+		ID:         utils.GetNewUUID(),
+		Type:       eventType,
+		BillID:     lineItem.BillID,
+		LineItem:   lineItem,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		logging.From(ctx).Error("failed to publish event of line item", "event_type", eventType, "line_item_id", lineItem.ID, "error", err)
+	}
+}
+
+func (tp *topicPublisher) PublishCustomer(ctx context.Context, eventType string, customerID string, customer *models.Customer) {
+	_, err := CustomerEvents.Publish(ctx, &models.CustomerEvent{
+		ID:         utils.GetNewUUID(),
+		Type:       eventType,
+		CustomerID: customerID,
+		Customer:   customer,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		logging.From(ctx).Error("failed to publish event of customer", "event_type", eventType, "customer_id", customerID, "error", err)
+	}
+}
+
+func (tp *topicPublisher) PublishCurrency(ctx context.Context, eventType string, currency *models.Currency) {
+	_, err := CurrencyEvents.Publish(ctx, &models.CurrencyEvent{
+		ID:         utils.GetNewUUID(),
+		Type:       eventType,
+		Currency:   currency,
+		OccurredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		logging.From(ctx).Error("failed to publish event of currency", "event_type", eventType, "currency_id", currency.ID, "error", err)
+	}
+}
+
+// BillSummaryProjection is an example subscriber keeping a summary of every
+// bill from the bill events. Failed events are retried.
+var BillSummaryProjection = pubsub.NewSubscription(BillEvents, "bill-summary-projection", pubsub.SubscriptionConfig[*models.BillEvent]{
+	Handler:     pubsub.MethodHandler((*APIService).ProjectBillSummary),
+	RetryPolicy: &pubsub.RetryPolicy{MaxRetries: 10},
+})
+
+func (bs *APIService) ProjectBillSummary(ctx context.Context, event *models.BillEvent) error {
+	return bs.Projection.ProjectBillEvent(billEventContext(ctx, event), event)
+}
+
+// billEventContext returns a copy of ctx carrying the tenant of the bill of
+// the event, whose logger names the event and the bill.
+func billEventContext(ctx context.Context, event *models.BillEvent) context.Context {
+	ctx = logging.With(ctx, "event_id", event.ID, "event_type", event.Type)
+	if event.Bill == nil {
+		return ctx
+	}
+	return logging.With(tenancy.WithTenant(ctx, event.Bill.TenantID), "tenant_id", event.Bill.TenantID, "bill_id", event.Bill.ID)
+}
+
+// encore:api auth method=GET path=/customers/:id/bill-summaries
+func (bs *APIService) ListBillSummariesHandler(ctx context.Context, id string) (*models.BillSummaries, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid customer id")
+		return &models.BillSummaries{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid customer id",
+		}
+	}
+
+	summaries, err := bs.Projection.ListBillSummaries(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing bill summaries of customer", "customer_id", id, "error", err)
+		return &models.BillSummaries{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list bill summaries",
+		}
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// encore:api auth method=POST path=/exchange-rates
+func (bs *APIService) CreateExchangeRateHandler(ctx context.Context, request *models.CreateExchangeRateRequest) (*models.ExchangeRate, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid exchange rate request")
+		return &models.ExchangeRate{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid exchange rate request",
+		}
+	}
+
+	rate, err := bs.ExchangeRate.Create(ctx, request.ToExchangeRate())
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create exchange rate", "error", err)
+		return &models.ExchangeRate{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to create exchange rate",
+		}
+	}
+
+	return rate, nil
+}
+
+// encore:api auth method=GET path=/exchange-rates
+func (bs *APIService) GetExchangeRateHandler(ctx context.Context, request *models.GetExchangeRateRequest) (*models.ExchangeRate, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid exchange rate request")
+		return &models.ExchangeRate{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid exchange rate request",
+		}
+	}
+
+	at := request.Date
+	if at.IsZero() {
+		at = time.Now().UTC()
+	}
+
+	rate, err := bs.ExchangeRate.GetRate(ctx, request.Base, request.Quote, at)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching exchange rate", "base", request.Base, "quote", request.Quote, "error", err)
+		return &models.ExchangeRate{}, apiError(err, "failed to get exchange rate")
+	}
+
+	return rate, nil
+}
+
+// encore:api auth method=POST path=/exchange-rates/sync
+func (bs *APIService) SyncExchangeRatesHandler(ctx context.Context) (*models.ExchangeRateList, error) {
+	rates, err := bs.ExchangeRate.Sync(ctx)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to sync exchange rates", "error", err)
+		return &models.ExchangeRateList{}, apiError(err, "failed to sync exchange rates")
+	}
+
+	return &models.ExchangeRateList{Rates: rates}, nil
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// encore:api auth method=GET path=/bills/:id/journal
+func (bs *APIService) GetBillJournalHandler(ctx context.Context, id string) (*models.JournalEntries, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid bill id")
+		return &models.JournalEntries{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid bill id",
+		}
+	}
+
+	entries, err := bs.Ledger.ListByBillID(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing journal entries of bill", "bill_id", id, "error", err)
+		return &models.JournalEntries{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list journal entries",
+		}
+	}
+
+	return entries, nil
+}
+
+// encore:api auth method=GET path=/ledger/check
+func (bs *APIService) CheckLedgerHandler(ctx context.Context) (*models.LedgerCheck, error) {
+	check, err := bs.Ledger.Check(ctx)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while checking the ledger", "error", err)
+		return &models.LedgerCheck{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to check the ledger",
+		}
+	}
+
+	if !check.Balanced {
+		logging.From(ctx).Warn("ledger has unbalanced journal entries", "imbalances", len(check.Imbalances))
+	}
+
+	return check, nil
+}
@@ -0,0 +1,39 @@
+package handlers
+
+import (
+	"net/http"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+)
+
+// MetricsHandler writes the metrics of the service in the Prometheus text
+// format, counting the open bills first. Metrics count the work of all
+// tenants, so only keys of the default tenant read them.
+//
+// encore:api auth raw method=GET path=/metrics
+func (bs *APIService) MetricsHandler(w http.ResponseWriter, req *http.Request) {
+	if tenancy.From(req.Context()) != tenancy.DefaultTenant {
+		logging.From(req.Context()).Warn("metrics requested by another tenant")
+		errs.HTTPError(w, &errs.Error{
+			Code:    errs.PermissionDenied,
+			Message: "metrics are only readable by keys of the default tenant",
+		})
+		return
+	}
+
+	open, err := bs.Bill.CountOpen(req.Context())
+	if err != nil {
+		logging.From(req.Context()).Error("error occurred while counting open bills", "error", err)
+		errs.HTTPError(w, err)
+		return
+	}
+	metrics.BillsOpen.Set(float64(open))
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	if err := metrics.Default.WritePrometheus(w); err != nil {
+		logging.From(req.Context()).Error("error occurred while writing metrics", "error", err)
+	}
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"encore.dev/pubsub"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// InvoiceEmails emails the invoice of every closed bill to its customer.
+// Failed sends are retried.
+var InvoiceEmails = pubsub.NewSubscription(BillEvents, "invoice-emails", pubsub.SubscriptionConfig[*models.BillEvent]{
+	Handler:     pubsub.MethodHandler((*APIService).SendClosedInvoice),
+	RetryPolicy: &pubsub.RetryPolicy{MaxRetries: 10},
+})
+
+func (bs *APIService) SendClosedInvoice(ctx context.Context, event *models.BillEvent) error {
+	return bs.Notification.SendClosedInvoice(billEventContext(ctx, event), event)
+}
+
+// encore:api auth method=POST path=/bills/:id/invoice/emails
+func (bs *APIService) SendInvoiceEmailHandler(ctx context.Context, id string) (*models.InvoiceDelivery, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid bill id")
+		return &models.InvoiceDelivery{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid bill id",
+		}
+	}
+
+	delivery, err := bs.Notification.SendInvoice(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while sending invoice email of bill", "bill_id", id, "error", err)
+		return &models.InvoiceDelivery{}, apiError(err, "failed to send invoice email")
+	}
+
+	return delivery, nil
+}
+
+// encore:api auth method=GET path=/bills/:id/invoice/emails
+func (bs *APIService) ListInvoiceEmailsHandler(ctx context.Context, id string) (*models.InvoiceDeliveries, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid bill id")
+		return &models.InvoiceDeliveries{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid bill id",
+		}
+	}
+
+	deliveries, err := bs.Notification.ListInvoiceDeliveries(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing invoice emails of bill", "bill_id", id, "error", err)
+		return &models.InvoiceDeliveries{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list invoice emails",
+		}
+	}
+
+	return deliveries, nil
+}
+
+// encore:api auth method=GET path=/email-templates/:name/:locale
+func (bs *APIService) GetEmailTemplateHandler(ctx context.Context, name string, locale string) (*models.EmailTemplate, error) {
+	if !models.IsValidLocale(locale) {
+		logging.From(ctx).Warn("invalid email template locale")
+		return &models.EmailTemplate{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid email template locale, locale must be a language tag such as en-US",
+		}
+	}
+
+	template, err := bs.Notification.GetTemplate(ctx, name, locale)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching email template", "name", name, "locale", locale, "error", err)
+		return &models.EmailTemplate{}, apiError(err, "failed to fetch email template")
+	}
+
+	return template, nil
+}
+
+// encore:api auth method=PUT path=/email-templates/:name/:locale
+func (bs *APIService) UpdateEmailTemplateHandler(ctx context.Context, name string, locale string, request *models.EmailTemplateRequest) (*models.EmailTemplate, error) {
+	if !models.IsValidLocale(locale) || !request.IsValid() {
+		logging.From(ctx).Warn("invalid email template request")
+		return &models.EmailTemplate{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid email template request, locale must be a language tag and subject, text and html are required",
+		}
+	}
+
+	template, err := bs.Notification.UpdateTemplate(ctx, name, locale, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating email template", "name", name, "locale", locale, "error", err)
+		return &models.EmailTemplate{}, apiError(err, "failed to update email template")
+	}
+
+	return template, nil
+}
+
+// encore:api auth method=DELETE path=/email-templates/:name/:locale
+func (bs *APIService) ResetEmailTemplateHandler(ctx context.Context, name string, locale string) (*models.EmailTemplate, error) {
+	template, err := bs.Notification.ResetTemplate(ctx, name, locale)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while resetting email template", "name", name, "locale", locale, "error", err)
+		return &models.EmailTemplate{}, apiError(err, "failed to reset email template")
+	}
+
+	return template, nil
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/stretchr/testify/suite"
+)
+
+type notificationHandlerTestSuite struct {
+	suite.Suite
+	notificationServiceMock *service.NotificationServiceMock
+	apiService              *APIService
+}
+
+func (suite *notificationHandlerTestSuite) SetupTest() {
+	suite.notificationServiceMock = new(service.NotificationServiceMock)
+	suite.apiService = &APIService{
+		Notification: suite.notificationServiceMock,
+	}
+}
+
+func (suite *notificationHandlerTestSuite) Test_SendInvoiceEmailHandlerSucceeds() {
+	ctx := context.Background()
+	delivery := &models.InvoiceDelivery{ID: "delivery id", BillID: "bill id", Status: models.InvoiceEmailSent}
+
+	suite.notificationServiceMock.On("SendInvoice", ctx, "bill id").Return(delivery, nil)
+
+	sent, err := suite.apiService.SendInvoiceEmailHandler(ctx, "bill id")
+	suite.Nil(err)
+	suite.Equal(delivery, sent)
+}
+
+func (suite *notificationHandlerTestSuite) Test_SendInvoiceEmailHandlerFailsForOpenBill() {
+	ctx := context.Background()
+
+	suite.notificationServiceMock.On("SendInvoice", ctx, "bill id").Return(&models.InvoiceDelivery{}, ce.BillNotClosedError)
+
+	_, err := suite.apiService.SendInvoiceEmailHandler(ctx, "bill id")
+	suite.NotNil(err)
+}
+
+func (suite *notificationHandlerTestSuite) Test_UpdateEmailTemplateHandlerFailsForInvalidLocale() {
+	request := &models.EmailTemplateRequest{Subject: "Invoice", Text: "Text", HTML: "<p>Text</p>"}
+
+	_, err := suite.apiService.UpdateEmailTemplateHandler(context.Background(), models.InvoiceEmailTemplate, "english", request)
+	suite.NotNil(err)
+}
+
+func (suite *notificationHandlerTestSuite) Test_UpdateEmailTemplateHandlerFailsForInvalidTemplate() {
+	ctx := context.Background()
+	request := &models.EmailTemplateRequest{Subject: "Invoice", Text: "{{ .Amount }}", HTML: "<p>Text</p>"}
+
+	suite.notificationServiceMock.On("UpdateTemplate", ctx, models.InvoiceEmailTemplate, "en", request).Return(&models.EmailTemplate{}, ce.InvalidEmailTemplateError)
+
+	_, err := suite.apiService.UpdateEmailTemplateHandler(ctx, models.InvoiceEmailTemplate, "en", request)
+	suite.NotNil(err)
+}
+
+func TestNotificationHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(notificationHandlerTestSuite))
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"fmt"
+	"log/slog"
+
+	"encore.dev"
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+	"encore.dev/middleware"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+)
+
+// endpointPermissions is the permission each endpoint requires of the role of
+// the api key, by endpoint name. Endpoints missing here are refused.
+var endpointPermissions = map[string]string{
+	"IssueAPIKeyHandler":  models.PermissionAPIKeysWrite,
+	"ListAPIKeysHandler":  models.PermissionAPIKeysRead,
+	"RevokeAPIKeyHandler": models.PermissionAPIKeysWrite,
+
+	"ListAuditEventsHandler": models.PermissionAuditRead,
+	"MetricsHandler":         models.PermissionMetricsRead,
+
+	"GetBillHandler":           models.PermissionBillsRead,
+	"CreateBillHandler":        models.PermissionBillsCreate,
+	"AddLineItemsHandler":      models.PermissionLineItemsAdd,
+	"RemoveLineItemsHandler":   models.PermissionLineItemsRemove,
+	"GetInvoiceHandler":        models.PermissionBillsRead,
+	"CloseBillHandler":         models.PermissionBillsClose,
+	"RecordPaymentHandler":     models.PermissionPaymentsRecord,
+	"CreditBillHandler":        models.PermissionBillsCredit,
+	"SendInvoiceEmailHandler":  models.PermissionInvoicesSend,
+	"ListInvoiceEmailsHandler": models.PermissionBillsRead,
+	"GetBillJournalHandler":    models.PermissionLedgerRead,
+	"CheckLedgerHandler":       models.PermissionLedgerRead,
+
+	"CreateProductHandler":  models.PermissionCatalogWrite,
+	"GetProductHandler":     models.PermissionCatalogRead,
+	"ListProductsHandler":   models.PermissionCatalogRead,
+	"UpdateProductHandler":  models.PermissionCatalogWrite,
+	"ArchiveProductHandler": models.PermissionCatalogWrite,
+	"CreatePlanHandler":     models.PermissionCatalogWrite,
+	"GetPlanHandler":        models.PermissionCatalogRead,
+	"ListPlansHandler":      models.PermissionCatalogRead,
+	"UpdatePlanHandler":     models.PermissionCatalogWrite,
+	"ArchivePlanHandler":    models.PermissionCatalogWrite,
+	"CreatePriceHandler":    models.PermissionCatalogWrite,
+	"GetPriceHandler":       models.PermissionCatalogRead,
+	"ListPricesHandler":     models.PermissionCatalogRead,
+	"ArchivePriceHandler":   models.PermissionCatalogWrite,
+	"CreateMeterHandler":    models.PermissionCatalogWrite,
+	"GetMeterHandler":       models.PermissionCatalogRead,
+
+	"GetCurrencyHandler":        models.PermissionCurrenciesRead,
+	"CreateCurrencyHandler":     models.PermissionCurrenciesWrite,
+	"ListCurrenciesHandler":     models.PermissionCurrenciesRead,
+	"UpdateCurrencyHandler":     models.PermissionCurrenciesWrite,
+	"ActivateCurrencyHandler":   models.PermissionCurrenciesWrite,
+	"DeactivateCurrencyHandler": models.PermissionCurrenciesWrite,
+	"DeleteCurrencyHandler":     models.PermissionCurrenciesWrite,
+
+	"GetCustomerHandler":            models.PermissionCustomersRead,
+	"CreateCustomerHandler":         models.PermissionCustomersWrite,
+	"ListCustomersHandler":          models.PermissionCustomersRead,
+	"UpdateCustomerHandler":         models.PermissionCustomersWrite,
+	"ArchiveCustomerHandler":        models.PermissionCustomersWrite,
+	"DeleteCustomerHandler":         models.PermissionCustomersDelete,
+	"PurgeCustomerHandler":          models.PermissionCustomersDelete,
+	"GetCustomerStatementHandler":   models.PermissionCustomersRead,
+	"PrintCustomerStatementHandler": models.PermissionCustomersRead,
+	"ListBillSummariesHandler":      models.PermissionBillsRead,
+
+	"CreateExchangeRateHandler": models.PermissionExchangeRatesWrite,
+	"GetExchangeRateHandler":    models.PermissionExchangeRatesRead,
+	"SyncExchangeRatesHandler":  models.PermissionExchangeRatesWrite,
+
+	"GetEmailTemplateHandler":    models.PermissionEmailTemplatesRead,
+	"UpdateEmailTemplateHandler": models.PermissionEmailTemplatesWrite,
+	"ResetEmailTemplateHandler":  models.PermissionEmailTemplatesWrite,
+
+	"GetSubscriptionHandler":        models.PermissionSubscriptionsRead,
+	"CreateSubscriptionHandler":     models.PermissionSubscriptionsWrite,
+	"CancelSubscriptionHandler":     models.PermissionSubscriptionsWrite,
+	"AddSubscriptionItemHandler":    models.PermissionSubscriptionsWrite,
+	"UpdateSubscriptionItemHandler": models.PermissionSubscriptionsWrite,
+	"RemoveSubscriptionItemHandler": models.PermissionSubscriptionsWrite,
+
+	"IngestUsageEventHandler": models.PermissionUsageWrite,
+
+	"RegisterWebhookHandler":       models.PermissionWebhooksWrite,
+	"ListWebhooksHandler":          models.PermissionWebhooksRead,
+	"DisableWebhookHandler":        models.PermissionWebhooksWrite,
+	"ListWebhookDeliveriesHandler": models.PermissionWebhooksRead,
+	"RedeliverWebhookHandler":      models.PermissionWebhooksWrite,
+}
+
+// PermissionDetails names the permission a refused request is missing.
+type PermissionDetails struct {
+	Permission string
+}
+
+func (PermissionDetails) ErrDetails() {}
+
+// PermissionMiddleware refuses api calls whose principal lacks the
+// permission of the endpoint in endpointPermissions.
+//
+//encore:middleware target=all
+func (bs *APIService) PermissionMiddleware(req middleware.Request, next middleware.Next) middleware.Response {
+	data := req.Data()
+	if data.Type != encore.APICall {
+		return next(req)
+	}
+
+	principal, _ := auth.Data().(*models.Principal)
+	if err := authorize(principal, data.Endpoint); err != nil {
+		return middleware.Response{Err: err}
+	}
+
+	return next(req)
+}
+
+// authorize returns the error refusing the call of the endpoint by the
+// principal, nil when it is allowed.
+func authorize(principal *models.Principal, endpoint string) error {
+	permission, ok := endpointPermissions[endpoint]
+	if !ok {
+		slog.Warn("endpoint has no permission", "endpoint", endpoint)
+		return &errs.Error{
+			Code:    errs.PermissionDenied,
+			Message: fmt.Sprintf("endpoint %s is not permitted", endpoint),
+		}
+	}
+
+	if principal == nil || !principal.Can(permission) {
+		return &errs.Error{
+			Code:    errs.PermissionDenied,
+			Message: fmt.Sprintf("missing permission %s", permission),
+			Details: PermissionDetails{Permission: permission},
+		}
+	}
+
+	return nil
+}
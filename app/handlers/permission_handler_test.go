@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/stretchr/testify/suite"
+)
+
+type permissionHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (suite *permissionHandlerTestSuite) Test_EveryEndpointHasAPermission() {
+	files, err := parser.ParseDir(token.NewFileSet(), ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, parser.ParseComments)
+	suite.Require().Nil(err)
+
+	endpoints := 0
+	for _, file := range files["handlers"].Files {
+		for _, decl := range file.Decls {
+			function, ok := decl.(*ast.FuncDecl)
+			if !ok || !isEndpoint(function) {
+				continue
+			}
+			endpoints++
+			_, ok = endpointPermissions[function.Name.Name]
+			suite.True(ok, "endpoint %s has no permission", function.Name.Name)
+		}
+	}
+	suite.Equal(len(endpointPermissions), endpoints)
+}
+
+// isEndpoint reports whether the function is annotated encore:api. The
+// annotation is a directive, so it is missing from the Text of the doc.
+func isEndpoint(function *ast.FuncDecl) bool {
+	if function.Doc == nil {
+		return false
+	}
+	for _, comment := range function.Doc.List {
+		if strings.Contains(comment.Text, "encore:api") {
+			return true
+		}
+	}
+	return false
+}
+
+func (suite *permissionHandlerTestSuite) Test_AuthorizeRefusesMissingPermission() {
+	err := authorize(&models.Principal{APIKeyID: "key id", Role: models.RoleBiller}, "CloseBillHandler")
+
+	suite.Require().NotNil(err)
+	suite.Equal(errs.PermissionDenied, errs.Code(err))
+	suite.Equal(PermissionDetails{Permission: models.PermissionBillsClose}, err.(*errs.Error).Details)
+}
+
+func (suite *permissionHandlerTestSuite) Test_AuthorizeAllowsPermittedRole() {
+	suite.Nil(authorize(&models.Principal{APIKeyID: "key id", Role: models.RoleBiller}, "AddLineItemsHandler"))
+	suite.Nil(authorize(&models.Principal{APIKeyID: "key id", Role: models.RoleFinanceAdmin}, "CloseBillHandler"))
+}
+
+func (suite *permissionHandlerTestSuite) Test_AuthorizeRefusesUnknownEndpoints() {
+	err := authorize(&models.Principal{APIKeyID: "key id", Role: models.RoleFinanceAdmin}, "UnknownHandler")
+
+	suite.Equal(errs.PermissionDenied, errs.Code(err))
+}
+
+func (suite *permissionHandlerTestSuite) Test_AuthorizeRefusesMissingPrincipal() {
+	err := authorize(nil, "GetBillHandler")
+
+	suite.Equal(errs.PermissionDenied, errs.Code(err))
+}
+
+func TestPermissionHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(permissionHandlerTestSuite))
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"encore.dev"
+	"encore.dev/beta/auth"
+	"encore.dev/beta/errs"
+	"encore.dev/middleware"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/ratelimit"
+)
+
+// Classes of endpoints with a rate limit of their own. Every api key has a
+// bucket per class, so a runaway integration flooding one class keeps the
+// others usable.
+const (
+	RateLimitClassDefault = "default"
+	// RateLimitClassWorkflow starts or signals the bill and subscription
+	// workflows.
+	RateLimitClassWorkflow = "workflow"
+	// RateLimitClassUsage ingests usage events, sent in high volume by
+	// design.
+	RateLimitClassUsage = "usage"
+	// RateLimitClassBulk reads or changes many records at once.
+	RateLimitClassBulk = "bulk"
+)
+
+// endpointRateLimitClasses is the class of the endpoints not in
+// RateLimitClassDefault, by endpoint name.
+var endpointRateLimitClasses = map[string]string{
+	"CreateBillHandler":             RateLimitClassWorkflow,
+	"AddLineItemsHandler":           RateLimitClassWorkflow,
+	"RemoveLineItemsHandler":        RateLimitClassWorkflow,
+	"CreateSubscriptionHandler":     RateLimitClassWorkflow,
+	"CancelSubscriptionHandler":     RateLimitClassWorkflow,
+	"AddSubscriptionItemHandler":    RateLimitClassWorkflow,
+	"UpdateSubscriptionItemHandler": RateLimitClassWorkflow,
+	"RemoveSubscriptionItemHandler": RateLimitClassWorkflow,
+	"RedeliverWebhookHandler":       RateLimitClassWorkflow,
+
+	"IngestUsageEventHandler": RateLimitClassUsage,
+
+	"CheckLedgerHandler":            RateLimitClassBulk,
+	"SyncExchangeRatesHandler":      RateLimitClassBulk,
+	"PurgeCustomerHandler":          RateLimitClassBulk,
+	"PrintCustomerStatementHandler": RateLimitClassBulk,
+	"ListAuditEventsHandler":        RateLimitClassBulk,
+}
+
+func endpointRateLimitClass(endpoint string) string {
+	if class, ok := endpointRateLimitClasses[endpoint]; ok {
+		return class
+	}
+	return RateLimitClassDefault
+}
+
+// RateLimitDetails describes the rate limit a refused request exceeded, with
+// the values of the RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset
+// and Retry-After headers, in whole seconds rounded up. Middleware of the
+// Encore release in use can't set response headers, so the values are only
+// given in the details of the error.
+type RateLimitDetails struct {
+	Class      string
+	Limit      int
+	Remaining  int
+	Reset      int
+	RetryAfter int
+}
+
+func (RateLimitDetails) ErrDetails() {}
+
+// RateLimitMiddleware refuses api calls of api keys exceeding the rate limit
+// of the class of the endpoint. Calls are let through when the limiter
+// fails, an outage of the limiter should not be one of the api.
+//
+//encore:middleware target=all
+func (bs *APIService) RateLimitMiddleware(req middleware.Request, next middleware.Next) middleware.Response {
+	data := req.Data()
+	if data.Type != encore.APICall || bs.RateLimiter == nil {
+		return next(req)
+	}
+
+	principal, ok := auth.Data().(*models.Principal)
+	if !ok {
+		return next(req)
+	}
+
+	class := endpointRateLimitClass(data.Endpoint)
+	result, err := bs.RateLimiter.Allow(req.Context(), rateLimitKey(principal, class), bs.RateLimits[class])
+	if err != nil {
+		logging.From(req.Context()).Error("error occurred while rate limiting api key", "endpoint", data.Endpoint, "api_key_id", principal.APIKeyID, "error", err)
+		return next(req)
+	}
+
+	if !result.Allowed {
+		return middleware.Response{Err: rateLimitError(class, result)}
+	}
+
+	return next(req)
+}
+
+func rateLimitKey(principal *models.Principal, class string) string {
+	return fmt.Sprintf("%s:%s", principal.APIKeyID, class)
+}
+
+// rateLimitSeconds rounds d up to whole seconds, so a client waiting them is
+// never refused again for retrying too early.
+func rateLimitSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
+func rateLimitError(class string, result ratelimit.Result) error {
+	retryAfter := rateLimitSeconds(result.RetryAfter)
+	return &errs.Error{
+		Code:    errs.ResourceExhausted,
+		Message: fmt.Sprintf("rate limit of %s requests exceeded, retry after %d seconds", class, retryAfter),
+		Details: RateLimitDetails{
+			Class:      class,
+			Limit:      result.Limit,
+			Remaining:  result.Remaining,
+			Reset:      rateLimitSeconds(result.Reset),
+			RetryAfter: retryAfter,
+		},
+	}
+}
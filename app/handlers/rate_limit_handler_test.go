@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/ratelimit"
+	"github.com/stretchr/testify/suite"
+)
+
+type rateLimitHandlerTestSuite struct {
+	suite.Suite
+}
+
+func (suite *rateLimitHandlerTestSuite) Test_EveryClassedEndpointHasAPermission() {
+	for endpoint := range endpointRateLimitClasses {
+		_, ok := endpointPermissions[endpoint]
+		suite.True(ok, "rate limited endpoint %s is unknown", endpoint)
+	}
+}
+
+func (suite *rateLimitHandlerTestSuite) Test_EndpointRateLimitClass() {
+	suite.Equal(RateLimitClassWorkflow, endpointRateLimitClass("AddLineItemsHandler"))
+	suite.Equal(RateLimitClassUsage, endpointRateLimitClass("IngestUsageEventHandler"))
+	suite.Equal(RateLimitClassDefault, endpointRateLimitClass("GetBillHandler"))
+}
+
+func (suite *rateLimitHandlerTestSuite) Test_RateLimitKeyIsPerAPIKeyAndClass() {
+	principal := &models.Principal{APIKeyID: "key id"}
+
+	suite.Equal("key id:workflow", rateLimitKey(principal, RateLimitClassWorkflow))
+	suite.NotEqual(rateLimitKey(principal, RateLimitClassWorkflow), rateLimitKey(principal, RateLimitClassDefault))
+}
+
+func (suite *rateLimitHandlerTestSuite) Test_RateLimitErrorDescribesTheLimit() {
+	err := rateLimitError(RateLimitClassWorkflow, ratelimit.Result{Limit: 20, Reset: 10 * time.Second, RetryAfter: time.Second})
+
+	suite.Equal(errs.ResourceExhausted, errs.Code(err))
+	suite.Equal(RateLimitDetails{Class: RateLimitClassWorkflow, Limit: 20, Remaining: 0, Reset: 10, RetryAfter: 1}, err.(*errs.Error).Details)
+}
+
+func (suite *rateLimitHandlerTestSuite) Test_RateLimitErrorRoundsSecondsUp() {
+	err := rateLimitError(RateLimitClassUsage, ratelimit.Result{Limit: 100, Reset: 1500 * time.Millisecond, RetryAfter: 400 * time.Millisecond})
+
+	suite.Equal(RateLimitDetails{Class: RateLimitClassUsage, Limit: 100, Remaining: 0, Reset: 2, RetryAfter: 1}, err.(*errs.Error).Details)
+	suite.Contains(err.(*errs.Error).Message, "retry after 1 seconds")
+}
+
+func TestRateLimitHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(rateLimitHandlerTestSuite))
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// encore:api auth method=GET path=/subscriptions/:id
+func (bs *APIService) GetSubscriptionHandler(ctx context.Context, id string) (*models.Subscription, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid subscription id")
+		return &models.Subscription{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid subscription id",
+		}
+	}
+	subscription, err := bs.Subscription.GetByID(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching subscription", "subscription_id", id, "error", err)
+		return &models.Subscription{}, apiError(err, "failed to get subscription")
+	}
+
+	return subscription, nil
+}
+
+// encore:api auth method=POST path=/subscriptions
+func (bs *APIService) CreateSubscriptionHandler(ctx context.Context, request *models.CreateSubscriptionRequest) (*models.Subscription, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid subscription request")
+		return &models.Subscription{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid subscription request",
+		}
+	}
+
+	subscription, err := bs.Subscription.Create(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create subscription", "error", err)
+		return &models.Subscription{}, apiError(err, "failed to create subscription")
+	}
+
+	return subscription, nil
+}
+
+// encore:api auth method=PUT path=/subscriptions/:id/cancel
+func (bs *APIService) CancelSubscriptionHandler(ctx context.Context, id string) (*models.Subscription, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid subscription id")
+		return &models.Subscription{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid subscription id",
+		}
+	}
+	subscription, err := bs.Subscription.Cancel(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while cancelling subscription", "subscription_id", id, "error", err)
+		return &models.Subscription{}, apiError(err, "failed to cancel subscription")
+	}
+
+	return subscription, nil
+}
+
+// encore:api auth method=POST path=/subscriptions/:id/items
+func (bs *APIService) AddSubscriptionItemHandler(ctx context.Context, id string, request *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid subscription item request")
+		return &models.SubscriptionItemChange{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid subscription item request",
+		}
+	}
+
+	change, err := bs.Subscription.AddItem(ctx, id, request)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while changing items of subscription", "subscription_id", id, "error", err)
+		return &models.SubscriptionItemChange{}, apiError(err, "failed to change subscription item")
+	}
+
+	return change, nil
+}
+
+// encore:api auth method=PUT path=/subscriptions/:id/items/:itemID
+func (bs *APIService) UpdateSubscriptionItemHandler(ctx context.Context, id string, itemID string, request *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error) {
+	if id == "" || itemID == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid subscription item request")
+		return &models.SubscriptionItemChange{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid subscription item request",
+		}
+	}
+
+	change, err := bs.Subscription.UpdateItem(ctx, id, itemID, request)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while changing items of subscription", "subscription_id", id, "error", err)
+		return &models.SubscriptionItemChange{}, apiError(err, "failed to change subscription item")
+	}
+
+	return change, nil
+}
+
+// encore:api auth method=PUT path=/subscriptions/:id/items/:itemID/remove
+func (bs *APIService) RemoveSubscriptionItemHandler(ctx context.Context, id string, itemID string) (*models.SubscriptionItemChange, error) {
+	if id == "" || itemID == "" {
+		logging.From(ctx).Warn("invalid subscription item id")
+		return &models.SubscriptionItemChange{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid subscription item id",
+		}
+	}
+
+	change, err := bs.Subscription.RemoveItem(ctx, id, itemID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while changing items of subscription", "subscription_id", id, "error", err)
+		return &models.SubscriptionItemChange{}, apiError(err, "failed to change subscription item")
+	}
+
+	return change, nil
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+)
+
+type subscriptionHandlerTestSuite struct {
+	suite.Suite
+	subscriptionServiceMock *service.SubscriptionServiceMock
+	apiService              *APIService
+}
+
+func (suite *subscriptionHandlerTestSuite) SetupTest() {
+	suite.subscriptionServiceMock = new(service.SubscriptionServiceMock)
+	suite.apiService = &APIService{
+		Subscription: suite.subscriptionServiceMock,
+	}
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_CreateSubscriptionHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.CreateSubscriptionRequest{
+		Description:     "pro plan",
+		CustomerID:      utils.GetNewUUID(),
+		CurrencyCode:    "USD",
+		BillingInterval: models.YearlyInterval,
+		StartDate:       time.Now().UTC(),
+	}
+
+	suite.subscriptionServiceMock.On("Create", ctx, request).Return(&models.Subscription{ID: utils.GetNewUUID()}, nil)
+
+	_, err := suite.apiService.CreateSubscriptionHandler(ctx, request)
+	suite.Nil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_CreateSubscriptionHandlerFailsWhenRequestIsInvalid() {
+	ctx := context.Background()
+	request := &models.CreateSubscriptionRequest{
+		Description:     "pro plan",
+		BillingInterval: models.CustomInterval,
+	}
+
+	_, err := suite.apiService.CreateSubscriptionHandler(ctx, request)
+	suite.NotNil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_CreateSubscriptionHandlerFailsWhenCustomerNotFound() {
+	ctx := context.Background()
+	request := &models.CreateSubscriptionRequest{
+		Description:     "pro plan",
+		CustomerID:      utils.GetNewUUID(),
+		CurrencyCode:    "USD",
+		BillingInterval: models.MonthlyInterval,
+		StartDate:       time.Now().UTC(),
+	}
+
+	suite.subscriptionServiceMock.On("Create", ctx, request).Return(&models.Subscription{}, ce.CustomerNotFoundError)
+
+	_, err := suite.apiService.CreateSubscriptionHandler(ctx, request)
+	suite.NotNil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_GetSubscriptionHandlerFailsWhenNotFound() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.subscriptionServiceMock.On("GetByID", ctx, id).Return(&models.Subscription{}, ce.SubscriptionNotFoundError)
+
+	_, err := suite.apiService.GetSubscriptionHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_CancelSubscriptionHandlerSucceeds() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.subscriptionServiceMock.On("Cancel", ctx, id).Return(&models.Subscription{ID: id, CancelAtPeriodEnd: true}, nil)
+
+	subscription, err := suite.apiService.CancelSubscriptionHandler(ctx, id)
+	suite.Nil(err)
+	suite.True(subscription.CancelAtPeriodEnd)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_CancelSubscriptionHandlerFailsWhenUnknownErrorOccured() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.subscriptionServiceMock.On("Cancel", ctx, id).Return(&models.Subscription{}, errors.New("test error"))
+
+	_, err := suite.apiService.CancelSubscriptionHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_AddSubscriptionItemHandlerSucceeds() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+	request := &models.SubscriptionItemRequest{PriceID: utils.GetNewUUID(), Quantity: 1}
+
+	suite.subscriptionServiceMock.On("AddItem", ctx, id, request).Return(&models.SubscriptionItemChange{}, nil)
+
+	_, err := suite.apiService.AddSubscriptionItemHandler(ctx, id, request)
+	suite.Nil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_AddSubscriptionItemHandlerFailsWhenPriceCurrencyDiffers() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+	request := &models.SubscriptionItemRequest{PriceID: utils.GetNewUUID(), Quantity: 1}
+
+	suite.subscriptionServiceMock.On("AddItem", ctx, id, request).Return(&models.SubscriptionItemChange{}, ce.PriceCurrencyMismatchError)
+
+	_, err := suite.apiService.AddSubscriptionItemHandler(ctx, id, request)
+	suite.NotNil(err)
+}
+
+func (suite *subscriptionHandlerTestSuite) Test_RemoveSubscriptionItemHandlerFailsWhenItemIsRemoved() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+	itemID := utils.GetNewUUID()
+
+	suite.subscriptionServiceMock.On("RemoveItem", ctx, id, itemID).Return(&models.SubscriptionItemChange{}, ce.SubscriptionItemRemovedError)
+
+	_, err := suite.apiService.RemoveSubscriptionItemHandler(ctx, id, itemID)
+	suite.NotNil(err)
+}
+
+func TestSubscriptionHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(subscriptionHandlerTestSuite))
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// encore:api auth method=POST path=/meters
+func (bs *APIService) CreateMeterHandler(ctx context.Context, request *models.CreateMeterRequest) (*models.Meter, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid meter request")
+		return &models.Meter{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid meter request",
+		}
+	}
+
+	meter, err := bs.Usage.CreateMeter(ctx, request.ToMeter())
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create meter", "error", err)
+		return &models.Meter{}, apiError(err, "failed to create meter")
+	}
+
+	return meter, nil
+}
+
+// encore:api auth method=GET path=/meters/:id
+func (bs *APIService) GetMeterHandler(ctx context.Context, id string) (*models.Meter, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid meter id")
+		return &models.Meter{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid meter id",
+		}
+	}
+	meter, err := bs.Usage.GetMeter(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching meter", "meter_id", id, "error", err)
+		return &models.Meter{}, apiError(err, "failed to get meter")
+	}
+
+	return meter, nil
+}
+
+// encore:api auth method=POST path=/usage/events
+func (bs *APIService) IngestUsageEventHandler(ctx context.Context, request *models.UsageEventRequest) (*models.UsageEvent, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid usage event")
+		return &models.UsageEvent{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid usage event",
+		}
+	}
+
+	event, err := bs.Usage.IngestEvent(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to record usage event", "error", err)
+		return &models.UsageEvent{}, apiError(err, "failed to record usage event")
+	}
+
+	return event, nil
+}
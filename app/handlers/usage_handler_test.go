@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type usageHandlerTestSuite struct {
+	suite.Suite
+	usageServiceMock *service.UsageServiceMock
+	apiService       *APIService
+}
+
+func (suite *usageHandlerTestSuite) SetupTest() {
+	suite.usageServiceMock = new(service.UsageServiceMock)
+	suite.apiService = &APIService{
+		Usage: suite.usageServiceMock,
+	}
+}
+
+func (suite *usageHandlerTestSuite) Test_CreateMeterHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.CreateMeterRequest{Code: "api_calls", Name: "API calls", Aggregation: models.SumAggregation, PlanID: utils.GetNewUUID()}
+
+	suite.usageServiceMock.On("CreateMeter", ctx, mock.Anything).Return(&models.Meter{ID: utils.GetNewUUID()}, nil)
+
+	_, err := suite.apiService.CreateMeterHandler(ctx, request)
+	suite.Nil(err)
+}
+
+func (suite *usageHandlerTestSuite) Test_CreateMeterHandlerFailsWhenMeterExists() {
+	ctx := context.Background()
+	request := &models.CreateMeterRequest{Code: "api_calls", Name: "API calls", Aggregation: models.SumAggregation, PlanID: utils.GetNewUUID()}
+
+	suite.usageServiceMock.On("CreateMeter", ctx, mock.Anything).Return(&models.Meter{}, ce.MeterAlreadyExistError)
+
+	_, err := suite.apiService.CreateMeterHandler(ctx, request)
+	suite.NotNil(err)
+}
+
+func (suite *usageHandlerTestSuite) Test_GetMeterHandlerFailsWhenMeterIsNotFound() {
+	ctx := context.Background()
+	id := utils.GetNewUUID()
+
+	suite.usageServiceMock.On("GetMeter", ctx, id).Return(&models.Meter{}, ce.MeterNotFoundError)
+
+	_, err := suite.apiService.GetMeterHandler(ctx, id)
+	suite.NotNil(err)
+}
+
+func (suite *usageHandlerTestSuite) Test_IngestUsageEventHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.UsageEventRequest{
+		IdempotencyID: "event-01",
+		CustomerID:    utils.GetNewUUID(),
+		MeterCode:     "api_calls",
+		Quantity:      1,
+		Timestamp:     time.Now().UTC(),
+	}
+
+	suite.usageServiceMock.On("IngestEvent", ctx, request).Return(&models.UsageEvent{ID: utils.GetNewUUID()}, nil)
+
+	_, err := suite.apiService.IngestUsageEventHandler(ctx, request)
+	suite.Nil(err)
+}
+
+func (suite *usageHandlerTestSuite) Test_IngestUsageEventHandlerFailsWhenRequestIsInvalid() {
+	ctx := context.Background()
+
+	_, err := suite.apiService.IngestUsageEventHandler(ctx, &models.UsageEventRequest{MeterCode: "api_calls"})
+	suite.NotNil(err)
+}
+
+func (suite *usageHandlerTestSuite) Test_IngestUsageEventHandlerFailsWhenUnknownErrorOccured() {
+	ctx := context.Background()
+	request := &models.UsageEventRequest{
+		IdempotencyID: "event-01",
+		CustomerID:    utils.GetNewUUID(),
+		MeterCode:     "api_calls",
+		Quantity:      1,
+		Timestamp:     time.Now().UTC(),
+	}
+
+	suite.usageServiceMock.On("IngestEvent", ctx, request).Return(&models.UsageEvent{}, errors.New("test error"))
+
+	_, err := suite.apiService.IngestUsageEventHandler(ctx, request)
+	suite.NotNil(err)
+}
+
+func TestUsageHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(usageHandlerTestSuite))
+}
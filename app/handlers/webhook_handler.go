@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+
+	"encore.dev/beta/errs"
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// encore:api auth method=POST path=/webhooks
+func (bs *APIService) RegisterWebhookHandler(ctx context.Context, request *models.WebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	if !request.IsValid() {
+		logging.From(ctx).Warn("invalid webhook endpoint request")
+		return &models.WebhookEndpoint{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid webhook endpoint request, URL must be an http or https url and events one of bill.created, line_item.added, line_item.removed, bill.closed and invoice.finalized",
+		}
+	}
+
+	endpoint, err := bs.Webhook.Register(ctx, request)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to register webhook endpoint", "error", err)
+		return &models.WebhookEndpoint{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to register webhook endpoint",
+		}
+	}
+
+	return endpoint, nil
+}
+
+// encore:api auth method=GET path=/webhooks
+func (bs *APIService) ListWebhooksHandler(ctx context.Context) (*models.WebhookEndpoints, error) {
+	endpoints, err := bs.Webhook.List(ctx)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to list webhook endpoints", "error", err)
+		return &models.WebhookEndpoints{}, &errs.Error{
+			Code:    errs.Unknown,
+			Message: "failed to list webhook endpoints",
+		}
+	}
+
+	return endpoints, nil
+}
+
+// encore:api auth method=DELETE path=/webhooks/:id
+func (bs *APIService) DisableWebhookHandler(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid webhook endpoint id")
+		return &models.WebhookEndpoint{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid webhook endpoint id",
+		}
+	}
+
+	endpoint, err := bs.Webhook.Disable(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while disabling webhook endpoint", "endpoint_id", id, "error", err)
+		return &models.WebhookEndpoint{}, apiError(err, "failed to disable webhook endpoint")
+	}
+
+	return endpoint, nil
+}
+
+// encore:api auth method=GET path=/webhooks/:id/deliveries
+func (bs *APIService) ListWebhookDeliveriesHandler(ctx context.Context, id string, request *models.ListWebhookDeliveriesRequest) (*models.WebhookDeliveryList, error) {
+	if id == "" || !request.IsValid() {
+		logging.From(ctx).Warn("invalid list webhook deliveries request")
+		return &models.WebhookDeliveryList{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid list webhook deliveries request",
+		}
+	}
+
+	deliveries, err := bs.Webhook.ListDeliveries(ctx, id, request)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing deliveries of webhook endpoint", "endpoint_id", id, "error", err)
+		return &models.WebhookDeliveryList{}, apiError(err, "failed to list webhook deliveries")
+	}
+
+	return deliveries, nil
+}
+
+// encore:api auth method=POST path=/webhook-deliveries/:id/redeliver
+func (bs *APIService) RedeliverWebhookHandler(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	if id == "" {
+		logging.From(ctx).Warn("invalid webhook delivery id")
+		return &models.WebhookDelivery{}, &errs.Error{
+			Code:    errs.InvalidArgument,
+			Message: "invalid webhook delivery id",
+		}
+	}
+
+	delivery, err := bs.Webhook.Redeliver(ctx, id)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while redelivering webhook delivery", "delivery_id", id, "error", err)
+		return &models.WebhookDelivery{}, apiError(err, "failed to redeliver webhook")
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	service "github.com/asheet-bhaskar/billing-service/app/services"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/stretchr/testify/suite"
+)
+
+type webhookHandlerTestSuite struct {
+	suite.Suite
+	webhookServiceMock *service.WebhookServiceMock
+	apiService         *APIService
+}
+
+func (suite *webhookHandlerTestSuite) SetupTest() {
+	suite.webhookServiceMock = new(service.WebhookServiceMock)
+	suite.apiService = &APIService{
+		Webhook: suite.webhookServiceMock,
+	}
+}
+
+func (suite *webhookHandlerTestSuite) Test_RegisterWebhookHandlerSucceeds() {
+	ctx := context.Background()
+	request := &models.WebhookEndpointRequest{URL: "https://crm.example.com/hooks", Events: []string{models.BillClosed}}
+	endpoint := &models.WebhookEndpoint{ID: "endpoint id", URL: request.URL, Secret: "whsec_secret", Active: true}
+
+	suite.webhookServiceMock.On("Register", ctx, request).Return(endpoint, nil)
+
+	registered, err := suite.apiService.RegisterWebhookHandler(ctx, request)
+	suite.Nil(err)
+	suite.Equal(endpoint, registered)
+}
+
+func (suite *webhookHandlerTestSuite) Test_RegisterWebhookHandlerFailsForUnknownEvent() {
+	request := &models.WebhookEndpointRequest{URL: "https://crm.example.com/hooks", Events: []string{"bill.deleted"}}
+
+	_, err := suite.apiService.RegisterWebhookHandler(context.Background(), request)
+	suite.NotNil(err)
+}
+
+func (suite *webhookHandlerTestSuite) Test_RedeliverWebhookHandlerFailsForDisabledEndpoint() {
+	ctx := context.Background()
+
+	suite.webhookServiceMock.On("Redeliver", ctx, "delivery id").Return(&models.WebhookDelivery{}, ce.WebhookEndpointDisabledError)
+
+	_, err := suite.apiService.RedeliverWebhookHandler(ctx, "delivery id")
+	suite.NotNil(err)
+}
+
+func TestWebhookHandlerTestSuite(t *testing.T) {
+	suite.Run(t, new(webhookHandlerTestSuite))
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"regexp"
+)
+
+var countryCodePattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// Address is a postal address. Country is an ISO 3166-1 alpha-2 code.
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	State      string
+	PostalCode string
+	Country    string
+}
+
+func (a Address) IsEmpty() bool {
+	return a == Address{}
+}
+
+// IsValid tells whether the address is empty or has at least a first line, a
+// city and a country.
+func (a Address) IsValid() bool {
+	if a.IsEmpty() {
+		return true
+	}
+	return a.Line1 != "" && a.City != "" && countryCodePattern.MatchString(a.Country)
+}
+
+func (a Address) Value() (driver.Value, error) {
+	b, err := json.Marshal(a)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (a *Address) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*a = Address{}
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for address")
+	}
+	return json.Unmarshal(b, a)
+}
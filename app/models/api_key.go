@@ -0,0 +1,111 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// APIKeyPrefix starts every api key, so leaked keys are easy to spot.
+	APIKeyPrefix = "bsk_"
+	// apiKeyDisplayLength is the number of leading characters of a key kept
+	// to tell keys apart.
+	apiKeyDisplayLength = 12
+
+	// BootstrapPrincipalID is the principal of the bootstrap api key of the
+	// configuration, used to issue the first keys. It is a RoleFinanceAdmin.
+	BootstrapPrincipalID = "bootstrap"
+)
+
+// tenantIDPattern matches tenant ids, lower case slugs such as acme-eu.
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,35}$`)
+
+// APIKey authenticates api requests. Only the hash of the key is stored, the
+// key itself is returned once when it is issued. Revoked keys are kept so
+// audit events keep naming them.
+type APIKey struct {
+	ID        string
+	TenantID  string
+	Name      string
+	Role      string
+	Prefix    string
+	KeyHash   string `json:"-"`
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
+
+// APIKeyRequest issues a key with the Role for the tenant of the principal
+// issuing it. Only the bootstrap principal may name another TenantID, to set
+// up tenants.
+type APIKeyRequest struct {
+	Name     string
+	Role     string
+	TenantID string
+}
+
+func (r *APIKeyRequest) IsValid() bool {
+	return strings.TrimSpace(r.Name) != "" && len(r.Name) <= 100 && IsRole(r.Role) && (r.TenantID == "" || IsValidTenantID(r.TenantID))
+}
+
+func IsValidTenantID(tenantID string) bool {
+	return tenantIDPattern.MatchString(tenantID)
+}
+
+// IssuedAPIKey is a new api key with the key itself.
+type IssuedAPIKey struct {
+	APIKey *APIKey
+	Key    string
+}
+
+type APIKeys struct {
+	Keys []*APIKey
+}
+
+// NewAPIKey returns the api key of key, a new key with APIKeyPrefix.
+func NewAPIKey(id string, name string, role string, key string, createdAt time.Time) *APIKey {
+	return &APIKey{
+		ID:        id,
+		Name:      name,
+		Role:      role,
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   HashAPIKey(key),
+		CreatedAt: createdAt,
+	}
+}
+
+// HashAPIKey returns the hex SHA-256 of key. Keys are long random strings, so
+// a fast hash does not make them easier to guess.
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Principal is who an api request is made by, resolved from its api key by
+// the auth handler. The request only sees the data of its TenantID, and may
+// only make the changes its Role grants.
+type Principal struct {
+	APIKeyID string
+	Name     string
+	Role     string
+	TenantID string
+}
+
+// Can reports whether the role of the principal grants the permission.
+func (p *Principal) Can(permission string) bool {
+	return HasPermission(p.Role, permission)
+}
+
+// IsBootstrap reports whether the principal is of the bootstrap api key.
+func (p *Principal) IsBootstrap() bool {
+	return p.APIKeyID == BootstrapPrincipalID
+}
+
+// Actor returns how changes made by the principal are recorded in the audit
+// log.
+func (p *Principal) Actor() string {
+	return fmt.Sprintf("api_key:%s", p.APIKeyID)
+}
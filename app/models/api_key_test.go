@@ -0,0 +1,39 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+}
+
+func (suite *APIKeyTestSuite) Test_HashAPIKey() {
+	suite.Equal("b09419204f4691ccc9d98537d0311e2fc893ca16e80125bd0fb0df08ce4fa370", HashAPIKey("bsk_test"))
+}
+
+func (suite *APIKeyTestSuite) Test_NewAPIKeyKeepsPrefixAndHash() {
+	key := "bsk_0123456789abcdef"
+
+	apiKey := NewAPIKey("key id", "ci", RoleViewer, key, time.Now().UTC())
+
+	suite.Equal("bsk_01234567", apiKey.Prefix)
+	suite.Equal(HashAPIKey(key), apiKey.KeyHash)
+	suite.Nil(apiKey.RevokedAt)
+}
+
+func (suite *APIKeyTestSuite) Test_APIKeyRequestIsValid() {
+	suite.True((&APIKeyRequest{Name: "billing cron", Role: RoleBiller}).IsValid())
+	suite.False((&APIKeyRequest{Name: "  ", Role: RoleBiller}).IsValid())
+	suite.False((&APIKeyRequest{Name: "billing cron"}).IsValid())
+	suite.False((&APIKeyRequest{Name: "billing cron", Role: "owner"}).IsValid())
+	suite.True((&APIKeyRequest{Name: "acme admin", Role: RoleFinanceAdmin, TenantID: "acme-eu"}).IsValid())
+	suite.False((&APIKeyRequest{Name: "acme admin", Role: RoleFinanceAdmin, TenantID: "Acme/EU"}).IsValid())
+}
+
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+// Audited entities.
+const (
+	BillEntity     = "bill"
+	LineItemEntity = "line_item"
+	CustomerEntity = "customer"
+	CurrencyEntity = "currency"
+	APIKeyEntity   = "api_key"
+)
+
+// Audited actions, named after the entity they change.
+const (
+	BillCreated         = "bill.created"
+	BillClosed          = "bill.closed"
+	BillPaymentRecorded = "bill.payment_recorded"
+	BillCredited        = "bill.credited"
+	LineItemAdded       = "line_item.added"
+	LineItemRemoved     = "line_item.removed"
+	CustomerCreated     = "customer.created"
+	CustomerUpdated     = "customer.updated"
+	CustomerArchived    = "customer.archived"
+	CustomerDeleted     = "customer.deleted"
+	CustomerPurged      = "customer.purged"
+	CurrencyCreated     = "currency.created"
+	CurrencyUpdated     = "currency.updated"
+	CurrencyActivated   = "currency.activated"
+	CurrencyDeactivated = "currency.deactivated"
+	CurrencyDeleted     = "currency.deleted"
+	APIKeyIssued        = "api_key.issued"
+	APIKeyRevoked       = "api_key.revoked"
+)
+
+var auditedEntities = map[string]bool{
+	BillEntity:     true,
+	LineItemEntity: true,
+	CustomerEntity: true,
+	CurrencyEntity: true,
+	APIKeyEntity:   true,
+}
+
+// AuditEvent records who changed an entity, how and when. Before and After
+// hold the entity as JSON, empty when it did not exist. Audit events are never
+// updated or deleted.
+type AuditEvent struct {
+	ID         string
+	TenantID   string
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	Before     string
+	After      string
+	RequestID  string
+	CreatedAt  time.Time
+}
+
+// ListAuditEventsRequest pages through the audit events of an entity, newest
+// first. EntityID is optional and narrows the events to one entity.
+type ListAuditEventsRequest struct {
+	EntityType string `query:"entity_type"`
+	EntityID   string `query:"entity_id"`
+	Limit      int    `query:"limit"`
+	Offset     int    `query:"offset"`
+}
+
+type AuditEventList struct {
+	Events []*AuditEvent
+	Total  int64
+}
+
+func (r *ListAuditEventsRequest) IsValid() bool {
+	return auditedEntities[r.EntityType] && r.Limit >= 0 && r.Limit <= MaxPageSize && r.Offset >= 0
+}
+
+// PageSize returns the requested limit, or DefaultPageSize when none is given.
+func (r *ListAuditEventsRequest) PageSize() int {
+	if r.Limit == 0 {
+		return DefaultPageSize
+	}
+	return r.Limit
+}
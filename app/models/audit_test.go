@@ -0,0 +1,29 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditTestSuite struct {
+	suite.Suite
+}
+
+func (suite *AuditTestSuite) Test_ListAuditEventsRequestIsValid() {
+	suite.True((&ListAuditEventsRequest{EntityType: LineItemEntity}).IsValid())
+	suite.True((&ListAuditEventsRequest{EntityType: BillEntity, EntityID: "bill id", Limit: MaxPageSize}).IsValid())
+	suite.False((&ListAuditEventsRequest{}).IsValid())
+	suite.False((&ListAuditEventsRequest{EntityType: "subscription"}).IsValid())
+	suite.False((&ListAuditEventsRequest{EntityType: BillEntity, Limit: MaxPageSize + 1}).IsValid())
+	suite.False((&ListAuditEventsRequest{EntityType: BillEntity, Offset: -1}).IsValid())
+}
+
+func (suite *AuditTestSuite) Test_ListAuditEventsRequestPageSize() {
+	suite.Equal(DefaultPageSize, (&ListAuditEventsRequest{}).PageSize())
+	suite.Equal(5, (&ListAuditEventsRequest{Limit: 5}).PageSize())
+}
+
+func TestAuditTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditTestSuite))
+}
@@ -4,61 +4,96 @@ import (
 	"time"
 )
 
+// Bill collects the line items charged to a customer for a period. ClosedAt
+// and DueDate, derived from PaymentTerms, are set when the bill is closed.
 type Bill struct {
-	ID          string
-	Description string
-	CustomerID  string
-	CurrencyID  string
-	Status      string
-	TotalAmount float64
-	PeriodStart time.Time
-	PeriodEnd   time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID           string
+	TenantID     string
+	Description  string
+	CustomerID   string
+	CurrencyID   string
+	Status       string
+	TotalAmount  float64
+	PaymentTerms string
+	ClosedAt     *time.Time
+	DueDate      *time.Time
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
 }
 
 type LineItem struct {
 	ID          string
+	TenantID    string
 	BillID      string
+	PriceID     string
 	Description string
+	Quantity    float64
 	Amount      float64
-	CreatedAt   time.Time
-	Removed     bool
+	// PricingDetails explains how the amount of a catalog priced line item
+	// was derived from its price.
+	PricingDetails string
+	CreatedAt      time.Time
+	Removed        bool
+	RemovedAt      *time.Time
 }
 
+// BillRequest creates a bill. CurrencyCode and PaymentTerms default to those
+// of the customer.
 type BillRequest struct {
 	Description  string
 	CustomerID   string
 	CurrencyCode string
+	PaymentTerms string
 	PeriodStart  time.Time
 	PeriodEnd    time.Time
 }
 
+// AddLineItemrequest adds either a free-form line item with a description and
+// amount, or a catalog line item referencing a price. For the latter the amount
+// is derived from the price and quantity, and the description defaults to the
+// product and plan names.
 type AddLineItemrequest struct {
 	BillID      string
+	PriceID     string
 	Description string
+	Quantity    float64
 	Amount      float64
 }
 
 func (r *BillRequest) IsValid() bool {
-	if (r.Description == "" || r.CustomerID == "" || r.CurrencyCode == "" || r.PeriodStart == time.Time{} ||
+	if (r.Description == "" || r.CustomerID == "" || r.PeriodStart == time.Time{} ||
 		r.PeriodEnd == time.Time{} || r.PeriodStart.After(r.PeriodEnd)) {
 		return false
 	}
-	return true
+	return r.PaymentTerms == "" || isValidPaymentTerms(r.PaymentTerms)
 }
 
 func (r *AddLineItemrequest) IsValid() bool {
-	if r.Description == "" || r.BillID == "" || r.Amount <= float64(0) {
+	if r.BillID == "" || r.Quantity < float64(0) {
+		return false
+	}
+	if r.PriceID != "" {
+		return true
+	}
+	if r.Description == "" || r.Amount <= float64(0) {
 		return false
 	}
 	return true
 }
 
 func (r *AddLineItemrequest) ToLineItem() *LineItem {
+	quantity := r.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
 	return &LineItem{
 		Description: r.Description,
 		Amount:      r.Amount,
 		BillID:      r.BillID,
+		PriceID:     r.PriceID,
+		Quantity:    quantity,
 	}
 }
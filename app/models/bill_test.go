@@ -48,6 +48,58 @@ func (suite *BillTestSuite) Test_IsValidReturnFalseWhenStartPeriodIsAfterEndPeri
 	suite.False(request.IsValid())
 }
 
+func (suite *BillTestSuite) Test_IsValidAllowsCustomerDefaults() {
+	request := *suite.validBillrequest
+	request.CurrencyCode = ""
+	suite.True(request.IsValid())
+
+	request.PaymentTerms = "net_5"
+	suite.False(request.IsValid())
+
+	request.PaymentTerms = Net45
+	suite.True(request.IsValid())
+}
+
+func (suite *BillTestSuite) Test_DueDate() {
+	closedAt := time.Date(2024, time.January, 20, 12, 0, 0, 0, time.UTC)
+
+	suite.Equal(closedAt, DueDate(DueOnReceipt, closedAt))
+	suite.Equal(time.Date(2024, time.February, 4, 12, 0, 0, 0, time.UTC), DueDate(Net15, closedAt))
+	suite.Equal(time.Date(2024, time.February, 19, 12, 0, 0, 0, time.UTC), DueDate(Net30, closedAt))
+}
+
+func (suite *BillTestSuite) Test_AddLineItemRequestIsValidWithPriceID() {
+	request := &AddLineItemrequest{
+		BillID:   "bill id",
+		PriceID:  "price id",
+		Quantity: 3,
+	}
+	suite.True(request.IsValid())
+
+	request.Quantity = -1
+	suite.False(request.IsValid())
+}
+
+func (suite *BillTestSuite) Test_AddLineItemRequestIsValidWithoutPriceID() {
+	request := &AddLineItemrequest{
+		BillID: "bill id",
+		Amount: 10,
+	}
+	suite.False(request.IsValid())
+
+	request.Description = "support"
+	suite.True(request.IsValid())
+}
+
+func (suite *BillTestSuite) Test_ToLineItemDefaultsQuantity() {
+	request := &AddLineItemrequest{
+		BillID:      "bill id",
+		Description: "support",
+		Amount:      10,
+	}
+	suite.Equal(float64(1), request.ToLineItem().Quantity)
+}
+
 func TestBillTestSuite(t *testing.T) {
 	suite.Run(t, new(BillTestSuite))
 }
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+)
+
+type Product struct {
+	ID          string
+	TenantID    string
+	Name        string
+	Description string
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type Plan struct {
+	ID              string
+	TenantID        string
+	ProductID       string
+	Name            string
+	BillingInterval string
+	IntervalDays    int
+	Active          bool
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Price is the amount charged for a plan in one currency. UnitAmount is the
+// per unit rate for per_unit pricing, the fee per block of PackageSize units
+// for package pricing and the fee itself for flat pricing. Graduated and
+// volume prices are charged from Tiers.
+type Price struct {
+	ID           string
+	TenantID     string
+	PlanID       string
+	CurrencyID   string
+	PricingModel string
+	UnitAmount   float64
+	PackageSize  float64
+	Tiers        PriceTiers `gorm:"type:jsonb"`
+	Active       bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type ProductList struct {
+	Products []*Product
+}
+
+type PlanList struct {
+	Plans []*Plan
+}
+
+type PriceList struct {
+	Prices []*Price
+}
+
+type CreateProductRequest struct {
+	Name        string
+	Description string
+}
+
+type UpdateProductRequest struct {
+	Name        string
+	Description string
+}
+
+type ListProductsRequest struct {
+	IncludeArchived bool `query:"include_archived"`
+}
+
+type CreatePlanRequest struct {
+	ProductID       string
+	Name            string
+	BillingInterval string
+	IntervalDays    int
+}
+
+type UpdatePlanRequest struct {
+	Name string
+}
+
+type CreatePriceRequest struct {
+	PlanID       string
+	CurrencyCode string
+	PricingModel string
+	UnitAmount   float64
+	PackageSize  float64
+	Tiers        PriceTiers
+}
+
+func (r *CreateProductRequest) IsValid() bool {
+	return r.Name != ""
+}
+
+func (r *CreateProductRequest) ToProduct() *Product {
+	return &Product{
+		Name:        r.Name,
+		Description: r.Description,
+		Active:      true,
+	}
+}
+
+func (r *UpdateProductRequest) IsValid() bool {
+	return r.Name != ""
+}
+
+func (r *CreatePlanRequest) IsValid() bool {
+	if r.ProductID == "" || r.Name == "" {
+		return false
+	}
+	return isValidInterval(r.BillingInterval, r.IntervalDays)
+}
+
+func (r *CreatePlanRequest) ToPlan() *Plan {
+	return &Plan{
+		ProductID:       r.ProductID,
+		Name:            r.Name,
+		BillingInterval: r.BillingInterval,
+		IntervalDays:    r.IntervalDays,
+		Active:          true,
+	}
+}
+
+func (r *UpdatePlanRequest) IsValid() bool {
+	return r.Name != ""
+}
+
+func (r *CreatePriceRequest) IsValid() bool {
+	if r.PlanID == "" || r.CurrencyCode == "" || r.UnitAmount < float64(0) {
+		return false
+	}
+	return isValidPricing(r.PricingModel, r.UnitAmount, r.PackageSize, r.Tiers)
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type CatalogTestSuite struct {
+	suite.Suite
+}
+
+func (suite *CatalogTestSuite) Test_CreateProductRequestIsValid() {
+	suite.True((&CreateProductRequest{Name: "API"}).IsValid())
+	suite.False((&CreateProductRequest{Description: "API calls"}).IsValid())
+}
+
+func (suite *CatalogTestSuite) Test_ToProductIsActive() {
+	product := (&CreateProductRequest{Name: "API"}).ToProduct()
+	suite.True(product.Active)
+}
+
+func (suite *CatalogTestSuite) Test_CreatePlanRequestIsValid() {
+	request := &CreatePlanRequest{
+		ProductID:       "product id",
+		Name:            "pro",
+		BillingInterval: MonthlyInterval,
+	}
+	suite.True(request.IsValid())
+
+	request.BillingInterval = CustomInterval
+	suite.False(request.IsValid())
+
+	request.IntervalDays = 7
+	suite.True(request.IsValid())
+
+	request.ProductID = ""
+	suite.False(request.IsValid())
+}
+
+func (suite *CatalogTestSuite) Test_CreatePriceRequestIsValid() {
+	request := &CreatePriceRequest{
+		PlanID:       "plan id",
+		CurrencyCode: "USD",
+		UnitAmount:   9.99,
+	}
+	suite.True(request.IsValid())
+
+	request.UnitAmount = -1
+	suite.False(request.IsValid())
+
+	request.UnitAmount = 0
+	request.CurrencyCode = ""
+	suite.False(request.IsValid())
+}
+
+func TestCatalogTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogTestSuite))
+}
@@ -1,16 +1,38 @@
 package models
 
 import (
+	"regexp"
 	"time"
 )
 
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// Currency is a currency bills are charged in. NumericCode and MinorUnits
+// come from the ISO 4217 reference data of its code; MinorUnits is the number
+// of decimals amounts in the currency are rounded to.
 type Currency struct {
-	ID        string
-	Code      string
-	Name      string
-	Symbol    string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID          string
+	TenantID    string
+	Code        string
+	NumericCode string
+	Name        string
+	Symbol      string
+	MinorUnits  int
+	Active      bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CurrencyList struct {
+	Currencies []*Currency
+}
+
+// ISOCurrency is an ISO 4217 currency, seeded by migration.
+type ISOCurrency struct {
+	Code        string
+	NumericCode string
+	MinorUnits  int
+	Name        string
 }
 
 type CreateCurrencyRequest struct {
@@ -19,11 +41,22 @@ type CreateCurrencyRequest struct {
 	Symbol string
 }
 
+// UpdateCurrencyRequest changes the name and symbol of a currency. Empty
+// fields are left unchanged.
+type UpdateCurrencyRequest struct {
+	Name   string
+	Symbol string
+}
+
+type ListCurrenciesRequest struct {
+	IncludeInactive bool `query:"include_inactive"`
+}
+
 func (r *CreateCurrencyRequest) IsValid() bool {
 	if r.Code == "" || r.Name == "" || r.Symbol == "" {
 		return false
 	}
-	return true
+	return currencyCodePattern.MatchString(r.Code)
 }
 
 func (r *CreateCurrencyRequest) ToCurrency() *Currency {
@@ -31,5 +64,25 @@ func (r *CreateCurrencyRequest) ToCurrency() *Currency {
 		Code:   r.Code,
 		Name:   r.Name,
 		Symbol: r.Symbol,
+		Active: true,
+	}
+}
+
+func (r *UpdateCurrencyRequest) IsValid() bool {
+	return r.Name != "" || r.Symbol != ""
+}
+
+// Round rounds amount to the minor units of the currency.
+func (c *Currency) Round(amount float64) float64 {
+	return roundAmount(amount, c.MinorUnits)
+}
+
+// Format renders amount with the currency symbol and minor units, e.g. $9.99.
+func (c *Currency) Format(amount float64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
 	}
+	return sign + c.Symbol + formatAmount(c.Round(amount), c.MinorUnits)
 }
@@ -32,6 +32,36 @@ func (suite *CurrencyTestSuite) Test_IsValidReturnTrue() {
 	suite.True(suite.validCurrency.IsValid())
 }
 
+func (suite *CurrencyTestSuite) Test_IsValidReturnFalseWhenCodeIsNotISOFormat() {
+	request := *suite.validCurrency
+	request.Code = "usd"
+	suite.False(request.IsValid())
+
+	request.Code = "US"
+	suite.False(request.IsValid())
+}
+
+func (suite *CurrencyTestSuite) Test_RoundUsesMinorUnits() {
+	suite.Equal(9.99, (&Currency{MinorUnits: 2}).Round(9.989))
+	suite.Equal(float64(1235), (&Currency{MinorUnits: 0}).Round(1234.5))
+	suite.Equal(1.235, (&Currency{MinorUnits: 3}).Round(1.2345))
+}
+
+func (suite *CurrencyTestSuite) Test_FormatUsesSymbolAndMinorUnits() {
+	suite.Equal("$9.90", (&Currency{Symbol: "$", MinorUnits: 2}).Format(9.9))
+	suite.Equal("¥1235", (&Currency{Symbol: "¥", MinorUnits: 0}).Format(1234.5))
+	suite.Equal("-$5.00", (&Currency{Symbol: "$", MinorUnits: 2}).Format(-5))
+}
+
+func (suite *CurrencyTestSuite) Test_ToCurrencyIsActive() {
+	suite.True(suite.validCurrency.ToCurrency().Active)
+}
+
+func (suite *CurrencyTestSuite) Test_UpdateCurrencyRequestIsValid() {
+	suite.False((&UpdateCurrencyRequest{}).IsValid())
+	suite.True((&UpdateCurrencyRequest{Symbol: "US$"}).IsValid())
+}
+
 func TestCurrencyTestSuite(t *testing.T) {
 	suite.Run(t, new(CurrencyTestSuite))
 }
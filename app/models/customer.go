@@ -1,33 +1,244 @@
 package models
 
-import "time"
+import (
+	"regexp"
+	"time"
+	// customer timezones are validated and applied without relying on the
+	// zoneinfo of the host
+	_ "time/tzdata"
+)
 
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+const (
+	DefaultLocale   = "en-US"
+	DefaultTimezone = "UTC"
+)
+
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Z]{2})?$`)
+
+// Customer is someone bills are charged to. CompanyName, TaxID and the
+// addresses are printed on invoices; Locale is a language tag such as en-US
+// and Timezone an IANA zone the invoice dates are shown in. Bills created
+// without a currency or payment terms use DefaultCurrency, a currency code,
+// and PaymentTerms.
 type Customer struct {
-	ID        string
-	FirstName string
-	LastName  string
-	Email     string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID              string
+	TenantID        string
+	FirstName       string
+	LastName        string
+	Email           string
+	CompanyName     string
+	TaxID           string
+	BillingAddress  Address `gorm:"type:jsonb"`
+	ShippingAddress Address `gorm:"type:jsonb"`
+	Locale          string
+	Timezone        string
+	DefaultCurrency string
+	PaymentTerms    string
+	Active          bool
+	DeletedAt       *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// CustomerList is one page of customers. Total is the number of customers
+// matching the request across all pages.
+type CustomerList struct {
+	Customers []*Customer
+	Total     int64
+}
+
+// Purge records the permanent removal of a customer and the data it owned.
+// Details lists what was removed.
+type Purge struct {
+	ID          string
+	TenantID    string
+	EntityType  string
+	EntityID    string
+	Reason      string
+	RequestedBy string
+	Details     string
+	CreatedAt   time.Time
+}
+
+type PurgeRequest struct {
+	Reason      string
+	RequestedBy string
 }
 
 type CreateCustomerRequest struct {
-	FirstName string
-	LastName  string
-	Email     string
+	FirstName       string
+	LastName        string
+	Email           string
+	CompanyName     string
+	TaxID           string
+	BillingAddress  Address
+	ShippingAddress Address
+	Locale          string
+	Timezone        string
+	DefaultCurrency string
+	PaymentTerms    string
+}
+
+type UpdateCustomerRequest struct {
+	FirstName       string
+	LastName        string
+	Email           string
+	CompanyName     string
+	TaxID           string
+	BillingAddress  Address
+	ShippingAddress Address
+	Locale          string
+	Timezone        string
+	DefaultCurrency string
+	PaymentTerms    string
+}
+
+// ListCustomersRequest pages through customers. Query matches a prefix of the
+// email, first name or last name, ignoring case. Deleted customers are never
+// listed.
+type ListCustomersRequest struct {
+	Query           string `query:"query"`
+	IncludeArchived bool   `query:"include_archived"`
+	Limit           int    `query:"limit"`
+	Offset          int    `query:"offset"`
 }
 
 func (r *CreateCustomerRequest) IsValid() bool {
 	if r.FirstName == "" || r.LastName == "" || r.Email == "" {
 		return false
 	}
-	return true
+	if r.PaymentTerms != "" && !isValidPaymentTerms(r.PaymentTerms) {
+		return false
+	}
+	return isValidProfile(r.BillingAddress, r.ShippingAddress, r.Locale, r.Timezone)
 }
 
 func (r *CreateCustomerRequest) ToCustomer() *Customer {
+	locale := r.Locale
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	timezone := r.Timezone
+	if timezone == "" {
+		timezone = DefaultTimezone
+	}
+	paymentTerms := r.PaymentTerms
+	if paymentTerms == "" {
+		paymentTerms = DueOnReceipt
+	}
+
 	return &Customer{
-		FirstName: r.FirstName,
-		LastName:  r.LastName,
-		Email:     r.Email,
+		FirstName:       r.FirstName,
+		LastName:        r.LastName,
+		Email:           r.Email,
+		CompanyName:     r.CompanyName,
+		TaxID:           r.TaxID,
+		BillingAddress:  r.BillingAddress,
+		ShippingAddress: r.ShippingAddress,
+		Locale:          locale,
+		Timezone:        timezone,
+		DefaultCurrency: r.DefaultCurrency,
+		PaymentTerms:    paymentTerms,
+		Active:          true,
+	}
+}
+
+// IsValid tells whether the request changes anything, empty fields are left
+// unchanged.
+func (r *UpdateCustomerRequest) IsValid() bool {
+	if *r == (UpdateCustomerRequest{}) {
+		return false
+	}
+	if r.PaymentTerms != "" && !isValidPaymentTerms(r.PaymentTerms) {
+		return false
+	}
+	return isValidProfile(r.BillingAddress, r.ShippingAddress, r.Locale, r.Timezone)
+}
+
+// ApplyTo sets the non empty fields of the request on customer. The email and
+// default currency are left to the caller, which has to check them.
+func (r *UpdateCustomerRequest) ApplyTo(customer *Customer) {
+	if r.FirstName != "" {
+		customer.FirstName = r.FirstName
+	}
+	if r.LastName != "" {
+		customer.LastName = r.LastName
+	}
+	if r.CompanyName != "" {
+		customer.CompanyName = r.CompanyName
+	}
+	if r.TaxID != "" {
+		customer.TaxID = r.TaxID
+	}
+	if !r.BillingAddress.IsEmpty() {
+		customer.BillingAddress = r.BillingAddress
+	}
+	if !r.ShippingAddress.IsEmpty() {
+		customer.ShippingAddress = r.ShippingAddress
+	}
+	if r.Locale != "" {
+		customer.Locale = r.Locale
+	}
+	if r.Timezone != "" {
+		customer.Timezone = r.Timezone
+	}
+	if r.PaymentTerms != "" {
+		customer.PaymentTerms = r.PaymentTerms
+	}
+}
+
+func isValidProfile(billingAddress, shippingAddress Address, locale, timezone string) bool {
+	if !billingAddress.IsValid() || !shippingAddress.IsValid() {
+		return false
+	}
+	if locale != "" && !localePattern.MatchString(locale) {
+		return false
+	}
+	if timezone != "" {
+		if _, err := time.LoadLocation(timezone); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *PurgeRequest) IsValid() bool {
+	return r.Reason != "" && r.RequestedBy != ""
+}
+
+func (r *ListCustomersRequest) IsValid() bool {
+	return r.Limit >= 0 && r.Limit <= MaxPageSize && r.Offset >= 0
+}
+
+// PageSize returns the requested limit, or DefaultPageSize when none is given.
+func (r *ListCustomersRequest) PageSize() int {
+	if r.Limit == 0 {
+		return DefaultPageSize
+	}
+	return r.Limit
+}
+
+// IsDeleted tells whether the customer was deleted. Deleted customers are kept
+// for the bills charged to them until they are purged.
+func (c *Customer) IsDeleted() bool {
+	return c.DeletedAt != nil
+}
+
+func (c *Customer) Name() string {
+	return c.FirstName + " " + c.LastName
+}
+
+// Location returns the timezone of the customer, UTC when it is not set or
+// unknown.
+func (c *Customer) Location() *time.Location {
+	location, err := time.LoadLocation(c.Timezone)
+	if err != nil || c.Timezone == "" {
+		return time.UTC
 	}
+	return location
 }
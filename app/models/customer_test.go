@@ -2,6 +2,7 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/suite"
 )
@@ -31,6 +32,69 @@ func (suite *CustomerTestSuite) Test_IsValidReturnTrue() {
 	suite.True(suite.validCustomer.IsValid())
 }
 
+func (suite *CustomerTestSuite) Test_ToCustomerIsActive() {
+	suite.True(suite.validCustomer.ToCustomer().Active)
+}
+
+func (suite *CustomerTestSuite) Test_UpdateCustomerRequestIsValid() {
+	suite.False((&UpdateCustomerRequest{}).IsValid())
+	suite.True((&UpdateCustomerRequest{Email: "john@mail.com"}).IsValid())
+}
+
+func (suite *CustomerTestSuite) Test_ListCustomersRequest() {
+	request := &ListCustomersRequest{}
+	suite.True(request.IsValid())
+	suite.Equal(DefaultPageSize, request.PageSize())
+
+	request.Limit = MaxPageSize + 1
+	suite.False(request.IsValid())
+
+	request.Limit = 5
+	suite.True(request.IsValid())
+	suite.Equal(5, request.PageSize())
+
+	request.Offset = -1
+	suite.False(request.IsValid())
+}
+
+func (suite *CustomerTestSuite) Test_ToCustomerDefaultsLocaleAndTimezone() {
+	customer := suite.validCustomer.ToCustomer()
+
+	suite.Equal(DefaultLocale, customer.Locale)
+	suite.Equal(DefaultTimezone, customer.Timezone)
+	suite.Equal(time.UTC, customer.Location())
+}
+
+func (suite *CustomerTestSuite) Test_IsValidChecksBillingProfile() {
+	request := *suite.validCustomer
+	request.Locale = "english"
+	suite.False(request.IsValid())
+
+	request.Locale = "en-GB"
+	request.Timezone = "Mars/Olympus"
+	suite.False(request.IsValid())
+
+	request.Timezone = "Europe/London"
+	request.BillingAddress = Address{Line1: "10 Downing Street", City: "London"}
+	suite.False(request.IsValid())
+
+	request.BillingAddress.Country = "GB"
+	suite.True(request.IsValid())
+}
+
+func (suite *CustomerTestSuite) Test_UpdateCustomerRequestApplyTo() {
+	customer := suite.validCustomer.ToCustomer()
+	request := &UpdateCustomerRequest{TaxID: "GB123456789", Timezone: "Europe/London"}
+
+	suite.True(request.IsValid())
+	request.ApplyTo(customer)
+
+	suite.Equal("GB123456789", customer.TaxID)
+	suite.Equal("Europe/London", customer.Timezone)
+	suite.Equal("John", customer.FirstName)
+	suite.Equal(DefaultLocale, customer.Locale)
+}
+
 func TestCustomerTestSuite(t *testing.T) {
 	suite.Run(t, new(CustomerTestSuite))
 }
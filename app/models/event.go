@@ -0,0 +1,77 @@
+package models
+
+import "time"
+
+// Domain events are published after the change is committed, and named after
+// the audited actions. Delivery is at least once and unordered, so subscribers
+// deduplicate by ID and order by OccurredAt, in the tenant of the entity of
+// the event.
+
+// BillEvent is published when a bill is created, closed, paid or credited.
+type BillEvent struct {
+	ID         string
+	Type       string
+	Bill       *Bill
+	OccurredAt time.Time
+}
+
+// LineItemEvent is published when a line item is added to or removed from a
+// bill.
+type LineItemEvent struct {
+	ID         string
+	Type       string
+	BillID     string
+	LineItem   *LineItem
+	OccurredAt time.Time
+}
+
+// CustomerEvent is published when a customer is created, updated, archived,
+// deleted or purged. Customer is nil for purged customers.
+type CustomerEvent struct {
+	ID         string
+	Type       string
+	CustomerID string
+	Customer   *Customer
+	OccurredAt time.Time
+}
+
+// CurrencyEvent is published when a currency is created, updated, activated,
+// deactivated or deleted.
+type CurrencyEvent struct {
+	ID         string
+	Type       string
+	Currency   *Currency
+	OccurredAt time.Time
+}
+
+// BillSummary is a projection of the bill events of a bill, kept as of the
+// latest event. TotalAmount is final once the bill is closed.
+type BillSummary struct {
+	BillID      string
+	TenantID    string
+	CustomerID  string
+	CurrencyID  string
+	Status      string
+	TotalAmount float64
+	DueDate     *time.Time
+	LastEventID string
+	LastEventAt time.Time
+}
+
+type BillSummaries struct {
+	Summaries []*BillSummary
+}
+
+// BillSummary returns the summary of the bill as of the event.
+func (e *BillEvent) BillSummary() *BillSummary {
+	return &BillSummary{
+		BillID:      e.Bill.ID,
+		CustomerID:  e.Bill.CustomerID,
+		CurrencyID:  e.Bill.CurrencyID,
+		Status:      e.Bill.Status,
+		TotalAmount: e.Bill.TotalAmount,
+		DueDate:     e.Bill.DueDate,
+		LastEventID: e.ID,
+		LastEventAt: e.OccurredAt,
+	}
+}
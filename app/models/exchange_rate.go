@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+)
+
+// ExchangeRate is the amount of QuoteCurrency one unit of BaseCurrency buys,
+// effective from EffectiveDate until a later rate for the same pair.
+type ExchangeRate struct {
+	ID            string
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+	EffectiveDate time.Time
+	CreatedAt     time.Time
+}
+
+type ExchangeRateList struct {
+	Rates []*ExchangeRate
+}
+
+type CreateExchangeRateRequest struct {
+	BaseCurrency  string
+	QuoteCurrency string
+	Rate          float64
+	EffectiveDate time.Time
+}
+
+type GetExchangeRateRequest struct {
+	Base  string    `query:"base"`
+	Quote string    `query:"quote"`
+	Date  time.Time `query:"date"`
+}
+
+func (r *CreateExchangeRateRequest) IsValid() bool {
+	if r.BaseCurrency == "" || r.QuoteCurrency == "" || r.BaseCurrency == r.QuoteCurrency ||
+		r.Rate <= float64(0) || r.EffectiveDate.IsZero() {
+		return false
+	}
+	return true
+}
+
+func (r *CreateExchangeRateRequest) ToExchangeRate() *ExchangeRate {
+	return &ExchangeRate{
+		BaseCurrency:  r.BaseCurrency,
+		QuoteCurrency: r.QuoteCurrency,
+		Rate:          r.Rate,
+		EffectiveDate: r.EffectiveDate.UTC(),
+	}
+}
+
+func (r *GetExchangeRateRequest) IsValid() bool {
+	return r.Base != "" && r.Quote != ""
+}
+
+// Inverse returns the rate of the opposite direction of the currency pair.
+func (e *ExchangeRate) Inverse() *ExchangeRate {
+	return &ExchangeRate{
+		ID:            e.ID,
+		BaseCurrency:  e.QuoteCurrency,
+		QuoteCurrency: e.BaseCurrency,
+		Rate:          1 / e.Rate,
+		EffectiveDate: e.EffectiveDate,
+		CreatedAt:     e.CreatedAt,
+	}
+}
+
+// Convert converts amount into the quote currency, rounded to minorUnits
+// decimals, the minor units of the quote currency.
+func (e *ExchangeRate) Convert(amount float64, minorUnits int) float64 {
+	return roundAmount(amount*e.Rate, minorUnits)
+}
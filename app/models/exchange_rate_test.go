@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ExchangeRateTestSuite struct {
+	suite.Suite
+	validRequest *CreateExchangeRateRequest
+}
+
+func (suite *ExchangeRateTestSuite) SetupTest() {
+	suite.validRequest = &CreateExchangeRateRequest{
+		BaseCurrency:  "USD",
+		QuoteCurrency: "EUR",
+		Rate:          0.9,
+		EffectiveDate: time.Now().UTC(),
+	}
+}
+
+func (suite *ExchangeRateTestSuite) Test_IsValidReturnTrue() {
+	suite.True(suite.validRequest.IsValid())
+}
+
+func (suite *ExchangeRateTestSuite) Test_IsValidReturnFalse() {
+	request := *suite.validRequest
+	request.Rate = 0
+	suite.False(request.IsValid())
+
+	request = *suite.validRequest
+	request.QuoteCurrency = "USD"
+	suite.False(request.IsValid())
+
+	request = *suite.validRequest
+	request.EffectiveDate = time.Time{}
+	suite.False(request.IsValid())
+}
+
+func (suite *ExchangeRateTestSuite) Test_InverseAndConvert() {
+	rate := &ExchangeRate{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.25}
+
+	inverse := rate.Inverse()
+	suite.Equal("USD", inverse.BaseCurrency)
+	suite.Equal("EUR", inverse.QuoteCurrency)
+	suite.Equal(0.8, inverse.Rate)
+	suite.Equal(8.33, inverse.Convert(10.41, 2))
+	suite.Equal(float64(8), inverse.Convert(10.41, 0))
+}
+
+func TestExchangeRateTestSuite(t *testing.T) {
+	suite.Run(t, new(ExchangeRateTestSuite))
+}
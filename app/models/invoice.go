@@ -3,18 +3,69 @@ package models
 import "time"
 
 type Invoice struct {
-	BillID      string
-	Description string
-	CustomerID  string
-	CurrencyID  string
-	Status      string
-	TotalAmount float64
+	BillID       string
+	Description  string
+	CustomerID   string
+	Customer     InvoiceCustomer
+	CurrencyID   string
+	CurrencyCode string
+	Status       string
+	TotalAmount  float64
+	// FormattedTotalAmount is the total with the currency symbol and minor
+	// units, e.g. $9.99.
+	FormattedTotalAmount string
+	PaymentTerms         string
+	// PeriodStart, PeriodEnd and DueDate are in the timezone of the customer.
+	// DueDate is set once the bill is closed.
 	PeriodStart time.Time
 	PeriodEnd   time.Time
+	DueDate     *time.Time
 	LineItems   []LineItem
+	// Conversion is set when the invoice is presented in a currency other
+	// than the bill currency.
+	Conversion *InvoiceConversion
 }
 
-func CreateInvoice(bill *Bill, lineItems []*LineItem, currencyCode string) *Invoice {
+// InvoiceCustomer is the billing profile of the customer as printed on the
+// invoice.
+type InvoiceCustomer struct {
+	Name            string
+	CompanyName     string
+	Email           string
+	TaxID           string
+	BillingAddress  Address
+	ShippingAddress Address
+	Locale          string
+	Timezone        string
+}
+
+func newInvoiceCustomer(customer *Customer) InvoiceCustomer {
+	return InvoiceCustomer{
+		Name:            customer.Name(),
+		CompanyName:     customer.CompanyName,
+		Email:           customer.Email,
+		TaxID:           customer.TaxID,
+		BillingAddress:  customer.BillingAddress,
+		ShippingAddress: customer.ShippingAddress,
+		Locale:          customer.Locale,
+		Timezone:        customer.Location().String(),
+	}
+}
+
+// InvoiceConversion states the rate the amounts of an invoice were converted
+// with from the bill currency.
+type InvoiceConversion struct {
+	BillCurrency    string
+	BillTotalAmount float64
+	Rate            float64
+	RateDate        time.Time
+}
+
+type InvoiceRequest struct {
+	Currency string `query:"currency"`
+}
+
+func CreateInvoice(bill *Bill, lineItems []*LineItem, currency *Currency, customer *Customer) *Invoice {
 	dereferencedLineItems := []LineItem{}
 	for _, item := range lineItems {
 		if !item.Removed {
@@ -22,15 +73,53 @@ func CreateInvoice(bill *Bill, lineItems []*LineItem, currencyCode string) *Invo
 		}
 	}
 
+	var dueDate *time.Time
+	if bill.DueDate != nil {
+		localDueDate := bill.DueDate.In(customer.Location())
+		dueDate = &localDueDate
+	}
+
 	return &Invoice{
-		BillID:      bill.ID,
-		Description: bill.Description,
-		CustomerID:  bill.CustomerID,
-		CurrencyID:  bill.CurrencyID,
-		Status:      bill.Status,
-		TotalAmount: bill.TotalAmount,
-		PeriodStart: bill.PeriodStart,
-		PeriodEnd:   bill.PeriodEnd,
-		LineItems:   dereferencedLineItems,
+		BillID:               bill.ID,
+		Description:          bill.Description,
+		CustomerID:           bill.CustomerID,
+		Customer:             newInvoiceCustomer(customer),
+		CurrencyID:           bill.CurrencyID,
+		CurrencyCode:         currency.Code,
+		Status:               bill.Status,
+		TotalAmount:          bill.TotalAmount,
+		FormattedTotalAmount: currency.Format(bill.TotalAmount),
+		PeriodStart:          bill.PeriodStart.In(customer.Location()),
+		PeriodEnd:            bill.PeriodEnd.In(customer.Location()),
+		PaymentTerms:         bill.PaymentTerms,
+		DueDate:              dueDate,
+		LineItems:            dereferencedLineItems,
+	}
+}
+
+// ConvertInvoice presents the invoice in currency, the quote currency of rate.
+// Line items are rounded to the minor units of currency and the total is their
+// sum, so it may differ by rounding from the converted bill total.
+func ConvertInvoice(invoice *Invoice, rate *ExchangeRate, currency *Currency) *Invoice {
+	converted := *invoice
+	converted.CurrencyID = currency.ID
+	converted.CurrencyCode = currency.Code
+	converted.Conversion = &InvoiceConversion{
+		BillCurrency:    invoice.CurrencyCode,
+		BillTotalAmount: invoice.TotalAmount,
+		Rate:            rate.Rate,
+		RateDate:        rate.EffectiveDate,
+	}
+
+	total := float64(0)
+	converted.LineItems = []LineItem{}
+	for _, item := range invoice.LineItems {
+		item.Amount = currency.Round(item.Amount * rate.Rate)
+		total += item.Amount
+		converted.LineItems = append(converted.LineItems, item)
 	}
+	converted.TotalAmount = currency.Round(total)
+	converted.FormattedTotalAmount = currency.Format(converted.TotalAmount)
+
+	return &converted
 }
@@ -0,0 +1,222 @@
+package models
+
+import (
+	"math"
+	"time"
+)
+
+// Ledger accounts. Receivable and unbilled accounts are kept per customer,
+// cash and revenue once for the whole ledger.
+const (
+	CashAccount       = "cash"
+	RevenueAccount    = "revenue"
+	ReceivableAccount = "receivable"
+	UnbilledAccount   = "unbilled"
+)
+
+const (
+	AssetAccountType  = "asset"
+	IncomeAccountType = "income"
+)
+
+// Kinds of journal entries, one for every change to a balance.
+const (
+	JournalLineItemAdded   = "line_item_added"
+	JournalLineItemRemoved = "line_item_removed"
+	JournalBillClosed      = "bill_closed"
+	JournalPayment         = "payment"
+	JournalCredit          = "credit"
+)
+
+// journalAccounts are the accounts debited and credited by each kind of
+// journal entry. Line items accrue revenue on the unbilled account of the
+// customer until the bill closes and moves it to the receivable account,
+// which payments and credits then settle.
+var journalAccounts = map[string][2]string{
+	JournalLineItemAdded:   {UnbilledAccount, RevenueAccount},
+	JournalLineItemRemoved: {RevenueAccount, UnbilledAccount},
+	JournalBillClosed:      {ReceivableAccount, UnbilledAccount},
+	JournalPayment:         {CashAccount, ReceivableAccount},
+	JournalCredit:          {RevenueAccount, ReceivableAccount},
+}
+
+// balanceTolerance absorbs the float error of summing rounded amounts.
+const balanceTolerance = 1e-6
+
+type LedgerAccount struct {
+	ID         string
+	TenantID   string
+	Code       string
+	Type       string
+	CustomerID string
+	CreatedAt  time.Time
+}
+
+// JournalEntry records one change to the ledger as postings whose debits and
+// credits balance in every currency. Entries are unique by kind and reference,
+// so posting the same change twice records it once.
+type JournalEntry struct {
+	ID          string
+	TenantID    string
+	Kind        string
+	ReferenceID string
+	BillID      string
+	CustomerID  string
+	Description string
+	Postings    []*Posting
+	CreatedAt   time.Time
+}
+
+// Posting debits or credits one account. Exactly one of Debit and Credit is
+// positive.
+type Posting struct {
+	ID             string
+	TenantID       string
+	JournalEntryID string
+	AccountID      string
+	CurrencyCode   string
+	Debit          float64
+	Credit         float64
+}
+
+// LedgerTransfer is a change to post to the ledger. A negative amount, such as
+// a credit line item, moves money the other way.
+type LedgerTransfer struct {
+	Kind         string
+	ReferenceID  string
+	BillID       string
+	CustomerID   string
+	Description  string
+	CurrencyCode string
+	Amount       float64
+}
+
+// LedgerImbalance is a journal entry whose postings do not balance in a
+// currency.
+type LedgerImbalance struct {
+	JournalEntryID string
+	CurrencyCode   string
+	Debit          float64
+	Credit         float64
+}
+
+type LedgerCheck struct {
+	Balanced   bool
+	Imbalances []*LedgerImbalance
+}
+
+type JournalEntries struct {
+	Entries []*JournalEntry
+}
+
+// PaymentRequest records a payment against a closed bill. Reference
+// identifies the payment, such as a bank transaction id, and is recorded at
+// most once.
+type PaymentRequest struct {
+	Amount    float64
+	Reference string
+}
+
+// CreditRequest credits part or all of what is owed on a closed bill.
+type CreditRequest struct {
+	Amount float64
+	Reason string
+}
+
+func (r *PaymentRequest) IsValid() bool {
+	return r.Amount > 0
+}
+
+func (r *CreditRequest) IsValid() bool {
+	return r.Amount > 0 && r.Reason != ""
+}
+
+// JournalAccounts returns the codes of the accounts debited and credited by
+// journal entries of the kind.
+func JournalAccounts(kind string) (string, string, bool) {
+	accounts, ok := journalAccounts[kind]
+	return accounts[0], accounts[1], ok
+}
+
+// AccountType returns the type of the account with the code.
+func AccountType(code string) string {
+	if code == RevenueAccount {
+		return IncomeAccountType
+	}
+	return AssetAccountType
+}
+
+// IsCustomerAccount reports whether the account with the code is kept per
+// customer.
+func IsCustomerAccount(code string) bool {
+	return code == ReceivableAccount || code == UnbilledAccount
+}
+
+// JournalEntry returns the entry debiting the amount of the transfer to debit
+// and crediting it to credit.
+func (t *LedgerTransfer) JournalEntry(debit, credit *LedgerAccount) *JournalEntry {
+	amount := t.Amount
+	if amount < 0 {
+		debit, credit, amount = credit, debit, -amount
+	}
+
+	return &JournalEntry{
+		Kind:        t.Kind,
+		ReferenceID: t.ReferenceID,
+		BillID:      t.BillID,
+		CustomerID:  t.CustomerID,
+		Description: t.Description,
+		Postings: []*Posting{
+			{AccountID: debit.ID, CurrencyCode: t.CurrencyCode, Debit: amount},
+			{AccountID: credit.ID, CurrencyCode: t.CurrencyCode, Credit: amount},
+		},
+	}
+}
+
+// IsBalanced reports whether the entry has postings, each posting is either a
+// debit or a credit, and debits equal credits in every currency.
+func (e *JournalEntry) IsBalanced() bool {
+	if len(e.Postings) == 0 {
+		return false
+	}
+
+	balances := map[string]float64{}
+	for _, posting := range e.Postings {
+		if posting.Debit < 0 || posting.Credit < 0 || (posting.Debit > 0) == (posting.Credit > 0) {
+			return false
+		}
+		balances[posting.CurrencyCode] += posting.Debit - posting.Credit
+	}
+
+	for _, balance := range balances {
+		if math.Abs(balance) > balanceTolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// Amount returns the total debited by the entry.
+func (e *JournalEntry) Amount() float64 {
+	amount := 0.0
+	for _, posting := range e.Postings {
+		amount += posting.Debit
+	}
+	return amount
+}
+
+// SameChange reports whether other records the same change as the entry, the
+// same amount in the same currency for the same bill.
+func (e *JournalEntry) SameChange(other *JournalEntry) bool {
+	return e.BillID == other.BillID && e.CurrencyCode() == other.CurrencyCode() &&
+		math.Abs(e.Amount()-other.Amount()) <= balanceTolerance
+}
+
+// CurrencyCode returns the currency of the entry. Entries posted by the
+// service are in a single currency.
+func (e *JournalEntry) CurrencyCode() string {
+	if len(e.Postings) == 0 {
+		return ""
+	}
+	return e.Postings[0].CurrencyCode
+}
@@ -0,0 +1,96 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type LedgerTestSuite struct {
+	suite.Suite
+	unbilled *LedgerAccount
+	revenue  *LedgerAccount
+}
+
+func (suite *LedgerTestSuite) SetupTest() {
+	suite.unbilled = &LedgerAccount{ID: "unbilled id", Code: UnbilledAccount, Type: AssetAccountType, CustomerID: "customer id"}
+	suite.revenue = &LedgerAccount{ID: "revenue id", Code: RevenueAccount, Type: IncomeAccountType}
+}
+
+func (suite *LedgerTestSuite) Test_JournalEntryDebitsAndCreditsTheAmount() {
+	transfer := &LedgerTransfer{Kind: JournalLineItemAdded, ReferenceID: "item id", CurrencyCode: "USD", Amount: 12.5}
+
+	entry := transfer.JournalEntry(suite.unbilled, suite.revenue)
+
+	suite.Require().Len(entry.Postings, 2)
+	suite.Equal(&Posting{AccountID: "unbilled id", CurrencyCode: "USD", Debit: 12.5}, entry.Postings[0])
+	suite.Equal(&Posting{AccountID: "revenue id", CurrencyCode: "USD", Credit: 12.5}, entry.Postings[1])
+	suite.True(entry.IsBalanced())
+	suite.Equal(12.5, entry.Amount())
+	suite.Equal("USD", entry.CurrencyCode())
+}
+
+func (suite *LedgerTestSuite) Test_JournalEntryOfNegativeAmountMovesTheOtherWay() {
+	transfer := &LedgerTransfer{Kind: JournalLineItemAdded, CurrencyCode: "USD", Amount: -3}
+
+	entry := transfer.JournalEntry(suite.unbilled, suite.revenue)
+
+	suite.Equal(&Posting{AccountID: "revenue id", CurrencyCode: "USD", Debit: 3}, entry.Postings[0])
+	suite.Equal(&Posting{AccountID: "unbilled id", CurrencyCode: "USD", Credit: 3}, entry.Postings[1])
+	suite.True(entry.IsBalanced())
+}
+
+func (suite *LedgerTestSuite) Test_SameChangeComparesBillAndAmount() {
+	transfer := &LedgerTransfer{Kind: JournalPayment, ReferenceID: "tx 01", BillID: "bill id", CurrencyCode: "USD", Amount: 12.5}
+	entry := transfer.JournalEntry(suite.unbilled, suite.revenue)
+
+	suite.True(entry.SameChange(transfer.JournalEntry(suite.unbilled, suite.revenue)))
+
+	transfer.Amount = 10
+	suite.False(entry.SameChange(transfer.JournalEntry(suite.unbilled, suite.revenue)))
+
+	transfer.Amount = 12.5
+	transfer.BillID = "other bill id"
+	suite.False(entry.SameChange(transfer.JournalEntry(suite.unbilled, suite.revenue)))
+}
+
+func (suite *LedgerTestSuite) Test_IsBalancedPerCurrency() {
+	suite.False((&JournalEntry{}).IsBalanced())
+
+	unbalanced := &JournalEntry{Postings: []*Posting{{CurrencyCode: "USD", Debit: 10}, {CurrencyCode: "USD", Credit: 9.99}}}
+	suite.False(unbalanced.IsBalanced())
+
+	crossCurrency := &JournalEntry{Postings: []*Posting{{CurrencyCode: "USD", Debit: 10}, {CurrencyCode: "EUR", Credit: 10}}}
+	suite.False(crossCurrency.IsBalanced())
+
+	bothSides := &JournalEntry{Postings: []*Posting{{CurrencyCode: "USD", Debit: 10, Credit: 10}}}
+	suite.False(bothSides.IsBalanced())
+
+	multiCurrency := &JournalEntry{Postings: []*Posting{
+		{CurrencyCode: "USD", Debit: 0.1}, {CurrencyCode: "USD", Debit: 0.2}, {CurrencyCode: "USD", Credit: 0.3},
+		{CurrencyCode: "EUR", Debit: 5}, {CurrencyCode: "EUR", Credit: 5},
+	}}
+	suite.True(multiCurrency.IsBalanced())
+}
+
+func (suite *LedgerTestSuite) Test_JournalAccounts() {
+	debit, credit, ok := JournalAccounts(JournalBillClosed)
+	suite.True(ok)
+	suite.Equal(ReceivableAccount, debit)
+	suite.Equal(UnbilledAccount, credit)
+
+	_, _, ok = JournalAccounts("refund")
+	suite.False(ok)
+}
+
+func (suite *LedgerTestSuite) Test_SettlementRequestsAreValid() {
+	suite.True((&PaymentRequest{Amount: 1}).IsValid())
+	suite.False((&PaymentRequest{Amount: 0}).IsValid())
+	suite.True((&CreditRequest{Amount: 1, Reason: "goodwill"}).IsValid())
+	suite.False((&CreditRequest{Amount: 1}).IsValid())
+	suite.False((&CreditRequest{Amount: -1, Reason: "goodwill"}).IsValid())
+}
+
+func TestLedgerTestSuite(t *testing.T) {
+	suite.Run(t, new(LedgerTestSuite))
+}
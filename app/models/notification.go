@@ -0,0 +1,196 @@
+package models
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+const (
+	InvoiceEmailTemplate = "invoice"
+
+	InvoiceEmailSent   = "sent"
+	InvoiceEmailFailed = "failed"
+)
+
+var EmailTemplateNames = []string{InvoiceEmailTemplate}
+
+// EmailTemplate is the subject and the plain text and HTML bodies of an email
+// in one locale, as Go templates. The HTML body is escaped for HTML.
+// Templates saved for a locale override the default ones of the service.
+type EmailTemplate struct {
+	Name      string
+	TenantID  string
+	Locale    string
+	Subject   string
+	Text      string
+	HTML      string
+	UpdatedAt time.Time
+}
+
+type EmailTemplateRequest struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func (r *EmailTemplateRequest) IsValid() bool {
+	return r.Subject != "" && r.Text != "" && r.HTML != ""
+}
+
+// RenderedEmail is an email template executed for one recipient.
+type RenderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func IsEmailTemplateName(name string) bool {
+	for _, templateName := range EmailTemplateNames {
+		if name == templateName {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// EmailTemplateLocales returns the locales templates are looked up in for
+// locale, most specific first: the locale, its language and the language of
+// DefaultLocale.
+func EmailTemplateLocales(locale string) []string {
+	locales := []string{}
+	for _, candidate := range []string{locale, language(locale), language(DefaultLocale)} {
+		if candidate == "" {
+			continue
+		}
+		seen := false
+		for _, l := range locales {
+			seen = seen || l == candidate
+		}
+		if !seen {
+			locales = append(locales, candidate)
+		}
+	}
+	return locales
+}
+
+func language(locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+	return language
+}
+
+// Parse checks the subject and bodies of the template are valid templates.
+func (t *EmailTemplate) Parse() error {
+	_, err := t.parse()
+	return err
+}
+
+// Render executes the template with data. Line breaks in the subject are
+// folded into spaces, so they never end up in the mail headers.
+func (t *EmailTemplate) Render(data any) (*RenderedEmail, error) {
+	parsed, err := t.parse()
+	if err != nil {
+		return &RenderedEmail{}, err
+	}
+
+	subject := &bytes.Buffer{}
+	if err = parsed.subject.Execute(subject, data); err != nil {
+		return &RenderedEmail{}, err
+	}
+
+	text := &bytes.Buffer{}
+	if err = parsed.text.Execute(text, data); err != nil {
+		return &RenderedEmail{}, err
+	}
+
+	html := &bytes.Buffer{}
+	if err = parsed.html.Execute(html, data); err != nil {
+		return &RenderedEmail{}, err
+	}
+
+	return &RenderedEmail{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+type parsedEmailTemplate struct {
+	subject *texttemplate.Template
+	text    *texttemplate.Template
+	html    *htmltemplate.Template
+}
+
+func (t *EmailTemplate) parse() (*parsedEmailTemplate, error) {
+	subject, err := texttemplate.New("subject").Option("missingkey=error").Parse(t.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	text, err := texttemplate.New("text").Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := htmltemplate.New("html").Option("missingkey=error").Parse(t.HTML)
+	if err != nil {
+		return nil, err
+	}
+
+	return &parsedEmailTemplate{subject: subject, text: text, html: html}, nil
+}
+
+// InvoiceEmail is the data invoice email templates are executed with. Amounts
+// are formatted in the currency of the invoice.
+type InvoiceEmail struct {
+	Invoice   *Invoice
+	Total     string
+	LineItems []InvoiceEmailLineItem
+}
+
+type InvoiceEmailLineItem struct {
+	Description string
+	Quantity    string
+	Amount      string
+}
+
+func NewInvoiceEmail(invoice *Invoice, currency *Currency) *InvoiceEmail {
+	lineItems := []InvoiceEmailLineItem{}
+	for _, item := range invoice.LineItems {
+		lineItems = append(lineItems, InvoiceEmailLineItem{
+			Description: item.Description,
+			Quantity:    strconv.FormatFloat(item.Quantity, 'f', -1, 64),
+			Amount:      currency.Format(item.Amount),
+		})
+	}
+
+	return &InvoiceEmail{
+		Invoice:   invoice,
+		Total:     currency.Format(invoice.TotalAmount),
+		LineItems: lineItems,
+	}
+}
+
+// InvoiceDelivery records an attempt to email the invoice of a bill.
+type InvoiceDelivery struct {
+	ID        string
+	TenantID  string
+	BillID    string
+	Recipient string
+	Locale    string
+	Subject   string
+	Status    string
+	Error     string
+	CreatedAt time.Time
+}
+
+type InvoiceDeliveries struct {
+	Deliveries []*InvoiceDelivery
+}
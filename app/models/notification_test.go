@@ -0,0 +1,55 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationTestSuite struct {
+	suite.Suite
+}
+
+func (suite *NotificationTestSuite) Test_EmailTemplateLocales() {
+	suite.Equal([]string{"de-AT", "de", "en"}, EmailTemplateLocales("de-AT"))
+	suite.Equal([]string{"en-US", "en"}, EmailTemplateLocales("en-US"))
+	suite.Equal([]string{"fr", "en"}, EmailTemplateLocales("fr"))
+	suite.Equal([]string{"en"}, EmailTemplateLocales(""))
+}
+
+func (suite *NotificationTestSuite) Test_RenderEscapesHTMLOnly() {
+	template := &EmailTemplate{
+		Subject: "Invoice for\n{{ .Name }}",
+		Text:    "Hello {{ .Name }}",
+		HTML:    "<p>Hello {{ .Name }}</p>",
+	}
+
+	email, err := template.Render(map[string]string{"Name": "Tom & Jerry"})
+
+	suite.Nil(err)
+	suite.Equal("Invoice for Tom & Jerry", email.Subject)
+	suite.Equal("Hello Tom & Jerry", email.Text)
+	suite.Equal("<p>Hello Tom &amp; Jerry</p>", email.HTML)
+}
+
+func (suite *NotificationTestSuite) Test_RenderFailsForMissingFields() {
+	template := &EmailTemplate{Subject: "Invoice", Text: "{{ .Total }}", HTML: "<p></p>"}
+
+	_, err := template.Render(map[string]string{"Name": "Tom"})
+
+	suite.NotNil(err)
+}
+
+func (suite *NotificationTestSuite) Test_NewInvoiceEmailFormatsAmounts() {
+	invoice := &Invoice{TotalAmount: 20.5, LineItems: []LineItem{{Description: "Seats", Quantity: 2.5, Amount: 20.5}}}
+
+	email := NewInvoiceEmail(invoice, &Currency{Symbol: "$", MinorUnits: 2})
+
+	suite.Equal("$20.50", email.Total)
+	suite.Equal("2.5", email.LineItems[0].Quantity)
+	suite.Equal("$20.50", email.LineItems[0].Amount)
+}
+
+func TestNotificationTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationTestSuite))
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+const (
+	DueOnReceipt = "due_on_receipt"
+	Net7         = "net_7"
+	Net15        = "net_15"
+	Net30        = "net_30"
+	Net45        = "net_45"
+	Net60        = "net_60"
+)
+
+var paymentTermDays = map[string]int{
+	DueOnReceipt: 0,
+	Net7:         7,
+	Net15:        15,
+	Net30:        30,
+	Net45:        45,
+	Net60:        60,
+}
+
+func isValidPaymentTerms(terms string) bool {
+	_, ok := paymentTermDays[terms]
+	return ok
+}
+
+// DueDate returns when a bill with the payment terms closed at closedAt is
+// due. Unknown terms are due on receipt.
+func DueDate(terms string, closedAt time.Time) time.Time {
+	return closedAt.AddDate(0, 0, paymentTermDays[terms])
+}
@@ -0,0 +1,213 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	PerUnitPricing   = "per_unit"
+	GraduatedPricing = "graduated"
+	VolumePricing    = "volume"
+	PackagePricing   = "package"
+	FlatPricing      = "flat"
+)
+
+// PriceTier is one tier of a graduated or volume price. UpTo is the inclusive
+// upper bound of the tier; the last tier has no upper bound and UpTo of zero.
+type PriceTier struct {
+	UpTo       float64
+	UnitAmount float64
+	FlatAmount float64
+}
+
+type PriceTiers []PriceTier
+
+func (t PriceTiers) Value() (driver.Value, error) {
+	if t == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (t *PriceTiers) Scan(value interface{}) error {
+	var b []byte
+	switch v := value.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return errors.New("unsupported type for price tiers")
+	}
+	return json.Unmarshal(b, t)
+}
+
+// PriceCalculation is the amount of a line item priced from a catalog price
+// and a human readable explanation of how it was derived.
+type PriceCalculation struct {
+	Amount      float64
+	Explanation string
+}
+
+func isValidPricing(model string, unitAmount float64, packageSize float64, tiers PriceTiers) bool {
+	switch model {
+	case "", PerUnitPricing, FlatPricing:
+		return unitAmount >= float64(0)
+	case PackagePricing:
+		return unitAmount >= float64(0) && packageSize > float64(0)
+	case GraduatedPricing, VolumePricing:
+		return isValidTiers(tiers)
+	}
+	return false
+}
+
+func isValidTiers(tiers PriceTiers) bool {
+	if len(tiers) == 0 {
+		return false
+	}
+
+	previous := float64(0)
+	for i, tier := range tiers {
+		if tier.UnitAmount < float64(0) || tier.FlatAmount < float64(0) {
+			return false
+		}
+		last := i == len(tiers)-1
+		if last != (tier.UpTo == float64(0)) {
+			return false
+		}
+		if !last && tier.UpTo <= previous {
+			return false
+		}
+		previous = tier.UpTo
+	}
+	return true
+}
+
+// CalculatePrice prices quantity units of price, rounding and explaining amounts
+// in minorUnits decimals, the minor units of the currency of the price. It has
+// no side effects so the same arguments always give the same amount and
+// explanation.
+func CalculatePrice(price *Price, quantity float64, minorUnits int) *PriceCalculation {
+	switch price.PricingModel {
+	case FlatPricing:
+		amount := roundAmount(price.UnitAmount, minorUnits)
+		return &PriceCalculation{
+			Amount:      amount,
+			Explanation: fmt.Sprintf("flat fee %s", formatAmount(amount, minorUnits)),
+		}
+	case PackagePricing:
+		packages := math.Ceil(quantity / price.PackageSize)
+		amount := roundAmount(packages*price.UnitAmount, minorUnits)
+		return &PriceCalculation{
+			Amount: amount,
+			Explanation: fmt.Sprintf("%s units in %s packages of %s x %s = %s", formatQuantity(quantity),
+				formatQuantity(packages), formatQuantity(price.PackageSize), formatAmount(price.UnitAmount, minorUnits), formatAmount(amount, minorUnits)),
+		}
+	case GraduatedPricing:
+		return graduatedPrice(price.Tiers, quantity, minorUnits)
+	case VolumePricing:
+		return volumePrice(price.Tiers, quantity, minorUnits)
+	default:
+		amount := roundAmount(price.UnitAmount*quantity, minorUnits)
+		return &PriceCalculation{
+			Amount:      amount,
+			Explanation: fmt.Sprintf("%s x %s = %s", formatQuantity(quantity), formatAmount(price.UnitAmount, minorUnits), formatAmount(amount, minorUnits)),
+		}
+	}
+}
+
+// graduatedPrice prices every unit at the rate of the tier it falls into.
+func graduatedPrice(tiers PriceTiers, quantity float64, minorUnits int) *PriceCalculation {
+	total := float64(0)
+	lower := float64(0)
+	steps := []string{}
+
+	for _, tier := range tiers {
+		if quantity <= lower {
+			break
+		}
+
+		units := quantity - lower
+		if tier.UpTo != float64(0) && tier.UpTo < quantity {
+			units = tier.UpTo - lower
+		}
+
+		amount := roundAmount(units*tier.UnitAmount+tier.FlatAmount, minorUnits)
+		total += amount
+		steps = append(steps, tierStep(lower, tier, units, amount, minorUnits))
+
+		lower = tier.UpTo
+		if tier.UpTo == float64(0) {
+			break
+		}
+	}
+
+	total = roundAmount(total, minorUnits)
+	steps = append(steps, fmt.Sprintf("total %s", formatAmount(total, minorUnits)))
+
+	return &PriceCalculation{
+		Amount:      total,
+		Explanation: strings.Join(steps, "; "),
+	}
+}
+
+// volumePrice prices all units at the rate of the tier the total quantity
+// falls into.
+func volumePrice(tiers PriceTiers, quantity float64, minorUnits int) *PriceCalculation {
+	lower := float64(0)
+	tier := tiers[len(tiers)-1]
+
+	for _, t := range tiers {
+		if t.UpTo == float64(0) || quantity <= t.UpTo {
+			tier = t
+			break
+		}
+		lower = t.UpTo
+	}
+
+	amount := roundAmount(quantity*tier.UnitAmount+tier.FlatAmount, minorUnits)
+
+	return &PriceCalculation{
+		Amount:      amount,
+		Explanation: fmt.Sprintf("all %s", tierStep(lower, tier, quantity, amount, minorUnits)),
+	}
+}
+
+func tierStep(lower float64, tier PriceTier, units float64, amount float64, minorUnits int) string {
+	bound := fmt.Sprintf("%s+", formatQuantity(lower+1))
+	if tier.UpTo != float64(0) {
+		bound = fmt.Sprintf("%s-%s", formatQuantity(lower+1), formatQuantity(tier.UpTo))
+	}
+
+	step := fmt.Sprintf("%s units in tier %s x %s", formatQuantity(units), bound, formatAmount(tier.UnitAmount, minorUnits))
+	if tier.FlatAmount != float64(0) {
+		step = fmt.Sprintf("%s + flat %s", step, formatAmount(tier.FlatAmount, minorUnits))
+	}
+	return fmt.Sprintf("%s = %s", step, formatAmount(amount, minorUnits))
+}
+
+func roundAmount(amount float64, minorUnits int) float64 {
+	factor := math.Pow10(minorUnits)
+	return math.Round(amount*factor) / factor
+}
+
+func formatAmount(amount float64, minorUnits int) string {
+	return strconv.FormatFloat(amount, 'f', minorUnits, 64)
+}
+
+func formatQuantity(quantity float64) string {
+	return strconv.FormatFloat(quantity, 'f', -1, 64)
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type PricingTestSuite struct {
+	suite.Suite
+	tiers PriceTiers
+}
+
+func (suite *PricingTestSuite) SetupTest() {
+	suite.tiers = PriceTiers{
+		{UpTo: 100, UnitAmount: 0.10},
+		{UpTo: 1000, UnitAmount: 0.08},
+		{UnitAmount: 0.05, FlatAmount: 5},
+	}
+}
+
+func (suite *PricingTestSuite) Test_PerUnitPrice() {
+	calculation := CalculatePrice(&Price{PricingModel: PerUnitPricing, UnitAmount: 9.99}, 3, 2)
+
+	suite.Equal(29.97, calculation.Amount)
+	suite.Equal("3 x 9.99 = 29.97", calculation.Explanation)
+}
+
+func (suite *PricingTestSuite) Test_PriceWithoutModelIsPerUnit() {
+	calculation := CalculatePrice(&Price{UnitAmount: 2}, 1.5, 2)
+
+	suite.Equal(float64(3), calculation.Amount)
+}
+
+func (suite *PricingTestSuite) Test_FlatPriceIgnoresQuantity() {
+	calculation := CalculatePrice(&Price{PricingModel: FlatPricing, UnitAmount: 49}, 12, 2)
+
+	suite.Equal(float64(49), calculation.Amount)
+	suite.Equal("flat fee 49.00", calculation.Explanation)
+}
+
+func (suite *PricingTestSuite) Test_PackagePriceRoundsUpToWholePackages() {
+	calculation := CalculatePrice(&Price{PricingModel: PackagePricing, UnitAmount: 5, PackageSize: 100}, 150, 2)
+
+	suite.Equal(float64(10), calculation.Amount)
+	suite.Equal("150 units in 2 packages of 100 x 5.00 = 10.00", calculation.Explanation)
+}
+
+func (suite *PricingTestSuite) Test_GraduatedPriceChargesEachTier() {
+	calculation := CalculatePrice(&Price{PricingModel: GraduatedPricing, Tiers: suite.tiers}, 1200, 2)
+
+	suite.Equal(float64(97), calculation.Amount)
+	suite.Equal("100 units in tier 1-100 x 0.10 = 10.00; "+
+		"900 units in tier 101-1000 x 0.08 = 72.00; "+
+		"200 units in tier 1001+ x 0.05 + flat 5.00 = 15.00; total 97.00", calculation.Explanation)
+}
+
+func (suite *PricingTestSuite) Test_GraduatedPriceStopsAtQuantity() {
+	calculation := CalculatePrice(&Price{PricingModel: GraduatedPricing, Tiers: suite.tiers}, 50, 2)
+
+	suite.Equal(float64(5), calculation.Amount)
+	suite.Equal("50 units in tier 1-100 x 0.10 = 5.00; total 5.00", calculation.Explanation)
+}
+
+func (suite *PricingTestSuite) Test_VolumePriceChargesAllUnitsAtReachedTier() {
+	calculation := CalculatePrice(&Price{PricingModel: VolumePricing, Tiers: suite.tiers}, 500, 2)
+
+	suite.Equal(float64(40), calculation.Amount)
+	suite.Equal("all 500 units in tier 101-1000 x 0.08 = 40.00", calculation.Explanation)
+
+	calculation = CalculatePrice(&Price{PricingModel: VolumePricing, Tiers: suite.tiers}, 2000, 2)
+	suite.Equal(float64(105), calculation.Amount)
+}
+
+func (suite *PricingTestSuite) Test_PriceIsRoundedToMinorUnits() {
+	calculation := CalculatePrice(&Price{PricingModel: PerUnitPricing, UnitAmount: 125}, 2.5, 0)
+
+	suite.Equal(float64(313), calculation.Amount)
+	suite.Equal("2.5 x 125 = 313", calculation.Explanation)
+
+	calculation = CalculatePrice(&Price{PricingModel: GraduatedPricing, Tiers: suite.tiers}, 50, 3)
+	suite.Equal(float64(5), calculation.Amount)
+	suite.Equal("50 units in tier 1-100 x 0.100 = 5.000; total 5.000", calculation.Explanation)
+}
+
+func (suite *PricingTestSuite) Test_CreatePriceRequestValidatesPricing() {
+	request := &CreatePriceRequest{PlanID: "plan id", CurrencyCode: "USD", PricingModel: GraduatedPricing, Tiers: suite.tiers}
+	suite.True(request.IsValid())
+
+	request.Tiers = PriceTiers{{UpTo: 100, UnitAmount: 0.10}}
+	suite.False(request.IsValid())
+
+	request.Tiers = PriceTiers{{UpTo: 100, UnitAmount: 0.10}, {UpTo: 50, UnitAmount: 0.08}, {UnitAmount: 0.05}}
+	suite.False(request.IsValid())
+
+	request.PricingModel = PackagePricing
+	request.UnitAmount = 5
+	suite.False(request.IsValid())
+
+	request.PackageSize = 100
+	suite.True(request.IsValid())
+
+	request.PricingModel = "stairstep"
+	suite.False(request.IsValid())
+}
+
+func (suite *PricingTestSuite) Test_PriceTiersRoundTrip() {
+	value, err := suite.tiers.Value()
+	suite.Require().Nil(err)
+
+	var tiers PriceTiers
+	suite.Require().Nil(tiers.Scan([]byte(value.(string))))
+	suite.Equal(suite.tiers, tiers)
+}
+
+func TestPricingTestSuite(t *testing.T) {
+	suite.Run(t, new(PricingTestSuite))
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// Proration is the share of a billing period left after a change, counted in
+// whole days or in seconds.
+type Proration struct {
+	Unit      string
+	Remaining int64
+	Total     int64
+}
+
+// CalculateProration returns the part of the period from periodStart to
+// periodEnd that remains at changedAt. Day based proration counts calendar
+// days in UTC, so a change any time during a day leaves that day remaining.
+func CalculateProration(periodStart, periodEnd, changedAt time.Time, unit string) *Proration {
+	proration := &Proration{Unit: unit}
+
+	if unit == SecondProration {
+		proration.Total = int64(periodEnd.Sub(periodStart) / time.Second)
+		proration.Remaining = int64(periodEnd.Sub(changedAt) / time.Second)
+	} else {
+		proration.Unit = DayProration
+		proration.Total = daysBetween(periodStart, periodEnd)
+		proration.Remaining = daysBetween(changedAt, periodEnd)
+	}
+
+	if proration.Remaining < 0 {
+		proration.Remaining = 0
+	}
+	if proration.Remaining > proration.Total {
+		proration.Remaining = proration.Total
+	}
+
+	return proration
+}
+
+// Apply returns the prorated part of amount, rounded to minorUnits decimals,
+// and explains how it was derived.
+func (p *Proration) Apply(amount float64, minorUnits int) *PriceCalculation {
+	if p.Total <= 0 {
+		return &PriceCalculation{Explanation: "empty period"}
+	}
+
+	prorated := roundAmount(amount*float64(p.Remaining)/float64(p.Total), minorUnits)
+	return &PriceCalculation{
+		Amount: prorated,
+		Explanation: fmt.Sprintf("%s x %d/%d %ss = %s", formatAmount(amount, minorUnits), p.Remaining, p.Total, p.Unit,
+			formatAmount(prorated, minorUnits)),
+	}
+}
+
+func daysBetween(from, to time.Time) int64 {
+	from = from.UTC()
+	to = to.UTC()
+	fromDay := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	toDay := time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.UTC)
+	return int64(toDay.Sub(fromDay) / (24 * time.Hour))
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ProrationTestSuite struct {
+	suite.Suite
+	start time.Time
+	end   time.Time
+}
+
+func (suite *ProrationTestSuite) SetupTest() {
+	suite.start = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	suite.end = time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *ProrationTestSuite) Test_DayProrationCountsChangeDayAsRemaining() {
+	changedAt := time.Date(2024, time.March, 16, 15, 30, 0, 0, time.UTC)
+
+	proration := CalculateProration(suite.start, suite.end, changedAt, DayProration)
+
+	suite.Equal(int64(16), proration.Remaining)
+	suite.Equal(int64(31), proration.Total)
+
+	calculation := proration.Apply(31, 2)
+	suite.Equal(float64(16), calculation.Amount)
+	suite.Equal("31.00 x 16/31 days = 16.00", calculation.Explanation)
+}
+
+func (suite *ProrationTestSuite) Test_ProrationIsRoundedToMinorUnits() {
+	changedAt := time.Date(2024, time.March, 16, 15, 30, 0, 0, time.UTC)
+
+	calculation := CalculateProration(suite.start, suite.end, changedAt, DayProration).Apply(1000, 0)
+	suite.Equal(float64(516), calculation.Amount)
+	suite.Equal("1000 x 16/31 days = 516", calculation.Explanation)
+
+	calculation = CalculateProration(suite.start, suite.end, changedAt, DayProration).Apply(10, 3)
+	suite.Equal(5.161, calculation.Amount)
+	suite.Equal("10.000 x 16/31 days = 5.161", calculation.Explanation)
+}
+
+func (suite *ProrationTestSuite) Test_SecondProration() {
+	changedAt := suite.start.Add(31 * 12 * time.Hour)
+
+	proration := CalculateProration(suite.start, suite.end, changedAt, SecondProration)
+
+	suite.Equal(proration.Total/2, proration.Remaining)
+	suite.Equal(float64(50), proration.Apply(100, 2).Amount)
+}
+
+func (suite *ProrationTestSuite) Test_ProrationIsClampedToPeriod() {
+	before := CalculateProration(suite.start, suite.end, suite.start.AddDate(0, 0, -5), DayProration)
+	suite.Equal(before.Total, before.Remaining)
+
+	after := CalculateProration(suite.start, suite.end, suite.end.AddDate(0, 0, 5), DayProration)
+	suite.Equal(int64(0), after.Remaining)
+	suite.Equal(float64(0), after.Apply(100, 2).Amount)
+}
+
+func (suite *ProrationTestSuite) Test_UnknownUnitIsDayProration() {
+	proration := CalculateProration(suite.start, suite.end, suite.start, "")
+
+	suite.Equal(DayProration, proration.Unit)
+}
+
+func TestProrationTestSuite(t *testing.T) {
+	suite.Run(t, new(ProrationTestSuite))
+}
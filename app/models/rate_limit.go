@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RateLimitBucket is a token bucket of a rate limit stored in the database,
+// shared by every instance of the service.
+type RateLimitBucket struct {
+	Key        string `gorm:"primaryKey"`
+	Tokens     float64
+	RefilledAt time.Time
+}
@@ -0,0 +1,108 @@
+package models
+
+// Roles of api keys. Every role has the permissions of the one before it.
+const (
+	RoleViewer       = "viewer"
+	RoleBiller       = "biller"
+	RoleFinanceAdmin = "finance_admin"
+)
+
+// Permissions, one per kind of operation, named after the resource they are
+// about.
+const (
+	PermissionBillsRead           = "bills:read"
+	PermissionBillsCreate         = "bills:create"
+	PermissionBillsClose          = "bills:close"
+	PermissionBillsCredit         = "bills:credit"
+	PermissionLineItemsAdd        = "line_items:add"
+	PermissionLineItemsRemove     = "line_items:remove"
+	PermissionPaymentsRecord      = "payments:record"
+	PermissionInvoicesSend        = "invoices:send"
+	PermissionCustomersRead       = "customers:read"
+	PermissionCustomersWrite      = "customers:write"
+	PermissionCustomersDelete     = "customers:delete"
+	PermissionCurrenciesRead      = "currencies:read"
+	PermissionCurrenciesWrite     = "currencies:write"
+	PermissionCatalogRead         = "catalog:read"
+	PermissionCatalogWrite        = "catalog:write"
+	PermissionSubscriptionsRead   = "subscriptions:read"
+	PermissionSubscriptionsWrite  = "subscriptions:write"
+	PermissionUsageWrite          = "usage:write"
+	PermissionExchangeRatesRead   = "exchange_rates:read"
+	PermissionExchangeRatesWrite  = "exchange_rates:write"
+	PermissionLedgerRead          = "ledger:read"
+	PermissionAuditRead           = "audit:read"
+	PermissionWebhooksRead        = "webhooks:read"
+	PermissionWebhooksWrite       = "webhooks:write"
+	PermissionEmailTemplatesRead  = "email_templates:read"
+	PermissionEmailTemplatesWrite = "email_templates:write"
+	PermissionAPIKeysRead         = "api_keys:read"
+	PermissionAPIKeysWrite        = "api_keys:write"
+	PermissionMetricsRead         = "metrics:read"
+)
+
+var viewerPermissions = []string{
+	PermissionBillsRead,
+	PermissionCustomersRead,
+	PermissionCurrenciesRead,
+	PermissionCatalogRead,
+	PermissionSubscriptionsRead,
+	PermissionExchangeRatesRead,
+	PermissionLedgerRead,
+}
+
+// billerPermissions bill customers day to day. Changing what was billed, by
+// closing bills, removing line items, crediting or recording payments, is
+// left to finance admins.
+var billerPermissions = append([]string{
+	PermissionBillsCreate,
+	PermissionLineItemsAdd,
+	PermissionInvoicesSend,
+	PermissionCustomersWrite,
+	PermissionSubscriptionsWrite,
+	PermissionUsageWrite,
+}, viewerPermissions...)
+
+var financeAdminPermissions = append([]string{
+	PermissionBillsClose,
+	PermissionBillsCredit,
+	PermissionLineItemsRemove,
+	PermissionPaymentsRecord,
+	PermissionCustomersDelete,
+	PermissionCurrenciesWrite,
+	PermissionCatalogWrite,
+	PermissionExchangeRatesWrite,
+	PermissionAuditRead,
+	PermissionWebhooksRead,
+	PermissionWebhooksWrite,
+	PermissionEmailTemplatesRead,
+	PermissionEmailTemplatesWrite,
+	PermissionAPIKeysRead,
+	PermissionAPIKeysWrite,
+	PermissionMetricsRead,
+}, billerPermissions...)
+
+var rolePermissions = map[string]map[string]bool{
+	RoleViewer:       permissionSet(viewerPermissions),
+	RoleBiller:       permissionSet(billerPermissions),
+	RoleFinanceAdmin: permissionSet(financeAdminPermissions),
+}
+
+func permissionSet(permissions []string) map[string]bool {
+	set := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		set[permission] = true
+	}
+	return set
+}
+
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// HasPermission reports whether the role grants the permission. Unknown
+// roles grant none.
+func HasPermission(role string, permission string) bool {
+	return rolePermissions[role][permission]
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RoleTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RoleTestSuite) Test_ViewerOnlyReads() {
+	suite.True(HasPermission(RoleViewer, PermissionBillsRead))
+	suite.False(HasPermission(RoleViewer, PermissionBillsCreate))
+	suite.False(HasPermission(RoleViewer, PermissionAPIKeysRead))
+}
+
+func (suite *RoleTestSuite) Test_BillerCanNotCloseBillsOrRemoveLineItems() {
+	suite.True(HasPermission(RoleBiller, PermissionBillsRead))
+	suite.True(HasPermission(RoleBiller, PermissionLineItemsAdd))
+	suite.False(HasPermission(RoleBiller, PermissionBillsClose))
+	suite.False(HasPermission(RoleBiller, PermissionLineItemsRemove))
+}
+
+func (suite *RoleTestSuite) Test_FinanceAdminHasEveryPermission() {
+	for role := range rolePermissions {
+		for permission := range rolePermissions[role] {
+			suite.True(HasPermission(RoleFinanceAdmin, permission), permission)
+		}
+	}
+	suite.True(HasPermission(RoleFinanceAdmin, PermissionBillsClose))
+}
+
+func (suite *RoleTestSuite) Test_UnknownRoleHasNoPermission() {
+	suite.False(IsRole("owner"))
+	suite.False(HasPermission("owner", PermissionBillsRead))
+	suite.False((&Principal{APIKeyID: "key id"}).Can(PermissionBillsRead))
+	suite.True((&Principal{APIKeyID: "key id", Role: RoleViewer}).Can(PermissionBillsRead))
+}
+
+func TestRoleTestSuite(t *testing.T) {
+	suite.Run(t, new(RoleTestSuite))
+}
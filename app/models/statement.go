@@ -0,0 +1,189 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	BillEntry    = "bill"
+	CreditEntry  = "credit"
+	PaymentEntry = "payment"
+)
+
+// Statement is the position of a customer over a window, one account per
+// currency the customer was billed in. Closed bills are charged on the day
+// they close, bills with a negative total and credits granted on bills are
+// credits, and payments are entered on the day they are recorded.
+type Statement struct {
+	CustomerID string
+	Customer   InvoiceCustomer
+	From       time.Time
+	To         time.Time
+	Accounts   []*StatementAccount
+}
+
+// StatementAccount is the position of the customer in one currency. The
+// balance of every entry is the running balance after it.
+type StatementAccount struct {
+	CurrencyCode   string
+	OpeningBalance float64
+	ClosingBalance float64
+	Entries        []StatementEntry
+
+	currency *Currency
+}
+
+type StatementEntry struct {
+	Date        time.Time
+	Type        string
+	BillID      string
+	Description string
+	Amount      float64
+	Balance     float64
+}
+
+// StatementRequest is the window of a statement. To defaults to now and a
+// zero From starts the statement at the first bill of the customer.
+type StatementRequest struct {
+	From time.Time `query:"from"`
+	To   time.Time `query:"to"`
+}
+
+func (r *StatementRequest) IsValid() bool {
+	return r.To.IsZero() || !r.From.After(r.To)
+}
+
+// CreateStatement builds the statement of the customer for [from, to) from its
+// bills closed before to and the payments and credit journal entries posted on
+// them, all oldest first. currencies holds the currencies of the bills by id.
+func CreateStatement(customer *Customer, bills []*Bill, settlements []*JournalEntry, currencies map[string]*Currency, from, to time.Time) *Statement {
+	location := customer.Location()
+	accounts := map[string]*StatementAccount{}
+
+	movements := []statementMovement{}
+	billCurrencies := map[string]*Currency{}
+	for _, bill := range bills {
+		currency := currencies[bill.CurrencyID]
+		billCurrencies[bill.ID] = currency
+
+		closedAt := bill.UpdatedAt
+		if bill.ClosedAt != nil {
+			closedAt = *bill.ClosedAt
+		}
+
+		entryType := BillEntry
+		if bill.TotalAmount < 0 {
+			entryType = CreditEntry
+		}
+
+		movements = append(movements, statementMovement{
+			currency: currency,
+			entry:    StatementEntry{Date: closedAt, Type: entryType, BillID: bill.ID, Description: bill.Description, Amount: bill.TotalAmount},
+		})
+	}
+
+	for _, settlement := range settlements {
+		currency, ok := billCurrencies[settlement.BillID]
+		if !ok {
+			continue
+		}
+
+		entryType := PaymentEntry
+		if settlement.Kind == JournalCredit {
+			entryType = CreditEntry
+		}
+
+		movements = append(movements, statementMovement{
+			currency: currency,
+			entry: StatementEntry{Date: settlement.CreatedAt, Type: entryType, BillID: settlement.BillID,
+				Description: settlement.Description, Amount: -settlement.Amount()},
+		})
+	}
+
+	sort.SliceStable(movements, func(i, j int) bool {
+		return movements[i].entry.Date.Before(movements[j].entry.Date)
+	})
+
+	for _, movement := range movements {
+		currency := movement.currency
+		account, ok := accounts[currency.Code]
+		if !ok {
+			account = &StatementAccount{CurrencyCode: currency.Code, Entries: []StatementEntry{}, currency: currency}
+			accounts[currency.Code] = account
+		}
+
+		account.ClosingBalance = currency.Round(account.ClosingBalance + movement.entry.Amount)
+		if movement.entry.Date.Before(from) {
+			account.OpeningBalance = account.ClosingBalance
+			continue
+		}
+
+		entry := movement.entry
+		entry.Date = entry.Date.In(location)
+		entry.Balance = account.ClosingBalance
+		account.Entries = append(account.Entries, entry)
+	}
+
+	statement := &Statement{
+		CustomerID: customer.ID,
+		Customer:   newInvoiceCustomer(customer),
+		From:       from.In(location),
+		To:         to.In(location),
+		Accounts:   []*StatementAccount{},
+	}
+	for _, account := range accounts {
+		statement.Accounts = append(statement.Accounts, account)
+	}
+	sort.Slice(statement.Accounts, func(i, j int) bool {
+		return statement.Accounts[i].CurrencyCode < statement.Accounts[j].CurrencyCode
+	})
+
+	return statement
+}
+
+// statementMovement is a bill or settlement in the currency of its account.
+type statementMovement struct {
+	currency *Currency
+	entry    StatementEntry
+}
+
+// Render renders the statement as plain text for sending to the customer.
+func (s *Statement) Render() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Statement for %s\n", s.Customer.Name)
+	if s.Customer.CompanyName != "" {
+		fmt.Fprintf(&b, "%s\n", s.Customer.CompanyName)
+	}
+	if s.From.IsZero() {
+		fmt.Fprintf(&b, "Up to %s\n", s.To.Format("2006-01-02"))
+	} else {
+		fmt.Fprintf(&b, "From %s to %s\n", s.From.Format("2006-01-02"), s.To.Format("2006-01-02"))
+	}
+
+	if len(s.Accounts) == 0 {
+		b.WriteString("\nNo bills.\n")
+		return b.String()
+	}
+
+	for _, account := range s.Accounts {
+		currency := account.currency
+
+		fmt.Fprintf(&b, "\n%s\n", account.CurrencyCode)
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "Date\tType\tDescription\tAmount\tBalance\t")
+		fmt.Fprintf(w, "\t\tOpening balance\t\t%s\t\n", currency.Format(account.OpeningBalance))
+		for _, entry := range account.Entries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t\n", entry.Date.Format("2006-01-02"), entry.Type,
+				entry.Description, currency.Format(entry.Amount), currency.Format(entry.Balance))
+		}
+		fmt.Fprintf(w, "\t\tClosing balance\t\t%s\t\n", currency.Format(account.ClosingBalance))
+		w.Flush()
+	}
+
+	return b.String()
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type StatementTestSuite struct {
+	suite.Suite
+	customer   *Customer
+	currencies map[string]*Currency
+}
+
+func (suite *StatementTestSuite) SetupTest() {
+	suite.customer = &Customer{ID: "customer id", FirstName: "John", LastName: "Jacobs", Timezone: "UTC"}
+	suite.currencies = map[string]*Currency{
+		"usd id": {ID: "usd id", Code: "USD", Symbol: "$", MinorUnits: 2},
+		"eur id": {ID: "eur id", Code: "EUR", Symbol: "€", MinorUnits: 2},
+	}
+}
+
+func (suite *StatementTestSuite) bill(id, currencyID string, amount float64, closedAt time.Time) *Bill {
+	return &Bill{ID: id, Description: id, CurrencyID: currencyID, Status: "closed", TotalAmount: amount, ClosedAt: &closedAt}
+}
+
+func (suite *StatementTestSuite) Test_CreateStatementKeepsRunningBalancePerCurrency() {
+	from := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	bills := []*Bill{
+		suite.bill("january", "usd id", 10, time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)),
+		suite.bill("february", "usd id", 20.5, time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC)),
+		suite.bill("refund", "usd id", -5, time.Date(2024, time.February, 12, 0, 0, 0, 0, time.UTC)),
+		suite.bill("euro", "eur id", 7, time.Date(2024, time.February, 15, 0, 0, 0, 0, time.UTC)),
+	}
+
+	statement := CreateStatement(suite.customer, bills, []*JournalEntry{}, suite.currencies, from, to)
+
+	suite.Require().Len(statement.Accounts, 2)
+	eur, usd := statement.Accounts[0], statement.Accounts[1]
+
+	suite.Equal("USD", usd.CurrencyCode)
+	suite.Equal(10.0, usd.OpeningBalance)
+	suite.Equal(25.5, usd.ClosingBalance)
+	suite.Require().Len(usd.Entries, 2)
+	suite.Equal(BillEntry, usd.Entries[0].Type)
+	suite.Equal(30.5, usd.Entries[0].Balance)
+	suite.Equal(CreditEntry, usd.Entries[1].Type)
+	suite.Equal(25.5, usd.Entries[1].Balance)
+
+	suite.Equal("EUR", eur.CurrencyCode)
+	suite.Equal(0.0, eur.OpeningBalance)
+	suite.Equal(7.0, eur.ClosingBalance)
+}
+
+func (suite *StatementTestSuite) Test_CreateStatementEntersPaymentsAndCredits() {
+	from := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	bills := []*Bill{
+		suite.bill("january", "usd id", 30, time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)),
+	}
+	settlements := []*JournalEntry{
+		{Kind: JournalCredit, BillID: "january", Description: "goodwill", CreatedAt: time.Date(2024, time.February, 3, 0, 0, 0, 0, time.UTC),
+			Postings: []*Posting{{CurrencyCode: "USD", Debit: 5}, {CurrencyCode: "USD", Credit: 5}}},
+		{Kind: JournalPayment, BillID: "january", Description: "Payment", CreatedAt: time.Date(2024, time.February, 2, 0, 0, 0, 0, time.UTC),
+			Postings: []*Posting{{CurrencyCode: "USD", Debit: 20}, {CurrencyCode: "USD", Credit: 20}}},
+	}
+
+	statement := CreateStatement(suite.customer, bills, settlements, suite.currencies, from, to)
+
+	suite.Require().Len(statement.Accounts, 1)
+	usd := statement.Accounts[0]
+	suite.Equal(30.0, usd.OpeningBalance)
+	suite.Equal(5.0, usd.ClosingBalance)
+	suite.Require().Len(usd.Entries, 2)
+	suite.Equal(PaymentEntry, usd.Entries[0].Type)
+	suite.Equal(-20.0, usd.Entries[0].Amount)
+	suite.Equal(10.0, usd.Entries[0].Balance)
+	suite.Equal(CreditEntry, usd.Entries[1].Type)
+	suite.Equal(5.0, usd.Entries[1].Balance)
+}
+
+func (suite *StatementTestSuite) Test_Render() {
+	from := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	bills := []*Bill{suite.bill("february", "usd id", 20.5, time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC))}
+
+	rendered := CreateStatement(suite.customer, bills, []*JournalEntry{}, suite.currencies, from, to).Render()
+
+	suite.Contains(rendered, "Statement for John Jacobs")
+	suite.Contains(rendered, "From 2024-02-01 to 2024-03-01")
+	suite.Contains(rendered, "2024-02-10")
+	suite.Contains(rendered, "$20.50")
+
+	empty := CreateStatement(suite.customer, []*Bill{}, []*JournalEntry{}, suite.currencies, time.Time{}, to).Render()
+	suite.Contains(empty, "Up to 2024-03-01")
+	suite.Contains(empty, "No bills.")
+}
+
+func (suite *StatementTestSuite) Test_StatementRequestIsValid() {
+	now := time.Now()
+
+	suite.True((&StatementRequest{}).IsValid())
+	suite.True((&StatementRequest{From: now}).IsValid())
+	suite.False((&StatementRequest{From: now, To: now.Add(-time.Hour)}).IsValid())
+}
+
+func TestStatementTestSuite(t *testing.T) {
+	suite.Run(t, new(StatementTestSuite))
+}
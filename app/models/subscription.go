@@ -0,0 +1,153 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	MonthlyInterval = "monthly"
+	YearlyInterval  = "yearly"
+	CustomInterval  = "custom"
+)
+
+const (
+	DayProration    = "day"
+	SecondProration = "second"
+)
+
+type Subscription struct {
+	ID              string
+	TenantID        string
+	Description     string
+	CustomerID      string
+	CurrencyID      string
+	BillingInterval string
+	IntervalDays    int
+	ProrationUnit   string
+	// AnchorDay is the day of the month monthly and yearly periods start on,
+	// the last day of months shorter than it.
+	AnchorDay          int
+	Status             string
+	CancelAtPeriodEnd  bool
+	CurrentBillID      string
+	CurrentPeriodStart time.Time
+	CurrentPeriodEnd   time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+type CreateSubscriptionRequest struct {
+	Description     string
+	CustomerID      string
+	CurrencyCode    string
+	BillingInterval string
+	IntervalDays    int
+	ProrationUnit   string
+	StartDate       time.Time
+}
+
+// SubscriptionItem is a recurring charge of a subscription. It is charged in
+// full on the bill of every period, and prorated when it is added, changed or
+// removed in the middle of a period.
+type SubscriptionItem struct {
+	ID             string
+	TenantID       string
+	SubscriptionID string
+	PriceID        string
+	Quantity       float64
+	Removed        bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type SubscriptionItemRequest struct {
+	PriceID  string
+	Quantity float64
+}
+
+// SubscriptionItemChange is a changed subscription item along with the
+// proration line items it added to the open bill.
+type SubscriptionItemChange struct {
+	Item      *SubscriptionItem
+	LineItems []*LineItem
+}
+
+// PeriodEnd returns the end of the billing period starting at start. Monthly
+// and yearly periods end on the anchor day, so a period clamped to the end of
+// a short month does not move the periods after it.
+func (s *Subscription) PeriodEnd(start time.Time) time.Time {
+	switch s.BillingInterval {
+	case MonthlyInterval:
+		return s.anchored(start, 0, 1)
+	case YearlyInterval:
+		return s.anchored(start, 1, 0)
+	default:
+		return start.AddDate(0, 0, s.IntervalDays)
+	}
+}
+
+// anchored returns the time of day of start, years and months later, on the
+// anchor day or the last day of the month when it is shorter.
+func (s *Subscription) anchored(start time.Time, years int, months int) time.Time {
+	day := s.AnchorDay
+	if day == 0 {
+		day = start.Day()
+	}
+
+	month := time.Date(start.Year()+years, start.Month()+time.Month(months), 1, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+	if last := month.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+	return month.AddDate(0, 0, day-1)
+}
+
+func (r *CreateSubscriptionRequest) IsValid() bool {
+	if r.Description == "" || r.CustomerID == "" || r.CurrencyCode == "" || r.StartDate.IsZero() {
+		return false
+	}
+	if r.ProrationUnit != "" && r.ProrationUnit != DayProration && r.ProrationUnit != SecondProration {
+		return false
+	}
+	return isValidInterval(r.BillingInterval, r.IntervalDays)
+}
+
+func isValidInterval(interval string, days int) bool {
+	switch interval {
+	case MonthlyInterval, YearlyInterval:
+		return true
+	case CustomInterval:
+		return days > 0
+	}
+	return false
+}
+
+func (r *CreateSubscriptionRequest) ToSubscription() *Subscription {
+	prorationUnit := r.ProrationUnit
+	if prorationUnit == "" {
+		prorationUnit = DayProration
+	}
+
+	return &Subscription{
+		Description:     r.Description,
+		CustomerID:      r.CustomerID,
+		BillingInterval: r.BillingInterval,
+		IntervalDays:    r.IntervalDays,
+		ProrationUnit:   prorationUnit,
+	}
+}
+
+func (r *SubscriptionItemRequest) IsValid() bool {
+	return r.PriceID != "" && r.Quantity >= float64(0)
+}
+
+func (r *SubscriptionItemRequest) ToSubscriptionItem() *SubscriptionItem {
+	quantity := r.Quantity
+	if quantity == 0 {
+		quantity = 1
+	}
+
+	return &SubscriptionItem{
+		PriceID:  r.PriceID,
+		Quantity: quantity,
+	}
+}
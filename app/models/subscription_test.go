@@ -0,0 +1,113 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SubscriptionTestSuite struct {
+	suite.Suite
+	validRequest   *CreateSubscriptionRequest
+	invalidRequest *CreateSubscriptionRequest
+}
+
+func (suite *SubscriptionTestSuite) SetupTest() {
+	suite.validRequest = &CreateSubscriptionRequest{
+		Description:     "pro plan",
+		CustomerID:      "customer id",
+		CurrencyCode:    "USD",
+		BillingInterval: MonthlyInterval,
+		StartDate:       time.Now().UTC(),
+	}
+
+	suite.invalidRequest = &CreateSubscriptionRequest{
+		Description: "pro plan",
+	}
+}
+
+func (suite *SubscriptionTestSuite) Test_IsValidReturnFalse() {
+	suite.False(suite.invalidRequest.IsValid())
+}
+
+func (suite *SubscriptionTestSuite) Test_IsValidReturnTrue() {
+	suite.True(suite.validRequest.IsValid())
+}
+
+func (suite *SubscriptionTestSuite) Test_IsValidReturnFalseWhenIntervalIsUnknown() {
+	request := *suite.validRequest
+	request.BillingInterval = "weekly"
+
+	suite.False(request.IsValid())
+}
+
+func (suite *SubscriptionTestSuite) Test_IsValidReturnFalseWhenCustomIntervalHasNoDays() {
+	request := *suite.validRequest
+	request.BillingInterval = CustomInterval
+
+	suite.False(request.IsValid())
+
+	request.IntervalDays = 14
+	suite.True(request.IsValid())
+}
+
+func (suite *SubscriptionTestSuite) Test_ProrationUnitDefaultsToDay() {
+	suite.Equal(DayProration, suite.validRequest.ToSubscription().ProrationUnit)
+
+	request := *suite.validRequest
+	request.ProrationUnit = "hour"
+	suite.False(request.IsValid())
+
+	request.ProrationUnit = SecondProration
+	suite.True(request.IsValid())
+	suite.Equal(SecondProration, request.ToSubscription().ProrationUnit)
+}
+
+func (suite *SubscriptionTestSuite) Test_SubscriptionItemRequest() {
+	suite.False((&SubscriptionItemRequest{Quantity: 1}).IsValid())
+	suite.False((&SubscriptionItemRequest{PriceID: "price id", Quantity: -1}).IsValid())
+
+	request := &SubscriptionItemRequest{PriceID: "price id"}
+	suite.True(request.IsValid())
+	suite.Equal(float64(1), request.ToSubscriptionItem().Quantity)
+}
+
+func (suite *SubscriptionTestSuite) Test_PeriodEnd() {
+	start := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC)
+
+	monthly := &Subscription{BillingInterval: MonthlyInterval}
+	suite.Equal(time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC), monthly.PeriodEnd(start))
+
+	yearly := &Subscription{BillingInterval: YearlyInterval}
+	suite.Equal(time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC), yearly.PeriodEnd(start))
+
+	leapDay := time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)
+	suite.Equal(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC), (&Subscription{BillingInterval: YearlyInterval, AnchorDay: 29}).PeriodEnd(leapDay))
+
+	custom := &Subscription{BillingInterval: CustomInterval, IntervalDays: 10}
+	suite.Equal(time.Date(2024, time.February, 10, 0, 0, 0, 0, time.UTC), custom.PeriodEnd(start))
+}
+
+func (suite *SubscriptionTestSuite) Test_PeriodEndKeepsTheAnchorDay() {
+	monthly := &Subscription{BillingInterval: MonthlyInterval, AnchorDay: 31}
+	start := time.Date(2025, time.January, 31, 9, 30, 0, 0, time.UTC)
+
+	ends := []time.Time{}
+	for i := 0; i < 4; i++ {
+		start = monthly.PeriodEnd(start)
+		ends = append(ends, start)
+	}
+
+	suite.Equal([]time.Time{
+		time.Date(2025, time.February, 28, 9, 30, 0, 0, time.UTC),
+		time.Date(2025, time.March, 31, 9, 30, 0, 0, time.UTC),
+		time.Date(2025, time.April, 30, 9, 30, 0, 0, time.UTC),
+		time.Date(2025, time.May, 31, 9, 30, 0, 0, time.UTC),
+	}, ends)
+	suite.Equal(time.Date(2026, time.January, 31, 9, 30, 0, 0, time.UTC), monthly.PeriodEnd(time.Date(2025, time.December, 31, 9, 30, 0, 0, time.UTC)))
+}
+
+func TestSubscriptionTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionTestSuite))
+}
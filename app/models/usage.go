@@ -0,0 +1,110 @@
+package models
+
+import (
+	"time"
+)
+
+const (
+	SumAggregation  = "sum"
+	MaxAggregation  = "max"
+	LastAggregation = "last"
+)
+
+type Meter struct {
+	ID          string
+	TenantID    string
+	Code        string
+	Name        string
+	Aggregation string
+	PlanID      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type UsageEvent struct {
+	ID            string
+	TenantID      string
+	IdempotencyID string
+	CustomerID    string
+	MeterID       string
+	Quantity      float64
+	Timestamp     time.Time
+	BillID        string
+	CreatedAt     time.Time
+}
+
+type CreateMeterRequest struct {
+	Code        string
+	Name        string
+	Aggregation string
+	PlanID      string
+}
+
+type UsageEventRequest struct {
+	IdempotencyID string
+	CustomerID    string
+	MeterCode     string
+	Quantity      float64
+	Timestamp     time.Time
+}
+
+func (r *CreateMeterRequest) IsValid() bool {
+	if r.Code == "" || r.Name == "" || r.PlanID == "" {
+		return false
+	}
+
+	switch r.Aggregation {
+	case SumAggregation, MaxAggregation, LastAggregation:
+		return true
+	}
+	return false
+}
+
+func (r *CreateMeterRequest) ToMeter() *Meter {
+	return &Meter{
+		Code:        r.Code,
+		Name:        r.Name,
+		Aggregation: r.Aggregation,
+		PlanID:      r.PlanID,
+	}
+}
+
+func (r *UsageEventRequest) IsValid() bool {
+	if r.IdempotencyID == "" || r.CustomerID == "" || r.MeterCode == "" || r.Quantity < float64(0) || r.Timestamp.IsZero() {
+		return false
+	}
+	return true
+}
+
+func (r *UsageEventRequest) ToUsageEvent() *UsageEvent {
+	return &UsageEvent{
+		IdempotencyID: r.IdempotencyID,
+		CustomerID:    r.CustomerID,
+		Quantity:      r.Quantity,
+		Timestamp:     r.Timestamp.UTC(),
+	}
+}
+
+// AggregateUsage reduces the events of a single meter to the billable quantity.
+func AggregateUsage(aggregation string, events []*UsageEvent) float64 {
+	quantity := float64(0)
+	var last *UsageEvent
+
+	for _, event := range events {
+		switch aggregation {
+		case SumAggregation:
+			quantity += event.Quantity
+		case MaxAggregation:
+			if event.Quantity > quantity {
+				quantity = event.Quantity
+			}
+		case LastAggregation:
+			if last == nil || !event.Timestamp.Before(last.Timestamp) {
+				last = event
+				quantity = event.Quantity
+			}
+		}
+	}
+
+	return quantity
+}
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type UsageTestSuite struct {
+	suite.Suite
+	events []*UsageEvent
+}
+
+func (suite *UsageTestSuite) SetupTest() {
+	now := time.Now().UTC()
+	suite.events = []*UsageEvent{
+		{Quantity: 5, Timestamp: now},
+		{Quantity: 12, Timestamp: now.Add(-time.Hour)},
+		{Quantity: 3, Timestamp: now.Add(time.Hour)},
+	}
+}
+
+func (suite *UsageTestSuite) Test_AggregateUsageSum() {
+	suite.Equal(float64(20), AggregateUsage(SumAggregation, suite.events))
+}
+
+func (suite *UsageTestSuite) Test_AggregateUsageMax() {
+	suite.Equal(float64(12), AggregateUsage(MaxAggregation, suite.events))
+}
+
+func (suite *UsageTestSuite) Test_AggregateUsageLast() {
+	suite.Equal(float64(3), AggregateUsage(LastAggregation, suite.events))
+}
+
+func (suite *UsageTestSuite) Test_AggregateUsageWithoutEvents() {
+	suite.Equal(float64(0), AggregateUsage(SumAggregation, []*UsageEvent{}))
+}
+
+func (suite *UsageTestSuite) Test_CreateMeterRequestIsValid() {
+	request := &CreateMeterRequest{Code: "api_calls", Name: "API calls", Aggregation: SumAggregation, PlanID: "plan id"}
+	suite.True(request.IsValid())
+
+	request.Aggregation = "avg"
+	suite.False(request.IsValid())
+}
+
+func (suite *UsageTestSuite) Test_UsageEventRequestIsValid() {
+	request := &UsageEventRequest{
+		IdempotencyID: "event-01",
+		CustomerID:    "customer id",
+		MeterCode:     "api_calls",
+		Quantity:      1,
+		Timestamp:     time.Now().UTC(),
+	}
+	suite.True(request.IsValid())
+
+	request.IdempotencyID = ""
+	suite.False(request.IsValid())
+}
+
+func TestUsageTestSuite(t *testing.T) {
+	suite.Run(t, new(UsageTestSuite))
+}
@@ -0,0 +1,191 @@
+package models
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// InvoiceFinalized is sent when the bill closes and its invoice no longer
+// changes.
+const InvoiceFinalized = "invoice.finalized"
+
+// WebhookEvents are the events sent to webhook endpoints.
+var WebhookEvents = []string{BillCreated, LineItemAdded, LineItemRemoved, BillClosed, InvoiceFinalized}
+
+// Statuses of a webhook delivery.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Headers of webhook requests. WebhookSignatureHeader carries the signature
+// in the form t=<unix timestamp>,v1=<hex HMAC-SHA256 of "<timestamp>.<body>">.
+const (
+	WebhookEventHeader     = "X-Billing-Event"
+	WebhookDeliveryHeader  = "X-Billing-Delivery"
+	WebhookSignatureHeader = "X-Billing-Signature"
+)
+
+// WebhookEndpoint receives the events it subscribes to, all events when
+// Events is empty. Payloads are signed with Secret.
+type WebhookEndpoint struct {
+	ID        string
+	TenantID  string
+	URL       string
+	Secret    string
+	Events    []string `gorm:"serializer:json"`
+	Active    bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type WebhookEndpointRequest struct {
+	URL    string
+	Events []string
+}
+
+type WebhookEndpoints struct {
+	Endpoints []*WebhookEndpoint
+}
+
+// WebhookPayload is the body posted to webhook endpoints.
+type WebhookPayload struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	CreatedAt string `json:"created_at"`
+	Data      any    `json:"data"`
+}
+
+// WebhookDelivery logs the delivery of one event to one endpoint. A
+// redelivery is logged as a new delivery of the same event.
+type WebhookDelivery struct {
+	ID             string
+	TenantID       string
+	EndpointID     string
+	EventID        string
+	EventType      string
+	BillID         string
+	Payload        string
+	Status         string
+	Attempts       int
+	ResponseStatus int
+	LastError      string
+	RedeliveryOf   string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// ListWebhookDeliveriesRequest pages through the deliveries of an endpoint,
+// newest first.
+type ListWebhookDeliveriesRequest struct {
+	Limit  int `query:"limit"`
+	Offset int `query:"offset"`
+}
+
+type WebhookDeliveryList struct {
+	Deliveries []*WebhookDelivery
+	Total      int64
+}
+
+func (r *WebhookEndpointRequest) IsValid() bool {
+	endpointURL, err := url.Parse(r.URL)
+	if err != nil || endpointURL.Host == "" || (endpointURL.Scheme != "http" && endpointURL.Scheme != "https") {
+		return false
+	}
+
+	for _, event := range r.Events {
+		if !IsWebhookEvent(event) {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *ListWebhookDeliveriesRequest) IsValid() bool {
+	return r.Limit >= 0 && r.Limit <= MaxPageSize && r.Offset >= 0
+}
+
+// PageSize returns the requested limit, or DefaultPageSize when none is given.
+func (r *ListWebhookDeliveriesRequest) PageSize() int {
+	if r.Limit == 0 {
+		return DefaultPageSize
+	}
+	return r.Limit
+}
+
+func IsWebhookEvent(event string) bool {
+	for _, webhookEvent := range WebhookEvents {
+		if event == webhookEvent {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscribes reports whether the endpoint receives the event.
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	if !e.Active {
+		return false
+	}
+
+	if len(e.Events) == 0 {
+		return true
+	}
+
+	for _, subscribed := range e.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// SignWebhook returns the signature header of the payload sent at timestamp.
+func SignWebhook(secret string, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	return fmt.Sprintf("t=%s,v1=%s", unix, webhookMAC(secret, unix, payload))
+}
+
+// VerifyWebhook reports whether signature is a valid signature of the payload
+// made no more than tolerance before now. Receivers use it to check that a
+// request came from the billing service.
+func VerifyWebhook(secret string, signature string, payload []byte, now time.Time, tolerance time.Duration) bool {
+	var unix, mac string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			unix = value
+		case "v1":
+			mac = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(unix, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	signedAt := time.Unix(seconds, 0)
+	if now.Sub(signedAt) > tolerance || signedAt.Sub(now) > tolerance {
+		return false
+	}
+
+	return hmac.Equal([]byte(mac), []byte(webhookMAC(secret, unix, payload)))
+}
+
+func webhookMAC(secret string, unix string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+}
+
+func (suite *WebhookTestSuite) Test_WebhookEndpointRequestIsValid() {
+	suite.True((&WebhookEndpointRequest{URL: "https://crm.example.com/hooks"}).IsValid())
+	suite.True((&WebhookEndpointRequest{URL: "http://localhost:8080/hooks", Events: []string{BillClosed, InvoiceFinalized}}).IsValid())
+	suite.False((&WebhookEndpointRequest{}).IsValid())
+	suite.False((&WebhookEndpointRequest{URL: "crm.example.com/hooks"}).IsValid())
+	suite.False((&WebhookEndpointRequest{URL: "ftp://crm.example.com/hooks"}).IsValid())
+	suite.False((&WebhookEndpointRequest{URL: "https://crm.example.com/hooks", Events: []string{CustomerCreated}}).IsValid())
+}
+
+func (suite *WebhookTestSuite) Test_WebhookEndpointSubscribes() {
+	all := &WebhookEndpoint{Active: true}
+	suite.True(all.Subscribes(BillCreated))
+	suite.True(all.Subscribes(InvoiceFinalized))
+
+	closed := &WebhookEndpoint{Active: true, Events: []string{BillClosed}}
+	suite.True(closed.Subscribes(BillClosed))
+	suite.False(closed.Subscribes(LineItemAdded))
+
+	inactive := &WebhookEndpoint{Events: []string{BillClosed}}
+	suite.False(inactive.Subscribes(BillClosed))
+}
+
+func (suite *WebhookTestSuite) Test_SignWebhook() {
+	timestamp := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"event id"}`)
+
+	signature := SignWebhook("secret", timestamp, payload)
+	suite.Equal("t=1700000000,v1=8e01acfb68f08c9f6a60db16175c7764c07093c8314c4c710224e1dbc981318f", signature)
+	suite.NotEqual(signature, SignWebhook("other secret", timestamp, payload))
+	suite.NotEqual(signature, SignWebhook("secret", timestamp.Add(time.Second), payload))
+}
+
+func (suite *WebhookTestSuite) Test_VerifyWebhook() {
+	timestamp := time.Unix(1700000000, 0)
+	payload := []byte(`{"id":"event id"}`)
+	signature := SignWebhook("secret", timestamp, payload)
+
+	suite.True(VerifyWebhook("secret", signature, payload, timestamp.Add(time.Minute), 5*time.Minute))
+	suite.False(VerifyWebhook("other secret", signature, payload, timestamp, 5*time.Minute))
+	suite.False(VerifyWebhook("secret", signature, []byte(`{"id":"other id"}`), timestamp, 5*time.Minute))
+	suite.False(VerifyWebhook("secret", signature, payload, timestamp.Add(10*time.Minute), 5*time.Minute))
+	suite.False(VerifyWebhook("secret", "v1=abc", payload, timestamp, 5*time.Minute))
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+// apiKeyLength is the number of random bytes in an api key.
+const apiKeyLength = 32
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal the request is
+// made by.
+func WithPrincipal(ctx context.Context, principal *models.Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of ctx. There is none outside of api
+// requests, such as in workflows.
+func PrincipalFrom(ctx context.Context) (*models.Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*models.Principal)
+	return principal, ok && principal != nil
+}
+
+type apiKeyService struct {
+	repository       repository.APIKeyRepository
+	audit            AuditService
+	bootstrapKeyHash string
+}
+
+type APIKeyService interface {
+	Issue(context.Context, *models.APIKeyRequest) (*models.IssuedAPIKey, error)
+	List(context.Context) (*models.APIKeys, error)
+	Revoke(context.Context, string) (*models.APIKey, error)
+	Authenticate(context.Context, string) (*models.Principal, error)
+}
+
+// NewAPIKeyService returns the api key service. bootstrapKeyHash is the
+// models.HashAPIKey of a key accepted besides the issued ones, to issue the
+// first keys with. It is disabled when empty.
+func NewAPIKeyService(repository repository.APIKeyRepository, audit AuditService, bootstrapKeyHash string) APIKeyService {
+	return &apiKeyService{
+		repository:       repository,
+		audit:            audit,
+		bootstrapKeyHash: bootstrapKeyHash,
+	}
+}
+
+// Issue creates an api key for the tenant of ctx, or the tenant of the
+// request when issued by the bootstrap principal. The key is only returned
+// here.
+func (as *apiKeyService) Issue(ctx context.Context, request *models.APIKeyRequest) (*models.IssuedAPIKey, error) {
+	if request.TenantID != "" && request.TenantID != tenancy.From(ctx) {
+		principal, ok := PrincipalFrom(ctx)
+		if !ok || !principal.IsBootstrap() {
+			logging.From(ctx).Warn("api key can not be issued for tenant", "tenant_id", request.TenantID)
+			return &models.IssuedAPIKey{}, ce.APIKeyTenantNotAllowedError
+		}
+		ctx = tenancy.WithTenant(ctx, request.TenantID)
+	}
+
+	secret, err := utils.SecureRandomString(apiKeyLength)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while generating api key", "error", err)
+		return &models.IssuedAPIKey{}, err
+	}
+
+	key := models.APIKeyPrefix + secret
+	apiKey, err := as.repository.Create(ctx, models.NewAPIKey(utils.GetNewUUID(), strings.TrimSpace(request.Name), request.Role, key, time.Now().UTC()))
+	if err != nil {
+		logging.From(ctx).Error("error occurred while issuing api key", "name", request.Name, "error", err)
+		return &models.IssuedAPIKey{}, err
+	}
+	as.audit.Record(ctx, models.APIKeyIssued, models.APIKeyEntity, apiKey.ID, nil, apiKey)
+
+	return &models.IssuedAPIKey{APIKey: apiKey, Key: key}, nil
+}
+
+func (as *apiKeyService) List(ctx context.Context) (*models.APIKeys, error) {
+	apiKeys, err := as.repository.List(ctx)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing api keys", "error", err)
+		return &models.APIKeys{}, err
+	}
+
+	return &models.APIKeys{Keys: apiKeys}, nil
+}
+
+// Revoke stops the api key from authenticating requests.
+func (as *apiKeyService) Revoke(ctx context.Context, id string) (*models.APIKey, error) {
+	before, err := as.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("api key not found", "api_key_id", id)
+		return &models.APIKey{}, err
+	}
+
+	if before.RevokedAt != nil {
+		return before, nil
+	}
+
+	apiKey, err := as.repository.Revoke(ctx, id, time.Now().UTC())
+	if err != nil {
+		logging.From(ctx).Error("error occurred while revoking api key", "api_key_id", id, "error", err)
+		return &models.APIKey{}, err
+	}
+	as.audit.Record(ctx, models.APIKeyRevoked, models.APIKeyEntity, apiKey.ID, before, apiKey)
+
+	return apiKey, nil
+}
+
+// Authenticate returns the principal of the key, or ce.InvalidAPIKeyError
+// for unknown and revoked keys.
+func (as *apiKeyService) Authenticate(ctx context.Context, key string) (*models.Principal, error) {
+	if !strings.HasPrefix(key, models.APIKeyPrefix) {
+		return &models.Principal{}, ce.InvalidAPIKeyError
+	}
+
+	keyHash := models.HashAPIKey(key)
+	if as.bootstrapKeyHash != "" && subtle.ConstantTimeCompare([]byte(keyHash), []byte(as.bootstrapKeyHash)) == 1 {
+		return &models.Principal{APIKeyID: models.BootstrapPrincipalID, Name: models.BootstrapPrincipalID, Role: models.RoleFinanceAdmin, TenantID: tenancy.DefaultTenant}, nil
+	}
+
+	apiKey, err := as.repository.GetByHash(ctx, keyHash)
+	if errors.Is(err, ce.APIKeyNotFoundError) {
+		return &models.Principal{}, ce.InvalidAPIKeyError
+	}
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while authenticating api key", "error", err)
+		return &models.Principal{}, err
+	}
+
+	if apiKey.RevokedAt != nil {
+		logging.From(ctx).Warn("api key is revoked", "api_key_id", apiKey.ID)
+		return &models.Principal{}, ce.InvalidAPIKeyError
+	}
+
+	return &models.Principal{APIKeyID: apiKey.ID, Name: apiKey.Name, Role: apiKey.Role, TenantID: apiKey.TenantID}, nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyServiceTestSuite struct {
+	suite.Suite
+	MockRepo  *repository.MockAPIKeyRepository
+	AuditMock *AuditServiceMock
+	as        APIKeyService
+}
+
+func (suite *APIKeyServiceTestSuite) SetupTest() {
+	suite.MockRepo = new(repository.MockAPIKeyRepository)
+	suite.AuditMock = new(AuditServiceMock)
+	suite.AuditMock.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.as = NewAPIKeyService(suite.MockRepo, suite.AuditMock, models.HashAPIKey("bsk_bootstrap"))
+}
+
+func (suite *APIKeyServiceTestSuite) Test_IssueStoresHashOnly() {
+	ctx := context.Background()
+	suite.MockRepo.On("Create", ctx, mock.Anything).Return(&models.APIKey{ID: "key id"}, nil)
+
+	issued, err := suite.as.Issue(ctx, &models.APIKeyRequest{Name: " billing cron ", Role: models.RoleBiller})
+
+	suite.Require().Nil(err)
+	suite.Require().True(strings.HasPrefix(issued.Key, models.APIKeyPrefix))
+	suite.Require().Len(issued.Key, len(models.APIKeyPrefix)+2*apiKeyLength)
+
+	apiKey := suite.MockRepo.Calls[0].Arguments.Get(1).(*models.APIKey)
+	suite.Require().Equal("billing cron", apiKey.Name)
+	suite.Require().Equal(models.RoleBiller, apiKey.Role)
+	suite.Require().Equal(models.HashAPIKey(issued.Key), apiKey.KeyHash)
+	suite.Require().Equal(issued.Key[:12], apiKey.Prefix)
+	suite.AuditMock.AssertCalled(suite.T(), "Record", ctx, models.APIKeyIssued, models.APIKeyEntity, "key id", nil, mock.Anything)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_IssueForAnotherTenantFailsForTenantKeys() {
+	principal := &models.Principal{APIKeyID: "key id", TenantID: "tenant-a"}
+	ctx := tenancy.WithTenant(WithPrincipal(context.Background(), principal), principal.TenantID)
+
+	_, err := suite.as.Issue(ctx, &models.APIKeyRequest{Name: "billing cron", TenantID: "tenant-b"})
+
+	suite.Require().ErrorIs(err, ce.APIKeyTenantNotAllowedError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_IssueForAnotherTenantSucceedsForBootstrapKey() {
+	principal := &models.Principal{APIKeyID: models.BootstrapPrincipalID, TenantID: tenancy.DefaultTenant}
+	ctx := tenancy.WithTenant(WithPrincipal(context.Background(), principal), principal.TenantID)
+	suite.MockRepo.On("Create", mock.Anything, mock.Anything).Return(&models.APIKey{ID: "key id", TenantID: "tenant-b"}, nil)
+
+	issued, err := suite.as.Issue(ctx, &models.APIKeyRequest{Name: "billing cron", TenantID: "tenant-b"})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("tenant-b", issued.APIKey.TenantID)
+	suite.Require().Equal("tenant-b", tenancy.From(suite.MockRepo.Calls[0].Arguments.Get(0).(context.Context)))
+}
+
+func (suite *APIKeyServiceTestSuite) Test_AuthenticateResolvesPrincipal() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByHash", ctx, models.HashAPIKey("bsk_key")).Return(&models.APIKey{ID: "key id", Name: "billing cron", Role: models.RoleBiller, TenantID: "tenant-a"}, nil)
+
+	principal, err := suite.as.Authenticate(ctx, "bsk_key")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(&models.Principal{APIKeyID: "key id", Name: "billing cron", Role: models.RoleBiller, TenantID: "tenant-a"}, principal)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_AuthenticateRefusesRevokedKeys() {
+	ctx := context.Background()
+	revokedAt := time.Now().UTC()
+	suite.MockRepo.On("GetByHash", ctx, models.HashAPIKey("bsk_key")).Return(&models.APIKey{ID: "key id", RevokedAt: &revokedAt}, nil)
+
+	_, err := suite.as.Authenticate(ctx, "bsk_key")
+
+	suite.Require().ErrorIs(err, ce.InvalidAPIKeyError)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_AuthenticateRefusesUnknownKeys() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByHash", ctx, models.HashAPIKey("bsk_key")).Return(&models.APIKey{}, ce.APIKeyNotFoundError)
+
+	_, err := suite.as.Authenticate(ctx, "bsk_key")
+	suite.Require().ErrorIs(err, ce.InvalidAPIKeyError)
+
+	_, err = suite.as.Authenticate(ctx, "not a key")
+	suite.Require().ErrorIs(err, ce.InvalidAPIKeyError)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_AuthenticateReturnsRepositoryErrors() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByHash", ctx, models.HashAPIKey("bsk_key")).Return(&models.APIKey{}, errors.New("test error"))
+
+	_, err := suite.as.Authenticate(ctx, "bsk_key")
+
+	suite.Require().NotNil(err)
+	suite.Require().NotEqual(ce.InvalidAPIKeyError, err)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_AuthenticateAcceptsBootstrapKey() {
+	principal, err := suite.as.Authenticate(context.Background(), "bsk_bootstrap")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(models.BootstrapPrincipalID, principal.APIKeyID)
+	suite.Require().Equal(tenancy.DefaultTenant, principal.TenantID)
+	suite.Require().Equal(models.RoleFinanceAdmin, principal.Role)
+	suite.MockRepo.AssertNotCalled(suite.T(), "GetByHash", mock.Anything, mock.Anything)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_RevokeRecordsAuditEvent() {
+	ctx := context.Background()
+	revokedAt := time.Now().UTC()
+	suite.MockRepo.On("GetByID", ctx, "key id").Return(&models.APIKey{ID: "key id"}, nil)
+	suite.MockRepo.On("Revoke", ctx, "key id", mock.Anything).Return(&models.APIKey{ID: "key id", RevokedAt: &revokedAt}, nil)
+
+	apiKey, err := suite.as.Revoke(ctx, "key id")
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(apiKey.RevokedAt)
+	suite.AuditMock.AssertCalled(suite.T(), "Record", ctx, models.APIKeyRevoked, models.APIKeyEntity, "key id", mock.Anything, mock.Anything)
+}
+
+func (suite *APIKeyServiceTestSuite) Test_PrincipalFromContext() {
+	_, ok := PrincipalFrom(context.Background())
+	suite.Require().False(ok)
+
+	principal, ok := PrincipalFrom(WithPrincipal(context.Background(), &models.Principal{APIKeyID: "key id"}))
+	suite.Require().True(ok)
+	suite.Require().Equal("api_key:key id", principal.Actor())
+}
+
+func TestAPIKeyServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyServiceTestSuite))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+// SystemActor is the actor of changes made outside of an api request, such
+// as by workflows.
+const SystemActor = "system"
+
+type requestIDContextKey struct{}
+
+// AuditContext identifies who made a change and in which request.
+type AuditContext struct {
+	Actor     string
+	RequestID string
+}
+
+// WithRequestID returns a copy of ctx carrying the id of the api request
+// recorded with the changes made under it.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// AuditContextFrom returns the audit context of ctx. The actor is the
+// principal of ctx, the system actor when there is none.
+func AuditContextFrom(ctx context.Context) AuditContext {
+	auditContext := AuditContext{Actor: SystemActor}
+	if principal, ok := PrincipalFrom(ctx); ok {
+		auditContext.Actor = principal.Actor()
+	}
+	auditContext.RequestID, _ = ctx.Value(requestIDContextKey{}).(string)
+	return auditContext
+}
+
+type auditService struct {
+	repository repository.AuditRepository
+}
+
+type AuditService interface {
+	Record(ctx context.Context, action string, entityType string, entityID string, before any, after any)
+	List(context.Context, *models.ListAuditEventsRequest) (*models.AuditEventList, error)
+}
+
+func NewAuditService(repository repository.AuditRepository) AuditService {
+	return &auditService{
+		repository: repository,
+	}
+}
+
+// Record appends the change to the audit log. before and after are the entity
+// before and after the change, nil when it did not exist. The change has
+// already been made, so a failure to record it is logged and not returned.
+func (as *auditService) Record(ctx context.Context, action string, entityType string, entityID string, before any, after any) {
+	auditContext := AuditContextFrom(ctx)
+	event := &models.AuditEvent{
+		ID:         utils.GetNewUUID(),
+		Actor:      auditContext.Actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     snapshot(before),
+		After:      snapshot(after),
+		RequestID:  auditContext.RequestID,
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	_, err := as.repository.Create(ctx, event)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while recording audit event", "action", action, "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+// snapshot returns the entity as JSON, empty for nil.
+func snapshot(entity any) string {
+	if entity == nil {
+		return ""
+	}
+
+	value, err := json.Marshal(entity)
+	if err != nil {
+		slog.Error("error occurred while encoding audit snapshot", "entity_type", fmt.Sprintf("%T", entity), "error", err)
+		return ""
+	}
+	return string(value)
+}
+
+func (as *auditService) List(ctx context.Context, request *models.ListAuditEventsRequest) (*models.AuditEventList, error) {
+	events, total, err := as.repository.List(ctx, request)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing audit events", "entity_type", request.EntityType, "error", err)
+		return &models.AuditEventList{}, err
+	}
+
+	return &models.AuditEventList{Events: events, Total: total}, nil
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuditServiceTestSuite struct {
+	suite.Suite
+	MockRepo *repository.MockAuditRepository
+	as       AuditService
+}
+
+func (suite *AuditServiceTestSuite) SetupTest() {
+	suite.MockRepo = new(repository.MockAuditRepository)
+	suite.as = NewAuditService(suite.MockRepo)
+}
+
+func (suite *AuditServiceTestSuite) Test_RecordCapturesActorRequestAndSnapshots() {
+	ctx := WithRequestID(WithPrincipal(context.Background(), &models.Principal{APIKeyID: "key id", Name: "jane"}), "request-01")
+	suite.MockRepo.On("Create", ctx, mock.Anything).Return(&models.AuditEvent{}, nil)
+	before := models.LineItem{ID: "item id", Amount: 10}
+	after := models.LineItem{ID: "item id", Amount: 10, Removed: true}
+
+	suite.as.Record(ctx, models.LineItemRemoved, models.LineItemEntity, "item id", before, after)
+
+	event := suite.MockRepo.Calls[0].Arguments.Get(1).(*models.AuditEvent)
+	suite.Require().NotEmpty(event.ID)
+	suite.Require().Equal("api_key:key id", event.Actor)
+	suite.Require().Equal("request-01", event.RequestID)
+	suite.Require().Equal(models.LineItemRemoved, event.Action)
+	suite.Require().Equal(models.LineItemEntity, event.EntityType)
+	suite.Require().Equal("item id", event.EntityID)
+	suite.Require().Contains(event.Before, `"Removed":false`)
+	suite.Require().Contains(event.After, `"Removed":true`)
+}
+
+func (suite *AuditServiceTestSuite) Test_RecordDefaultsToSystemActorAndEmptySnapshots() {
+	ctx := context.Background()
+	suite.MockRepo.On("Create", ctx, mock.Anything).Return(&models.AuditEvent{}, nil)
+
+	suite.as.Record(ctx, models.CurrencyDeleted, models.CurrencyEntity, "currency id", &models.Currency{ID: "currency id"}, nil)
+
+	event := suite.MockRepo.Calls[0].Arguments.Get(1).(*models.AuditEvent)
+	suite.Require().Equal(SystemActor, event.Actor)
+	suite.Require().Empty(event.RequestID)
+	suite.Require().NotEmpty(event.Before)
+	suite.Require().Empty(event.After)
+}
+
+func (suite *AuditServiceTestSuite) Test_RecordDoesNotFailWhenErrorIsOccurred() {
+	ctx := context.Background()
+	suite.MockRepo.On("Create", ctx, mock.Anything).Return(&models.AuditEvent{}, errors.New("test error"))
+
+	suite.NotPanics(func() {
+		suite.as.Record(ctx, models.BillCreated, models.BillEntity, "bill id", nil, &models.Bill{ID: "bill id"})
+	})
+}
+
+func (suite *AuditServiceTestSuite) Test_List() {
+	ctx := context.Background()
+	request := &models.ListAuditEventsRequest{EntityType: models.LineItemEntity, EntityID: "item id"}
+	events := []*models.AuditEvent{{ID: "event id", EntityType: models.LineItemEntity, EntityID: "item id"}}
+	suite.MockRepo.On("List", ctx, request).Return(events, int64(1), nil)
+
+	list, err := suite.as.List(ctx, request)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(events, list.Events)
+	suite.Require().Equal(int64(1), list.Total)
+}
+
+func TestAuditServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditServiceTestSuite))
+}
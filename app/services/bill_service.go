@@ -3,7 +3,6 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
@@ -11,6 +10,9 @@ import (
 	tc "github.com/asheet-bhaskar/billing-service/app/workflows/temporal"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
 	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 	"go.temporal.io/sdk/client"
 )
@@ -19,6 +21,12 @@ type billService struct {
 	repository         repository.BillRepository
 	currencyRepository repository.CurrencyRepository
 	customerRepository repository.CustomerRepository
+	catalogRepository  repository.CatalogRepository
+	exchangeRates      ExchangeRateService
+	ledger             LedgerService
+	audit              AuditService
+	webhooks           WebhookService
+	events             EventPublisher
 	temporalClient     tc.TemporalClient
 }
 
@@ -28,61 +36,109 @@ type BillService interface {
 	AddLineItems(context.Context, *models.LineItem) (*models.LineItem, error)
 	RemoveLineItems(context.Context, string, string) (*models.LineItem, error)
 	Close(context.Context, string) (*models.Bill, error)
-	Invoice(ctx context.Context, billID string) (*models.Invoice, error)
+	Invoice(ctx context.Context, billID string, currencyCode string) (*models.Invoice, error)
+	RecordPayment(context.Context, string, *models.PaymentRequest) (*models.JournalEntry, error)
+	Credit(context.Context, string, *models.CreditRequest) (*models.JournalEntry, error)
+	CountOpen(context.Context) (int64, error)
 }
 
 func NewBillService(repository repository.BillRepository, currencyRepository repository.CurrencyRepository,
-	customerRepository repository.CustomerRepository, temporalClient tc.TemporalClient) BillService {
+	customerRepository repository.CustomerRepository, catalogRepository repository.CatalogRepository,
+	exchangeRates ExchangeRateService, ledger LedgerService, audit AuditService, webhooks WebhookService,
+	events EventPublisher, temporalClient tc.TemporalClient) BillService {
 	return &billService{
 		repository:         repository,
 		currencyRepository: currencyRepository,
 		customerRepository: customerRepository,
+		catalogRepository:  catalogRepository,
+		exchangeRates:      exchangeRates,
+		ledger:             ledger,
+		audit:              audit,
+		webhooks:           webhooks,
+		events:             events,
 		temporalClient:     temporalClient,
 	}
 }
 
+// Create opens a bill for the customer. Bills without a currency or payment
+// terms use the defaults of the customer.
 func (bs *billService) Create(ctx context.Context, request *models.BillRequest) (*models.Bill, error) {
-	currency, err := bs.currencyRepository.GetByCode(ctx, request.CurrencyCode)
+	customer, err := bs.customerRepository.GetByID(ctx, request.CustomerID)
+
 	if err != nil {
-		log.Printf("error while finding the currency for code %s\n", request.CurrencyCode)
+		logging.From(ctx).Error("error while finding the customer", "customer_id", request.CustomerID, "error", err)
 		return &models.Bill{}, err
 	}
 
-	customer, err := bs.customerRepository.GetByID(ctx, request.CustomerID)
+	if customer.IsDeleted() {
+		logging.From(ctx).Warn("customer is deleted", "customer_id", request.CustomerID)
+		return &models.Bill{}, ce.CustomerNotFoundError.WithID(request.CustomerID)
+	}
+
+	currencyCode := request.CurrencyCode
+	if currencyCode == "" {
+		currencyCode = customer.DefaultCurrency
+	}
+
+	if currencyCode == "" {
+		logging.From(ctx).Info("no currency given and customer has no default currency", "customer_id", customer.ID)
+		return &models.Bill{}, ce.BillCurrencyRequiredError.WithID(customer.ID)
+	}
 
+	currency, err := bs.currencyRepository.GetByCode(ctx, currencyCode)
 	if err != nil {
-		log.Printf("error while finding the customer for id %s\n", request.CustomerID)
+		logging.From(ctx).Error("error while finding the currency", "currency_code", currencyCode, "error", err)
 		return &models.Bill{}, err
 	}
+
+	if !currency.Active {
+		logging.From(ctx).Warn("currency is inactive", "currency_code", currencyCode)
+		return &models.Bill{}, ce.CurrencyInactiveError.WithID(currencyCode)
+	}
+
+	paymentTerms := request.PaymentTerms
+	if paymentTerms == "" {
+		paymentTerms = customer.PaymentTerms
+	}
+	if paymentTerms == "" {
+		paymentTerms = models.DueOnReceipt
+	}
+
 	bill := &models.Bill{
-		ID:          utils.GetNewUUID(),
-		Description: request.Description,
-		CustomerID:  customer.ID,
-		CurrencyID:  currency.ID,
-		Status:      "open",
-		TotalAmount: 0.0,
-		PeriodStart: request.PeriodStart,
-		PeriodEnd:   request.PeriodEnd,
-		CreatedAt:   time.Now().UTC(),
-		UpdatedAt:   time.Now().UTC(),
+		ID:           utils.GetNewUUID(),
+		Description:  request.Description,
+		CustomerID:   customer.ID,
+		CurrencyID:   currency.ID,
+		Status:       "open",
+		TotalAmount:  0.0,
+		PaymentTerms: paymentTerms,
+		PeriodStart:  request.PeriodStart,
+		PeriodEnd:    request.PeriodEnd,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
 	}
 
 	bill, err = bs.repository.Create(ctx, bill)
 	if err != nil {
-		log.Printf("error occured while creating bill. error %s\n", err.Error())
+		logging.From(ctx).Error("error occurred while creating bill", "error", err)
 		return &models.Bill{}, err
 	}
+	bs.audit.Record(ctx, models.BillCreated, models.BillEntity, bill.ID, nil, bill)
+	bs.webhooks.Publish(ctx, models.BillCreated, bill.ID, bill)
+	bs.events.PublishBill(ctx, models.BillCreated, bill)
 
-	workflowID := fmt.Sprintf("BILL-%s", bill.ID)
+	workflowID := tenancy.WorkflowID(ctx, fmt.Sprintf("BILL-%s", bill.ID))
 	options := client.StartWorkflowOptions{
 		ID:        workflowID,
 		TaskQueue: "CREATE_BILL_QUEUE",
 	}
 
-	_, err = bs.temporalClient.ExecuteWorkflow(context.Background(), options, workflows.BillingWorkflow, bill)
+	_, err = bs.temporalClient.ExecuteWorkflow(tenancy.Detach(ctx), options, workflows.BillingWorkflow, bill)
 	if err != nil {
-		log.Printf("failed to create workflow execution for bill id %s", bill.ID)
+		logging.From(ctx).Error("failed to create workflow execution for bill", "bill_id", bill.ID, "error", err)
+		metrics.WorkflowStartFailures.Inc("BillingWorkflow")
 	}
+	metrics.BillsCreated.Inc()
 
 	return bill, nil
 }
@@ -90,137 +146,453 @@ func (bs *billService) Create(ctx context.Context, request *models.BillRequest)
 func (bs *billService) GetByID(ctx context.Context, id string) (*models.Bill, error) {
 	bill, err := bs.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("error occured while fetching bill with id %s. error %s\n", id, err.Error())
+		logging.From(ctx).Error("error occurred while fetching bill", "bill_id", id, "error", err)
 		return &models.Bill{}, err
 	}
 
 	return bill, nil
 }
 
-func (bs *billService) AddLineItems(ctx context.Context, lineItem *models.LineItem) (*models.LineItem, error) {
-	bill, err := bs.repository.GetByID(ctx, lineItem.BillID)
-	if err == ce.BillNotFoundError || err != nil {
-		log.Printf("bill not found for id %s\n", lineItem.BillID)
-		return lineItem, err
-	}
-
-	if bill.Status == "closed" {
-		log.Printf("bill is already closed for id %s\n", lineItem.BillID)
-		return lineItem, ce.BillClosedError
+// CountOpen counts the open bills of all tenants.
+func (bs *billService) CountOpen(ctx context.Context) (int64, error) {
+	count, err := bs.repository.CountOpen(ctx)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while counting open bills", "error", err)
+		return 0, err
 	}
 
-	lineItem.ID = utils.GetNewUUID()
-	lineItem, err = bs.repository.AddLineItems(ctx, lineItem)
+	return count, nil
+}
 
+// AddLineItems adds the line item to the open bill. The line item and its
+// journal entry are written in one transaction, with the bill locked so it is
+// not closed meanwhile.
+func (bs *billService) AddLineItems(ctx context.Context, lineItem *models.LineItem) (*models.LineItem, error) {
+	bill := &models.Bill{}
+	err := bs.repository.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		bill, err = bs.repository.GetByIDForUpdate(ctx, lineItem.BillID)
+		if err != nil {
+			logging.From(ctx).Warn("bill not found", "bill_id", lineItem.BillID)
+			return err
+		}
+
+		if bill.Status == "closed" {
+			logging.From(ctx).Warn("bill is already closed", "bill_id", lineItem.BillID)
+			return ce.BillClosedError.WithID(bill.ID)
+		}
+
+		currency, err := bs.currencyRepository.GetByID(ctx, bill.CurrencyID)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching currency for bill", "bill_id", bill.ID, "error", err)
+			return err
+		}
+
+		if lineItem.PriceID != "" {
+			lineItem, err = bs.priceLineItem(ctx, bill, currency, lineItem)
+			if err != nil {
+				logging.From(ctx).Error("error while pricing line item for price", "price_id", lineItem.PriceID, "error", err)
+				return err
+			}
+		}
+		lineItem.Amount = currency.Round(lineItem.Amount)
+
+		lineItem.ID = utils.GetNewUUID()
+		lineItem, err = bs.repository.AddLineItems(ctx, lineItem)
+		if err != nil {
+			logging.From(ctx).Error("error while adding line item", "line_item_id", lineItem.ID, "error", err)
+			return err
+		}
+
+		_, err = bs.ledger.Post(ctx, lineItemTransfer(models.JournalLineItemAdded, bill, currency, lineItem))
+		if err != nil {
+			logging.From(ctx).Error("error while posting line item to the ledger", "line_item_id", lineItem.ID, "error", err)
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		log.Printf("error while adding line item %v. error is %s\n", lineItem, err.Error())
 		return lineItem, err
 	}
 
+	bs.audit.Record(ctx, models.LineItemAdded, models.LineItemEntity, lineItem.ID, nil, lineItem)
+	metrics.LineItemsAdded.Inc()
+	bs.webhooks.Publish(ctx, models.LineItemAdded, bill.ID, lineItem)
+	bs.events.PublishLineItem(ctx, models.LineItemAdded, lineItem)
+
 	signal := workflows.LineItemSignal{
 		BillID: bill.ID,
 		ItemID: lineItem.ID,
 	}
 
-	err = bs.temporalClient.SignalWorkflow(context.Background(), fmt.Sprintf("BILL-%s", bill.ID), "", "ADD_BILL_ITEM_CHANNEL", signal)
+	err = bs.temporalClient.SignalWorkflow(tenancy.Detach(ctx), tenancy.WorkflowID(ctx, fmt.Sprintf("BILL-%s", bill.ID)), "", "ADD_BILL_ITEM_CHANNEL", signal)
 	if err != nil {
-		log.Println("Error while signalling the workflow", err)
+		logging.From(ctx).Error("error while signalling the workflow", "error", err)
+		metrics.WorkflowSignalFailures.Inc("ADD_BILL_ITEM_CHANNEL")
 	}
 
 	return lineItem, nil
 }
 
-func (bs *billService) RemoveLineItems(ctx context.Context, billID string, itemID string) (*models.LineItem, error) {
-	lineItem, err := bs.repository.GetLineItemByID(ctx, itemID)
+// priceLineItem fills in the amount of a line item referencing a catalog price,
+// priced in the minor units of the currency of the bill, and its description
+// when none was given.
+func (bs *billService) priceLineItem(ctx context.Context, bill *models.Bill, currency *models.Currency, lineItem *models.LineItem) (*models.LineItem, error) {
+	price, err := bs.catalogRepository.GetPriceByID(ctx, lineItem.PriceID)
+	if err != nil {
+		return lineItem, err
+	}
 
-	if err == ce.LineItemNotFoundError || err != nil {
-		log.Printf("line item not found for id %s\n", itemID)
-		return &models.LineItem{}, err
+	if !price.Active {
+		return lineItem, ce.PriceArchivedError.WithID(price.ID)
 	}
 
-	if lineItem.Removed {
-		log.Printf("line item already for id %s\n", itemID)
-		return &models.LineItem{}, ce.LineItemAlreadyRemovedError
+	if price.CurrencyID != bill.CurrencyID {
+		return lineItem, ce.PriceCurrencyMismatchError.WithID(price.ID)
 	}
 
-	bill, err := bs.repository.GetByID(ctx, billID)
+	plan, err := bs.catalogRepository.GetPlanByID(ctx, price.PlanID)
+	if err != nil {
+		return lineItem, err
+	}
 
-	if err == ce.BillNotFoundError || err != nil {
-		log.Printf("bill not found for id %s\n", billID)
-		return &models.LineItem{}, err
+	if !plan.Active {
+		return lineItem, ce.PlanArchivedError.WithID(plan.ID)
+	}
+
+	product, err := bs.catalogRepository.GetProductByID(ctx, plan.ProductID)
+	if err != nil {
+		return lineItem, err
+	}
+
+	if !product.Active {
+		return lineItem, ce.ProductArchivedError.WithID(product.ID)
 	}
 
-	if bill.Status == "closed" {
-		log.Printf("bill is already closed for id %s\n", lineItem.BillID)
-		return lineItem, ce.BillClosedError
+	calculation := models.CalculatePrice(price, lineItem.Quantity, currency.MinorUnits)
+	lineItem.Amount = calculation.Amount
+	lineItem.PricingDetails = calculation.Explanation
+	if lineItem.Description == "" {
+		lineItem.Description = fmt.Sprintf("%s - %s", product.Name, plan.Name)
 	}
 
-	lineItemUpdated, err := bs.repository.RemoveLineItems(ctx, lineItem)
+	return lineItem, nil
+}
 
+// RemoveLineItems removes the line item from the open bill. The removal and
+// its journal entry are written in one transaction, with the bill locked so it
+// is not closed meanwhile.
+func (bs *billService) RemoveLineItems(ctx context.Context, billID string, itemID string) (*models.LineItem, error) {
+	bill := &models.Bill{}
+	before := models.LineItem{}
+	lineItemUpdated := &models.LineItem{}
+	err := bs.repository.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		bill, err = bs.repository.GetByIDForUpdate(ctx, billID)
+		if err != nil {
+			logging.From(ctx).Warn("bill not found", "bill_id", billID)
+			return err
+		}
+
+		lineItem, err := bs.repository.GetLineItemByID(ctx, itemID)
+		if err != nil {
+			logging.From(ctx).Warn("line item not found", "line_item_id", itemID)
+			return err
+		}
+
+		if lineItem.BillID != bill.ID {
+			logging.From(ctx).Warn("line item is not on the bill", "bill_id", billID, "line_item_id", itemID)
+			return ce.LineItemNotFoundError.WithID(itemID)
+		}
+
+		if lineItem.Removed {
+			logging.From(ctx).Warn("line item already removed", "line_item_id", itemID)
+			return ce.LineItemAlreadyRemovedError.WithID(itemID)
+		}
+
+		if bill.Status == "closed" {
+			logging.From(ctx).Warn("bill is already closed", "bill_id", billID)
+			return ce.BillClosedError.WithID(bill.ID)
+		}
+
+		currency, err := bs.currencyRepository.GetByID(ctx, bill.CurrencyID)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching currency for bill", "bill_id", bill.ID, "error", err)
+			return err
+		}
+
+		before = *lineItem
+		lineItemUpdated, err = bs.repository.RemoveLineItems(ctx, lineItem)
+		if err != nil {
+			logging.From(ctx).Error("error while removing line item", "line_item_id", lineItem.ID, "error", err)
+			return err
+		}
+
+		_, err = bs.ledger.Post(ctx, lineItemTransfer(models.JournalLineItemRemoved, bill, currency, lineItemUpdated))
+		if err != nil {
+			logging.From(ctx).Error("error while posting removal of line item to the ledger", "line_item_id", lineItem.ID, "error", err)
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		log.Printf("error while removing line item %v. error is %s\n", lineItem, err.Error())
-		return lineItemUpdated, err
+		return &models.LineItem{}, err
 	}
 
+	bs.audit.Record(ctx, models.LineItemRemoved, models.LineItemEntity, lineItemUpdated.ID, before, lineItemUpdated)
+	metrics.LineItemsRemoved.Inc()
+	bs.webhooks.Publish(ctx, models.LineItemRemoved, bill.ID, lineItemUpdated)
+	bs.events.PublishLineItem(ctx, models.LineItemRemoved, lineItemUpdated)
+
 	signal := workflows.LineItemSignal{
 		BillID: bill.ID,
 		ItemID: lineItemUpdated.ID,
 	}
 
-	err = bs.temporalClient.SignalWorkflow(context.Background(), fmt.Sprintf("BILL-%s", bill.ID), "", "REMOVE_BILL_ITEM_CHANNEL", signal)
+	err = bs.temporalClient.SignalWorkflow(tenancy.Detach(ctx), tenancy.WorkflowID(ctx, fmt.Sprintf("BILL-%s", bill.ID)), "", "REMOVE_BILL_ITEM_CHANNEL", signal)
 	if err != nil {
-		log.Println("Error while signalling the workflow", err)
+		logging.From(ctx).Error("error while signalling the workflow", "error", err)
+		metrics.WorkflowSignalFailures.Inc("REMOVE_BILL_ITEM_CHANNEL")
 	}
 
 	return lineItemUpdated, nil
 }
 
-func (bs *billService) Close(ctx context.Context, billID string) (*models.Bill, error) {
-	bill, err := bs.repository.GetByID(ctx, billID)
+func lineItemTransfer(kind string, bill *models.Bill, currency *models.Currency, lineItem *models.LineItem) *models.LedgerTransfer {
+	return &models.LedgerTransfer{
+		Kind:         kind,
+		ReferenceID:  lineItem.ID,
+		BillID:       bill.ID,
+		CustomerID:   bill.CustomerID,
+		Description:  lineItem.Description,
+		CurrencyCode: currency.Code,
+		Amount:       lineItem.Amount,
+	}
+}
 
-	if err == ce.BillNotFoundError || err != nil {
-		log.Printf("bill not found for id %s\n", billID)
+// Close closes the bill and sets its due date from its payment terms. The
+// unbilled balance of the bill moves to the receivable account of the
+// customer and becomes the bill total, in one transaction with the bill
+// locked so no line item is added meanwhile. Webhook endpoints are sent the
+// closed bill and then its final invoice.
+func (bs *billService) Close(ctx context.Context, billID string) (*models.Bill, error) {
+	bill := &models.Bill{}
+	before := models.Bill{}
+	err := bs.repository.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		bill, err = bs.repository.GetByIDForUpdate(ctx, billID)
+		if err != nil {
+			logging.From(ctx).Warn("bill not found", "bill_id", billID)
+			return err
+		}
+
+		if bill.Status == "closed" {
+			logging.From(ctx).Warn("bill is already closed", "bill_id", billID)
+			return ce.BillClosedError.WithID(billID)
+		}
+
+		currency, err := bs.currencyRepository.GetByID(ctx, bill.CurrencyID)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching currency for bill", "bill_id", billID, "error", err)
+			return err
+		}
+
+		total, err := bs.ledger.BillBalance(ctx, billID, models.UnbilledAccount)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching unbilled balance of bill", "bill_id", billID, "error", err)
+			return err
+		}
+		total = currency.Round(total)
+
+		_, err = bs.ledger.Post(ctx, &models.LedgerTransfer{
+			Kind:         models.JournalBillClosed,
+			ReferenceID:  bill.ID,
+			BillID:       bill.ID,
+			CustomerID:   bill.CustomerID,
+			Description:  bill.Description,
+			CurrencyCode: currency.Code,
+			Amount:       total,
+		})
+		if err != nil {
+			logging.From(ctx).Error("error while posting close of bill to the ledger", "bill_id", billID, "error", err)
+			return err
+		}
+
+		err = bs.repository.UpdateBillAmount(ctx, billID, total)
+		if err != nil {
+			logging.From(ctx).Error("error while updating total of bill", "bill_id", billID, "error", err)
+			return err
+		}
+
+		before = *bill
+		closedAt := time.Now().UTC()
+		bill, err = bs.repository.Close(ctx, billID, closedAt, models.DueDate(bill.PaymentTerms, closedAt))
+		if err != nil {
+			logging.From(ctx).Error("error while closing bill", "bill_id", billID, "error", err)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
 		return bill, err
 	}
 
-	if bill.Status == "closed" {
-		log.Printf("bill is already closed for id %s\n", billID)
-		return bill, ce.BillClosedError
+	bs.audit.Record(ctx, models.BillClosed, models.BillEntity, bill.ID, before, bill)
+	metrics.BillsClosed.Inc()
+	bs.webhooks.Publish(ctx, models.BillClosed, bill.ID, bill)
+	bs.events.PublishBill(ctx, models.BillClosed, bill)
+
+	invoice, err := bs.Invoice(ctx, bill.ID, "")
+	if err != nil {
+		logging.From(ctx).Error("error while finalizing invoice of bill", "bill_id", billID, "error", err)
+		return bill, nil
+	}
+	bs.webhooks.Publish(ctx, models.InvoiceFinalized, bill.ID, invoice)
+
+	return bill, nil
+}
+
+// RecordPayment records a payment of what is owed on the closed bill.
+func (bs *billService) RecordPayment(ctx context.Context, billID string, request *models.PaymentRequest) (*models.JournalEntry, error) {
+	referenceID := request.Reference
+	description := fmt.Sprintf("Payment %s", request.Reference)
+	if referenceID == "" {
+		referenceID = utils.GetNewUUID()
+		description = "Payment"
 	}
 
-	bill, err = bs.repository.Close(ctx, billID)
+	return bs.settle(ctx, billID, models.JournalPayment, referenceID, description, request.Amount)
+}
 
+// Credit credits part or all of what is owed on the closed bill.
+func (bs *billService) Credit(ctx context.Context, billID string, request *models.CreditRequest) (*models.JournalEntry, error) {
+	return bs.settle(ctx, billID, models.JournalCredit, utils.GetNewUUID(), request.Reason, request.Amount)
+}
+
+// settle posts a payment or credit against the receivable balance of the
+// closed bill. Bills are never settled beyond what is owed on them, the bill
+// is locked while the balance due is checked and posted against.
+func (bs *billService) settle(ctx context.Context, billID string, kind string, referenceID string, description string, amount float64) (*models.JournalEntry, error) {
+	bill := &models.Bill{}
+	entry := &models.JournalEntry{}
+	err := bs.repository.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		bill, err = bs.repository.GetByIDForUpdate(ctx, billID)
+		if err != nil {
+			logging.From(ctx).Warn("bill not found", "bill_id", billID)
+			return err
+		}
+
+		if bill.Status != "closed" {
+			logging.From(ctx).Warn("bill is not closed", "bill_id", billID)
+			return ce.BillNotClosedError.WithID(billID)
+		}
+
+		currency, err := bs.currencyRepository.GetByID(ctx, bill.CurrencyID)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching currency for bill", "bill_id", billID, "error", err)
+			return err
+		}
+
+		due, err := bs.ledger.BillBalance(ctx, billID, models.ReceivableAccount)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching balance due of bill", "bill_id", billID, "error", err)
+			return err
+		}
+
+		amount = currency.Round(amount)
+		if amount > currency.Round(due) {
+			logging.From(ctx).Warn("amount exceeds balance due of bill", "kind", kind, "amount", amount, "due", due, "bill_id", billID)
+			return ce.AmountExceedsBalanceDueError.WithID(billID)
+		}
+
+		entry, err = bs.ledger.Post(ctx, &models.LedgerTransfer{
+			Kind:         kind,
+			ReferenceID:  referenceID,
+			BillID:       bill.ID,
+			CustomerID:   bill.CustomerID,
+			Description:  description,
+			CurrencyCode: currency.Code,
+			Amount:       amount,
+		})
+		if err != nil {
+			logging.From(ctx).Error("error while posting to the ledger for bill", "kind", kind, "bill_id", billID, "error", err)
+			return err
+		}
+
+		return nil
+	})
 	if err != nil {
-		log.Printf("error while closing bill id %s. error is %s\n", billID, err.Error())
-		return bill, err
+		return &models.JournalEntry{}, err
 	}
 
-	return bill, nil
+	action := models.BillPaymentRecorded
+	if kind == models.JournalCredit {
+		action = models.BillCredited
+	}
+	bs.audit.Record(ctx, action, models.BillEntity, bill.ID, nil, entry)
+	bs.events.PublishBill(ctx, action, bill)
+
+	return entry, nil
 }
 
-func (bs *billService) Invoice(ctx context.Context, billID string) (*models.Invoice, error) {
+// Invoice returns the invoice of the bill. When currencyCode is set and differs
+// from the bill currency the amounts are converted with the exchange rate
+// effective at the end of the bill period, or now for a period that has not
+// ended yet.
+func (bs *billService) Invoice(ctx context.Context, billID string, currencyCode string) (*models.Invoice, error) {
 	invoice := &models.Invoice{}
 
 	bill, err := bs.repository.GetByID(ctx, billID)
 
-	if err == ce.BillNotFoundError || err != nil {
-		log.Printf("bill not found for id %s\n", billID)
+	if err != nil {
+		logging.From(ctx).Warn("bill not found", "bill_id", billID)
 		return invoice, err
 	}
 
 	currency, err := bs.currencyRepository.GetByID(ctx, bill.CurrencyID)
 	if err != nil {
-		log.Printf("error while fetching currency code for bill id %s\n", billID)
+		logging.From(ctx).Error("error while fetching currency code for bill", "bill_id", billID, "error", err)
 		return invoice, err
 	}
 
 	lineItems, err := bs.repository.GetLineItemsByBillID(ctx, bill.ID)
-	if err != nil || err != nil {
-		log.Printf("error while fetching line items for bill id %s\n", billID)
+	if err != nil {
+		logging.From(ctx).Error("error while fetching line items for bill", "bill_id", billID, "error", err)
 		return invoice, err
 	}
 
-	invoice = models.CreateInvoice(bill, lineItems, currency.Code)
+	customer, err := bs.customerRepository.GetByID(ctx, bill.CustomerID)
+	if err != nil {
+		logging.From(ctx).Error("error while fetching customer for bill", "bill_id", billID, "error", err)
+		return invoice, err
+	}
+
+	invoice = models.CreateInvoice(bill, lineItems, currency, customer)
+
+	if currencyCode == "" || currencyCode == currency.Code {
+		return invoice, nil
+	}
+
+	rateAt := bill.PeriodEnd
+	if now := time.Now().UTC(); rateAt.After(now) {
+		rateAt = now
+	}
+
+	presentmentCurrency, err := bs.currencyRepository.GetByCode(ctx, currencyCode)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the currency", "currency_code", currencyCode, "error", err)
+		return &models.Invoice{}, err
+	}
+
+	rate, err := bs.exchangeRates.GetRate(ctx, currency.Code, currencyCode, rateAt)
+	if err != nil {
+		logging.From(ctx).Error("error while fetching exchange rate for bill", "base", currency.Code, "quote", currencyCode, "bill_id", billID, "error", err)
+		return &models.Invoice{}, err
+	}
 
-	return invoice, nil
+	return models.ConvertInvoice(invoice, rate, presentmentCurrency), nil
 }
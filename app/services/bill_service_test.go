@@ -10,6 +10,7 @@ import (
 	tc "github.com/asheet-bhaskar/billing-service/app/workflows/temporal"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
 	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
@@ -20,6 +21,12 @@ type BillServiceTestSuite struct {
 	BillMockRepo       *repository.MockBillRepository
 	CustomerMockRepo   *repository.MockCustomerRepository
 	CurrencyMockRepo   *repository.MockCurrencyRepository
+	CatalogMockRepo    *repository.MockCatalogRepository
+	ExchangeRateMock   *ExchangeRateServiceMock
+	LedgerMock         *LedgerServiceMock
+	AuditMock          *AuditServiceMock
+	WebhookMock        *WebhookServiceMock
+	EventsMock         *EventPublisherMock
 	TemporalClientMock *tc.MockTemporalClient
 	bs                 BillService
 	billRequest        *models.BillRequest
@@ -33,14 +40,25 @@ func (suite *BillServiceTestSuite) SetupTest() {
 	billMockRepo := new(repository.MockBillRepository)
 	customerMockRepo := new(repository.MockCustomerRepository)
 	currencyMockRepo := new(repository.MockCurrencyRepository)
+	catalogMockRepo := new(repository.MockCatalogRepository)
 	temporalClientMock := new(tc.MockTemporalClient)
 
 	suite.BillMockRepo = billMockRepo
 	suite.CustomerMockRepo = customerMockRepo
 	suite.CurrencyMockRepo = currencyMockRepo
+	suite.CatalogMockRepo = catalogMockRepo
+	suite.ExchangeRateMock = new(ExchangeRateServiceMock)
+	suite.LedgerMock = new(LedgerServiceMock)
+	suite.AuditMock = new(AuditServiceMock)
+	suite.AuditMock.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.WebhookMock = new(WebhookServiceMock)
+	suite.WebhookMock.On("Publish", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.EventsMock = new(EventPublisherMock)
+	suite.EventsMock.On("PublishBill", mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.EventsMock.On("PublishLineItem", mock.Anything, mock.Anything, mock.Anything).Return()
 	suite.TemporalClientMock = temporalClientMock
 
-	suite.bs = NewBillService(billMockRepo, currencyMockRepo, customerMockRepo, temporalClientMock)
+	suite.bs = NewBillService(billMockRepo, currencyMockRepo, customerMockRepo, catalogMockRepo, suite.ExchangeRateMock, suite.LedgerMock, suite.AuditMock, suite.WebhookMock, suite.EventsMock, temporalClientMock)
 	currencyID := utils.GetNewUUID()
 	customerID := utils.GetNewUUID()
 
@@ -68,7 +86,7 @@ func (suite *BillServiceTestSuite) SetupTest() {
 
 func (suite *BillServiceTestSuite) Test_CreateBillReturnsErrorWhenFails() {
 	ctx := context.Background()
-	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID}, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID, Active: true}, nil)
 	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
 	suite.BillMockRepo.On("Create", ctx, mock.Anything).Return(&models.Bill{}, errors.New("test-error"))
 
@@ -81,14 +99,58 @@ func (suite *BillServiceTestSuite) Test_CreateBillReturnsErrorWhenFails() {
 func (suite *BillServiceTestSuite) Test_CreateBillReturnsNilErrorWhenSucceeds() {
 	ctx := context.Background()
 	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{}, nil)
-	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID}, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID, Active: true}, nil)
 	suite.BillMockRepo.On("Create", ctx, mock.Anything).Return(&models.Bill{}, nil)
 	suite.TemporalClientMock.On("ExecuteWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {})
+	created := metrics.BillsCreated.Value()
 
 	bill, err := suite.bs.Create(ctx, suite.billRequest)
 
 	suite.Require().Nil(err)
 	suite.Require().Equal(&models.Bill{}, bill)
+	suite.Require().Equal(created+1, metrics.BillsCreated.Value())
+}
+
+func (suite *BillServiceTestSuite) Test_CreateBillUsesCustomerDefaults() {
+	ctx := context.Background()
+	request := *suite.billRequest
+	request.CurrencyCode = ""
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).
+		Return(&models.Customer{ID: suite.customerID, DefaultCurrency: "EUR", PaymentTerms: models.Net30}, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{ID: suite.currencyID, Active: true}, nil)
+	suite.BillMockRepo.On("Create", ctx, mock.Anything).Return(&models.Bill{}, nil)
+	suite.TemporalClientMock.On("ExecuteWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {})
+
+	_, err := suite.bs.Create(ctx, &request)
+
+	suite.Require().Nil(err)
+	created := suite.BillMockRepo.Calls[0].Arguments.Get(1).(*models.Bill)
+	suite.Require().Equal(suite.currencyID, created.CurrencyID)
+	suite.Require().Equal(models.Net30, created.PaymentTerms)
+}
+
+func (suite *BillServiceTestSuite) Test_CreateBillFailsWhenCurrencyIsInactive() {
+	ctx := context.Background()
+	request := *suite.billRequest
+	request.CurrencyCode = ""
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID, DefaultCurrency: "EUR"}, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{ID: suite.currencyID, Code: "EUR", Active: false}, nil)
+
+	_, err := suite.bs.Create(ctx, &request)
+
+	suite.Require().ErrorIs(err, ce.CurrencyInactiveError)
+	suite.BillMockRepo.AssertNotCalled(suite.T(), "Create", ctx, mock.Anything)
+}
+
+func (suite *BillServiceTestSuite) Test_CreateBillFailsWhenNoCurrencyIsGiven() {
+	ctx := context.Background()
+	request := *suite.billRequest
+	request.CurrencyCode = ""
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
+
+	_, err := suite.bs.Create(ctx, &request)
+
+	suite.Require().ErrorIs(err, ce.BillCurrencyRequiredError)
 }
 
 func (suite *BillServiceTestSuite) Test_GetByIDReturnsErrorWhenFails() {
@@ -111,6 +173,25 @@ func (suite *BillServiceTestSuite) Test_GetByIDReturnsNilErrorWhenSucceeds() {
 	suite.Require().Equal(&models.Bill{}, bill)
 }
 
+func (suite *BillServiceTestSuite) Test_CountOpenReturnsErrorWhenFails() {
+	ctx := context.Background()
+	suite.BillMockRepo.On("CountOpen", ctx).Return(int64(0), errors.New("test-error"))
+
+	_, err := suite.bs.CountOpen(ctx)
+
+	suite.Require().NotNil(err)
+}
+
+func (suite *BillServiceTestSuite) Test_CountOpenReturnsCountWhenSucceeds() {
+	ctx := context.Background()
+	suite.BillMockRepo.On("CountOpen", ctx).Return(int64(3), nil)
+
+	count, err := suite.bs.CountOpen(ctx)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(int64(3), count)
+}
+
 func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenBillNotFound() {
 	lineItem := &models.LineItem{
 		ID:          utils.GetNewUUID(),
@@ -121,11 +202,11 @@ func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenBillNotFound() {
 		Removed:     false,
 	}
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Bill{}, ce.BillNotFoundError)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&models.Bill{}, ce.BillNotFoundError)
 
 	_, err := suite.bs.AddLineItems(ctx, lineItem)
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillNotFoundError, err)
+	suite.Require().ErrorIs(err, ce.BillNotFoundError)
 }
 
 func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenBillIsClosed() {
@@ -142,11 +223,11 @@ func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenBillIsClosed() {
 	bill.Status = "closed"
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&bill, nil)
 
 	_, err := suite.bs.AddLineItems(ctx, lineItem)
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillClosedError, err)
+	suite.Require().ErrorIs(err, ce.BillClosedError)
 }
 
 func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenErrorIsOccurred() {
@@ -161,7 +242,8 @@ func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenErrorIsOccurred() {
 
 	ctx := context.Background()
 	testError := errors.New("test-error")
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
 	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, testError)
 
 	_, err := suite.bs.AddLineItems(ctx, lineItem)
@@ -180,13 +262,165 @@ func (suite *BillServiceTestSuite) Test_AddLineItemSucceeds() {
 	}
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
 	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
 	suite.TemporalClientMock.On("SignalWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	added := metrics.LineItemsAdded.Value()
 
 	lineItemSaved, err := suite.bs.AddLineItems(ctx, lineItem)
 	suite.Require().Nil(err)
 	suite.Require().Equal(lineItem, lineItemSaved)
+	suite.Require().Equal(added+1, metrics.LineItemsAdded.Value())
+	transfer := suite.LedgerMock.Calls[0].Arguments.Get(1).(*models.LedgerTransfer)
+	suite.Require().Equal(models.JournalLineItemAdded, transfer.Kind)
+	suite.Require().Equal(lineItem.ID, transfer.ReferenceID)
+	suite.Require().Equal(100.0, transfer.Amount)
+	suite.WebhookMock.AssertCalled(suite.T(), "Publish", ctx, models.LineItemAdded, suite.bill.ID, lineItem)
+	suite.EventsMock.AssertCalled(suite.T(), "PublishLineItem", ctx, models.LineItemAdded, lineItem)
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenPostingFails() {
+	lineItem := &models.LineItem{
+		BillID:      suite.bill.ID,
+		Description: "line item 05",
+		Amount:      100.0,
+	}
+
+	ctx := context.Background()
+	testError := errors.New("test-error")
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, testError)
+	added := metrics.LineItemsAdded.Value()
+
+	_, err := suite.bs.AddLineItems(ctx, lineItem)
+
+	suite.Require().Equal(testError, err)
+	suite.Require().Equal(added, metrics.LineItemsAdded.Value())
+	suite.AuditMock.AssertNotCalled(suite.T(), "Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.TemporalClientMock.AssertNotCalled(suite.T(), "SignalWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemCountsSignalFailures() {
+	lineItem := &models.LineItem{
+		ID:          utils.GetNewUUID(),
+		BillID:      suite.bill.ID,
+		Description: "line item 03",
+		Amount:      100.0,
+	}
+
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+	suite.TemporalClientMock.On("SignalWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("workflow not found"))
+	failures := metrics.WorkflowSignalFailures.Value("ADD_BILL_ITEM_CHANNEL")
+
+	_, err := suite.bs.AddLineItems(ctx, lineItem)
+	suite.Require().Nil(err)
+	suite.Require().Equal(failures+1, metrics.WorkflowSignalFailures.Value("ADD_BILL_ITEM_CHANNEL"))
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemFillsAmountAndDescriptionFromPrice() {
+	lineItem := &models.LineItem{
+		BillID:   suite.bill.ID,
+		PriceID:  utils.GetNewUUID(),
+		Quantity: 3,
+	}
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, lineItem.PriceID).Return(&models.Price{ID: lineItem.PriceID, PlanID: "plan-id", CurrencyID: suite.currencyID, UnitAmount: 9.99, Active: true}, nil)
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, "plan-id").Return(&models.Plan{ID: "plan-id", ProductID: "product-id", Name: "pro", Active: true}, nil)
+	suite.CatalogMockRepo.On("GetProductByID", ctx, "product-id").Return(&models.Product{ID: "product-id", Name: "API", Active: true}, nil)
+	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+	suite.TemporalClientMock.On("SignalWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	lineItemSaved, err := suite.bs.AddLineItems(ctx, lineItem)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(29.97, lineItemSaved.Amount)
+	suite.Require().Equal("API - pro", lineItemSaved.Description)
+	suite.Require().Equal("3 x 9.99 = 29.97", lineItemSaved.PricingDetails)
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemPricesInCurrencyMinorUnits() {
+	lineItem := &models.LineItem{
+		BillID:   suite.bill.ID,
+		PriceID:  utils.GetNewUUID(),
+		Quantity: 2.5,
+	}
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "JPY", MinorUnits: 0}, nil)
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, lineItem.PriceID).Return(&models.Price{ID: lineItem.PriceID, PlanID: "plan-id", CurrencyID: suite.currencyID, UnitAmount: 125, Active: true}, nil)
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, "plan-id").Return(&models.Plan{ID: "plan-id", ProductID: "product-id", Name: "pro", Active: true}, nil)
+	suite.CatalogMockRepo.On("GetProductByID", ctx, "product-id").Return(&models.Product{ID: "product-id", Name: "API", Active: true}, nil)
+	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+	suite.TemporalClientMock.On("SignalWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	lineItemSaved, err := suite.bs.AddLineItems(ctx, lineItem)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(float64(313), lineItemSaved.Amount)
+	suite.Require().Equal("2.5 x 125 = 313", lineItemSaved.PricingDetails)
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenPriceCurrencyDiffers() {
+	lineItem := &models.LineItem{
+		BillID:   suite.bill.ID,
+		PriceID:  utils.GetNewUUID(),
+		Quantity: 1,
+	}
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, lineItem.PriceID).Return(&models.Price{ID: lineItem.PriceID, CurrencyID: utils.GetNewUUID(), UnitAmount: 9.99, Active: true}, nil)
+
+	_, err := suite.bs.AddLineItems(ctx, lineItem)
+
+	suite.Require().ErrorIs(err, ce.PriceCurrencyMismatchError)
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemFailsWhenPriceIsArchived() {
+	lineItem := &models.LineItem{
+		BillID:   suite.bill.ID,
+		PriceID:  utils.GetNewUUID(),
+		Quantity: 1,
+	}
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, lineItem.PriceID).Return(&models.Price{ID: lineItem.PriceID, CurrencyID: suite.currencyID, Active: false}, nil)
+
+	_, err := suite.bs.AddLineItems(ctx, lineItem)
+
+	suite.Require().ErrorIs(err, ce.PriceArchivedError)
+}
+
+func (suite *BillServiceTestSuite) Test_AddLineItemRoundsAmountToCurrencyMinorUnits() {
+	lineItem := &models.LineItem{
+		BillID:      suite.bill.ID,
+		Description: "line item 04",
+		Amount:      1234.5,
+	}
+
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "JPY", MinorUnits: 0}, nil)
+	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+	suite.TemporalClientMock.On("SignalWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	lineItemSaved, err := suite.bs.AddLineItems(ctx, lineItem)
+	suite.Require().Nil(err)
+	suite.Require().Equal(float64(1235), lineItemSaved.Amount)
 }
 
 func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenBillNotFound() {
@@ -199,12 +433,12 @@ func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenBillNotFound() {
 		Removed:     false,
 	}
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Bill{}, ce.BillNotFoundError)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&models.Bill{}, ce.BillNotFoundError)
 	suite.BillMockRepo.On("GetLineItemByID", ctx, mock.Anything).Return(lineItem, nil)
 
 	_, err := suite.bs.RemoveLineItems(ctx, "", lineItem.ID)
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillNotFoundError, err)
+	suite.Require().ErrorIs(err, ce.BillNotFoundError)
 }
 
 func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenBillIsClosed() {
@@ -221,12 +455,32 @@ func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenBillIsClosed() {
 	bill.Status = "closed"
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&bill, nil)
 	suite.BillMockRepo.On("GetLineItemByID", ctx, mock.Anything).Return(lineItem, nil)
 
 	_, err := suite.bs.RemoveLineItems(ctx, "", lineItem.ID)
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillClosedError, err)
+	suite.Require().ErrorIs(err, ce.BillClosedError)
+}
+
+func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenItemIsOnAnotherBill() {
+	lineItem := &models.LineItem{
+		ID:          utils.GetNewUUID(),
+		BillID:      utils.GetNewUUID(),
+		Description: "line item 02",
+		Amount:      100.0,
+		CreatedAt:   time.Now().UTC(),
+		Removed:     false,
+	}
+
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, suite.bill.ID).Return(suite.bill, nil)
+	suite.BillMockRepo.On("GetLineItemByID", ctx, lineItem.ID).Return(lineItem, nil)
+
+	_, err := suite.bs.RemoveLineItems(ctx, suite.bill.ID, lineItem.ID)
+	suite.Require().ErrorIs(err, ce.LineItemNotFoundError)
+	suite.BillMockRepo.AssertNotCalled(suite.T(), "RemoveLineItems", ctx, mock.Anything)
+	suite.LedgerMock.AssertNotCalled(suite.T(), "Post", ctx, mock.Anything)
 }
 
 func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenErrorIsOccurred() {
@@ -241,7 +495,8 @@ func (suite *BillServiceTestSuite) Test_RemoveLineItemFailsWhenErrorIsOccurred()
 
 	ctx := context.Background()
 	testError := errors.New("test-error")
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
 	suite.BillMockRepo.On("AddLineItems", ctx, mock.Anything).Return(lineItem, testError)
 	suite.BillMockRepo.On("GetLineItemByID", ctx, mock.Anything).Return(lineItem, nil)
 
@@ -261,25 +516,31 @@ func (suite *BillServiceTestSuite) Test_RemoveLineItemSucceeds() {
 	}
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(suite.bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(suite.bill, nil)
 	suite.BillMockRepo.On("RemoveLineItems", ctx, mock.Anything).Return(lineItem, nil)
 	suite.BillMockRepo.On("GetLineItemByID", ctx, mock.Anything).Return(lineItem, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
 	suite.TemporalClientMock.On("SignalWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	removed := metrics.LineItemsRemoved.Value()
 
 	lineItemSaved, err := suite.bs.RemoveLineItems(ctx, "", lineItem.ID)
 	suite.Require().Nil(err)
 	suite.Require().Equal(lineItem, lineItemSaved)
+	suite.Require().Equal(removed+1, metrics.LineItemsRemoved.Value())
+	suite.AuditMock.AssertCalled(suite.T(), "Record", ctx, models.LineItemRemoved, models.LineItemEntity, lineItem.ID, mock.Anything, lineItem)
+	suite.WebhookMock.AssertCalled(suite.T(), "Publish", ctx, models.LineItemRemoved, suite.bill.ID, lineItem)
 }
 
 func (suite *BillServiceTestSuite) Test_CloseBillFailsWhenBillNotFound() {
 	bill := *suite.bill
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Bill{}, ce.BillNotFoundError)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&models.Bill{}, ce.BillNotFoundError)
 
 	_, err := suite.bs.Close(ctx, bill.ID)
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillNotFoundError, err)
+	suite.Require().ErrorIs(err, ce.BillNotFoundError)
 }
 
 func (suite *BillServiceTestSuite) Test_CloseBillFailsWhenBillIsClosed() {
@@ -287,11 +548,11 @@ func (suite *BillServiceTestSuite) Test_CloseBillFailsWhenBillIsClosed() {
 	bill.Status = "closed"
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&bill, nil)
 
 	_, err := suite.bs.Close(ctx, bill.ID)
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillClosedError, err)
+	suite.Require().ErrorIs(err, ce.BillClosedError)
 }
 
 func (suite *BillServiceTestSuite) Test_CloseBillFailsWhenErrorIsOccurred() {
@@ -299,26 +560,159 @@ func (suite *BillServiceTestSuite) Test_CloseBillFailsWhenErrorIsOccurred() {
 	testError := errors.New("test error")
 	ctx := context.Background()
 
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
-	suite.BillMockRepo.On("Close", ctx, mock.Anything).Return(&bill, testError)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&bill, nil)
+	suite.mockCloseLedger(ctx, bill.ID, 0)
+	suite.BillMockRepo.On("Close", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&bill, testError)
 
 	_, err := suite.bs.Close(ctx, bill.ID)
 	suite.Require().NotNil(err)
 	suite.Require().Equal(testError, err)
 }
 
+func (suite *BillServiceTestSuite) Test_CloseBillSetsDueDateFromPaymentTerms() {
+	ctx := context.Background()
+	bill := *suite.bill
+	bill.PaymentTerms = models.Net15
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, bill.ID).Return(&bill, nil)
+	suite.mockCloseLedger(ctx, bill.ID, 0)
+	suite.BillMockRepo.On("Close", ctx, bill.ID, mock.Anything, mock.Anything).Return(&bill, nil)
+
+	before := time.Now().UTC()
+	_, err := suite.bs.Close(ctx, bill.ID)
+
+	suite.Require().Nil(err)
+	closedAt := suite.BillMockRepo.Calls[2].Arguments.Get(2).(time.Time)
+	dueDate := suite.BillMockRepo.Calls[2].Arguments.Get(3).(time.Time)
+	suite.Require().False(closedAt.Before(before))
+	suite.Require().Equal(closedAt.AddDate(0, 0, 15), dueDate)
+}
+
 func (suite *BillServiceTestSuite) Test_CloseBillSucceeds() {
 	bill := *suite.bill
 	closedBill := *suite.bill
 	closedBill.Status = "closed"
 
 	ctx := context.Background()
-	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
-	suite.BillMockRepo.On("Close", ctx, mock.Anything).Return(&closedBill, nil)
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, mock.Anything).Return(&bill, nil)
+	suite.mockCloseLedger(ctx, bill.ID, 0)
+	suite.BillMockRepo.On("Close", ctx, mock.Anything, mock.Anything, mock.Anything).Return(&closedBill, nil)
+	closed := metrics.BillsClosed.Value()
 
 	billActual, err := suite.bs.Close(ctx, suite.bill.ID)
 	suite.Require().Nil(err)
 	suite.Require().Equal("closed", billActual.Status)
+	suite.Require().Equal(closed+1, metrics.BillsClosed.Value())
+}
+
+func (suite *BillServiceTestSuite) Test_CloseBillMovesUnbilledBalanceToReceivable() {
+	bill := *suite.bill
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, bill.ID).Return(&bill, nil)
+	suite.mockCloseLedger(ctx, bill.ID, 42.004)
+	suite.BillMockRepo.On("Close", ctx, bill.ID, mock.Anything, mock.Anything).Return(&bill, nil)
+
+	_, err := suite.bs.Close(ctx, bill.ID)
+
+	suite.Require().Nil(err)
+	suite.BillMockRepo.AssertCalled(suite.T(), "UpdateBillAmount", ctx, bill.ID, 42.0)
+	transfer := suite.LedgerMock.Calls[1].Arguments.Get(1).(*models.LedgerTransfer)
+	suite.Require().Equal(models.JournalBillClosed, transfer.Kind)
+	suite.Require().Equal(bill.ID, transfer.ReferenceID)
+	suite.Require().Equal(suite.customerID, transfer.CustomerID)
+	suite.Require().Equal("USD", transfer.CurrencyCode)
+	suite.Require().Equal(42.0, transfer.Amount)
+}
+
+// mockCloseLedger mocks closing the bill with the unbilled balance in the
+// ledger and finalizing its invoice.
+func (suite *BillServiceTestSuite) mockCloseLedger(ctx context.Context, billID string, balance float64) {
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.LedgerMock.On("BillBalance", ctx, billID, models.UnbilledAccount).Return(balance, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+	suite.BillMockRepo.On("UpdateBillAmount", ctx, billID, mock.Anything).Return(nil)
+	suite.BillMockRepo.On("GetByID", ctx, billID).Return(&models.Bill{ID: billID, CustomerID: suite.customerID, CurrencyID: suite.currencyID, Status: "closed"}, nil)
+	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, billID).Return([]*models.LineItem{}, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
+}
+
+func (suite *BillServiceTestSuite) Test_CloseBillPublishesClosedBillAndFinalInvoice() {
+	bill := *suite.bill
+	closedBill := *suite.bill
+	closedBill.Status = "closed"
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, bill.ID).Return(&bill, nil)
+	suite.mockCloseLedger(ctx, bill.ID, 0)
+	suite.BillMockRepo.On("Close", ctx, bill.ID, mock.Anything, mock.Anything).Return(&closedBill, nil)
+
+	_, err := suite.bs.Close(ctx, bill.ID)
+
+	suite.Require().Nil(err)
+	suite.WebhookMock.AssertCalled(suite.T(), "Publish", ctx, models.BillClosed, bill.ID, &closedBill)
+	suite.EventsMock.AssertCalled(suite.T(), "PublishBill", ctx, models.BillClosed, &closedBill)
+	suite.Require().Equal(models.InvoiceFinalized, suite.WebhookMock.Calls[1].Arguments.Get(1))
+	invoice := suite.WebhookMock.Calls[1].Arguments.Get(3).(*models.Invoice)
+	suite.Require().Equal(bill.ID, invoice.BillID)
+}
+
+func (suite *BillServiceTestSuite) Test_RecordPaymentFailsWhenBillIsOpen() {
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, suite.bill.ID).Return(suite.bill, nil)
+
+	_, err := suite.bs.RecordPayment(ctx, suite.bill.ID, &models.PaymentRequest{Amount: 10})
+
+	suite.Require().ErrorIs(err, ce.BillNotClosedError)
+}
+
+func (suite *BillServiceTestSuite) Test_RecordPaymentFailsWhenAmountExceedsBalanceDue() {
+	bill := *suite.bill
+	bill.Status = "closed"
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, bill.ID).Return(&bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.LedgerMock.On("BillBalance", ctx, bill.ID, models.ReceivableAccount).Return(25.0, nil)
+
+	_, err := suite.bs.RecordPayment(ctx, bill.ID, &models.PaymentRequest{Amount: 25.01})
+
+	suite.Require().ErrorIs(err, ce.AmountExceedsBalanceDueError)
+	suite.LedgerMock.AssertNotCalled(suite.T(), "Post", mock.Anything, mock.Anything)
+}
+
+func (suite *BillServiceTestSuite) Test_RecordPaymentPostsToLedger() {
+	bill := *suite.bill
+	bill.Status = "closed"
+	entry := &models.JournalEntry{ID: utils.GetNewUUID(), Kind: models.JournalPayment}
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, bill.ID).Return(&bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.LedgerMock.On("BillBalance", ctx, bill.ID, models.ReceivableAccount).Return(25.0, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(entry, nil)
+
+	recorded, err := suite.bs.RecordPayment(ctx, bill.ID, &models.PaymentRequest{Amount: 25, Reference: "txn-01"})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(entry, recorded)
+	transfer := suite.LedgerMock.Calls[1].Arguments.Get(1).(*models.LedgerTransfer)
+	suite.Require().Equal(models.JournalPayment, transfer.Kind)
+	suite.Require().Equal("txn-01", transfer.ReferenceID)
+	suite.Require().Equal(25.0, transfer.Amount)
+}
+
+func (suite *BillServiceTestSuite) Test_CreditPostsToLedger() {
+	bill := *suite.bill
+	bill.Status = "closed"
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByIDForUpdate", ctx, bill.ID).Return(&bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: "USD", MinorUnits: 2}, nil)
+	suite.LedgerMock.On("BillBalance", ctx, bill.ID, models.ReceivableAccount).Return(25.0, nil)
+	suite.LedgerMock.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+
+	_, err := suite.bs.Credit(ctx, bill.ID, &models.CreditRequest{Amount: 5, Reason: "goodwill"})
+
+	suite.Require().Nil(err)
+	transfer := suite.LedgerMock.Calls[1].Arguments.Get(1).(*models.LedgerTransfer)
+	suite.Require().Equal(models.JournalCredit, transfer.Kind)
+	suite.Require().Equal("goodwill", transfer.Description)
+	suite.Require().Equal(5.0, transfer.Amount)
 }
 
 func (suite *BillServiceTestSuite) Test_InvoiceFailsWhenBillNotFound() {
@@ -327,9 +721,9 @@ func (suite *BillServiceTestSuite) Test_InvoiceFailsWhenBillNotFound() {
 	ctx := context.Background()
 	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, ce.BillNotFoundError)
 
-	_, err := suite.bs.Invoice(ctx, suite.bill.ID)
+	_, err := suite.bs.Invoice(ctx, suite.bill.ID, "")
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.BillNotFoundError, err)
+	suite.Require().ErrorIs(err, ce.BillNotFoundError)
 }
 
 func (suite *BillServiceTestSuite) Test_InvoiceFailsWhenCurrencyNotFound() {
@@ -339,9 +733,9 @@ func (suite *BillServiceTestSuite) Test_InvoiceFailsWhenCurrencyNotFound() {
 	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
 	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{}, ce.CurrencyNotFoundError)
 
-	_, err := suite.bs.Invoice(ctx, suite.bill.ID)
+	_, err := suite.bs.Invoice(ctx, suite.bill.ID, "")
 	suite.Require().NotNil(err)
-	suite.Require().Equal(ce.CurrencyNotFoundError, err)
+	suite.Require().ErrorIs(err, ce.CurrencyNotFoundError)
 }
 
 func (suite *BillServiceTestSuite) Test_InvoiceFailsErrorOccuredWhileFetchingLineItems() {
@@ -353,7 +747,7 @@ func (suite *BillServiceTestSuite) Test_InvoiceFailsErrorOccuredWhileFetchingLin
 	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{Code: "001"}, nil)
 	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, mock.Anything).Return([]*models.LineItem{&models.LineItem{}}, testError)
 
-	_, err := suite.bs.Invoice(ctx, suite.bill.ID)
+	_, err := suite.bs.Invoice(ctx, suite.bill.ID, "")
 	suite.Require().NotNil(err)
 	suite.Require().Equal(testError, err)
 }
@@ -374,8 +768,9 @@ func (suite *BillServiceTestSuite) Test_InvoiceSucceedsWhenBillNotHasRemovedLine
 	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
 	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{Code: "001"}, nil)
 	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, mock.Anything).Return(lineItems, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
 
-	lineItemsActual, err := suite.bs.Invoice(ctx, suite.bill.ID)
+	lineItemsActual, err := suite.bs.Invoice(ctx, suite.bill.ID, "")
 	suite.Require().Nil(err)
 	suite.Require().Equal(len(lineItems), len(lineItemsActual.LineItems))
 }
@@ -396,12 +791,94 @@ func (suite *BillServiceTestSuite) Test_InvoiceSucceedsWhenBillHasRemovedLineIte
 	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
 	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{Code: "001"}, nil)
 	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, mock.Anything).Return(lineItems, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
 
-	lineItemsActual, err := suite.bs.Invoice(ctx, suite.bill.ID)
+	lineItemsActual, err := suite.bs.Invoice(ctx, suite.bill.ID, "")
 	suite.Require().Nil(err)
 	suite.Require().Equal(0, len(lineItemsActual.LineItems))
 }
 
+func (suite *BillServiceTestSuite) Test_InvoiceConvertsAmountsToRequestedCurrency() {
+	bill := *suite.bill
+	bill.TotalAmount = 150.0
+	bill.PeriodEnd = time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+	lineItems := []*models.LineItem{
+		{ID: utils.GetNewUUID(), BillID: bill.ID, Description: "line item 001", Amount: 100.0},
+		{ID: utils.GetNewUUID(), BillID: bill.ID, Description: "line item 002", Amount: 50.0},
+	}
+	rateDate := time.Date(2024, time.March, 30, 0, 0, 0, 0, time.UTC)
+
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{Code: "USD", Symbol: "$", MinorUnits: 2}, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{Code: "EUR", Symbol: "€", MinorUnits: 2}, nil)
+	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, mock.Anything).Return(lineItems, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
+	suite.ExchangeRateMock.On("GetRate", ctx, "USD", "EUR", bill.PeriodEnd).
+		Return(&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "EUR", Rate: 0.9, EffectiveDate: rateDate}, nil)
+
+	invoice, err := suite.bs.Invoice(ctx, suite.bill.ID, "EUR")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("EUR", invoice.CurrencyCode)
+	suite.Require().Equal(135.0, invoice.TotalAmount)
+	suite.Require().Equal(90.0, invoice.LineItems[0].Amount)
+	suite.Require().Equal("€135.00", invoice.FormattedTotalAmount)
+	suite.Require().Equal("USD", invoice.Conversion.BillCurrency)
+	suite.Require().Equal(150.0, invoice.Conversion.BillTotalAmount)
+	suite.Require().Equal(0.9, invoice.Conversion.Rate)
+	suite.Require().Equal(rateDate, invoice.Conversion.RateDate)
+}
+
+func (suite *BillServiceTestSuite) Test_InvoiceFailsWhenExchangeRateNotFound() {
+	bill := *suite.bill
+
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{Code: "USD"}, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{Code: "EUR"}, nil)
+	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, mock.Anything).Return([]*models.LineItem{}, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
+	suite.ExchangeRateMock.On("GetRate", ctx, "USD", "EUR", mock.Anything).Return(&models.ExchangeRate{}, ce.ExchangeRateNotFoundError)
+
+	_, err := suite.bs.Invoice(ctx, suite.bill.ID, "EUR")
+
+	suite.Require().ErrorIs(err, ce.ExchangeRateNotFoundError)
+}
+
+func (suite *BillServiceTestSuite) Test_InvoiceIncludesCustomerBillingProfile() {
+	bill := *suite.bill
+	bill.PeriodStart = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	customer := &models.Customer{
+		ID:             suite.customerID,
+		FirstName:      "John",
+		LastName:       "Jacobs",
+		Email:          "john.jacon@mail.com",
+		CompanyName:    "Jacobs GmbH",
+		TaxID:          "DE123456789",
+		BillingAddress: models.Address{Line1: "Unter den Linden 1", City: "Berlin", PostalCode: "10117", Country: "DE"},
+		Locale:         "de-DE",
+		Timezone:       "Europe/Berlin",
+	}
+
+	ctx := context.Background()
+	suite.BillMockRepo.On("GetByID", ctx, mock.Anything).Return(&bill, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, mock.Anything).Return(&models.Currency{Code: "EUR"}, nil)
+	suite.BillMockRepo.On("GetLineItemsByBillID", ctx, mock.Anything).Return([]*models.LineItem{}, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(customer, nil)
+
+	invoice, err := suite.bs.Invoice(ctx, suite.bill.ID, "")
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("John Jacobs", invoice.Customer.Name)
+	suite.Require().Equal("Jacobs GmbH", invoice.Customer.CompanyName)
+	suite.Require().Equal("DE123456789", invoice.Customer.TaxID)
+	suite.Require().Equal(customer.BillingAddress, invoice.Customer.BillingAddress)
+	suite.Require().Equal("de-DE", invoice.Customer.Locale)
+	suite.Require().Equal("Europe/Berlin", invoice.PeriodStart.Location().String())
+	suite.Require().Equal(1, invoice.PeriodStart.Hour())
+}
+
 func TestBillServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(BillServiceTestSuite))
 }
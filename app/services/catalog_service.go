@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+type catalogService struct {
+	repository         repository.CatalogRepository
+	currencyRepository repository.CurrencyRepository
+}
+
+type CatalogService interface {
+	CreateProduct(context.Context, *models.Product) (*models.Product, error)
+	GetProduct(context.Context, string) (*models.Product, error)
+	ListProducts(context.Context, bool) ([]*models.Product, error)
+	UpdateProduct(context.Context, string, *models.UpdateProductRequest) (*models.Product, error)
+	ArchiveProduct(context.Context, string) (*models.Product, error)
+	CreatePlan(context.Context, *models.Plan) (*models.Plan, error)
+	GetPlan(context.Context, string) (*models.Plan, error)
+	ListPlans(context.Context, string) ([]*models.Plan, error)
+	UpdatePlan(context.Context, string, *models.UpdatePlanRequest) (*models.Plan, error)
+	ArchivePlan(context.Context, string) (*models.Plan, error)
+	CreatePrice(context.Context, *models.CreatePriceRequest) (*models.Price, error)
+	GetPrice(context.Context, string) (*models.Price, error)
+	ListPrices(context.Context, string) ([]*models.Price, error)
+	ArchivePrice(context.Context, string) (*models.Price, error)
+}
+
+func NewCatalogService(repository repository.CatalogRepository, currencyRepository repository.CurrencyRepository) CatalogService {
+	return &catalogService{
+		repository:         repository,
+		currencyRepository: currencyRepository,
+	}
+}
+
+func (cs *catalogService) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	product.ID = utils.GetNewUUID()
+	product.CreatedAt = time.Now().UTC()
+	product.UpdatedAt = time.Now().UTC()
+
+	product, err := cs.repository.CreateProduct(ctx, product)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating product", "error", err)
+		return &models.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (cs *catalogService) GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	product, err := cs.repository.GetProductByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching product", "product_id", id, "error", err)
+		return &models.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (cs *catalogService) ListProducts(ctx context.Context, includeArchived bool) ([]*models.Product, error) {
+	products, err := cs.repository.ListProducts(ctx, includeArchived)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing products", "error", err)
+		return []*models.Product{}, err
+	}
+
+	return products, nil
+}
+
+func (cs *catalogService) UpdateProduct(ctx context.Context, id string, request *models.UpdateProductRequest) (*models.Product, error) {
+	product, err := cs.repository.GetProductByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("product not found", "product_id", id)
+		return &models.Product{}, err
+	}
+
+	product.Name = request.Name
+	product.Description = request.Description
+
+	product, err = cs.repository.UpdateProduct(ctx, product)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating product", "product_id", id, "error", err)
+		return &models.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (cs *catalogService) ArchiveProduct(ctx context.Context, id string) (*models.Product, error) {
+	product, err := cs.repository.GetProductByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("product not found", "product_id", id)
+		return &models.Product{}, err
+	}
+
+	product.Active = false
+
+	product, err = cs.repository.UpdateProduct(ctx, product)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving product", "product_id", id, "error", err)
+		return &models.Product{}, err
+	}
+
+	return product, nil
+}
+
+func (cs *catalogService) CreatePlan(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	product, err := cs.repository.GetProductByID(ctx, plan.ProductID)
+	if err != nil {
+		logging.From(ctx).Warn("product not found", "product_id", plan.ProductID)
+		return &models.Plan{}, err
+	}
+
+	if !product.Active {
+		logging.From(ctx).Warn("product is archived", "product_id", product.ID)
+		return &models.Plan{}, ce.ProductArchivedError.WithID(product.ID)
+	}
+
+	plan.ID = utils.GetNewUUID()
+	plan.CreatedAt = time.Now().UTC()
+	plan.UpdatedAt = time.Now().UTC()
+
+	plan, err = cs.repository.CreatePlan(ctx, plan)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating plan", "error", err)
+		return &models.Plan{}, err
+	}
+
+	return plan, nil
+}
+
+func (cs *catalogService) GetPlan(ctx context.Context, id string) (*models.Plan, error) {
+	plan, err := cs.repository.GetPlanByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching plan", "plan_id", id, "error", err)
+		return &models.Plan{}, err
+	}
+
+	return plan, nil
+}
+
+func (cs *catalogService) ListPlans(ctx context.Context, productID string) ([]*models.Plan, error) {
+	plans, err := cs.repository.ListPlansByProductID(ctx, productID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing plans for product", "product_id", productID, "error", err)
+		return []*models.Plan{}, err
+	}
+
+	return plans, nil
+}
+
+func (cs *catalogService) UpdatePlan(ctx context.Context, id string, request *models.UpdatePlanRequest) (*models.Plan, error) {
+	plan, err := cs.repository.GetPlanByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("plan not found", "plan_id", id)
+		return &models.Plan{}, err
+	}
+
+	plan.Name = request.Name
+
+	plan, err = cs.repository.UpdatePlan(ctx, plan)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating plan", "plan_id", id, "error", err)
+		return &models.Plan{}, err
+	}
+
+	return plan, nil
+}
+
+func (cs *catalogService) ArchivePlan(ctx context.Context, id string) (*models.Plan, error) {
+	plan, err := cs.repository.GetPlanByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("plan not found", "plan_id", id)
+		return &models.Plan{}, err
+	}
+
+	plan.Active = false
+
+	plan, err = cs.repository.UpdatePlan(ctx, plan)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving plan", "plan_id", id, "error", err)
+		return &models.Plan{}, err
+	}
+
+	return plan, nil
+}
+
+func (cs *catalogService) CreatePrice(ctx context.Context, request *models.CreatePriceRequest) (*models.Price, error) {
+	plan, err := cs.repository.GetPlanByID(ctx, request.PlanID)
+	if err != nil {
+		logging.From(ctx).Warn("plan not found", "plan_id", request.PlanID)
+		return &models.Price{}, err
+	}
+
+	if !plan.Active {
+		logging.From(ctx).Warn("plan is archived", "plan_id", plan.ID)
+		return &models.Price{}, ce.PlanArchivedError.WithID(plan.ID)
+	}
+
+	currency, err := cs.currencyRepository.GetByCode(ctx, request.CurrencyCode)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the currency", "currency_code", request.CurrencyCode, "error", err)
+		return &models.Price{}, err
+	}
+
+	if !currency.Active {
+		logging.From(ctx).Warn("currency is inactive", "currency_code", request.CurrencyCode)
+		return &models.Price{}, ce.CurrencyInactiveError.WithID(request.CurrencyCode)
+	}
+
+	pricingModel := request.PricingModel
+	if pricingModel == "" {
+		pricingModel = models.PerUnitPricing
+	}
+
+	price := &models.Price{
+		ID:           utils.GetNewUUID(),
+		PlanID:       plan.ID,
+		CurrencyID:   currency.ID,
+		PricingModel: pricingModel,
+		UnitAmount:   request.UnitAmount,
+		PackageSize:  request.PackageSize,
+		Tiers:        request.Tiers,
+		Active:       true,
+		CreatedAt:    time.Now().UTC(),
+		UpdatedAt:    time.Now().UTC(),
+	}
+
+	price, err = cs.repository.CreatePrice(ctx, price)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating price", "error", err)
+		return &models.Price{}, err
+	}
+
+	return price, nil
+}
+
+func (cs *catalogService) GetPrice(ctx context.Context, id string) (*models.Price, error) {
+	price, err := cs.repository.GetPriceByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching price", "price_id", id, "error", err)
+		return &models.Price{}, err
+	}
+
+	return price, nil
+}
+
+func (cs *catalogService) ListPrices(ctx context.Context, planID string) ([]*models.Price, error) {
+	prices, err := cs.repository.ListPricesByPlanID(ctx, planID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing prices for plan", "plan_id", planID, "error", err)
+		return []*models.Price{}, err
+	}
+
+	return prices, nil
+}
+
+func (cs *catalogService) ArchivePrice(ctx context.Context, id string) (*models.Price, error) {
+	price, err := cs.repository.GetPriceByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("price not found", "price_id", id)
+		return &models.Price{}, err
+	}
+
+	price.Active = false
+
+	price, err = cs.repository.UpdatePrice(ctx, price)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving price", "price_id", id, "error", err)
+		return &models.Price{}, err
+	}
+
+	return price, nil
+}
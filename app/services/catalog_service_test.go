@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CatalogServiceTestSuite struct {
+	suite.Suite
+	CatalogMockRepo  *repository.MockCatalogRepository
+	CurrencyMockRepo *repository.MockCurrencyRepository
+	cs               CatalogService
+	product          *models.Product
+	plan             *models.Plan
+	price            *models.Price
+}
+
+func (suite *CatalogServiceTestSuite) SetupTest() {
+	suite.CatalogMockRepo = new(repository.MockCatalogRepository)
+	suite.CurrencyMockRepo = new(repository.MockCurrencyRepository)
+	suite.cs = NewCatalogService(suite.CatalogMockRepo, suite.CurrencyMockRepo)
+
+	suite.product = &models.Product{
+		ID:        utils.GetNewUUID(),
+		Name:      "API",
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	suite.plan = &models.Plan{
+		ID:              utils.GetNewUUID(),
+		ProductID:       suite.product.ID,
+		Name:            "pro",
+		BillingInterval: models.MonthlyInterval,
+		Active:          true,
+	}
+	suite.price = &models.Price{
+		ID:         utils.GetNewUUID(),
+		PlanID:     suite.plan.ID,
+		CurrencyID: utils.GetNewUUID(),
+		UnitAmount: 9.99,
+		Active:     true,
+	}
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreateProductReturnsErrorWhenFails() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("CreateProduct", ctx, mock.Anything).Return(&models.Product{}, errors.New("test-error"))
+
+	_, err := suite.cs.CreateProduct(ctx, &models.Product{Name: "API", Active: true})
+
+	suite.Require().Error(err)
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreateProductReturnsNilErrorWhenSucceeds() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("CreateProduct", ctx, mock.Anything).Return(suite.product, nil)
+
+	product, err := suite.cs.CreateProduct(ctx, &models.Product{Name: "API", Active: true})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(suite.product, product)
+}
+
+func (suite *CatalogServiceTestSuite) Test_ArchiveProductSetsInactive() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetProductByID", ctx, suite.product.ID).Return(suite.product, nil)
+	suite.CatalogMockRepo.On("UpdateProduct", ctx, mock.Anything).Return(suite.product, nil)
+
+	product, err := suite.cs.ArchiveProduct(ctx, suite.product.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().False(product.Active)
+}
+
+func (suite *CatalogServiceTestSuite) Test_UpdateProductFailsWhenProductNotFound() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetProductByID", ctx, suite.product.ID).Return(&models.Product{}, ce.ProductNotFoundError)
+
+	_, err := suite.cs.UpdateProduct(ctx, suite.product.ID, &models.UpdateProductRequest{Name: "API v2"})
+
+	suite.Require().ErrorIs(err, ce.ProductNotFoundError)
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreatePlanFailsWhenProductIsArchived() {
+	ctx := context.Background()
+	product := *suite.product
+	product.Active = false
+	suite.CatalogMockRepo.On("GetProductByID", ctx, product.ID).Return(&product, nil)
+
+	_, err := suite.cs.CreatePlan(ctx, &models.Plan{ProductID: product.ID, Name: "pro"})
+
+	suite.Require().ErrorIs(err, ce.ProductArchivedError)
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreatePlanReturnsNilErrorWhenSucceeds() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetProductByID", ctx, suite.product.ID).Return(suite.product, nil)
+	suite.CatalogMockRepo.On("CreatePlan", ctx, mock.Anything).Return(suite.plan, nil)
+
+	plan, err := suite.cs.CreatePlan(ctx, &models.Plan{ProductID: suite.product.ID, Name: "pro"})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(suite.plan, plan)
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreatePriceFailsWhenCurrencyNotFound() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, suite.plan.ID).Return(suite.plan, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "XYZ").Return(&models.Currency{}, ce.CurrencyNotFoundError)
+
+	_, err := suite.cs.CreatePrice(ctx, &models.CreatePriceRequest{PlanID: suite.plan.ID, CurrencyCode: "XYZ", UnitAmount: 1})
+
+	suite.Require().ErrorIs(err, ce.CurrencyNotFoundError)
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreatePriceFailsWhenCurrencyIsInactive() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, suite.plan.ID).Return(suite.plan, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.price.CurrencyID}, nil)
+
+	_, err := suite.cs.CreatePrice(ctx, &models.CreatePriceRequest{PlanID: suite.plan.ID, CurrencyCode: "USD", UnitAmount: 1})
+
+	suite.Require().ErrorIs(err, ce.CurrencyInactiveError)
+}
+
+func (suite *CatalogServiceTestSuite) Test_CreatePriceReturnsNilErrorWhenSucceeds() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, suite.plan.ID).Return(suite.plan, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.price.CurrencyID, Active: true}, nil)
+	suite.CatalogMockRepo.On("CreatePrice", ctx, mock.Anything).Return(suite.price, nil)
+
+	price, err := suite.cs.CreatePrice(ctx, &models.CreatePriceRequest{PlanID: suite.plan.ID, CurrencyCode: "USD", UnitAmount: 9.99})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(suite.price, price)
+
+	created := suite.CatalogMockRepo.Calls[1].Arguments.Get(1).(*models.Price)
+	suite.Require().Equal(suite.price.CurrencyID, created.CurrencyID)
+	suite.Require().True(created.Active)
+}
+
+func (suite *CatalogServiceTestSuite) Test_ArchivePriceSetsInactive() {
+	ctx := context.Background()
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, suite.price.ID).Return(suite.price, nil)
+	suite.CatalogMockRepo.On("UpdatePrice", ctx, mock.Anything).Return(suite.price, nil)
+
+	price, err := suite.cs.ArchivePrice(ctx, suite.price.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().False(price.Active)
+}
+
+func TestCatalogServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogServiceTestSuite))
+}
@@ -2,35 +2,58 @@ package service
 
 import (
 	"context"
-	"log"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 )
 
 type currencyService struct {
 	repository repository.CurrencyRepository
+	audit      AuditService
+	events     EventPublisher
 }
 
 type CurrencyService interface {
 	Create(context.Context, *models.Currency) (*models.Currency, error)
 	GetByID(context.Context, string) (*models.Currency, error)
+	List(context.Context, bool) ([]*models.Currency, error)
+	Update(context.Context, string, *models.UpdateCurrencyRequest) (*models.Currency, error)
+	Activate(context.Context, string) (*models.Currency, error)
+	Deactivate(context.Context, string) (*models.Currency, error)
+	Delete(context.Context, string) error
 }
 
-func NewCurrencyService(repository repository.CurrencyRepository) CurrencyService {
+func NewCurrencyService(repository repository.CurrencyRepository, audit AuditService, events EventPublisher) CurrencyService {
 	return &currencyService{
 		repository: repository,
+		audit:      audit,
+		events:     events,
 	}
 }
 
+// Create creates a currency for an ISO 4217 code, taking its numeric code and
+// minor units from the reference data.
 func (cs *currencyService) Create(ctx context.Context, currency *models.Currency) (*models.Currency, error) {
+	isoCurrency, err := cs.repository.GetISOCurrency(ctx, currency.Code)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while finding iso currency", "code", currency.Code, "error", err)
+		return &models.Currency{}, err
+	}
+
 	currency.ID = utils.GetNewUUID()
-	currency, err := cs.repository.Create(ctx, currency)
+	currency.Active = true
+	currency.NumericCode = isoCurrency.NumericCode
+	currency.MinorUnits = isoCurrency.MinorUnits
+	currency, err = cs.repository.Create(ctx, currency)
 	if err != nil {
-		log.Printf("error occured while creating currency. error %s\n", err.Error())
+		logging.From(ctx).Error("error occurred while creating currency", "error", err)
 		return &models.Currency{}, err
 	}
+	cs.audit.Record(ctx, models.CurrencyCreated, models.CurrencyEntity, currency.ID, nil, currency)
+	cs.events.PublishCurrency(ctx, models.CurrencyCreated, currency)
 
 	return currency, nil
 }
@@ -38,9 +61,112 @@ func (cs *currencyService) Create(ctx context.Context, currency *models.Currency
 func (cs *currencyService) GetByID(ctx context.Context, id string) (*models.Currency, error) {
 	currency, err := cs.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("error occured while fetching currency with id %s. error %s\n", id, err.Error())
+		logging.From(ctx).Error("error occurred while fetching currency", "currency_id", id, "error", err)
+		return &models.Currency{}, err
+	}
+
+	return currency, nil
+}
+
+func (cs *currencyService) List(ctx context.Context, includeInactive bool) ([]*models.Currency, error) {
+	currencies, err := cs.repository.List(ctx, includeInactive)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing currencies", "error", err)
+		return []*models.Currency{}, err
+	}
+
+	return currencies, nil
+}
+
+func (cs *currencyService) Update(ctx context.Context, id string, request *models.UpdateCurrencyRequest) (*models.Currency, error) {
+	currency, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("currency not found", "currency_id", id)
+		return &models.Currency{}, err
+	}
+
+	before := *currency
+	if request.Name != "" {
+		currency.Name = request.Name
+	}
+	if request.Symbol != "" {
+		currency.Symbol = request.Symbol
+	}
+
+	currency, err = cs.repository.Update(ctx, currency)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating currency", "currency_id", id, "error", err)
+		return &models.Currency{}, err
+	}
+	cs.audit.Record(ctx, models.CurrencyUpdated, models.CurrencyEntity, currency.ID, before, currency)
+	cs.events.PublishCurrency(ctx, models.CurrencyUpdated, currency)
+
+	return currency, nil
+}
+
+func (cs *currencyService) Activate(ctx context.Context, id string) (*models.Currency, error) {
+	return cs.setActive(ctx, id, true)
+}
+
+// Deactivate stops the currency from being used for new subscriptions and
+// prices. Existing subscriptions keep renewing in it.
+func (cs *currencyService) Deactivate(ctx context.Context, id string) (*models.Currency, error) {
+	return cs.setActive(ctx, id, false)
+}
+
+func (cs *currencyService) setActive(ctx context.Context, id string, active bool) (*models.Currency, error) {
+	currency, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("currency not found", "currency_id", id)
 		return &models.Currency{}, err
 	}
 
+	before := *currency
+	currency.Active = active
+	currency, err = cs.repository.Update(ctx, currency)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating currency", "currency_id", id, "error", err)
+		return &models.Currency{}, err
+	}
+
+	action := models.CurrencyDeactivated
+	if active {
+		action = models.CurrencyActivated
+	}
+	cs.audit.Record(ctx, action, models.CurrencyEntity, currency.ID, before, currency)
+	cs.events.PublishCurrency(ctx, action, currency)
+
 	return currency, nil
 }
+
+// Delete deletes a currency nothing is charged in. Currencies that bills,
+// subscriptions or prices reference, or that are the default currency of
+// customers, can only be deactivated.
+func (cs *currencyService) Delete(ctx context.Context, id string) error {
+	currency, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("currency not found", "currency_id", id)
+		return err
+	}
+
+	referenced, err := cs.repository.IsReferenced(ctx, currency)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while checking references of currency", "currency_id", id, "error", err)
+		return err
+	}
+
+	if referenced {
+		logging.From(ctx).Warn("currency is in use", "currency_id", id)
+		return ce.CurrencyInUseError.WithID(id)
+	}
+
+	err = cs.repository.Delete(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while deleting currency", "currency_id", id, "error", err)
+		return err
+	}
+	cs.audit.Record(ctx, models.CurrencyDeleted, models.CurrencyEntity, id, currency, nil)
+	cs.events.PublishCurrency(ctx, models.CurrencyDeleted, currency)
+
+	return nil
+}
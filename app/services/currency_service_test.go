@@ -8,22 +8,30 @@ import (
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type CurrencyServiceTestSuite struct {
 	suite.Suite
-	MockRepo *repository.MockCurrencyRepository
-	cs       CurrencyService
-	currency *models.Currency
+	MockRepo   *repository.MockCurrencyRepository
+	AuditMock  *AuditServiceMock
+	EventsMock *EventPublisherMock
+	cs         CurrencyService
+	currency   *models.Currency
 }
 
 func (suite *CurrencyServiceTestSuite) SetupTest() {
 
 	mockRepo := new(repository.MockCurrencyRepository)
 	suite.MockRepo = mockRepo
-	suite.cs = NewCurrencyService(mockRepo)
+	suite.AuditMock = new(AuditServiceMock)
+	suite.AuditMock.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.EventsMock = new(EventPublisherMock)
+	suite.EventsMock.On("PublishCurrency", mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.cs = NewCurrencyService(mockRepo, suite.AuditMock, suite.EventsMock)
 
 	suite.currency = &models.Currency{
 		ID:        utils.GetNewUUID(),
@@ -37,6 +45,7 @@ func (suite *CurrencyServiceTestSuite) SetupTest() {
 
 func (suite *CurrencyServiceTestSuite) Test_CreateCurrencyReturnsErrorWhenFails() {
 	ctx := context.Background()
+	suite.MockRepo.On("GetISOCurrency", ctx, "USD").Return(&models.ISOCurrency{Code: "USD", NumericCode: "840", MinorUnits: 2}, nil)
 	suite.MockRepo.On("Create", ctx, suite.currency).Return(&models.Currency{}, errors.New("test-error"))
 
 	currency, err := suite.cs.Create(ctx, suite.currency)
@@ -47,12 +56,27 @@ func (suite *CurrencyServiceTestSuite) Test_CreateCurrencyReturnsErrorWhenFails(
 
 func (suite *CurrencyServiceTestSuite) Test_CreateCurrencyReturnsNilErrorWhenSucceeds() {
 	ctx := context.Background()
+	suite.MockRepo.On("GetISOCurrency", ctx, "USD").Return(&models.ISOCurrency{Code: "USD", NumericCode: "840", MinorUnits: 2}, nil)
 	suite.MockRepo.On("Create", ctx, suite.currency).Return(suite.currency, nil)
 
 	currency, err := suite.cs.Create(ctx, suite.currency)
 
 	suite.Require().Nil(err)
 	suite.Require().Equal(suite.currency, currency)
+	suite.Require().Equal("840", currency.NumericCode)
+	suite.Require().Equal(2, currency.MinorUnits)
+}
+
+func (suite *CurrencyServiceTestSuite) Test_CreateCurrencyFailsWhenCodeIsNotISO() {
+	ctx := context.Background()
+	currency := *suite.currency
+	currency.Code = "XYZ"
+	suite.MockRepo.On("GetISOCurrency", ctx, "XYZ").Return(&models.ISOCurrency{}, ce.ISOCurrencyNotFoundError)
+
+	_, err := suite.cs.Create(ctx, &currency)
+
+	suite.Require().ErrorIs(err, ce.ISOCurrencyNotFoundError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Create", ctx, &currency)
 }
 
 func (suite *CurrencyServiceTestSuite) Test_GetByIDReturnsErrorWhenFails() {
@@ -77,6 +101,71 @@ func (suite *CurrencyServiceTestSuite) Test_GetByIDReturnsNilErrorWhenSucceeds()
 	suite.Require().Equal(suite.currency, currency)
 }
 
+func (suite *CurrencyServiceTestSuite) Test_UpdateChangesOnlyGivenFields() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.currency.ID).Return(suite.currency, nil)
+	suite.MockRepo.On("Update", ctx, suite.currency).Return(suite.currency, nil)
+
+	currency, err := suite.cs.Update(ctx, suite.currency.ID, &models.UpdateCurrencyRequest{Symbol: "US$"})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("US$", currency.Symbol)
+	suite.Require().Equal("United states dollars", currency.Name)
+}
+
+func (suite *CurrencyServiceTestSuite) Test_DeactivateAndActivate() {
+	ctx := context.Background()
+	suite.currency.Active = true
+	suite.MockRepo.On("GetByID", ctx, suite.currency.ID).Return(suite.currency, nil)
+	suite.MockRepo.On("Update", ctx, suite.currency).Return(suite.currency, nil)
+
+	currency, err := suite.cs.Deactivate(ctx, suite.currency.ID)
+	suite.Require().Nil(err)
+	suite.Require().False(currency.Active)
+
+	currency, err = suite.cs.Activate(ctx, suite.currency.ID)
+	suite.Require().Nil(err)
+	suite.Require().True(currency.Active)
+
+	deactivated := suite.AuditMock.Calls[0].Arguments
+	suite.Require().Equal(models.CurrencyDeactivated, deactivated.Get(1))
+	suite.Require().True(deactivated.Get(4).(models.Currency).Active)
+	suite.Require().Equal(models.CurrencyActivated, suite.AuditMock.Calls[1].Arguments.Get(1))
+	suite.Require().Equal(models.CurrencyDeactivated, suite.EventsMock.Calls[0].Arguments.Get(1))
+	suite.Require().Equal(models.CurrencyActivated, suite.EventsMock.Calls[1].Arguments.Get(1))
+}
+
+func (suite *CurrencyServiceTestSuite) Test_DeleteFailsWhenCurrencyIsInUse() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.currency.ID).Return(suite.currency, nil)
+	suite.MockRepo.On("IsReferenced", ctx, suite.currency).Return(true, nil)
+
+	err := suite.cs.Delete(ctx, suite.currency.ID)
+
+	suite.Require().ErrorIs(err, ce.CurrencyInUseError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Delete", ctx, suite.currency.ID)
+}
+
+func (suite *CurrencyServiceTestSuite) Test_DeleteSucceedsWhenCurrencyIsUnused() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.currency.ID).Return(suite.currency, nil)
+	suite.MockRepo.On("IsReferenced", ctx, suite.currency).Return(false, nil)
+	suite.MockRepo.On("Delete", ctx, suite.currency.ID).Return(nil)
+
+	err := suite.cs.Delete(ctx, suite.currency.ID)
+
+	suite.Require().Nil(err)
+}
+
+func (suite *CurrencyServiceTestSuite) Test_DeleteFailsWhenCurrencyNotFound() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.currency.ID).Return(&models.Currency{}, ce.CurrencyNotFoundError)
+
+	err := suite.cs.Delete(ctx, suite.currency.ID)
+
+	suite.Require().ErrorIs(err, ce.CurrencyNotFoundError)
+}
+
 func TestCurrencyServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(CurrencyServiceTestSuite))
 }
@@ -2,35 +2,71 @@ package service
 
 import (
 	"context"
-	"log"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 )
 
 type customerService struct {
-	repository repository.CustomerRepository
+	repository         repository.CustomerRepository
+	currencyRepository repository.CurrencyRepository
+	billRepository     repository.BillRepository
+	ledgerRepository   repository.LedgerRepository
+	audit              AuditService
+	events             EventPublisher
 }
 
 type CustomerService interface {
 	Create(context.Context, *models.Customer) (*models.Customer, error)
 	GetByID(context.Context, string) (*models.Customer, error)
+	List(context.Context, *models.ListCustomersRequest) (*models.CustomerList, error)
+	Update(context.Context, string, *models.UpdateCustomerRequest) (*models.Customer, error)
+	Archive(context.Context, string) (*models.Customer, error)
+	Delete(context.Context, string) (*models.Customer, error)
+	Purge(context.Context, string, *models.PurgeRequest) (*models.Purge, error)
+	Statement(context.Context, string, *models.StatementRequest) (*models.Statement, error)
 }
 
-func NewCustomerService(repository repository.CustomerRepository) CustomerService {
+func NewCustomerService(repository repository.CustomerRepository, currencyRepository repository.CurrencyRepository,
+	billRepository repository.BillRepository, ledgerRepository repository.LedgerRepository, audit AuditService, events EventPublisher) CustomerService {
 	return &customerService{
-		repository: repository,
+		repository:         repository,
+		currencyRepository: currencyRepository,
+		billRepository:     billRepository,
+		ledgerRepository:   ledgerRepository,
+		audit:              audit,
+		events:             events,
 	}
 }
 
+// Create creates a customer. Emails are unique across customers, ignoring
+// case, archived customers included.
 func (cs *customerService) Create(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	err := cs.checkEmailAvailable(ctx, customer.Email, "")
+	if err != nil {
+		return &models.Customer{}, err
+	}
+
+	err = cs.checkDefaultCurrency(ctx, customer.DefaultCurrency)
+	if err != nil {
+		return &models.Customer{}, err
+	}
+
 	customer.ID = utils.GetNewUUID()
-	customer, err := cs.repository.Create(ctx, customer)
+	customer.Active = true
+	customer, err = cs.repository.Create(ctx, customer)
 	if err != nil {
-		log.Printf("error occured while creating custome. error %s\n", err.Error())
+		logging.From(ctx).Error("error occurred while creating customer", "error", err)
 		return &models.Customer{}, err
 	}
+	cs.audit.Record(ctx, models.CustomerCreated, models.CustomerEntity, customer.ID, nil, customer)
+	cs.events.PublishCustomer(ctx, models.CustomerCreated, customer.ID, customer)
 
 	return customer, nil
 }
@@ -38,9 +74,242 @@ func (cs *customerService) Create(ctx context.Context, customer *models.Customer
 func (cs *customerService) GetByID(ctx context.Context, id string) (*models.Customer, error) {
 	customer, err := cs.repository.GetByID(ctx, id)
 	if err != nil {
-		log.Printf("error occured while fetching customer with id %s. error %s\n", id, err.Error())
+		logging.From(ctx).Error("error occurred while fetching customer", "customer_id", id, "error", err)
+		return &models.Customer{}, err
+	}
+
+	return customer, nil
+}
+
+func (cs *customerService) List(ctx context.Context, request *models.ListCustomersRequest) (*models.CustomerList, error) {
+	customers, total, err := cs.repository.List(ctx, request)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing customers", "error", err)
+		return &models.CustomerList{}, err
+	}
+
+	return &models.CustomerList{Customers: customers, Total: total}, nil
+}
+
+func (cs *customerService) Update(ctx context.Context, id string, request *models.UpdateCustomerRequest) (*models.Customer, error) {
+	customer, err := cs.existingCustomer(ctx, id)
+	if err != nil {
+		return &models.Customer{}, err
+	}
+	before := *customer
+
+	if request.Email != "" {
+		if !strings.EqualFold(request.Email, customer.Email) {
+			err = cs.checkEmailAvailable(ctx, request.Email, customer.ID)
+			if err != nil {
+				return &models.Customer{}, err
+			}
+		}
+		customer.Email = request.Email
+	}
+	if request.DefaultCurrency != "" {
+		err = cs.checkDefaultCurrency(ctx, request.DefaultCurrency)
+		if err != nil {
+			return &models.Customer{}, err
+		}
+		customer.DefaultCurrency = request.DefaultCurrency
+	}
+	request.ApplyTo(customer)
+
+	customer, err = cs.repository.Update(ctx, customer)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while updating customer", "customer_id", id, "error", err)
+		return &models.Customer{}, err
+	}
+	cs.audit.Record(ctx, models.CustomerUpdated, models.CustomerEntity, customer.ID, before, customer)
+	cs.events.PublishCustomer(ctx, models.CustomerUpdated, customer.ID, customer)
+
+	return customer, nil
+}
+
+// Archive hides a customer from listings and stops new subscriptions for it.
+// Its bills and running subscriptions are left as they are.
+func (cs *customerService) Archive(ctx context.Context, id string) (*models.Customer, error) {
+	customer, err := cs.existingCustomer(ctx, id)
+	if err != nil {
+		return &models.Customer{}, err
+	}
+
+	before := *customer
+	customer.Active = false
+
+	customer, err = cs.repository.Update(ctx, customer)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while archiving customer", "customer_id", id, "error", err)
+		return &models.Customer{}, err
+	}
+	cs.audit.Record(ctx, models.CustomerArchived, models.CustomerEntity, customer.ID, before, customer)
+	cs.events.PublishCustomer(ctx, models.CustomerArchived, customer.ID, customer)
+
+	return customer, nil
+}
+
+// Delete soft deletes a customer. The customer is kept, archived, for the
+// bills charged to it and its email can be used by a new customer. Customers
+// with active subscriptions can't be deleted.
+func (cs *customerService) Delete(ctx context.Context, id string) (*models.Customer, error) {
+	customer, err := cs.existingCustomer(ctx, id)
+	if err != nil {
+		return &models.Customer{}, err
+	}
+
+	active, err := cs.repository.HasActiveSubscriptions(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while checking subscriptions of customer", "customer_id", id, "error", err)
+		return &models.Customer{}, err
+	}
+
+	if active {
+		logging.From(ctx).Warn("customer has active subscriptions", "customer_id", id)
+		return &models.Customer{}, ce.CustomerHasActiveSubscriptionsError.WithID(id)
+	}
+
+	before := *customer
+	deletedAt := time.Now().UTC()
+	customer.Active = false
+	customer.DeletedAt = &deletedAt
+
+	customer, err = cs.repository.Update(ctx, customer)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while deleting customer", "customer_id", id, "error", err)
+		return &models.Customer{}, err
+	}
+	cs.audit.Record(ctx, models.CustomerDeleted, models.CustomerEntity, customer.ID, before, customer)
+	cs.events.PublishCustomer(ctx, models.CustomerDeleted, customer.ID, customer)
+
+	return customer, nil
+}
+
+// Purge permanently removes a deleted customer along with its open bills,
+// subscriptions and usage events, and records who asked for it and why.
+// Customers with closed bills can't be purged.
+func (cs *customerService) Purge(ctx context.Context, id string, request *models.PurgeRequest) (*models.Purge, error) {
+	customer, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("customer not found", "customer_id", id)
+		return &models.Purge{}, err
+	}
+
+	if !customer.IsDeleted() {
+		logging.From(ctx).Warn("customer is not deleted", "customer_id", id)
+		return &models.Purge{}, ce.CustomerNotDeletedError.WithID(id)
+	}
+
+	purge := &models.Purge{
+		ID:          utils.GetNewUUID(),
+		EntityType:  "customer",
+		EntityID:    customer.ID,
+		Reason:      request.Reason,
+		RequestedBy: request.RequestedBy,
+		CreatedAt:   time.Now().UTC(),
+	}
+
+	purge, err = cs.repository.Purge(ctx, purge)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while purging customer", "customer_id", id, "error", err)
+		return &models.Purge{}, err
+	}
+	// the customer is gone for good, only the purge itself is recorded
+	cs.audit.Record(ctx, models.CustomerPurged, models.CustomerEntity, customer.ID, nil, purge)
+	cs.events.PublishCustomer(ctx, models.CustomerPurged, customer.ID, nil)
+
+	logging.From(ctx).Info("customer purged", "customer_id", id, "requested_by", purge.RequestedBy)
+	return purge, nil
+}
+
+// Statement returns the statement of the customer for the window of the
+// request. Deleted customers keep their statement.
+func (cs *customerService) Statement(ctx context.Context, id string, request *models.StatementRequest) (*models.Statement, error) {
+	customer, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("customer not found", "customer_id", id)
+		return &models.Statement{}, err
+	}
+
+	to := request.To
+	if to.IsZero() {
+		to = time.Now().UTC()
+	}
+
+	bills, err := cs.billRepository.ListClosedByCustomerID(ctx, customer.ID, to)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching bills of customer", "customer_id", id, "error", err)
+		return &models.Statement{}, err
+	}
+
+	currencies := map[string]*models.Currency{}
+	for _, bill := range bills {
+		if _, ok := currencies[bill.CurrencyID]; ok {
+			continue
+		}
+
+		currency, err := cs.currencyRepository.GetByID(ctx, bill.CurrencyID)
+		if err != nil {
+			logging.From(ctx).Error("error while fetching currency of bill", "currency_id", bill.CurrencyID, "bill_id", bill.ID, "error", err)
+			return &models.Statement{}, err
+		}
+		currencies[currency.ID] = currency
+	}
+
+	settlements, err := cs.ledgerRepository.ListByCustomerID(ctx, customer.ID, []string{models.JournalPayment, models.JournalCredit}, to)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching payments and credits of customer", "customer_id", id, "error", err)
+		return &models.Statement{}, err
+	}
+
+	return models.CreateStatement(customer, bills, settlements, currencies, request.From, to), nil
+}
+
+// existingCustomer returns the customer unless it is deleted.
+func (cs *customerService) existingCustomer(ctx context.Context, id string) (*models.Customer, error) {
+	customer, err := cs.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("customer not found", "customer_id", id)
 		return &models.Customer{}, err
 	}
 
+	if customer.IsDeleted() {
+		logging.From(ctx).Warn("customer is deleted", "customer_id", id)
+		return &models.Customer{}, ce.CustomerNotFoundError.WithID(id)
+	}
+
 	return customer, nil
 }
+
+func (cs *customerService) checkEmailAvailable(ctx context.Context, email, customerID string) error {
+	existing, err := cs.repository.GetByEmail(ctx, email)
+	if err == nil && existing.ID != customerID {
+		logging.From(ctx).Warn("customer already exists for email", "email", email)
+		return ce.CustomerAlreadyExistError
+	}
+
+	if err != nil && !errors.Is(err, ce.CustomerNotFoundError) {
+		return err
+	}
+
+	return nil
+}
+
+func (cs *customerService) checkDefaultCurrency(ctx context.Context, code string) error {
+	if code == "" {
+		return nil
+	}
+
+	currency, err := cs.currencyRepository.GetByCode(ctx, code)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the currency", "code", code, "error", err)
+		return err
+	}
+
+	if !currency.Active {
+		logging.From(ctx).Warn("currency is inactive", "code", code)
+		return ce.CurrencyInactiveError.WithID(code)
+	}
+
+	return nil
+}
@@ -8,22 +8,36 @@ import (
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
 
 type CustomerServiceTestSuite struct {
 	suite.Suite
-	MockRepo *repository.MockCustomerRepository
-	cs       CustomerService
-	customer *models.Customer
+	MockRepo         *repository.MockCustomerRepository
+	CurrencyMockRepo *repository.MockCurrencyRepository
+	BillMockRepo     *repository.MockBillRepository
+	LedgerMockRepo   *repository.MockLedgerRepository
+	AuditMock        *AuditServiceMock
+	EventsMock       *EventPublisherMock
+	cs               CustomerService
+	customer         *models.Customer
 }
 
 func (suite *CustomerServiceTestSuite) SetupTest() {
 
 	mockRepo := new(repository.MockCustomerRepository)
 	suite.MockRepo = mockRepo
-	suite.cs = NewCustomerService(mockRepo)
+	suite.CurrencyMockRepo = new(repository.MockCurrencyRepository)
+	suite.BillMockRepo = new(repository.MockBillRepository)
+	suite.LedgerMockRepo = new(repository.MockLedgerRepository)
+	suite.AuditMock = new(AuditServiceMock)
+	suite.AuditMock.On("Record", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.EventsMock = new(EventPublisherMock)
+	suite.EventsMock.On("PublishCustomer", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return()
+	suite.cs = NewCustomerService(mockRepo, suite.CurrencyMockRepo, suite.BillMockRepo, suite.LedgerMockRepo, suite.AuditMock, suite.EventsMock)
 
 	suite.customer = &models.Customer{
 		ID:        utils.GetNewUUID(),
@@ -37,6 +51,7 @@ func (suite *CustomerServiceTestSuite) SetupTest() {
 
 func (suite *CustomerServiceTestSuite) Test_CreateCustomerReturnsErrorWhenFails() {
 	ctx := context.Background()
+	suite.MockRepo.On("GetByEmail", ctx, suite.customer.Email).Return(&models.Customer{}, ce.CustomerNotFoundError)
 	suite.MockRepo.On("Create", ctx, suite.customer).Return(&models.Customer{}, errors.New("test-error"))
 
 	customer, err := suite.cs.Create(ctx, suite.customer)
@@ -47,6 +62,7 @@ func (suite *CustomerServiceTestSuite) Test_CreateCustomerReturnsErrorWhenFails(
 
 func (suite *CustomerServiceTestSuite) Test_CreateCustomerReturnsNilErrorWhenSucceeds() {
 	ctx := context.Background()
+	suite.MockRepo.On("GetByEmail", ctx, suite.customer.Email).Return(&models.Customer{}, ce.CustomerNotFoundError)
 	suite.MockRepo.On("Create", ctx, suite.customer).Return(suite.customer, nil)
 
 	customer, err := suite.cs.Create(ctx, suite.customer)
@@ -75,6 +91,185 @@ func (suite *CustomerServiceTestSuite) Test_GetByIDReturnsNilErrorWhenSucceeds()
 	suite.Require().Equal(suite.customer, customer)
 }
 
+func (suite *CustomerServiceTestSuite) Test_CreateCustomerFailsWhenEmailIsTaken() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByEmail", ctx, suite.customer.Email).Return(&models.Customer{ID: utils.GetNewUUID()}, nil)
+
+	_, err := suite.cs.Create(ctx, suite.customer)
+
+	suite.Require().ErrorIs(err, ce.CustomerAlreadyExistError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Create", ctx, suite.customer)
+}
+
+func (suite *CustomerServiceTestSuite) Test_ListReturnsPageAndTotal() {
+	ctx := context.Background()
+	request := &models.ListCustomersRequest{Query: "jo", Limit: 1}
+	suite.MockRepo.On("List", ctx, request).Return([]*models.Customer{suite.customer}, int64(3), nil)
+
+	list, err := suite.cs.List(ctx, request)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal([]*models.Customer{suite.customer}, list.Customers)
+	suite.Require().Equal(int64(3), list.Total)
+}
+
+func (suite *CustomerServiceTestSuite) Test_UpdateFailsWhenEmailBelongsToAnotherCustomer() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.MockRepo.On("GetByEmail", ctx, "jane@mail.com").Return(&models.Customer{ID: utils.GetNewUUID()}, nil)
+
+	_, err := suite.cs.Update(ctx, suite.customer.ID, &models.UpdateCustomerRequest{Email: "jane@mail.com"})
+
+	suite.Require().ErrorIs(err, ce.CustomerAlreadyExistError)
+}
+
+func (suite *CustomerServiceTestSuite) Test_UpdateChangesOnlyGivenFields() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.MockRepo.On("Update", ctx, suite.customer).Return(suite.customer, nil)
+
+	customer, err := suite.cs.Update(ctx, suite.customer.ID, &models.UpdateCustomerRequest{LastName: "Jacobson", Email: "JOHN.JACON@mail.com"})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("John", customer.FirstName)
+	suite.Require().Equal("Jacobson", customer.LastName)
+	suite.Require().Equal("JOHN.JACON@mail.com", customer.Email)
+	suite.MockRepo.AssertNotCalled(suite.T(), "GetByEmail", ctx, "JOHN.JACON@mail.com")
+
+	audited := suite.AuditMock.Calls[0].Arguments
+	suite.Require().Equal(models.CustomerUpdated, audited.Get(1))
+	suite.Require().Equal("Jacobs", audited.Get(4).(models.Customer).LastName)
+	suite.Require().Equal(customer, audited.Get(5))
+}
+
+func (suite *CustomerServiceTestSuite) Test_ArchiveDeactivatesCustomer() {
+	ctx := context.Background()
+	suite.customer.Active = true
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.MockRepo.On("Update", ctx, suite.customer).Return(suite.customer, nil)
+
+	customer, err := suite.cs.Archive(ctx, suite.customer.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().False(customer.Active)
+}
+
+func (suite *CustomerServiceTestSuite) Test_DeleteFailsWhenSubscriptionsAreActive() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.MockRepo.On("HasActiveSubscriptions", ctx, suite.customer.ID).Return(true, nil)
+
+	_, err := suite.cs.Delete(ctx, suite.customer.ID)
+
+	suite.Require().ErrorIs(err, ce.CustomerHasActiveSubscriptionsError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Update", ctx, suite.customer)
+}
+
+func (suite *CustomerServiceTestSuite) Test_DeleteSoftDeletesCustomer() {
+	ctx := context.Background()
+	suite.customer.Active = true
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.MockRepo.On("HasActiveSubscriptions", ctx, suite.customer.ID).Return(false, nil)
+	suite.MockRepo.On("Update", ctx, suite.customer).Return(suite.customer, nil)
+
+	customer, err := suite.cs.Delete(ctx, suite.customer.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().True(customer.IsDeleted())
+	suite.Require().False(customer.Active)
+}
+
+func (suite *CustomerServiceTestSuite) Test_UpdateFailsWhenCustomerIsDeleted() {
+	ctx := context.Background()
+	deletedAt := time.Now().UTC()
+	suite.customer.DeletedAt = &deletedAt
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+
+	_, err := suite.cs.Update(ctx, suite.customer.ID, &models.UpdateCustomerRequest{FirstName: "Jane"})
+
+	suite.Require().ErrorIs(err, ce.CustomerNotFoundError)
+}
+
+func (suite *CustomerServiceTestSuite) Test_PurgeFailsWhenCustomerIsNotDeleted() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+
+	_, err := suite.cs.Purge(ctx, suite.customer.ID, &models.PurgeRequest{Reason: "gdpr request", RequestedBy: "ops@mail.com"})
+
+	suite.Require().ErrorIs(err, ce.CustomerNotDeletedError)
+}
+
+func (suite *CustomerServiceTestSuite) Test_PurgeRecordsRequester() {
+	ctx := context.Background()
+	deletedAt := time.Now().UTC()
+	suite.customer.DeletedAt = &deletedAt
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.MockRepo.On("Purge", ctx, mock.Anything).Return(&models.Purge{EntityID: suite.customer.ID, RequestedBy: "ops@mail.com"}, nil)
+
+	_, err := suite.cs.Purge(ctx, suite.customer.ID, &models.PurgeRequest{Reason: "gdpr request", RequestedBy: "ops@mail.com"})
+
+	suite.Require().Nil(err)
+	purge := suite.MockRepo.Calls[1].Arguments.Get(1).(*models.Purge)
+	suite.Require().Equal("customer", purge.EntityType)
+	suite.Require().Equal(suite.customer.ID, purge.EntityID)
+	suite.Require().Equal("gdpr request", purge.Reason)
+	suite.EventsMock.AssertCalled(suite.T(), "PublishCustomer", ctx, models.CustomerPurged, suite.customer.ID, (*models.Customer)(nil))
+}
+
+func (suite *CustomerServiceTestSuite) Test_CreateCustomerFailsWhenDefaultCurrencyIsInactive() {
+	ctx := context.Background()
+	suite.customer.DefaultCurrency = "EUR"
+	suite.MockRepo.On("GetByEmail", ctx, suite.customer.Email).Return(&models.Customer{}, ce.CustomerNotFoundError)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{Code: "EUR"}, nil)
+
+	_, err := suite.cs.Create(ctx, suite.customer)
+
+	suite.Require().ErrorIs(err, ce.CurrencyInactiveError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Create", ctx, suite.customer)
+}
+
+func (suite *CustomerServiceTestSuite) Test_UpdateSetsDefaultCurrencyAndPaymentTerms() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{Code: "EUR", Active: true}, nil)
+	suite.MockRepo.On("Update", ctx, suite.customer).Return(suite.customer, nil)
+
+	customer, err := suite.cs.Update(ctx, suite.customer.ID, &models.UpdateCustomerRequest{DefaultCurrency: "EUR", PaymentTerms: models.Net30})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("EUR", customer.DefaultCurrency)
+	suite.Require().Equal(models.Net30, customer.PaymentTerms)
+}
+
+func (suite *CustomerServiceTestSuite) Test_StatementDefaultsToNow() {
+	ctx := context.Background()
+	currencyID := utils.GetNewUUID()
+	closedAt := time.Now().UTC().Add(-time.Hour)
+	bills := []*models.Bill{{ID: utils.GetNewUUID(), CurrencyID: currencyID, Status: "closed", TotalAmount: 10, ClosedAt: &closedAt}}
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(suite.customer, nil)
+	suite.BillMockRepo.On("ListClosedByCustomerID", ctx, suite.customer.ID, mock.Anything).Return(bills, nil)
+	suite.CurrencyMockRepo.On("GetByID", ctx, currencyID).Return(&models.Currency{ID: currencyID, Code: "USD", MinorUnits: 2}, nil).Once()
+	payments := []*models.JournalEntry{{Kind: models.JournalPayment, BillID: bills[0].ID, CreatedAt: time.Now().UTC().Add(-time.Minute),
+		Postings: []*models.Posting{{CurrencyCode: "USD", Debit: 4}, {CurrencyCode: "USD", Credit: 4}}}}
+	suite.LedgerMockRepo.On("ListByCustomerID", ctx, suite.customer.ID, []string{models.JournalPayment, models.JournalCredit}, mock.Anything).Return(payments, nil)
+
+	statement, err := suite.cs.Statement(ctx, suite.customer.ID, &models.StatementRequest{})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(statement.Accounts, 1)
+	suite.Require().Equal(6.0, statement.Accounts[0].ClosingBalance)
+	suite.Require().WithinDuration(time.Now(), statement.To, time.Minute)
+}
+
+func (suite *CustomerServiceTestSuite) Test_StatementFailsWhenCustomerNotFound() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetByID", ctx, suite.customer.ID).Return(&models.Customer{}, ce.CustomerNotFoundError)
+
+	_, err := suite.cs.Statement(ctx, suite.customer.ID, &models.StatementRequest{})
+
+	suite.Require().ErrorIs(err, ce.CustomerNotFoundError)
+}
+
 func TestCustomerServiceTestSuite(t *testing.T) {
 	suite.Run(t, new(CustomerServiceTestSuite))
 }
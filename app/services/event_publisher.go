@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+)
+
+// EventPublisher publishes the domain events of committed changes. The change
+// has already been made, so publishers log failures instead of returning them.
+type EventPublisher interface {
+	PublishBill(ctx context.Context, eventType string, bill *models.Bill)
+	PublishLineItem(ctx context.Context, eventType string, lineItem *models.LineItem)
+	PublishCustomer(ctx context.Context, eventType string, customerID string, customer *models.Customer)
+	PublishCurrency(ctx context.Context, eventType string, currency *models.Currency)
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// ExchangeRateProvider is a source of exchange rates that are synced into the
+// exchange rate store.
+type ExchangeRateProvider interface {
+	Rates(context.Context) ([]*models.ExchangeRate, error)
+}
+
+type fileExchangeRateProvider struct {
+	path string
+}
+
+// NewFileExchangeRateProvider returns a provider reading rates from a JSON file
+// holding a list of objects with BaseCurrency, QuoteCurrency, Rate and
+// EffectiveDate fields.
+func NewFileExchangeRateProvider(path string) ExchangeRateProvider {
+	return &fileExchangeRateProvider{
+		path: path,
+	}
+}
+
+func (fp *fileExchangeRateProvider) Rates(ctx context.Context) ([]*models.ExchangeRate, error) {
+	content, err := os.ReadFile(fp.path)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while reading exchange rates file", "path", fp.path, "error", err)
+		return []*models.ExchangeRate{}, err
+	}
+
+	requests := []*models.CreateExchangeRateRequest{}
+	err = json.Unmarshal(content, &requests)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while parsing exchange rates file", "path", fp.path, "error", err)
+		return []*models.ExchangeRate{}, err
+	}
+
+	rates := []*models.ExchangeRate{}
+	for _, request := range requests {
+		if !request.IsValid() {
+			logging.From(ctx).Warn("skipping invalid exchange rate in file", "base", request.BaseCurrency, "quote", request.QuoteCurrency, "path", fp.path)
+			continue
+		}
+		rates = append(rates, request.ToExchangeRate())
+	}
+
+	return rates, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+type exchangeRateService struct {
+	repository repository.ExchangeRateRepository
+	provider   ExchangeRateProvider
+}
+
+type ExchangeRateService interface {
+	Create(context.Context, *models.ExchangeRate) (*models.ExchangeRate, error)
+	GetRate(context.Context, string, string, time.Time) (*models.ExchangeRate, error)
+	Sync(context.Context) ([]*models.ExchangeRate, error)
+}
+
+// NewExchangeRateService returns the exchange rate service. provider may be nil
+// when rates are only created through the api.
+func NewExchangeRateService(repository repository.ExchangeRateRepository, provider ExchangeRateProvider) ExchangeRateService {
+	return &exchangeRateService{
+		repository: repository,
+		provider:   provider,
+	}
+}
+
+func (es *exchangeRateService) Create(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	rate.ID = utils.GetNewUUID()
+	rate.CreatedAt = time.Now().UTC()
+
+	rate, err := es.repository.Upsert(ctx, rate)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while storing exchange rate", "error", err)
+		return &models.ExchangeRate{}, err
+	}
+
+	return rate, nil
+}
+
+// GetRate returns the rate converting base to quote effective at the given
+// time. When only the opposite direction is stored its inverse is returned.
+func (es *exchangeRateService) GetRate(ctx context.Context, base string, quote string, at time.Time) (*models.ExchangeRate, error) {
+	if base == quote {
+		return &models.ExchangeRate{BaseCurrency: base, QuoteCurrency: quote, Rate: 1, EffectiveDate: at}, nil
+	}
+
+	rate, err := es.repository.GetEffective(ctx, base, quote, at)
+	if err == nil {
+		return rate, nil
+	}
+
+	if !errors.Is(err, ce.ExchangeRateNotFoundError) {
+		logging.From(ctx).Error("error occurred while fetching exchange rate", "base", base, "quote", quote, "error", err)
+		return &models.ExchangeRate{}, err
+	}
+
+	inverse, err := es.repository.GetEffective(ctx, quote, base, at)
+	if err != nil {
+		logging.From(ctx).Warn("exchange rate not found", "base", base, "quote", quote, "at", at)
+		return &models.ExchangeRate{}, err
+	}
+
+	return inverse.Inverse(), nil
+}
+
+// Sync stores the rates of the provider and returns them.
+func (es *exchangeRateService) Sync(ctx context.Context) ([]*models.ExchangeRate, error) {
+	if es.provider == nil {
+		return []*models.ExchangeRate{}, ce.ExchangeRateProviderNotConfiguredError
+	}
+
+	rates, err := es.provider.Rates(ctx)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching exchange rates from provider", "error", err)
+		return []*models.ExchangeRate{}, err
+	}
+
+	synced := []*models.ExchangeRate{}
+	for _, rate := range rates {
+		rate, err = es.Create(ctx, rate)
+		if err != nil {
+			return synced, err
+		}
+		synced = append(synced, rate)
+	}
+
+	logging.From(ctx).Info("synced exchange rates", "synced", len(synced))
+	return synced, nil
+}
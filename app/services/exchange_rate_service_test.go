@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExchangeRateServiceTestSuite struct {
+	suite.Suite
+	ExchangeRateMockRepo *repository.MockExchangeRateRepository
+	es                   ExchangeRateService
+	at                   time.Time
+}
+
+func (suite *ExchangeRateServiceTestSuite) SetupTest() {
+	suite.ExchangeRateMockRepo = new(repository.MockExchangeRateRepository)
+	suite.es = NewExchangeRateService(suite.ExchangeRateMockRepo, nil)
+	suite.at = time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_GetRateReturnsStoredRate() {
+	ctx := context.Background()
+	suite.ExchangeRateMockRepo.On("GetEffective", ctx, "USD", "EUR", suite.at).Return(&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "EUR", Rate: 0.9}, nil)
+
+	rate, err := suite.es.GetRate(ctx, "USD", "EUR", suite.at)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(0.9, rate.Rate)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_GetRateFallsBackToInverseRate() {
+	ctx := context.Background()
+	suite.ExchangeRateMockRepo.On("GetEffective", ctx, "USD", "EUR", suite.at).Return(&models.ExchangeRate{}, ce.ExchangeRateNotFoundError)
+	suite.ExchangeRateMockRepo.On("GetEffective", ctx, "EUR", "USD", suite.at).Return(&models.ExchangeRate{BaseCurrency: "EUR", QuoteCurrency: "USD", Rate: 1.25}, nil)
+
+	rate, err := suite.es.GetRate(ctx, "USD", "EUR", suite.at)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("USD", rate.BaseCurrency)
+	suite.Require().Equal("EUR", rate.QuoteCurrency)
+	suite.Require().Equal(0.8, rate.Rate)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_GetRateFailsWhenNoRateInEitherDirection() {
+	ctx := context.Background()
+	suite.ExchangeRateMockRepo.On("GetEffective", ctx, mock.Anything, mock.Anything, suite.at).Return(&models.ExchangeRate{}, ce.ExchangeRateNotFoundError)
+
+	_, err := suite.es.GetRate(ctx, "USD", "EUR", suite.at)
+
+	suite.Require().ErrorIs(err, ce.ExchangeRateNotFoundError)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_GetRateDoesNotFallBackOnOtherErrors() {
+	ctx := context.Background()
+	testError := errors.New("test error")
+	suite.ExchangeRateMockRepo.On("GetEffective", ctx, "USD", "EUR", suite.at).Return(&models.ExchangeRate{}, testError)
+
+	_, err := suite.es.GetRate(ctx, "USD", "EUR", suite.at)
+
+	suite.Require().Equal(testError, err)
+	suite.ExchangeRateMockRepo.AssertNumberOfCalls(suite.T(), "GetEffective", 1)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_SyncFailsWithoutProvider() {
+	_, err := suite.es.Sync(context.Background())
+
+	suite.Require().ErrorIs(err, ce.ExchangeRateProviderNotConfiguredError)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_SyncStoresRatesFromFile() {
+	ctx := context.Background()
+	path := filepath.Join(suite.T().TempDir(), "rates.json")
+	content := `[
+		{"BaseCurrency": "USD", "QuoteCurrency": "EUR", "Rate": 0.9, "EffectiveDate": "2024-03-01T00:00:00Z"},
+		{"BaseCurrency": "USD", "QuoteCurrency": "USD", "Rate": 1, "EffectiveDate": "2024-03-01T00:00:00Z"}
+	]`
+	suite.Require().Nil(os.WriteFile(path, []byte(content), 0600))
+
+	suite.ExchangeRateMockRepo.On("Upsert", ctx, mock.Anything).Return(&models.ExchangeRate{BaseCurrency: "USD", QuoteCurrency: "EUR", Rate: 0.9}, nil)
+	es := NewExchangeRateService(suite.ExchangeRateMockRepo, NewFileExchangeRateProvider(path))
+
+	rates, err := es.Sync(ctx)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, len(rates))
+	stored := suite.ExchangeRateMockRepo.Calls[0].Arguments.Get(1).(*models.ExchangeRate)
+	suite.Require().Equal(time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), stored.EffectiveDate)
+}
+
+func (suite *ExchangeRateServiceTestSuite) Test_SyncFailsWhenFileIsMissing() {
+	es := NewExchangeRateService(suite.ExchangeRateMockRepo, NewFileExchangeRateProvider(filepath.Join(suite.T().TempDir(), "missing.json")))
+
+	_, err := es.Sync(context.Background())
+
+	suite.Require().Error(err)
+}
+
+func TestExchangeRateServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ExchangeRateServiceTestSuite))
+}
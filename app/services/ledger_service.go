@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+type ledgerService struct {
+	repository repository.LedgerRepository
+}
+
+type LedgerService interface {
+	Post(context.Context, *models.LedgerTransfer) (*models.JournalEntry, error)
+	BillBalance(context.Context, string, string) (float64, error)
+	ListByBillID(context.Context, string) (*models.JournalEntries, error)
+	Check(context.Context) (*models.LedgerCheck, error)
+}
+
+func NewLedgerService(repository repository.LedgerRepository) LedgerService {
+	return &ledgerService{
+		repository: repository,
+	}
+}
+
+// Post records the transfer as a balanced journal entry between the accounts
+// of its kind. Transfers of a zero amount move nothing and are not recorded.
+func (ls *ledgerService) Post(ctx context.Context, transfer *models.LedgerTransfer) (*models.JournalEntry, error) {
+	debitCode, creditCode, ok := models.JournalAccounts(transfer.Kind)
+	if !ok {
+		return &models.JournalEntry{}, fmt.Errorf("unknown journal entry kind %s", transfer.Kind)
+	}
+
+	if transfer.Amount == 0 {
+		return &models.JournalEntry{}, nil
+	}
+
+	debit, err := ls.account(ctx, debitCode, transfer.CustomerID)
+	if err != nil {
+		return &models.JournalEntry{}, err
+	}
+
+	credit, err := ls.account(ctx, creditCode, transfer.CustomerID)
+	if err != nil {
+		return &models.JournalEntry{}, err
+	}
+
+	entry := transfer.JournalEntry(debit, credit)
+	if !entry.IsBalanced() {
+		logging.From(ctx).Warn("journal entry does not balance", "kind", transfer.Kind, "reference_id", transfer.ReferenceID)
+		return &models.JournalEntry{}, ce.JournalEntryUnbalancedError.WithID(transfer.ReferenceID)
+	}
+
+	entry.ID = utils.GetNewUUID()
+	for _, posting := range entry.Postings {
+		posting.ID = utils.GetNewUUID()
+	}
+
+	entry, err = ls.repository.Post(ctx, entry)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while posting journal entry", "kind", transfer.Kind, "reference_id", transfer.ReferenceID, "error", err)
+		return &models.JournalEntry{}, err
+	}
+
+	return entry, nil
+}
+
+// account returns the ledger account with the code, the one of the customer
+// for accounts kept per customer.
+func (ls *ledgerService) account(ctx context.Context, code string, customerID string) (*models.LedgerAccount, error) {
+	account := &models.LedgerAccount{
+		ID:   utils.GetNewUUID(),
+		Code: code,
+		Type: models.AccountType(code),
+	}
+	if models.IsCustomerAccount(code) {
+		account.CustomerID = customerID
+	}
+
+	account, err := ls.repository.GetOrCreateAccount(ctx, account)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching account of customer", "code", code, "customer_id", customerID, "error", err)
+		return &models.LedgerAccount{}, err
+	}
+
+	return account, nil
+}
+
+// BillBalance returns the balance of the bill on the account with the code,
+// debits less credits.
+func (ls *ledgerService) BillBalance(ctx context.Context, billID string, code string) (float64, error) {
+	balance, err := ls.repository.BillBalance(ctx, billID, code)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching balance of bill", "code", code, "bill_id", billID, "error", err)
+		return 0, err
+	}
+
+	return balance, nil
+}
+
+func (ls *ledgerService) ListByBillID(ctx context.Context, billID string) (*models.JournalEntries, error) {
+	entries, err := ls.repository.ListByBillID(ctx, billID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing journal entries of bill", "bill_id", billID, "error", err)
+		return &models.JournalEntries{}, err
+	}
+
+	return &models.JournalEntries{Entries: entries}, nil
+}
+
+// Check verifies that every journal entry balances in every currency.
+func (ls *ledgerService) Check(ctx context.Context) (*models.LedgerCheck, error) {
+	imbalances, err := ls.repository.ListImbalances(ctx)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while checking the ledger", "error", err)
+		return &models.LedgerCheck{}, err
+	}
+
+	return &models.LedgerCheck{Balanced: len(imbalances) == 0, Imbalances: imbalances}, nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LedgerServiceTestSuite struct {
+	suite.Suite
+	MockRepo *repository.MockLedgerRepository
+	ls       LedgerService
+}
+
+func (suite *LedgerServiceTestSuite) SetupTest() {
+	suite.MockRepo = new(repository.MockLedgerRepository)
+	suite.ls = NewLedgerService(suite.MockRepo)
+}
+
+func (suite *LedgerServiceTestSuite) account(code string, customerID string) *models.LedgerAccount {
+	return &models.LedgerAccount{ID: code + " id", Code: code, Type: models.AccountType(code), CustomerID: customerID}
+}
+
+func (suite *LedgerServiceTestSuite) Test_PostRecordsBalancedEntryOnAccountsOfKind() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetOrCreateAccount", ctx, mock.MatchedBy(func(a *models.LedgerAccount) bool {
+		return a.Code == models.CashAccount && a.CustomerID == ""
+	})).Return(suite.account(models.CashAccount, ""), nil)
+	suite.MockRepo.On("GetOrCreateAccount", ctx, mock.MatchedBy(func(a *models.LedgerAccount) bool {
+		return a.Code == models.ReceivableAccount && a.CustomerID == "customer id"
+	})).Return(suite.account(models.ReceivableAccount, "customer id"), nil)
+	suite.MockRepo.On("Post", ctx, mock.Anything).Return(&models.JournalEntry{}, nil)
+
+	_, err := suite.ls.Post(ctx, &models.LedgerTransfer{
+		Kind: models.JournalPayment, ReferenceID: "txn-01", BillID: "bill id", CustomerID: "customer id", CurrencyCode: "USD", Amount: 20,
+	})
+
+	suite.Require().Nil(err)
+	entry := suite.MockRepo.Calls[2].Arguments.Get(1).(*models.JournalEntry)
+	suite.Require().NotEmpty(entry.ID)
+	suite.Require().Len(entry.Postings, 2)
+	suite.Equal("cash id", entry.Postings[0].AccountID)
+	suite.Equal(20.0, entry.Postings[0].Debit)
+	suite.Equal("receivable id", entry.Postings[1].AccountID)
+	suite.Equal(20.0, entry.Postings[1].Credit)
+	suite.NotEmpty(entry.Postings[0].ID)
+}
+
+func (suite *LedgerServiceTestSuite) Test_PostSkipsZeroAmounts() {
+	entry, err := suite.ls.Post(context.Background(), &models.LedgerTransfer{Kind: models.JournalBillClosed, Amount: 0})
+
+	suite.Require().Nil(err)
+	suite.Require().Empty(entry.Postings)
+	suite.MockRepo.AssertNotCalled(suite.T(), "Post", mock.Anything, mock.Anything)
+}
+
+func (suite *LedgerServiceTestSuite) Test_PostFailsForUnknownKind() {
+	_, err := suite.ls.Post(context.Background(), &models.LedgerTransfer{Kind: "refund", Amount: 1})
+
+	suite.Require().NotNil(err)
+}
+
+func (suite *LedgerServiceTestSuite) Test_CheckReportsImbalances() {
+	ctx := context.Background()
+	imbalances := []*models.LedgerImbalance{{JournalEntryID: "entry id", CurrencyCode: "USD", Debit: 10, Credit: 9}}
+	suite.MockRepo.On("ListImbalances", ctx).Return(imbalances, nil)
+
+	check, err := suite.ls.Check(ctx)
+
+	suite.Require().Nil(err)
+	suite.Require().False(check.Balanced)
+	suite.Require().Equal(imbalances, check.Imbalances)
+}
+
+func (suite *LedgerServiceTestSuite) Test_CheckFailsWhenErrorIsOccurred() {
+	ctx := context.Background()
+	testError := errors.New("test error")
+	suite.MockRepo.On("ListImbalances", ctx).Return([]*models.LedgerImbalance{}, testError)
+
+	_, err := suite.ls.Check(ctx)
+
+	suite.Require().Equal(testError, err)
+}
+
+func TestLedgerServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(LedgerServiceTestSuite))
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/stretchr/testify/mock"
@@ -21,6 +22,36 @@ func (m *CustomerServiceMock) GetByID(ctx context.Context, id string) (*models.C
 	return args.Get(0).(*models.Customer), args.Error(1)
 }
 
+func (m *CustomerServiceMock) List(ctx context.Context, request *models.ListCustomersRequest) (*models.CustomerList, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.CustomerList), args.Error(1)
+}
+
+func (m *CustomerServiceMock) Update(ctx context.Context, id string, request *models.UpdateCustomerRequest) (*models.Customer, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *CustomerServiceMock) Archive(ctx context.Context, id string) (*models.Customer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *CustomerServiceMock) Delete(ctx context.Context, id string) (*models.Customer, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *CustomerServiceMock) Purge(ctx context.Context, id string, request *models.PurgeRequest) (*models.Purge, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*models.Purge), args.Error(1)
+}
+
+func (m *CustomerServiceMock) Statement(ctx context.Context, id string, request *models.StatementRequest) (*models.Statement, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*models.Statement), args.Error(1)
+}
+
 type CurrencyServiceMock struct {
 	mock.Mock
 }
@@ -35,6 +66,31 @@ func (m *CurrencyServiceMock) GetByID(ctx context.Context, id string) (*models.C
 	return args.Get(0).(*models.Currency), args.Error(1)
 }
 
+func (m *CurrencyServiceMock) List(ctx context.Context, includeInactive bool) ([]*models.Currency, error) {
+	args := m.Called(ctx, includeInactive)
+	return args.Get(0).([]*models.Currency), args.Error(1)
+}
+
+func (m *CurrencyServiceMock) Update(ctx context.Context, id string, request *models.UpdateCurrencyRequest) (*models.Currency, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*models.Currency), args.Error(1)
+}
+
+func (m *CurrencyServiceMock) Activate(ctx context.Context, id string) (*models.Currency, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Currency), args.Error(1)
+}
+
+func (m *CurrencyServiceMock) Deactivate(ctx context.Context, id string) (*models.Currency, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Currency), args.Error(1)
+}
+
+func (m *CurrencyServiceMock) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 type BillServiceMock struct {
 	mock.Mock
 }
@@ -64,7 +120,335 @@ func (m *BillServiceMock) Close(ctx context.Context, id string) (*models.Bill, e
 	return args.Get(0).(*models.Bill), args.Error(1)
 }
 
-func (m *BillServiceMock) Invoice(ctx context.Context, billID string) (*models.Invoice, error) {
-	args := m.Called(ctx, billID)
+func (m *BillServiceMock) Invoice(ctx context.Context, billID string, currencyCode string) (*models.Invoice, error) {
+	args := m.Called(ctx, billID, currencyCode)
 	return args.Get(0).(*models.Invoice), args.Error(1)
 }
+
+func (m *BillServiceMock) RecordPayment(ctx context.Context, billID string, request *models.PaymentRequest) (*models.JournalEntry, error) {
+	args := m.Called(ctx, billID, request)
+	return args.Get(0).(*models.JournalEntry), args.Error(1)
+}
+
+func (m *BillServiceMock) Credit(ctx context.Context, billID string, request *models.CreditRequest) (*models.JournalEntry, error) {
+	args := m.Called(ctx, billID, request)
+	return args.Get(0).(*models.JournalEntry), args.Error(1)
+}
+
+func (m *BillServiceMock) CountOpen(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type SubscriptionServiceMock struct {
+	mock.Mock
+}
+
+func (m *SubscriptionServiceMock) Create(ctx context.Context, request *models.CreateSubscriptionRequest) (*models.Subscription, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionServiceMock) GetByID(ctx context.Context, id string) (*models.Subscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionServiceMock) Cancel(ctx context.Context, id string) (*models.Subscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+func (m *SubscriptionServiceMock) AddItem(ctx context.Context, subscriptionID string, request *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error) {
+	args := m.Called(ctx, subscriptionID, request)
+	return args.Get(0).(*models.SubscriptionItemChange), args.Error(1)
+}
+
+func (m *SubscriptionServiceMock) UpdateItem(ctx context.Context, subscriptionID string, itemID string, request *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error) {
+	args := m.Called(ctx, subscriptionID, itemID, request)
+	return args.Get(0).(*models.SubscriptionItemChange), args.Error(1)
+}
+
+func (m *SubscriptionServiceMock) RemoveItem(ctx context.Context, subscriptionID string, itemID string) (*models.SubscriptionItemChange, error) {
+	args := m.Called(ctx, subscriptionID, itemID)
+	return args.Get(0).(*models.SubscriptionItemChange), args.Error(1)
+}
+
+type CatalogServiceMock struct {
+	mock.Mock
+}
+
+func (m *CatalogServiceMock) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	args := m.Called(ctx, product)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *CatalogServiceMock) GetProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *CatalogServiceMock) ListProducts(ctx context.Context, includeArchived bool) ([]*models.Product, error) {
+	args := m.Called(ctx, includeArchived)
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *CatalogServiceMock) UpdateProduct(ctx context.Context, id string, request *models.UpdateProductRequest) (*models.Product, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *CatalogServiceMock) ArchiveProduct(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *CatalogServiceMock) CreatePlan(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *CatalogServiceMock) GetPlan(ctx context.Context, id string) (*models.Plan, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *CatalogServiceMock) ListPlans(ctx context.Context, productID string) ([]*models.Plan, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*models.Plan), args.Error(1)
+}
+
+func (m *CatalogServiceMock) UpdatePlan(ctx context.Context, id string, request *models.UpdatePlanRequest) (*models.Plan, error) {
+	args := m.Called(ctx, id, request)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *CatalogServiceMock) ArchivePlan(ctx context.Context, id string) (*models.Plan, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *CatalogServiceMock) CreatePrice(ctx context.Context, request *models.CreatePriceRequest) (*models.Price, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+func (m *CatalogServiceMock) GetPrice(ctx context.Context, id string) (*models.Price, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+func (m *CatalogServiceMock) ListPrices(ctx context.Context, planID string) ([]*models.Price, error) {
+	args := m.Called(ctx, planID)
+	return args.Get(0).([]*models.Price), args.Error(1)
+}
+
+func (m *CatalogServiceMock) ArchivePrice(ctx context.Context, id string) (*models.Price, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+type UsageServiceMock struct {
+	mock.Mock
+}
+
+func (m *UsageServiceMock) CreateMeter(ctx context.Context, meter *models.Meter) (*models.Meter, error) {
+	args := m.Called(ctx, meter)
+	return args.Get(0).(*models.Meter), args.Error(1)
+}
+
+func (m *UsageServiceMock) GetMeter(ctx context.Context, id string) (*models.Meter, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Meter), args.Error(1)
+}
+
+func (m *UsageServiceMock) IngestEvent(ctx context.Context, request *models.UsageEventRequest) (*models.UsageEvent, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.UsageEvent), args.Error(1)
+}
+
+func (m *UsageServiceMock) RateBill(ctx context.Context, billID string) ([]*models.LineItem, error) {
+	args := m.Called(ctx, billID)
+	return args.Get(0).([]*models.LineItem), args.Error(1)
+}
+
+type ExchangeRateServiceMock struct {
+	mock.Mock
+}
+
+func (m *ExchangeRateServiceMock) Create(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+func (m *ExchangeRateServiceMock) GetRate(ctx context.Context, base string, quote string, at time.Time) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, base, quote, at)
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+func (m *ExchangeRateServiceMock) Sync(ctx context.Context) ([]*models.ExchangeRate, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.ExchangeRate), args.Error(1)
+}
+
+type LedgerServiceMock struct {
+	mock.Mock
+}
+
+func (m *LedgerServiceMock) Post(ctx context.Context, transfer *models.LedgerTransfer) (*models.JournalEntry, error) {
+	args := m.Called(ctx, transfer)
+	return args.Get(0).(*models.JournalEntry), args.Error(1)
+}
+
+func (m *LedgerServiceMock) BillBalance(ctx context.Context, billID string, code string) (float64, error) {
+	args := m.Called(ctx, billID, code)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *LedgerServiceMock) ListByBillID(ctx context.Context, billID string) (*models.JournalEntries, error) {
+	args := m.Called(ctx, billID)
+	return args.Get(0).(*models.JournalEntries), args.Error(1)
+}
+
+func (m *LedgerServiceMock) Check(ctx context.Context) (*models.LedgerCheck, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*models.LedgerCheck), args.Error(1)
+}
+
+type AuditServiceMock struct {
+	mock.Mock
+}
+
+func (m *AuditServiceMock) Record(ctx context.Context, action string, entityType string, entityID string, before any, after any) {
+	m.Called(ctx, action, entityType, entityID, before, after)
+}
+
+func (m *AuditServiceMock) List(ctx context.Context, request *models.ListAuditEventsRequest) (*models.AuditEventList, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.AuditEventList), args.Error(1)
+}
+
+type WebhookServiceMock struct {
+	mock.Mock
+}
+
+func (m *WebhookServiceMock) Register(ctx context.Context, request *models.WebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+}
+
+func (m *WebhookServiceMock) List(ctx context.Context) (*models.WebhookEndpoints, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*models.WebhookEndpoints), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Disable(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Publish(ctx context.Context, eventType string, billID string, data any) {
+	m.Called(ctx, eventType, billID, data)
+}
+
+func (m *WebhookServiceMock) ListDeliveries(ctx context.Context, endpointID string, request *models.ListWebhookDeliveriesRequest) (*models.WebhookDeliveryList, error) {
+	args := m.Called(ctx, endpointID, request)
+	return args.Get(0).(*models.WebhookDeliveryList), args.Error(1)
+}
+
+func (m *WebhookServiceMock) Redeliver(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+type EventPublisherMock struct {
+	mock.Mock
+}
+
+func (m *EventPublisherMock) PublishBill(ctx context.Context, eventType string, bill *models.Bill) {
+	m.Called(ctx, eventType, bill)
+}
+
+func (m *EventPublisherMock) PublishLineItem(ctx context.Context, eventType string, lineItem *models.LineItem) {
+	m.Called(ctx, eventType, lineItem)
+}
+
+func (m *EventPublisherMock) PublishCustomer(ctx context.Context, eventType string, customerID string, customer *models.Customer) {
+	m.Called(ctx, eventType, customerID, customer)
+}
+
+func (m *EventPublisherMock) PublishCurrency(ctx context.Context, eventType string, currency *models.Currency) {
+	m.Called(ctx, eventType, currency)
+}
+
+type ProjectionServiceMock struct {
+	mock.Mock
+}
+
+func (m *ProjectionServiceMock) ProjectBillEvent(ctx context.Context, event *models.BillEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *ProjectionServiceMock) ListBillSummaries(ctx context.Context, customerID string) (*models.BillSummaries, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).(*models.BillSummaries), args.Error(1)
+}
+
+type NotificationServiceMock struct {
+	mock.Mock
+}
+
+func (m *NotificationServiceMock) SendInvoice(ctx context.Context, billID string) (*models.InvoiceDelivery, error) {
+	args := m.Called(ctx, billID)
+	return args.Get(0).(*models.InvoiceDelivery), args.Error(1)
+}
+
+func (m *NotificationServiceMock) SendClosedInvoice(ctx context.Context, event *models.BillEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *NotificationServiceMock) ListInvoiceDeliveries(ctx context.Context, billID string) (*models.InvoiceDeliveries, error) {
+	args := m.Called(ctx, billID)
+	return args.Get(0).(*models.InvoiceDeliveries), args.Error(1)
+}
+
+func (m *NotificationServiceMock) GetTemplate(ctx context.Context, name string, locale string) (*models.EmailTemplate, error) {
+	args := m.Called(ctx, name, locale)
+	return args.Get(0).(*models.EmailTemplate), args.Error(1)
+}
+
+func (m *NotificationServiceMock) UpdateTemplate(ctx context.Context, name string, locale string, request *models.EmailTemplateRequest) (*models.EmailTemplate, error) {
+	args := m.Called(ctx, name, locale, request)
+	return args.Get(0).(*models.EmailTemplate), args.Error(1)
+}
+
+func (m *NotificationServiceMock) ResetTemplate(ctx context.Context, name string, locale string) (*models.EmailTemplate, error) {
+	args := m.Called(ctx, name, locale)
+	return args.Get(0).(*models.EmailTemplate), args.Error(1)
+}
+
+type APIKeyServiceMock struct {
+	mock.Mock
+}
+
+func (m *APIKeyServiceMock) Issue(ctx context.Context, request *models.APIKeyRequest) (*models.IssuedAPIKey, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).(*models.IssuedAPIKey), args.Error(1)
+}
+
+func (m *APIKeyServiceMock) List(ctx context.Context) (*models.APIKeys, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*models.APIKeys), args.Error(1)
+}
+
+func (m *APIKeyServiceMock) Revoke(ctx context.Context, id string) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *APIKeyServiceMock) Authenticate(ctx context.Context, key string) (*models.Principal, error) {
+	args := m.Called(ctx, key)
+	return args.Get(0).(*models.Principal), args.Error(1)
+}
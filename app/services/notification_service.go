@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/mail"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+// defaultTemplates are the email templates used for locales without a saved
+// template, named <name>.<locale>.<subject|txt|html>.tmpl.
+//
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+type notificationService struct {
+	repository         repository.NotificationRepository
+	currencyRepository repository.CurrencyRepository
+	bills              BillService
+	transport          mail.Transport
+	from               string
+}
+
+// NotificationService emails invoices to customers from editable, localized
+// templates and records every attempt per bill.
+type NotificationService interface {
+	SendInvoice(context.Context, string) (*models.InvoiceDelivery, error)
+	SendClosedInvoice(context.Context, *models.BillEvent) error
+	ListInvoiceDeliveries(context.Context, string) (*models.InvoiceDeliveries, error)
+	GetTemplate(ctx context.Context, name string, locale string) (*models.EmailTemplate, error)
+	UpdateTemplate(ctx context.Context, name string, locale string, request *models.EmailTemplateRequest) (*models.EmailTemplate, error)
+	ResetTemplate(ctx context.Context, name string, locale string) (*models.EmailTemplate, error)
+}
+
+func NewNotificationService(repository repository.NotificationRepository, currencyRepository repository.CurrencyRepository, bills BillService, transport mail.Transport, from string) NotificationService {
+	return &notificationService{
+		repository:         repository,
+		currencyRepository: currencyRepository,
+		bills:              bills,
+		transport:          transport,
+		from:               from,
+	}
+}
+
+// SendInvoice emails the invoice of the closed bill to the customer, in the
+// locale of the customer. The delivery is recorded whether or not the
+// transport accepts the message.
+func (ns *notificationService) SendInvoice(ctx context.Context, billID string) (*models.InvoiceDelivery, error) {
+	invoice, err := ns.bills.Invoice(ctx, billID, "")
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching invoice of bill", "bill_id", billID, "error", err)
+		return &models.InvoiceDelivery{}, err
+	}
+
+	if invoice.Status != "closed" {
+		logging.From(ctx).Warn("bill is not closed", "bill_id", billID)
+		return &models.InvoiceDelivery{}, ce.BillNotClosedError.WithID(billID)
+	}
+
+	currency, err := ns.currencyRepository.GetByCode(ctx, invoice.CurrencyCode)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching currency of bill", "currency_code", invoice.CurrencyCode, "bill_id", billID, "error", err)
+		return &models.InvoiceDelivery{}, err
+	}
+
+	template, err := ns.GetTemplate(ctx, models.InvoiceEmailTemplate, invoice.Customer.Locale)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching invoice email template for locale", "locale", invoice.Customer.Locale, "error", err)
+		return &models.InvoiceDelivery{}, err
+	}
+
+	email, err := template.Render(models.NewInvoiceEmail(invoice, currency))
+	if err != nil {
+		logging.From(ctx).Error("error occurred while rendering invoice email of bill", "bill_id", billID, "error", err)
+		return &models.InvoiceDelivery{}, err
+	}
+
+	delivery := &models.InvoiceDelivery{
+		ID:        utils.GetNewUUID(),
+		BillID:    billID,
+		Recipient: invoice.Customer.Email,
+		Locale:    template.Locale,
+		Subject:   email.Subject,
+		Status:    models.InvoiceEmailSent,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	sendErr := ns.transport.Send(ctx, &mail.Message{
+		From:    ns.from,
+		To:      invoice.Customer.Email,
+		Subject: email.Subject,
+		Text:    email.Text,
+		HTML:    email.HTML,
+	})
+	if sendErr != nil {
+		delivery.Status = models.InvoiceEmailFailed
+		delivery.Error = sendErr.Error()
+	}
+
+	delivery, err = ns.repository.CreateInvoiceDelivery(ctx, delivery)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while recording invoice delivery of bill", "bill_id", billID, "error", err)
+		return &models.InvoiceDelivery{}, err
+	}
+
+	if sendErr != nil {
+		logging.From(ctx).Error("error occurred while sending invoice email of bill", "bill_id", billID, "error", sendErr)
+		return &models.InvoiceDelivery{}, ce.InvoiceEmailNotSentError.WithID(billID).Wrap(sendErr)
+	}
+
+	return delivery, nil
+}
+
+// SendClosedInvoice emails the invoice of the bill of a bill closed event,
+// unless it was sent already. Errors are returned so the event is redelivered.
+func (ns *notificationService) SendClosedInvoice(ctx context.Context, event *models.BillEvent) error {
+	if event.Type != models.BillClosed || event.Bill == nil {
+		return nil
+	}
+
+	deliveries, err := ns.repository.ListInvoiceDeliveries(ctx, event.Bill.ID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing invoice deliveries of bill", "bill_id", event.Bill.ID, "error", err)
+		return err
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.Status == models.InvoiceEmailSent {
+			logging.From(ctx).Warn("invoice of bill already sent", "bill_id", event.Bill.ID)
+			return nil
+		}
+	}
+
+	_, err = ns.SendInvoice(ctx, event.Bill.ID)
+	return err
+}
+
+func (ns *notificationService) ListInvoiceDeliveries(ctx context.Context, billID string) (*models.InvoiceDeliveries, error) {
+	deliveries, err := ns.repository.ListInvoiceDeliveries(ctx, billID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing invoice deliveries of bill", "bill_id", billID, "error", err)
+		return &models.InvoiceDeliveries{}, err
+	}
+
+	return &models.InvoiceDeliveries{Deliveries: deliveries}, nil
+}
+
+// GetTemplate returns the template used for locale: the saved or default
+// template of the first of models.EmailTemplateLocales that has one.
+func (ns *notificationService) GetTemplate(ctx context.Context, name string, locale string) (*models.EmailTemplate, error) {
+	if !models.IsEmailTemplateName(name) {
+		logging.From(ctx).Warn("email template not found for name", "name", name)
+		return &models.EmailTemplate{}, ce.EmailTemplateNotFoundError.WithID(name)
+	}
+
+	saved, err := ns.repository.ListTemplates(ctx, name)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing email templates", "name", name, "error", err)
+		return &models.EmailTemplate{}, err
+	}
+
+	for _, candidate := range models.EmailTemplateLocales(locale) {
+		for _, template := range saved {
+			if template.Locale == candidate {
+				return template, nil
+			}
+		}
+
+		if template, ok := defaultTemplate(name, candidate); ok {
+			return template, nil
+		}
+	}
+
+	logging.From(ctx).Warn("email template not found", "name", name, "locale", locale)
+	return &models.EmailTemplate{}, ce.EmailTemplateNotFoundError.WithID(fmt.Sprintf("%s/%s", name, locale))
+}
+
+// UpdateTemplate saves the template of the locale. Templates are rendered with
+// sample data first, so templates referring to unknown fields are refused.
+func (ns *notificationService) UpdateTemplate(ctx context.Context, name string, locale string, request *models.EmailTemplateRequest) (*models.EmailTemplate, error) {
+	if !models.IsEmailTemplateName(name) {
+		logging.From(ctx).Warn("email template not found for name", "name", name)
+		return &models.EmailTemplate{}, ce.EmailTemplateNotFoundError.WithID(name)
+	}
+
+	template := &models.EmailTemplate{
+		Name:      name,
+		Locale:    locale,
+		Subject:   request.Subject,
+		Text:      request.Text,
+		HTML:      request.HTML,
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	if _, err := template.Render(sampleTemplateData(name)); err != nil {
+		logging.From(ctx).Warn("invalid email template", "name", name, "locale", locale, "error", err)
+		return &models.EmailTemplate{}, ce.InvalidEmailTemplateError.WithID(fmt.Sprintf("%s/%s", name, locale)).Wrap(err)
+	}
+
+	template, err := ns.repository.UpsertTemplate(ctx, template)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while saving email template", "name", name, "locale", locale, "error", err)
+		return &models.EmailTemplate{}, err
+	}
+
+	return template, nil
+}
+
+// ResetTemplate deletes the saved template of the locale and returns the
+// template used for it from then on.
+func (ns *notificationService) ResetTemplate(ctx context.Context, name string, locale string) (*models.EmailTemplate, error) {
+	err := ns.repository.DeleteTemplate(ctx, name, locale)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while deleting email template", "name", name, "locale", locale, "error", err)
+		return &models.EmailTemplate{}, err
+	}
+
+	return ns.GetTemplate(ctx, name, locale)
+}
+
+func defaultTemplate(name string, locale string) (*models.EmailTemplate, bool) {
+	parts := map[string]string{}
+	for _, part := range []string{"subject", "txt", "html"} {
+		content, err := defaultTemplates.ReadFile(fmt.Sprintf("templates/%s.%s.%s.tmpl", name, locale, part))
+		if err != nil {
+			return nil, false
+		}
+		parts[part] = string(content)
+	}
+
+	return &models.EmailTemplate{
+		Name:    name,
+		Locale:  locale,
+		Subject: parts["subject"],
+		Text:    parts["txt"],
+		HTML:    parts["html"],
+	}, true
+}
+
+// sampleTemplateData returns data templates of the name are checked with.
+func sampleTemplateData(name string) any {
+	dueDate := time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC)
+	invoice := &models.Invoice{
+		BillID:       "c0d7a3f2-0000-4000-8000-000000000000",
+		Description:  "Subscription",
+		CurrencyCode: "USD",
+		Status:       "closed",
+		TotalAmount:  9.99,
+		Customer:     models.InvoiceCustomer{Name: "Jane Doe", Email: "jane@example.com", Locale: models.DefaultLocale},
+		PeriodStart:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		DueDate:      &dueDate,
+		LineItems:    []models.LineItem{{Description: "Pro plan", Quantity: 1, Amount: 9.99}},
+	}
+
+	return models.NewInvoiceEmail(invoice, &models.Currency{Code: "USD", Symbol: "$", MinorUnits: 2})
+}
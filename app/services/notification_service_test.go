@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/mail"
+	"github.com/asheet-bhaskar/billing-service/pkg/mail/mailtest"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationServiceTestSuite struct {
+	suite.Suite
+	MockRepo         *repository.MockNotificationRepository
+	CurrencyMockRepo *repository.MockCurrencyRepository
+	BillServiceMock  *BillServiceMock
+	server           *mailtest.Server
+	ns               NotificationService
+	invoice          *models.Invoice
+}
+
+func (suite *NotificationServiceTestSuite) SetupTest() {
+	suite.MockRepo = new(repository.MockNotificationRepository)
+	suite.CurrencyMockRepo = new(repository.MockCurrencyRepository)
+	suite.BillServiceMock = new(BillServiceMock)
+	suite.server = mailtest.NewServer()
+	transport := mail.NewSMTPTransport(suite.server.Addr, "", "")
+	suite.ns = NewNotificationService(suite.MockRepo, suite.CurrencyMockRepo, suite.BillServiceMock, transport, "Billing <billing@example.com>")
+
+	dueDate := time.Date(2024, 2, 14, 0, 0, 0, 0, time.UTC)
+	suite.invoice = &models.Invoice{
+		BillID:       "bill id",
+		CurrencyCode: "EUR",
+		Status:       "closed",
+		TotalAmount:  12.5,
+		Customer:     models.InvoiceCustomer{Name: "Jana Schmidt", Email: "jana@example.com", Locale: "de-AT"},
+		PeriodStart:  time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		PeriodEnd:    time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+		DueDate:      &dueDate,
+		LineItems:    []models.LineItem{{Description: "<Pro> plan", Quantity: 2, Amount: 12.5}},
+	}
+}
+
+func (suite *NotificationServiceTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *NotificationServiceTestSuite) mockInvoice(ctx context.Context, saved []*models.EmailTemplate) {
+	suite.BillServiceMock.On("Invoice", ctx, "bill id", "").Return(suite.invoice, nil)
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "EUR").Return(&models.Currency{Code: "EUR", Symbol: "€", MinorUnits: 2}, nil)
+	suite.MockRepo.On("ListTemplates", ctx, models.InvoiceEmailTemplate).Return(saved, nil)
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendInvoiceRendersTemplateOfCustomerLanguage() {
+	ctx := context.Background()
+	suite.mockInvoice(ctx, []*models.EmailTemplate{})
+	suite.MockRepo.On("CreateInvoiceDelivery", ctx, mock.Anything).Return(&models.InvoiceDelivery{ID: "delivery id"}, nil)
+
+	_, err := suite.ns.SendInvoice(ctx, "bill id")
+
+	suite.Require().Nil(err)
+	messages := suite.server.Messages()
+	suite.Require().Len(messages, 1)
+	suite.Require().Equal([]string{"jana@example.com"}, messages[0].To)
+	suite.Require().Contains(messages[0].Data, "Gesamtbetrag: =E2=82=AC12.50")
+	suite.Require().Contains(messages[0].Data, "F=C3=A4llig am 14.02.2024")
+	suite.Require().Contains(messages[0].Data, "&lt;Pro&gt; plan")
+
+	delivery := suite.MockRepo.Calls[1].Arguments.Get(1).(*models.InvoiceDelivery)
+	suite.Require().Equal("bill id", delivery.BillID)
+	suite.Require().Equal("jana@example.com", delivery.Recipient)
+	suite.Require().Equal("de", delivery.Locale)
+	suite.Require().Equal("Rechnung bill id über €12.50", delivery.Subject)
+	suite.Require().Equal(models.InvoiceEmailSent, delivery.Status)
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendInvoicePrefersSavedTemplate() {
+	ctx := context.Background()
+	suite.mockInvoice(ctx, []*models.EmailTemplate{
+		{Name: models.InvoiceEmailTemplate, Locale: "de-AT", Subject: "Ihre Rechnung", Text: "{{ .Total }}", HTML: "<p>{{ .Total }}</p>"},
+	})
+	suite.MockRepo.On("CreateInvoiceDelivery", ctx, mock.Anything).Return(&models.InvoiceDelivery{ID: "delivery id"}, nil)
+
+	_, err := suite.ns.SendInvoice(ctx, "bill id")
+
+	suite.Require().Nil(err)
+	delivery := suite.MockRepo.Calls[1].Arguments.Get(1).(*models.InvoiceDelivery)
+	suite.Require().Equal("de-AT", delivery.Locale)
+	suite.Require().Equal("Ihre Rechnung", delivery.Subject)
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendInvoiceFallsBackToEnglish() {
+	ctx := context.Background()
+	suite.invoice.Customer.Locale = "fr-FR"
+	suite.mockInvoice(ctx, []*models.EmailTemplate{})
+	suite.MockRepo.On("CreateInvoiceDelivery", ctx, mock.Anything).Return(&models.InvoiceDelivery{ID: "delivery id"}, nil)
+
+	_, err := suite.ns.SendInvoice(ctx, "bill id")
+
+	suite.Require().Nil(err)
+	delivery := suite.MockRepo.Calls[1].Arguments.Get(1).(*models.InvoiceDelivery)
+	suite.Require().Equal("en", delivery.Locale)
+	suite.Require().Contains(suite.server.Messages()[0].Data, "Due on February 14, 2024.")
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendInvoiceRecordsFailedDelivery() {
+	ctx := context.Background()
+	suite.server.Reject = true
+	suite.mockInvoice(ctx, []*models.EmailTemplate{})
+	suite.MockRepo.On("CreateInvoiceDelivery", ctx, mock.Anything).Return(&models.InvoiceDelivery{ID: "delivery id"}, nil)
+
+	_, err := suite.ns.SendInvoice(ctx, "bill id")
+
+	suite.Require().ErrorIs(err, ce.InvoiceEmailNotSentError)
+	delivery := suite.MockRepo.Calls[1].Arguments.Get(1).(*models.InvoiceDelivery)
+	suite.Require().Equal(models.InvoiceEmailFailed, delivery.Status)
+	suite.Require().True(strings.Contains(delivery.Error, "554"))
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendInvoiceFailsForOpenBill() {
+	ctx := context.Background()
+	suite.invoice.Status = "open"
+	suite.BillServiceMock.On("Invoice", ctx, "bill id", "").Return(suite.invoice, nil)
+
+	_, err := suite.ns.SendInvoice(ctx, "bill id")
+
+	suite.Require().ErrorIs(err, ce.BillNotClosedError)
+	suite.Require().Empty(suite.server.Messages())
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendClosedInvoiceSkipsSentInvoices() {
+	ctx := context.Background()
+	suite.MockRepo.On("ListInvoiceDeliveries", ctx, "bill id").Return([]*models.InvoiceDelivery{
+		{ID: "failed", Status: models.InvoiceEmailFailed},
+		{ID: "sent", Status: models.InvoiceEmailSent},
+	}, nil)
+
+	err := suite.ns.SendClosedInvoice(ctx, &models.BillEvent{Type: models.BillClosed, Bill: &models.Bill{ID: "bill id"}})
+
+	suite.Require().Nil(err)
+	suite.BillServiceMock.AssertNotCalled(suite.T(), "Invoice", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendClosedInvoiceIgnoresOtherEvents() {
+	err := suite.ns.SendClosedInvoice(context.Background(), &models.BillEvent{Type: models.BillCreated, Bill: &models.Bill{ID: "bill id"}})
+
+	suite.Require().Nil(err)
+	suite.MockRepo.AssertNotCalled(suite.T(), "ListInvoiceDeliveries", mock.Anything, mock.Anything)
+}
+
+func (suite *NotificationServiceTestSuite) Test_SendClosedInvoiceReturnsErrors() {
+	ctx := context.Background()
+	suite.MockRepo.On("ListInvoiceDeliveries", ctx, "bill id").Return([]*models.InvoiceDelivery{}, errors.New("test error"))
+
+	err := suite.ns.SendClosedInvoice(ctx, &models.BillEvent{Type: models.BillClosed, Bill: &models.Bill{ID: "bill id"}})
+
+	suite.Require().NotNil(err)
+}
+
+func (suite *NotificationServiceTestSuite) Test_UpdateTemplateRefusesUnknownFields() {
+	ctx := context.Background()
+
+	_, err := suite.ns.UpdateTemplate(ctx, models.InvoiceEmailTemplate, "en", &models.EmailTemplateRequest{Subject: "Invoice", Text: "{{ .Amount }}", HTML: "<p></p>"})
+
+	suite.Require().ErrorIs(err, ce.InvalidEmailTemplateError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "UpsertTemplate", mock.Anything, mock.Anything)
+}
+
+func (suite *NotificationServiceTestSuite) Test_UpdateTemplateSavesTemplate() {
+	ctx := context.Background()
+	suite.MockRepo.On("UpsertTemplate", ctx, mock.Anything).Return(&models.EmailTemplate{}, nil)
+
+	_, err := suite.ns.UpdateTemplate(ctx, models.InvoiceEmailTemplate, "en-GB", &models.EmailTemplateRequest{Subject: "Invoice {{ .Invoice.BillID }}", Text: "{{ .Total }}", HTML: "<p>{{ .Total }}</p>"})
+
+	suite.Require().Nil(err)
+	template := suite.MockRepo.Calls[0].Arguments.Get(1).(*models.EmailTemplate)
+	suite.Require().Equal(models.InvoiceEmailTemplate, template.Name)
+	suite.Require().Equal("en-GB", template.Locale)
+}
+
+func (suite *NotificationServiceTestSuite) Test_GetTemplateFailsForUnknownName() {
+	_, err := suite.ns.GetTemplate(context.Background(), "receipt", "en")
+
+	suite.Require().ErrorIs(err, ce.EmailTemplateNotFoundError)
+}
+
+func (suite *NotificationServiceTestSuite) Test_DefaultTemplatesRender() {
+	for _, locale := range []string{"en", "de"} {
+		template, ok := defaultTemplate(models.InvoiceEmailTemplate, locale)
+		suite.Require().True(ok, locale)
+
+		_, err := template.Render(sampleTemplateData(models.InvoiceEmailTemplate))
+		suite.Require().Nil(err, locale)
+	}
+}
+
+func TestNotificationServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationServiceTestSuite))
+}
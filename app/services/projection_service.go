@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+type projectionService struct {
+	repository repository.ProjectionRepository
+}
+
+// ProjectionService keeps read models built from domain events.
+type ProjectionService interface {
+	ProjectBillEvent(context.Context, *models.BillEvent) error
+	ListBillSummaries(context.Context, string) (*models.BillSummaries, error)
+}
+
+func NewProjectionService(repository repository.ProjectionRepository) ProjectionService {
+	return &projectionService{
+		repository: repository,
+	}
+}
+
+// ProjectBillEvent updates the summary of the bill of the event. Errors are
+// returned so the event is redelivered.
+func (ps *projectionService) ProjectBillEvent(ctx context.Context, event *models.BillEvent) error {
+	if event.Bill == nil {
+		logging.From(ctx).Warn("bill event has no bill", "event_id", event.ID)
+		return nil
+	}
+
+	err := ps.repository.UpsertBillSummary(ctx, event.BillSummary())
+	if err != nil {
+		logging.From(ctx).Error("error occurred while projecting bill event", "event_id", event.ID, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+func (ps *projectionService) ListBillSummaries(ctx context.Context, customerID string) (*models.BillSummaries, error) {
+	summaries, err := ps.repository.ListBillSummaries(ctx, customerID)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing bill summaries of customer", "customer_id", customerID, "error", err)
+		return &models.BillSummaries{}, err
+	}
+
+	return &models.BillSummaries{Summaries: summaries}, nil
+}
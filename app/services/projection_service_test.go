@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type ProjectionServiceTestSuite struct {
+	suite.Suite
+	MockRepo *repository.MockProjectionRepository
+	ps       ProjectionService
+}
+
+func (suite *ProjectionServiceTestSuite) SetupTest() {
+	suite.MockRepo = new(repository.MockProjectionRepository)
+	suite.ps = NewProjectionService(suite.MockRepo)
+}
+
+func (suite *ProjectionServiceTestSuite) Test_ProjectBillEventSavesSummaryAsOfEvent() {
+	ctx := context.Background()
+	occurredAt := time.Now().UTC()
+	event := &models.BillEvent{
+		ID:         "event id",
+		Type:       models.BillClosed,
+		Bill:       &models.Bill{ID: "bill id", CustomerID: "customer id", Status: "closed", TotalAmount: 42},
+		OccurredAt: occurredAt,
+	}
+	suite.MockRepo.On("UpsertBillSummary", ctx, mock.Anything).Return(nil)
+
+	err := suite.ps.ProjectBillEvent(ctx, event)
+
+	suite.Require().Nil(err)
+	summary := suite.MockRepo.Calls[0].Arguments.Get(1).(*models.BillSummary)
+	suite.Require().Equal("bill id", summary.BillID)
+	suite.Require().Equal("customer id", summary.CustomerID)
+	suite.Require().Equal("closed", summary.Status)
+	suite.Require().Equal(42.0, summary.TotalAmount)
+	suite.Require().Equal("event id", summary.LastEventID)
+	suite.Require().Equal(occurredAt, summary.LastEventAt)
+}
+
+func (suite *ProjectionServiceTestSuite) Test_ProjectBillEventReturnsErrorForRedelivery() {
+	ctx := context.Background()
+	testError := errors.New("test error")
+	suite.MockRepo.On("UpsertBillSummary", ctx, mock.Anything).Return(testError)
+
+	err := suite.ps.ProjectBillEvent(ctx, &models.BillEvent{ID: "event id", Bill: &models.Bill{ID: "bill id"}})
+
+	suite.Require().Equal(testError, err)
+}
+
+func (suite *ProjectionServiceTestSuite) Test_ProjectBillEventSkipsEventWithoutBill() {
+	err := suite.ps.ProjectBillEvent(context.Background(), &models.BillEvent{ID: "event id"})
+
+	suite.Require().Nil(err)
+	suite.MockRepo.AssertNotCalled(suite.T(), "UpsertBillSummary", mock.Anything, mock.Anything)
+}
+
+func TestProjectionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectionServiceTestSuite))
+}
@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/app/workflows"
+	tc "github.com/asheet-bhaskar/billing-service/app/workflows/temporal"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"go.temporal.io/sdk/client"
+)
+
+type subscriptionService struct {
+	repository         repository.SubscriptionRepository
+	currencyRepository repository.CurrencyRepository
+	customerRepository repository.CustomerRepository
+	catalogRepository  repository.CatalogRepository
+	billService        BillService
+	temporalClient     tc.TemporalClient
+}
+
+type SubscriptionService interface {
+	Create(context.Context, *models.CreateSubscriptionRequest) (*models.Subscription, error)
+	GetByID(context.Context, string) (*models.Subscription, error)
+	Cancel(context.Context, string) (*models.Subscription, error)
+	AddItem(context.Context, string, *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error)
+	UpdateItem(context.Context, string, string, *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error)
+	RemoveItem(context.Context, string, string) (*models.SubscriptionItemChange, error)
+}
+
+func NewSubscriptionService(repository repository.SubscriptionRepository, currencyRepository repository.CurrencyRepository,
+	customerRepository repository.CustomerRepository, catalogRepository repository.CatalogRepository,
+	billService BillService, temporalClient tc.TemporalClient) SubscriptionService {
+	return &subscriptionService{
+		repository:         repository,
+		currencyRepository: currencyRepository,
+		customerRepository: customerRepository,
+		catalogRepository:  catalogRepository,
+		billService:        billService,
+		temporalClient:     temporalClient,
+	}
+}
+
+func (ss *subscriptionService) Create(ctx context.Context, request *models.CreateSubscriptionRequest) (*models.Subscription, error) {
+	currency, err := ss.currencyRepository.GetByCode(ctx, request.CurrencyCode)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the currency", "currency_code", request.CurrencyCode, "error", err)
+		return &models.Subscription{}, err
+	}
+
+	if !currency.Active {
+		logging.From(ctx).Warn("currency is inactive", "currency_code", request.CurrencyCode)
+		return &models.Subscription{}, ce.CurrencyInactiveError.WithID(request.CurrencyCode)
+	}
+
+	customer, err := ss.customerRepository.GetByID(ctx, request.CustomerID)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the customer", "customer_id", request.CustomerID, "error", err)
+		return &models.Subscription{}, err
+	}
+
+	if customer.IsDeleted() {
+		logging.From(ctx).Warn("customer is deleted", "customer_id", request.CustomerID)
+		return &models.Subscription{}, ce.CustomerNotFoundError.WithID(request.CustomerID)
+	}
+
+	if !customer.Active {
+		logging.From(ctx).Warn("customer is archived", "customer_id", request.CustomerID)
+		return &models.Subscription{}, ce.CustomerArchivedError.WithID(request.CustomerID)
+	}
+
+	subscription := request.ToSubscription()
+	subscription.ID = utils.GetNewUUID()
+	subscription.CustomerID = customer.ID
+	subscription.CurrencyID = currency.ID
+	subscription.Status = "active"
+	subscription.CurrentPeriodStart = request.StartDate.UTC()
+	subscription.AnchorDay = subscription.CurrentPeriodStart.Day()
+	subscription.CurrentPeriodEnd = subscription.PeriodEnd(subscription.CurrentPeriodStart)
+	subscription.CreatedAt = time.Now().UTC()
+	subscription.UpdatedAt = time.Now().UTC()
+
+	subscription, err = ss.repository.Create(ctx, subscription)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating subscription", "error", err)
+		return &models.Subscription{}, err
+	}
+
+	options := client.StartWorkflowOptions{
+		ID:        tenancy.WorkflowID(ctx, fmt.Sprintf("SUBSCRIPTION-%s", subscription.ID)),
+		TaskQueue: "CREATE_BILL_QUEUE",
+	}
+
+	_, err = ss.temporalClient.ExecuteWorkflow(tenancy.Detach(ctx), options, workflows.SubscriptionWorkflow, subscription)
+	if err != nil {
+		logging.From(ctx).Error("failed to create workflow execution for subscription", "subscription_id", subscription.ID, "error", err)
+		metrics.WorkflowStartFailures.Inc("SubscriptionWorkflow")
+	}
+
+	return subscription, nil
+}
+
+func (ss *subscriptionService) GetByID(ctx context.Context, id string) (*models.Subscription, error) {
+	subscription, err := ss.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching subscription", "subscription_id", id, "error", err)
+		return &models.Subscription{}, err
+	}
+
+	return subscription, nil
+}
+
+// Cancel marks the subscription to be cancelled once its current period ends.
+// The subscription workflow closes the current bill and stops renewing then.
+func (ss *subscriptionService) Cancel(ctx context.Context, id string) (*models.Subscription, error) {
+	subscription, err := ss.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("subscription not found", "subscription_id", id)
+		return subscription, err
+	}
+
+	if subscription.Status == "cancelled" {
+		logging.From(ctx).Warn("subscription is already cancelled", "subscription_id", id)
+		return subscription, ce.SubscriptionCancelledError.WithID(id)
+	}
+
+	subscription.CancelAtPeriodEnd = true
+	subscription, err = ss.repository.Update(ctx, subscription)
+	if err != nil {
+		logging.From(ctx).Error("error while cancelling subscription", "subscription_id", id, "error", err)
+		return subscription, err
+	}
+
+	err = ss.temporalClient.SignalWorkflow(tenancy.Detach(ctx), tenancy.WorkflowID(ctx, fmt.Sprintf("SUBSCRIPTION-%s", subscription.ID)), "", "CANCEL_SUBSCRIPTION_CHANNEL", subscription.ID)
+	if err != nil {
+		logging.From(ctx).Error("error while signalling the workflow", "error", err)
+		metrics.WorkflowSignalFailures.Inc("CANCEL_SUBSCRIPTION_CHANNEL")
+	}
+
+	return subscription, nil
+}
+
+// AddItem adds a recurring charge to the subscription. Added in the middle of a
+// period, the remaining part of the period is charged on the open bill. The
+// charge is added first, so the item is not added when it can not be charged.
+func (ss *subscriptionService) AddItem(ctx context.Context, subscriptionID string, request *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error) {
+	subscription, err := ss.activeSubscription(ctx, subscriptionID)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	item := request.ToSubscriptionItem()
+	price, name, err := ss.recurringPrice(ctx, subscription, item.PriceID)
+	if err != nil {
+		logging.From(ctx).Error("error while finding price for subscription", "price_id", item.PriceID, "subscription_id", subscriptionID, "error", err)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	change := &models.SubscriptionItemChange{LineItems: []*models.LineItem{}}
+	err = ss.prorate(ctx, subscription, change, price, name, item.Quantity, false)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	item.ID = utils.GetNewUUID()
+	item.SubscriptionID = subscription.ID
+	item.CreatedAt = time.Now().UTC()
+	item.UpdatedAt = time.Now().UTC()
+
+	item, err = ss.repository.CreateItem(ctx, item)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating subscription item", "error", err)
+		ss.reverseProrations(ctx, change)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	change.Item = item
+	return change, nil
+}
+
+// UpdateItem changes the price or quantity of a recurring charge. Changed in
+// the middle of a period, the unused part of the old charge is credited and the
+// remaining part of the new charge is charged on the open bill. The item is
+// changed once both are added, and neither is kept when the change fails.
+func (ss *subscriptionService) UpdateItem(ctx context.Context, subscriptionID string, itemID string, request *models.SubscriptionItemRequest) (*models.SubscriptionItemChange, error) {
+	subscription, err := ss.activeSubscription(ctx, subscriptionID)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	item, err := ss.subscriptionItem(ctx, subscription, itemID)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	oldPrice, oldName, err := ss.pricedItem(ctx, item.PriceID)
+	if err != nil {
+		logging.From(ctx).Error("error while finding price of subscription item", "price_id", item.PriceID, "item_id", itemID, "error", err)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	updated := request.ToSubscriptionItem()
+	newPrice, newName, err := ss.recurringPrice(ctx, subscription, updated.PriceID)
+	if err != nil {
+		logging.From(ctx).Error("error while finding price for subscription", "price_id", updated.PriceID, "subscription_id", subscriptionID, "error", err)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	change := &models.SubscriptionItemChange{LineItems: []*models.LineItem{}}
+	err = ss.prorate(ctx, subscription, change, oldPrice, oldName, item.Quantity, true)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	err = ss.prorate(ctx, subscription, change, newPrice, newName, updated.Quantity, false)
+	if err != nil {
+		ss.reverseProrations(ctx, change)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	item.PriceID = updated.PriceID
+	item.Quantity = updated.Quantity
+
+	item, err = ss.repository.UpdateItem(ctx, item)
+	if err != nil {
+		logging.From(ctx).Error("error while updating subscription item", "item_id", itemID, "error", err)
+		ss.reverseProrations(ctx, change)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	change.Item = item
+	return change, nil
+}
+
+// RemoveItem removes a recurring charge. Removed in the middle of a period, the
+// unused part of the charge is credited on the open bill. The credit is added
+// first, so the item is kept when it can not be credited.
+func (ss *subscriptionService) RemoveItem(ctx context.Context, subscriptionID string, itemID string) (*models.SubscriptionItemChange, error) {
+	subscription, err := ss.activeSubscription(ctx, subscriptionID)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	item, err := ss.subscriptionItem(ctx, subscription, itemID)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	price, name, err := ss.pricedItem(ctx, item.PriceID)
+	if err != nil {
+		logging.From(ctx).Error("error while finding price of subscription item", "price_id", item.PriceID, "item_id", itemID, "error", err)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	change := &models.SubscriptionItemChange{LineItems: []*models.LineItem{}}
+	err = ss.prorate(ctx, subscription, change, price, name, item.Quantity, true)
+	if err != nil {
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	item.Removed = true
+	item, err = ss.repository.UpdateItem(ctx, item)
+	if err != nil {
+		logging.From(ctx).Error("error while removing subscription item", "item_id", itemID, "error", err)
+		ss.reverseProrations(ctx, change)
+		return &models.SubscriptionItemChange{}, err
+	}
+
+	change.Item = item
+	return change, nil
+}
+
+func (ss *subscriptionService) activeSubscription(ctx context.Context, id string) (*models.Subscription, error) {
+	subscription, err := ss.repository.GetByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Warn("subscription not found", "subscription_id", id)
+		return subscription, err
+	}
+
+	if subscription.Status == "cancelled" {
+		logging.From(ctx).Warn("subscription is cancelled", "subscription_id", id)
+		return subscription, ce.SubscriptionCancelledError.WithID(id)
+	}
+
+	return subscription, nil
+}
+
+func (ss *subscriptionService) subscriptionItem(ctx context.Context, subscription *models.Subscription, itemID string) (*models.SubscriptionItem, error) {
+	item, err := ss.repository.GetItemByID(ctx, itemID)
+	if err != nil {
+		logging.From(ctx).Warn("subscription item not found", "item_id", itemID)
+		return item, err
+	}
+
+	if item.SubscriptionID != subscription.ID {
+		logging.From(ctx).Warn("subscription item does not belong to subscription", "item_id", itemID, "subscription_id", subscription.ID)
+		return item, ce.SubscriptionItemNotFoundError.WithID(itemID)
+	}
+
+	if item.Removed {
+		logging.From(ctx).Warn("subscription item is removed", "item_id", itemID)
+		return item, ce.SubscriptionItemRemovedError.WithID(itemID)
+	}
+
+	return item, nil
+}
+
+// recurringPrice returns a price that can be charged on the subscription and
+// the product and plan name it is charged under.
+func (ss *subscriptionService) recurringPrice(ctx context.Context, subscription *models.Subscription, priceID string) (*models.Price, string, error) {
+	price, name, err := ss.pricedItem(ctx, priceID)
+	if err != nil {
+		return price, name, err
+	}
+
+	if !price.Active {
+		return price, name, ce.PriceArchivedError.WithID(price.ID)
+	}
+
+	if price.CurrencyID != subscription.CurrencyID {
+		return price, name, ce.PriceCurrencyMismatchError.WithID(price.ID)
+	}
+
+	return price, name, nil
+}
+
+func (ss *subscriptionService) pricedItem(ctx context.Context, priceID string) (*models.Price, string, error) {
+	price, err := ss.catalogRepository.GetPriceByID(ctx, priceID)
+	if err != nil {
+		return price, "", err
+	}
+
+	plan, err := ss.catalogRepository.GetPlanByID(ctx, price.PlanID)
+	if err != nil {
+		return price, "", err
+	}
+
+	product, err := ss.catalogRepository.GetProductByID(ctx, plan.ProductID)
+	if err != nil {
+		return price, "", err
+	}
+
+	return price, fmt.Sprintf("%s - %s", product.Name, plan.Name), nil
+}
+
+// prorate adds a line item on the open bill of the subscription for the part of
+// the current period that remains. Credits are added with a negative amount.
+// Nothing is added outside of a period that has an open bill.
+func (ss *subscriptionService) prorate(ctx context.Context, subscription *models.Subscription, change *models.SubscriptionItemChange,
+	price *models.Price, name string, quantity float64, credit bool) error {
+	now := time.Now().UTC()
+	if subscription.CurrentBillID == "" || now.Before(subscription.CurrentPeriodStart) || !now.Before(subscription.CurrentPeriodEnd) {
+		return nil
+	}
+
+	currency, err := ss.currencyRepository.GetByID(ctx, subscription.CurrencyID)
+	if err != nil {
+		logging.From(ctx).Error("error while fetching currency for subscription", "subscription_id", subscription.ID, "error", err)
+		return err
+	}
+
+	charge := models.CalculatePrice(price, quantity, currency.MinorUnits)
+	proration := models.CalculateProration(subscription.CurrentPeriodStart, subscription.CurrentPeriodEnd, now, subscription.ProrationUnit)
+	prorated := proration.Apply(charge.Amount, currency.MinorUnits)
+
+	if prorated.Amount == float64(0) {
+		return nil
+	}
+
+	lineItem := &models.LineItem{
+		BillID:         subscription.CurrentBillID,
+		Description:    fmt.Sprintf("Remaining time on %s from %s", name, now.Format("2006-01-02")),
+		Quantity:       quantity,
+		Amount:         prorated.Amount,
+		PricingDetails: fmt.Sprintf("%s; prorated %s", charge.Explanation, prorated.Explanation),
+	}
+
+	if credit {
+		lineItem.Description = fmt.Sprintf("Unused time on %s from %s", name, now.Format("2006-01-02"))
+		lineItem.Amount = -prorated.Amount
+	}
+
+	lineItem, err = ss.billService.AddLineItems(ctx, lineItem)
+	if err != nil {
+		logging.From(ctx).Error("error while adding proration for subscription", "subscription_id", subscription.ID, "error", err)
+		return err
+	}
+
+	change.LineItems = append(change.LineItems, lineItem)
+	return nil
+}
+
+// reverseProrations removes the proration line items of a change that could
+// not be saved, so the open bill does not charge or credit it.
+func (ss *subscriptionService) reverseProrations(ctx context.Context, change *models.SubscriptionItemChange) {
+	for _, lineItem := range change.LineItems {
+		_, err := ss.billService.RemoveLineItems(ctx, lineItem.BillID, lineItem.ID)
+		if err != nil {
+			logging.From(ctx).Error("error while reversing proration for subscription", "line_item_id", lineItem.ID, "bill_id", lineItem.BillID, "error", err)
+		}
+	}
+}
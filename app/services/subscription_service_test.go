@@ -0,0 +1,360 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	tc "github.com/asheet-bhaskar/billing-service/app/workflows/temporal"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SubscriptionServiceTestSuite struct {
+	suite.Suite
+	SubscriptionMockRepo *repository.MockSubscriptionRepository
+	CustomerMockRepo     *repository.MockCustomerRepository
+	CurrencyMockRepo     *repository.MockCurrencyRepository
+	CatalogMockRepo      *repository.MockCatalogRepository
+	BillServiceMock      *BillServiceMock
+	TemporalClientMock   *tc.MockTemporalClient
+	ss                   SubscriptionService
+	request              *models.CreateSubscriptionRequest
+	subscription         *models.Subscription
+	currencyID           string
+	customerID           string
+}
+
+func (suite *SubscriptionServiceTestSuite) SetupTest() {
+	suite.SubscriptionMockRepo = new(repository.MockSubscriptionRepository)
+	suite.CustomerMockRepo = new(repository.MockCustomerRepository)
+	suite.CurrencyMockRepo = new(repository.MockCurrencyRepository)
+	suite.CatalogMockRepo = new(repository.MockCatalogRepository)
+	suite.BillServiceMock = new(BillServiceMock)
+	suite.TemporalClientMock = new(tc.MockTemporalClient)
+	suite.ss = NewSubscriptionService(suite.SubscriptionMockRepo, suite.CurrencyMockRepo, suite.CustomerMockRepo,
+		suite.CatalogMockRepo, suite.BillServiceMock, suite.TemporalClientMock)
+
+	suite.currencyID = utils.GetNewUUID()
+	suite.customerID = utils.GetNewUUID()
+	start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	suite.request = &models.CreateSubscriptionRequest{
+		Description:     "pro plan",
+		CustomerID:      suite.customerID,
+		CurrencyCode:    "USD",
+		BillingInterval: models.MonthlyInterval,
+		StartDate:       start,
+	}
+
+	suite.subscription = &models.Subscription{
+		ID:                 utils.GetNewUUID(),
+		Description:        "pro plan",
+		CustomerID:         suite.customerID,
+		CurrencyID:         suite.currencyID,
+		BillingInterval:    models.MonthlyInterval,
+		Status:             "active",
+		CurrentPeriodStart: start,
+		CurrentPeriodEnd:   start.AddDate(0, 1, 0),
+	}
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CreateSubscriptionReturnsErrorWhenCurrencyNotFound() {
+	ctx := context.Background()
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{}, ce.CurrencyNotFoundError)
+
+	_, err := suite.ss.Create(ctx, suite.request)
+
+	suite.Require().ErrorIs(err, ce.CurrencyNotFoundError)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CreateSubscriptionReturnsErrorWhenCurrencyIsInactive() {
+	ctx := context.Background()
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID}, nil)
+
+	_, err := suite.ss.Create(ctx, suite.request)
+
+	suite.Require().ErrorIs(err, ce.CurrencyInactiveError)
+	suite.SubscriptionMockRepo.AssertNotCalled(suite.T(), "Create", ctx, mock.Anything)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CreateSubscriptionReturnsErrorWhenCustomerIsArchived() {
+	ctx := context.Background()
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID, Active: true}, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID}, nil)
+
+	_, err := suite.ss.Create(ctx, suite.request)
+
+	suite.Require().ErrorIs(err, ce.CustomerArchivedError)
+	suite.SubscriptionMockRepo.AssertNotCalled(suite.T(), "Create", ctx, mock.Anything)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CreateSubscriptionReturnsErrorWhenFails() {
+	ctx := context.Background()
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID, Active: true}, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID, Active: true}, nil)
+	suite.SubscriptionMockRepo.On("Create", ctx, mock.Anything).Return(&models.Subscription{}, errors.New("test-error"))
+
+	_, err := suite.ss.Create(ctx, suite.request)
+
+	suite.Require().Error(err)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CreateSubscriptionStartsWorkflowWhenSucceeds() {
+	ctx := context.Background()
+	suite.CurrencyMockRepo.On("GetByCode", ctx, "USD").Return(&models.Currency{ID: suite.currencyID, Active: true}, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.customerID).Return(&models.Customer{ID: suite.customerID, Active: true}, nil)
+	suite.SubscriptionMockRepo.On("Create", ctx, mock.Anything).Return(suite.subscription, nil)
+	suite.TemporalClientMock.On("ExecuteWorkflow", ctx, mock.Anything, mock.Anything, mock.Anything)
+
+	subscription, err := suite.ss.Create(ctx, suite.request)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(suite.subscription, subscription)
+	suite.TemporalClientMock.AssertNumberOfCalls(suite.T(), "ExecuteWorkflow", 1)
+
+	created := suite.SubscriptionMockRepo.Calls[0].Arguments.Get(1).(*models.Subscription)
+	suite.Require().Equal(suite.request.StartDate, created.CurrentPeriodStart)
+	suite.Require().Equal(suite.request.StartDate.AddDate(0, 1, 0), created.CurrentPeriodEnd)
+	suite.Require().Equal(suite.request.StartDate.Day(), created.AnchorDay)
+	suite.Require().Equal("active", created.Status)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_GetByIDReturnsErrorWhenFails() {
+	ctx := context.Background()
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(&models.Subscription{}, ce.SubscriptionNotFoundError)
+
+	_, err := suite.ss.GetByID(ctx, suite.subscription.ID)
+
+	suite.Require().ErrorIs(err, ce.SubscriptionNotFoundError)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CancelFailsWhenSubscriptionIsCancelled() {
+	ctx := context.Background()
+	subscription := *suite.subscription
+	subscription.Status = "cancelled"
+	suite.SubscriptionMockRepo.On("GetByID", ctx, subscription.ID).Return(&subscription, nil)
+
+	_, err := suite.ss.Cancel(ctx, subscription.ID)
+
+	suite.Require().ErrorIs(err, ce.SubscriptionCancelledError)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CancelSignalsWorkflowWhenSucceeds() {
+	ctx := context.Background()
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("Update", ctx, mock.Anything).Return(suite.subscription, nil)
+	suite.TemporalClientMock.On("SignalWorkflow", ctx, "SUBSCRIPTION-"+suite.subscription.ID, "", "CANCEL_SUBSCRIPTION_CHANNEL", suite.subscription.ID).Return(nil)
+
+	subscription, err := suite.ss.Cancel(ctx, suite.subscription.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().True(subscription.CancelAtPeriodEnd)
+	suite.TemporalClientMock.AssertExpectations(suite.T())
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_CancelSignalsWorkflowOfTenant() {
+	ctx := tenancy.WithTenant(context.Background(), "tenant-a")
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("Update", ctx, mock.Anything).Return(suite.subscription, nil)
+	suite.TemporalClientMock.On("SignalWorkflow", mock.Anything, "tenant-a/SUBSCRIPTION-"+suite.subscription.ID, "", "CANCEL_SUBSCRIPTION_CHANNEL", suite.subscription.ID).Return(nil)
+
+	_, err := suite.ss.Cancel(ctx, suite.subscription.ID)
+
+	suite.Require().Nil(err)
+	suite.TemporalClientMock.AssertExpectations(suite.T())
+	signalled := suite.TemporalClientMock.Calls[0].Arguments.Get(0).(context.Context)
+	suite.Require().Equal("tenant-a", tenancy.From(signalled))
+}
+
+// midPeriod puts the subscription 10 days into a 30 day period with an open bill.
+func (suite *SubscriptionServiceTestSuite) midPeriod() {
+	start := time.Now().UTC().AddDate(0, 0, -10)
+	suite.subscription.BillingInterval = models.CustomInterval
+	suite.subscription.IntervalDays = 30
+	suite.subscription.ProrationUnit = models.DayProration
+	suite.subscription.CurrentBillID = utils.GetNewUUID()
+	suite.subscription.CurrentPeriodStart = start
+	suite.subscription.CurrentPeriodEnd = start.AddDate(0, 0, 30)
+}
+
+func (suite *SubscriptionServiceTestSuite) mockCurrency(ctx context.Context, code string, minorUnits int) {
+	suite.CurrencyMockRepo.On("GetByID", ctx, suite.currencyID).Return(&models.Currency{ID: suite.currencyID, Code: code, MinorUnits: minorUnits, Active: true}, nil)
+}
+
+func (suite *SubscriptionServiceTestSuite) mockPrice(ctx context.Context, id string, unitAmount float64) {
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, id).Return(&models.Price{ID: id, PlanID: "plan-id", CurrencyID: suite.currencyID, UnitAmount: unitAmount, Active: true}, nil)
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, "plan-id").Return(&models.Plan{ID: "plan-id", ProductID: "product-id", Name: "pro", Active: true}, nil)
+	suite.CatalogMockRepo.On("GetProductByID", ctx, "product-id").Return(&models.Product{ID: "product-id", Name: "API", Active: true}, nil)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_AddItemChargesRemainingPeriod() {
+	ctx := context.Background()
+	suite.midPeriod()
+	suite.mockCurrency(ctx, "USD", 2)
+	priceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, priceID, 30)
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("CreateItem", ctx, mock.Anything).Return(&models.SubscriptionItem{ID: utils.GetNewUUID(), PriceID: priceID, Quantity: 1}, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, nil)
+
+	_, err := suite.ss.AddItem(ctx, suite.subscription.ID, &models.SubscriptionItemRequest{PriceID: priceID})
+
+	suite.Require().Nil(err)
+	suite.BillServiceMock.AssertNumberOfCalls(suite.T(), "AddLineItems", 1)
+	charge := suite.BillServiceMock.Calls[0].Arguments.Get(1).(*models.LineItem)
+	suite.Require().Equal(suite.subscription.CurrentBillID, charge.BillID)
+	suite.Require().Equal(float64(20), charge.Amount)
+	suite.Require().Equal("1 x 30.00 = 30.00; prorated 30.00 x 20/30 days = 20.00", charge.PricingDetails)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_AddItemProratesInCurrencyMinorUnits() {
+	ctx := context.Background()
+	suite.midPeriod()
+	suite.mockCurrency(ctx, "JPY", 0)
+	priceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, priceID, 1000)
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("CreateItem", ctx, mock.Anything).Return(&models.SubscriptionItem{ID: utils.GetNewUUID(), PriceID: priceID, Quantity: 1}, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, nil)
+
+	_, err := suite.ss.AddItem(ctx, suite.subscription.ID, &models.SubscriptionItemRequest{PriceID: priceID})
+
+	suite.Require().Nil(err)
+	charge := suite.BillServiceMock.Calls[0].Arguments.Get(1).(*models.LineItem)
+	suite.Require().Equal(float64(667), charge.Amount)
+	suite.Require().Equal("1 x 1000 = 1000; prorated 1000 x 20/30 days = 667", charge.PricingDetails)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_AddItemDoesNotProrateWithoutOpenBill() {
+	ctx := context.Background()
+	priceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, priceID, 30)
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("CreateItem", ctx, mock.Anything).Return(&models.SubscriptionItem{ID: utils.GetNewUUID(), PriceID: priceID, Quantity: 1}, nil)
+
+	change, err := suite.ss.AddItem(ctx, suite.subscription.ID, &models.SubscriptionItemRequest{PriceID: priceID})
+
+	suite.Require().Nil(err)
+	suite.Require().Empty(change.LineItems)
+	suite.BillServiceMock.AssertNotCalled(suite.T(), "AddLineItems", ctx, mock.Anything)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_AddItemFailsWhenPriceCurrencyDiffers() {
+	ctx := context.Background()
+	priceID := utils.GetNewUUID()
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.CatalogMockRepo.On("GetPriceByID", ctx, priceID).Return(&models.Price{ID: priceID, PlanID: "plan-id", CurrencyID: utils.GetNewUUID(), Active: true}, nil)
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, "plan-id").Return(&models.Plan{ID: "plan-id", ProductID: "product-id"}, nil)
+	suite.CatalogMockRepo.On("GetProductByID", ctx, "product-id").Return(&models.Product{ID: "product-id"}, nil)
+
+	_, err := suite.ss.AddItem(ctx, suite.subscription.ID, &models.SubscriptionItemRequest{PriceID: priceID})
+
+	suite.Require().ErrorIs(err, ce.PriceCurrencyMismatchError)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_UpdateItemCreditsOldAndChargesNewPrice() {
+	ctx := context.Background()
+	suite.midPeriod()
+	suite.mockCurrency(ctx, "USD", 2)
+	oldPriceID := utils.GetNewUUID()
+	newPriceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, oldPriceID, 30)
+	suite.mockPrice(ctx, newPriceID, 60)
+	item := &models.SubscriptionItem{ID: utils.GetNewUUID(), SubscriptionID: suite.subscription.ID, PriceID: oldPriceID, Quantity: 1}
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("GetItemByID", ctx, item.ID).Return(item, nil)
+	suite.SubscriptionMockRepo.On("UpdateItem", ctx, mock.Anything).Return(item, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, nil)
+
+	change, err := suite.ss.UpdateItem(ctx, suite.subscription.ID, item.ID, &models.SubscriptionItemRequest{PriceID: newPriceID, Quantity: 1})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(newPriceID, change.Item.PriceID)
+	suite.BillServiceMock.AssertNumberOfCalls(suite.T(), "AddLineItems", 2)
+	credit := suite.BillServiceMock.Calls[0].Arguments.Get(1).(*models.LineItem)
+	charge := suite.BillServiceMock.Calls[1].Arguments.Get(1).(*models.LineItem)
+	suite.Require().Equal(float64(-20), credit.Amount)
+	suite.Require().Equal(float64(40), charge.Amount)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_AddItemIsNotAddedWhenChargeFails() {
+	ctx := context.Background()
+	suite.midPeriod()
+	suite.mockCurrency(ctx, "USD", 2)
+	priceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, priceID, 30)
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, ce.BillClosedError)
+
+	_, err := suite.ss.AddItem(ctx, suite.subscription.ID, &models.SubscriptionItemRequest{PriceID: priceID})
+
+	suite.Require().ErrorIs(err, ce.BillClosedError)
+	suite.SubscriptionMockRepo.AssertNotCalled(suite.T(), "CreateItem", ctx, mock.Anything)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_UpdateItemReversesCreditWhenChargeFails() {
+	ctx := context.Background()
+	suite.midPeriod()
+	suite.mockCurrency(ctx, "USD", 2)
+	oldPriceID := utils.GetNewUUID()
+	newPriceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, oldPriceID, 30)
+	suite.mockPrice(ctx, newPriceID, 60)
+	item := &models.SubscriptionItem{ID: utils.GetNewUUID(), SubscriptionID: suite.subscription.ID, PriceID: oldPriceID, Quantity: 1}
+	credit := &models.LineItem{ID: utils.GetNewUUID(), BillID: suite.subscription.CurrentBillID, Amount: -20}
+	testError := errors.New("test-error")
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("GetItemByID", ctx, item.ID).Return(item, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(credit, nil).Once()
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, testError).Once()
+	suite.BillServiceMock.On("RemoveLineItems", ctx, credit.BillID, credit.ID).Return(credit, nil)
+
+	_, err := suite.ss.UpdateItem(ctx, suite.subscription.ID, item.ID, &models.SubscriptionItemRequest{PriceID: newPriceID, Quantity: 1})
+
+	suite.Require().Equal(testError, err)
+	suite.Require().Equal(oldPriceID, item.PriceID)
+	suite.BillServiceMock.AssertCalled(suite.T(), "RemoveLineItems", ctx, credit.BillID, credit.ID)
+	suite.SubscriptionMockRepo.AssertNotCalled(suite.T(), "UpdateItem", ctx, mock.Anything)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_RemoveItemCreditsUnusedPeriod() {
+	ctx := context.Background()
+	suite.midPeriod()
+	suite.mockCurrency(ctx, "USD", 2)
+	priceID := utils.GetNewUUID()
+	suite.mockPrice(ctx, priceID, 30)
+	item := &models.SubscriptionItem{ID: utils.GetNewUUID(), SubscriptionID: suite.subscription.ID, PriceID: priceID, Quantity: 2}
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("GetItemByID", ctx, item.ID).Return(item, nil)
+	suite.SubscriptionMockRepo.On("UpdateItem", ctx, mock.Anything).Return(item, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, nil)
+
+	change, err := suite.ss.RemoveItem(ctx, suite.subscription.ID, item.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().True(change.Item.Removed)
+	credit := suite.BillServiceMock.Calls[0].Arguments.Get(1).(*models.LineItem)
+	suite.Require().Equal(float64(-40), credit.Amount)
+}
+
+func (suite *SubscriptionServiceTestSuite) Test_RemoveItemFailsWhenItemIsRemoved() {
+	ctx := context.Background()
+	item := &models.SubscriptionItem{ID: utils.GetNewUUID(), SubscriptionID: suite.subscription.ID, Removed: true}
+	suite.SubscriptionMockRepo.On("GetByID", ctx, suite.subscription.ID).Return(suite.subscription, nil)
+	suite.SubscriptionMockRepo.On("GetItemByID", ctx, item.ID).Return(item, nil)
+
+	_, err := suite.ss.RemoveItem(ctx, suite.subscription.ID, item.ID)
+
+	suite.Require().ErrorIs(err, ce.SubscriptionItemRemovedError)
+}
+
+func TestSubscriptionServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionServiceTestSuite))
+}
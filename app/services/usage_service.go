@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+)
+
+type usageService struct {
+	repository         repository.UsageRepository
+	billRepository     repository.BillRepository
+	customerRepository repository.CustomerRepository
+	catalogRepository  repository.CatalogRepository
+	billService        BillService
+}
+
+type UsageService interface {
+	CreateMeter(context.Context, *models.Meter) (*models.Meter, error)
+	GetMeter(context.Context, string) (*models.Meter, error)
+	IngestEvent(context.Context, *models.UsageEventRequest) (*models.UsageEvent, error)
+	RateBill(context.Context, string) ([]*models.LineItem, error)
+}
+
+func NewUsageService(repository repository.UsageRepository, billRepository repository.BillRepository,
+	customerRepository repository.CustomerRepository, catalogRepository repository.CatalogRepository,
+	billService BillService) UsageService {
+	return &usageService{
+		repository:         repository,
+		billRepository:     billRepository,
+		customerRepository: customerRepository,
+		catalogRepository:  catalogRepository,
+		billService:        billService,
+	}
+}
+
+func (us *usageService) CreateMeter(ctx context.Context, meter *models.Meter) (*models.Meter, error) {
+	_, err := us.repository.GetMeterByCode(ctx, meter.Code)
+	if err == nil {
+		logging.From(ctx).Warn("meter already exists", "code", meter.Code)
+		return &models.Meter{}, ce.MeterAlreadyExistError.WithID(meter.Code)
+	}
+
+	if !errors.Is(err, ce.MeterNotFoundError) {
+		return &models.Meter{}, err
+	}
+
+	_, err = us.catalogRepository.GetPlanByID(ctx, meter.PlanID)
+	if err != nil {
+		logging.From(ctx).Warn("plan not found", "plan_id", meter.PlanID)
+		return &models.Meter{}, err
+	}
+
+	meter.ID = utils.GetNewUUID()
+	meter.CreatedAt = time.Now().UTC()
+	meter.UpdatedAt = time.Now().UTC()
+
+	meter, err = us.repository.CreateMeter(ctx, meter)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating meter", "error", err)
+		return &models.Meter{}, err
+	}
+
+	return meter, nil
+}
+
+func (us *usageService) GetMeter(ctx context.Context, id string) (*models.Meter, error) {
+	meter, err := us.repository.GetMeterByID(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching meter", "meter_id", id, "error", err)
+		return &models.Meter{}, err
+	}
+
+	return meter, nil
+}
+
+// IngestEvent records a usage event. Events are deduplicated on their
+// idempotency id, so a retried or concurrent request returns the event
+// recorded first.
+func (us *usageService) IngestEvent(ctx context.Context, request *models.UsageEventRequest) (*models.UsageEvent, error) {
+	event, err := us.repository.GetEventByIdempotencyID(ctx, request.IdempotencyID)
+	if err == nil {
+		logging.From(ctx).Warn("usage event already recorded", "idempotency_id", request.IdempotencyID)
+		return event, nil
+	}
+
+	if !errors.Is(err, ce.UsageEventNotFoundError) {
+		return &models.UsageEvent{}, err
+	}
+
+	meter, err := us.repository.GetMeterByCode(ctx, request.MeterCode)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the meter", "meter_code", request.MeterCode, "error", err)
+		return &models.UsageEvent{}, err
+	}
+
+	customer, err := us.customerRepository.GetByID(ctx, request.CustomerID)
+	if err != nil {
+		logging.From(ctx).Error("error while finding the customer", "customer_id", request.CustomerID, "error", err)
+		return &models.UsageEvent{}, err
+	}
+
+	if customer.IsDeleted() {
+		logging.From(ctx).Warn("customer is deleted", "customer_id", request.CustomerID)
+		return &models.UsageEvent{}, ce.CustomerNotFoundError.WithID(request.CustomerID)
+	}
+
+	event = request.ToUsageEvent()
+	event.ID = utils.GetNewUUID()
+	event.CustomerID = customer.ID
+	event.MeterID = meter.ID
+	event.CreatedAt = time.Now().UTC()
+
+	event, err = us.repository.CreateEvent(ctx, event)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while creating usage event", "error", err)
+		return &models.UsageEvent{}, err
+	}
+
+	return event, nil
+}
+
+// RateBill converts the customer's unbilled usage up to the end of the bill
+// period into one priced line item per meter priced in the bill currency.
+// Events of earlier periods that arrived after their bill was rated are rolled
+// into this bill. Events are claimed by the bill before rating, and released
+// again if their line item could not be added.
+func (us *usageService) RateBill(ctx context.Context, billID string) ([]*models.LineItem, error) {
+	lineItems := []*models.LineItem{}
+
+	bill, err := us.billRepository.GetByID(ctx, billID)
+	if err != nil {
+		logging.From(ctx).Warn("bill not found", "bill_id", billID)
+		return lineItems, err
+	}
+
+	if bill.Status == "closed" {
+		logging.From(ctx).Warn("bill is already closed", "bill_id", billID)
+		return lineItems, ce.BillClosedError.WithID(billID)
+	}
+
+	events, err := us.repository.ClaimEvents(ctx, bill)
+	if err != nil {
+		logging.From(ctx).Error("error while claiming usage events for bill", "bill_id", billID, "error", err)
+		return lineItems, err
+	}
+
+	late := 0
+	for _, event := range events {
+		if event.Timestamp.Before(bill.PeriodStart) {
+			late++
+		}
+	}
+	if late > 0 {
+		logging.From(ctx).Warn("rolling usage events of earlier periods into bill", "bill_id", billID, "events", late)
+	}
+
+	eventsByMeter := map[string][]*models.UsageEvent{}
+	for _, event := range events {
+		eventsByMeter[event.MeterID] = append(eventsByMeter[event.MeterID], event)
+	}
+
+	meterIDs := []string{}
+	for meterID := range eventsByMeter {
+		meterIDs = append(meterIDs, meterID)
+	}
+	sort.Strings(meterIDs)
+
+	for i, meterID := range meterIDs {
+		lineItem, err := us.rateMeter(ctx, bill, meterID, eventsByMeter[meterID])
+		if err != nil {
+			logging.From(ctx).Error("error while rating usage of meter for bill", "meter_id", meterID, "bill_id", billID, "error", err)
+			us.releaseMeters(ctx, meterIDs[i:], eventsByMeter)
+			return lineItems, err
+		}
+
+		if lineItem != nil {
+			lineItems = append(lineItems, lineItem)
+		}
+	}
+
+	return lineItems, nil
+}
+
+func (us *usageService) rateMeter(ctx context.Context, bill *models.Bill, meterID string, events []*models.UsageEvent) (*models.LineItem, error) {
+	meter, err := us.repository.GetMeterByID(ctx, meterID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := models.AggregateUsage(meter.Aggregation, events)
+	if quantity == 0 {
+		return nil, nil
+	}
+
+	prices, err := us.catalogRepository.ListPricesByPlanID(ctx, meter.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, price := range prices {
+		if price.Active && price.CurrencyID == bill.CurrencyID {
+			return us.billService.AddLineItems(ctx, &models.LineItem{
+				BillID:      bill.ID,
+				PriceID:     price.ID,
+				Description: meter.Name,
+				Quantity:    quantity,
+			})
+		}
+	}
+
+	return nil, ce.PriceNotFoundError
+}
+
+func (us *usageService) releaseMeters(ctx context.Context, meterIDs []string, eventsByMeter map[string][]*models.UsageEvent) {
+	ids := []string{}
+	for _, meterID := range meterIDs {
+		for _, event := range eventsByMeter[meterID] {
+			ids = append(ids, event.ID)
+		}
+	}
+
+	err := us.repository.ReleaseEvents(ctx, ids)
+	if err != nil {
+		logging.From(ctx).Error("error while releasing usage events", "error", err)
+	}
+}
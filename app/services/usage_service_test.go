@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type UsageServiceTestSuite struct {
+	suite.Suite
+	UsageMockRepo    *repository.MockUsageRepository
+	BillMockRepo     *repository.MockBillRepository
+	CustomerMockRepo *repository.MockCustomerRepository
+	CatalogMockRepo  *repository.MockCatalogRepository
+	BillServiceMock  *BillServiceMock
+	us               UsageService
+	bill             *models.Bill
+	meter            *models.Meter
+	request          *models.UsageEventRequest
+}
+
+func (suite *UsageServiceTestSuite) SetupTest() {
+	suite.UsageMockRepo = new(repository.MockUsageRepository)
+	suite.BillMockRepo = new(repository.MockBillRepository)
+	suite.CustomerMockRepo = new(repository.MockCustomerRepository)
+	suite.CatalogMockRepo = new(repository.MockCatalogRepository)
+	suite.BillServiceMock = new(BillServiceMock)
+	suite.us = NewUsageService(suite.UsageMockRepo, suite.BillMockRepo, suite.CustomerMockRepo, suite.CatalogMockRepo, suite.BillServiceMock)
+
+	now := time.Now().UTC()
+	suite.bill = &models.Bill{
+		ID:          utils.GetNewUUID(),
+		CustomerID:  utils.GetNewUUID(),
+		CurrencyID:  utils.GetNewUUID(),
+		Status:      "open",
+		PeriodStart: now.Add(-time.Hour * 24),
+		PeriodEnd:   now,
+	}
+	suite.meter = &models.Meter{
+		ID:          utils.GetNewUUID(),
+		Code:        "api_calls",
+		Name:        "API calls",
+		Aggregation: models.SumAggregation,
+		PlanID:      utils.GetNewUUID(),
+	}
+	suite.request = &models.UsageEventRequest{
+		IdempotencyID: "event-01",
+		CustomerID:    suite.bill.CustomerID,
+		MeterCode:     "api_calls",
+		Quantity:      5,
+		Timestamp:     now,
+	}
+}
+
+func (suite *UsageServiceTestSuite) Test_CreateMeterFailsWhenCodeExists() {
+	ctx := context.Background()
+	suite.UsageMockRepo.On("GetMeterByCode", ctx, "api_calls").Return(suite.meter, nil)
+
+	_, err := suite.us.CreateMeter(ctx, &models.Meter{Code: "api_calls"})
+
+	suite.Require().ErrorIs(err, ce.MeterAlreadyExistError)
+}
+
+func (suite *UsageServiceTestSuite) Test_CreateMeterReturnsNilErrorWhenSucceeds() {
+	ctx := context.Background()
+	suite.UsageMockRepo.On("GetMeterByCode", ctx, "api_calls").Return(&models.Meter{}, ce.MeterNotFoundError)
+	suite.CatalogMockRepo.On("GetPlanByID", ctx, suite.meter.PlanID).Return(&models.Plan{ID: suite.meter.PlanID}, nil)
+	suite.UsageMockRepo.On("CreateMeter", ctx, mock.Anything).Return(suite.meter, nil)
+
+	meter, err := suite.us.CreateMeter(ctx, &models.Meter{Code: "api_calls", PlanID: suite.meter.PlanID})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(suite.meter, meter)
+}
+
+func (suite *UsageServiceTestSuite) Test_IngestEventReturnsExistingEventForDuplicateIdempotencyID() {
+	ctx := context.Background()
+	existing := &models.UsageEvent{ID: utils.GetNewUUID(), IdempotencyID: "event-01"}
+	suite.UsageMockRepo.On("GetEventByIdempotencyID", ctx, "event-01").Return(existing, nil)
+
+	event, err := suite.us.IngestEvent(ctx, suite.request)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(existing, event)
+	suite.UsageMockRepo.AssertNotCalled(suite.T(), "CreateEvent", mock.Anything, mock.Anything)
+}
+
+func (suite *UsageServiceTestSuite) Test_IngestEventFailsWhenMeterNotFound() {
+	ctx := context.Background()
+	suite.UsageMockRepo.On("GetEventByIdempotencyID", ctx, "event-01").Return(&models.UsageEvent{}, ce.UsageEventNotFoundError)
+	suite.UsageMockRepo.On("GetMeterByCode", ctx, "api_calls").Return(&models.Meter{}, ce.MeterNotFoundError)
+
+	_, err := suite.us.IngestEvent(ctx, suite.request)
+
+	suite.Require().ErrorIs(err, ce.MeterNotFoundError)
+}
+
+func (suite *UsageServiceTestSuite) Test_IngestEventReturnsNilErrorWhenSucceeds() {
+	ctx := context.Background()
+	suite.UsageMockRepo.On("GetEventByIdempotencyID", ctx, "event-01").Return(&models.UsageEvent{}, ce.UsageEventNotFoundError)
+	suite.UsageMockRepo.On("GetMeterByCode", ctx, "api_calls").Return(suite.meter, nil)
+	suite.CustomerMockRepo.On("GetByID", ctx, suite.request.CustomerID).Return(&models.Customer{ID: suite.request.CustomerID}, nil)
+	suite.UsageMockRepo.On("CreateEvent", ctx, mock.Anything).Return(&models.UsageEvent{MeterID: suite.meter.ID}, nil)
+
+	event, err := suite.us.IngestEvent(ctx, suite.request)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(suite.meter.ID, event.MeterID)
+}
+
+func (suite *UsageServiceTestSuite) Test_RateBillFailsWhenBillIsClosed() {
+	ctx := context.Background()
+	bill := *suite.bill
+	bill.Status = "closed"
+	suite.BillMockRepo.On("GetByID", ctx, bill.ID).Return(&bill, nil)
+
+	_, err := suite.us.RateBill(ctx, bill.ID)
+
+	suite.Require().ErrorIs(err, ce.BillClosedError)
+}
+
+func (suite *UsageServiceTestSuite) Test_RateBillAddsAggregatedLineItem() {
+	ctx := context.Background()
+	priceID := utils.GetNewUUID()
+	events := []*models.UsageEvent{
+		{ID: utils.GetNewUUID(), MeterID: suite.meter.ID, Quantity: 5},
+		{ID: utils.GetNewUUID(), MeterID: suite.meter.ID, Quantity: 7},
+	}
+	suite.BillMockRepo.On("GetByID", ctx, suite.bill.ID).Return(suite.bill, nil)
+	suite.UsageMockRepo.On("ClaimEvents", ctx, suite.bill).Return(events, nil)
+	suite.UsageMockRepo.On("GetMeterByID", ctx, suite.meter.ID).Return(suite.meter, nil)
+	suite.CatalogMockRepo.On("ListPricesByPlanID", ctx, suite.meter.PlanID).Return([]*models.Price{
+		{ID: utils.GetNewUUID(), CurrencyID: utils.GetNewUUID(), Active: true},
+		{ID: priceID, CurrencyID: suite.bill.CurrencyID, Active: true},
+	}, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, &models.LineItem{
+		BillID:      suite.bill.ID,
+		PriceID:     priceID,
+		Description: "API calls",
+		Quantity:    12,
+	}).Return(&models.LineItem{ID: utils.GetNewUUID(), Quantity: 12}, nil)
+
+	lineItems, err := suite.us.RateBill(ctx, suite.bill.ID)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, len(lineItems))
+	suite.Require().Equal(float64(12), lineItems[0].Quantity)
+}
+
+func (suite *UsageServiceTestSuite) Test_RateBillReleasesEventsWhenLineItemFails() {
+	ctx := context.Background()
+	events := []*models.UsageEvent{
+		{ID: utils.GetNewUUID(), MeterID: suite.meter.ID, Quantity: 5},
+	}
+	testError := errors.New("test-error")
+	suite.BillMockRepo.On("GetByID", ctx, suite.bill.ID).Return(suite.bill, nil)
+	suite.UsageMockRepo.On("ClaimEvents", ctx, suite.bill).Return(events, nil)
+	suite.UsageMockRepo.On("GetMeterByID", ctx, suite.meter.ID).Return(suite.meter, nil)
+	suite.CatalogMockRepo.On("ListPricesByPlanID", ctx, suite.meter.PlanID).Return([]*models.Price{
+		{ID: utils.GetNewUUID(), CurrencyID: suite.bill.CurrencyID, Active: true},
+	}, nil)
+	suite.BillServiceMock.On("AddLineItems", ctx, mock.Anything).Return(&models.LineItem{}, testError)
+	suite.UsageMockRepo.On("ReleaseEvents", ctx, []string{events[0].ID}).Return(nil)
+
+	_, err := suite.us.RateBill(ctx, suite.bill.ID)
+
+	suite.Require().Equal(testError, err)
+	suite.UsageMockRepo.AssertCalled(suite.T(), "ReleaseEvents", ctx, []string{events[0].ID})
+}
+
+func (suite *UsageServiceTestSuite) Test_RateBillFailsWhenNoPriceInBillCurrency() {
+	ctx := context.Background()
+	events := []*models.UsageEvent{
+		{ID: utils.GetNewUUID(), MeterID: suite.meter.ID, Quantity: 5},
+	}
+	suite.BillMockRepo.On("GetByID", ctx, suite.bill.ID).Return(suite.bill, nil)
+	suite.UsageMockRepo.On("ClaimEvents", ctx, suite.bill).Return(events, nil)
+	suite.UsageMockRepo.On("GetMeterByID", ctx, suite.meter.ID).Return(suite.meter, nil)
+	suite.CatalogMockRepo.On("ListPricesByPlanID", ctx, suite.meter.PlanID).Return([]*models.Price{}, nil)
+	suite.UsageMockRepo.On("ReleaseEvents", ctx, mock.Anything).Return(nil)
+
+	_, err := suite.us.RateBill(ctx, suite.bill.ID)
+
+	suite.Require().ErrorIs(err, ce.PriceNotFoundError)
+}
+
+func TestUsageServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(UsageServiceTestSuite))
+}
@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/app/workflows"
+	tc "github.com/asheet-bhaskar/billing-service/app/workflows/temporal"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"go.temporal.io/sdk/client"
+)
+
+// webhookSecretLength is the number of random bytes in a webhook secret.
+const webhookSecretLength = 32
+
+type webhookService struct {
+	repository     repository.WebhookRepository
+	temporalClient tc.TemporalClient
+}
+
+type WebhookService interface {
+	Register(context.Context, *models.WebhookEndpointRequest) (*models.WebhookEndpoint, error)
+	List(context.Context) (*models.WebhookEndpoints, error)
+	Disable(context.Context, string) (*models.WebhookEndpoint, error)
+	Publish(ctx context.Context, eventType string, billID string, data any)
+	ListDeliveries(context.Context, string, *models.ListWebhookDeliveriesRequest) (*models.WebhookDeliveryList, error)
+	Redeliver(context.Context, string) (*models.WebhookDelivery, error)
+}
+
+func NewWebhookService(repository repository.WebhookRepository, temporalClient tc.TemporalClient) WebhookService {
+	return &webhookService{
+		repository:     repository,
+		temporalClient: temporalClient,
+	}
+}
+
+// Register adds an endpoint receiving the events of the request, all events
+// when none are given. The secret signing its payloads is only returned here.
+func (ws *webhookService) Register(ctx context.Context, request *models.WebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	secret, err := utils.SecureRandomString(webhookSecretLength)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while generating webhook secret", "error", err)
+		return &models.WebhookEndpoint{}, err
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		ID:        utils.GetNewUUID(),
+		URL:       request.URL,
+		Secret:    fmt.Sprintf("whsec_%s", secret),
+		Events:    request.Events,
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	endpoint, err = ws.repository.CreateEndpoint(ctx, endpoint)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while registering webhook endpoint", "url", request.URL, "error", err)
+		return &models.WebhookEndpoint{}, err
+	}
+
+	return endpoint, nil
+}
+
+// List returns the active endpoints without their secrets.
+func (ws *webhookService) List(ctx context.Context) (*models.WebhookEndpoints, error) {
+	endpoints, err := ws.repository.ListEndpoints(ctx)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing webhook endpoints", "error", err)
+		return &models.WebhookEndpoints{}, err
+	}
+
+	for _, endpoint := range endpoints {
+		endpoint.Secret = ""
+	}
+
+	return &models.WebhookEndpoints{Endpoints: endpoints}, nil
+}
+
+// Disable stops sending events to the endpoint. Pending deliveries to it fail
+// on their next attempt.
+func (ws *webhookService) Disable(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	endpoint, err := ws.repository.DisableEndpoint(ctx, id)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while disabling webhook endpoint", "endpoint_id", id, "error", err)
+		return &models.WebhookEndpoint{}, err
+	}
+
+	endpoint.Secret = ""
+	return endpoint, nil
+}
+
+// Publish sends the event to every endpoint subscribed to it. Each delivery is
+// logged and handed to a workflow that retries it until it succeeds. The
+// change has already been made, so failures are logged and not returned.
+func (ws *webhookService) Publish(ctx context.Context, eventType string, billID string, data any) {
+	endpoints, err := ws.repository.ListEndpoints(ctx)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing webhook endpoints for event", "event_type", eventType, "error", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	payload := &models.WebhookPayload{
+		ID:        utils.GetNewUUID(),
+		Type:      eventType,
+		CreatedAt: now.Format(time.RFC3339),
+		Data:      data,
+	}
+
+	var body []byte
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(eventType) {
+			continue
+		}
+
+		if body == nil {
+			body, err = json.Marshal(payload)
+			if err != nil {
+				logging.From(ctx).Error("error occurred while encoding webhook event", "event_type", eventType, "error", err)
+				return
+			}
+		}
+
+		delivery := &models.WebhookDelivery{
+			ID:         utils.GetNewUUID(),
+			EndpointID: endpoint.ID,
+			EventID:    payload.ID,
+			EventType:  eventType,
+			BillID:     billID,
+			Payload:    string(body),
+			Status:     models.DeliveryPending,
+			CreatedAt:  now,
+			UpdatedAt:  now,
+		}
+
+		_, err = ws.deliver(ctx, delivery)
+		if err != nil {
+			logging.From(ctx).Error("error occurred while delivering webhook event to endpoint", "event_type", eventType, "endpoint_id", endpoint.ID, "error", err)
+		}
+	}
+}
+
+// deliver logs the delivery and starts the workflow sending it.
+func (ws *webhookService) deliver(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	delivery, err := ws.repository.CreateDelivery(ctx, delivery)
+	if err != nil {
+		return delivery, err
+	}
+
+	options := client.StartWorkflowOptions{
+		ID:        tenancy.WorkflowID(ctx, fmt.Sprintf("WEBHOOK-DELIVERY-%s", delivery.ID)),
+		TaskQueue: "CREATE_BILL_QUEUE",
+	}
+
+	_, err = ws.temporalClient.ExecuteWorkflow(tenancy.Detach(ctx), options, workflows.WebhookDeliveryWorkflow, delivery.ID)
+	if err != nil {
+		logging.From(ctx).Error("failed to create workflow execution for webhook delivery", "delivery_id", delivery.ID, "error", err)
+		metrics.WorkflowStartFailures.Inc("WebhookDeliveryWorkflow")
+	}
+
+	return delivery, nil
+}
+
+func (ws *webhookService) ListDeliveries(ctx context.Context, endpointID string, request *models.ListWebhookDeliveriesRequest) (*models.WebhookDeliveryList, error) {
+	_, err := ws.repository.GetEndpointByID(ctx, endpointID)
+	if err != nil {
+		logging.From(ctx).Warn("webhook endpoint not found", "endpoint_id", endpointID)
+		return &models.WebhookDeliveryList{}, err
+	}
+
+	deliveries, total, err := ws.repository.ListDeliveries(ctx, endpointID, request)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while listing deliveries of webhook endpoint", "endpoint_id", endpointID, "error", err)
+		return &models.WebhookDeliveryList{}, err
+	}
+
+	return &models.WebhookDeliveryList{Deliveries: deliveries, Total: total}, nil
+}
+
+// Redeliver sends the event of the delivery to its endpoint again, logged as
+// a new delivery. Receivers tell redeliveries apart by the event id.
+func (ws *webhookService) Redeliver(ctx context.Context, deliveryID string) (*models.WebhookDelivery, error) {
+	original, err := ws.repository.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		logging.From(ctx).Warn("webhook delivery not found", "delivery_id", deliveryID)
+		return &models.WebhookDelivery{}, err
+	}
+
+	endpoint, err := ws.repository.GetEndpointByID(ctx, original.EndpointID)
+	if err != nil {
+		logging.From(ctx).Warn("webhook endpoint not found", "endpoint_id", original.EndpointID)
+		return &models.WebhookDelivery{}, err
+	}
+
+	if !endpoint.Active {
+		logging.From(ctx).Warn("webhook endpoint is disabled", "endpoint_id", endpoint.ID)
+		return &models.WebhookDelivery{}, ce.WebhookEndpointDisabledError.WithID(endpoint.ID)
+	}
+
+	now := time.Now().UTC()
+	delivery := &models.WebhookDelivery{
+		ID:           utils.GetNewUUID(),
+		EndpointID:   original.EndpointID,
+		EventID:      original.EventID,
+		EventType:    original.EventType,
+		BillID:       original.BillID,
+		Payload:      original.Payload,
+		Status:       models.DeliveryPending,
+		RedeliveryOf: original.ID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	delivery, err = ws.deliver(ctx, delivery)
+	if err != nil {
+		logging.From(ctx).Error("error occurred while redelivering webhook delivery", "delivery_id", deliveryID, "error", err)
+		return &models.WebhookDelivery{}, err
+	}
+
+	return delivery, nil
+}
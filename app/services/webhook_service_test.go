@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	tc "github.com/asheet-bhaskar/billing-service/app/workflows/temporal"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/client"
+)
+
+type WebhookServiceTestSuite struct {
+	suite.Suite
+	MockRepo           *repository.MockWebhookRepository
+	TemporalClientMock *tc.MockTemporalClient
+	ws                 WebhookService
+}
+
+func (suite *WebhookServiceTestSuite) SetupTest() {
+	suite.MockRepo = new(repository.MockWebhookRepository)
+	suite.TemporalClientMock = new(tc.MockTemporalClient)
+	suite.ws = NewWebhookService(suite.MockRepo, suite.TemporalClientMock)
+}
+
+func (suite *WebhookServiceTestSuite) Test_RegisterGeneratesSecret() {
+	ctx := context.Background()
+	suite.MockRepo.On("CreateEndpoint", ctx, mock.Anything).Return(&models.WebhookEndpoint{}, nil)
+
+	_, err := suite.ws.Register(ctx, &models.WebhookEndpointRequest{URL: "https://crm.example.com/hooks", Events: []string{models.BillClosed}})
+
+	suite.Require().Nil(err)
+	endpoint := suite.MockRepo.Calls[0].Arguments.Get(1).(*models.WebhookEndpoint)
+	suite.Require().NotEmpty(endpoint.ID)
+	suite.Require().True(strings.HasPrefix(endpoint.Secret, "whsec_"))
+	suite.Require().Len(endpoint.Secret, len("whsec_")+2*webhookSecretLength)
+	suite.Require().True(endpoint.Active)
+	suite.Require().Equal([]string{models.BillClosed}, endpoint.Events)
+}
+
+func (suite *WebhookServiceTestSuite) Test_ListHidesSecrets() {
+	ctx := context.Background()
+	suite.MockRepo.On("ListEndpoints", ctx).Return([]*models.WebhookEndpoint{{ID: "endpoint id", Secret: "whsec_secret", Active: true}}, nil)
+
+	endpoints, err := suite.ws.List(ctx)
+
+	suite.Require().Nil(err)
+	suite.Require().Len(endpoints.Endpoints, 1)
+	suite.Require().Empty(endpoints.Endpoints[0].Secret)
+}
+
+func (suite *WebhookServiceTestSuite) Test_PublishDeliversToSubscribedEndpoints() {
+	ctx := context.Background()
+	suite.MockRepo.On("ListEndpoints", ctx).Return([]*models.WebhookEndpoint{
+		{ID: "all events", Active: true},
+		{ID: "closed bills", Active: true, Events: []string{models.BillClosed}},
+	}, nil)
+	suite.MockRepo.On("CreateDelivery", ctx, mock.Anything).Return(&models.WebhookDelivery{ID: "delivery id"}, nil)
+	suite.TemporalClientMock.On("ExecuteWorkflow", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	suite.ws.Publish(ctx, models.LineItemAdded, "bill id", &models.LineItem{ID: "item id"})
+
+	suite.MockRepo.AssertNumberOfCalls(suite.T(), "CreateDelivery", 1)
+	delivery := suite.MockRepo.Calls[1].Arguments.Get(1).(*models.WebhookDelivery)
+	suite.Require().Equal("all events", delivery.EndpointID)
+	suite.Require().Equal(models.LineItemAdded, delivery.EventType)
+	suite.Require().Equal("bill id", delivery.BillID)
+	suite.Require().Equal(models.DeliveryPending, delivery.Status)
+
+	payload := map[string]any{}
+	suite.Require().Nil(json.Unmarshal([]byte(delivery.Payload), &payload))
+	suite.Require().Equal(delivery.EventID, payload["id"])
+	suite.Require().Equal(models.LineItemAdded, payload["type"])
+
+	options := suite.TemporalClientMock.Calls[0].Arguments.Get(1).(client.StartWorkflowOptions)
+	suite.Require().Equal("WEBHOOK-DELIVERY-delivery id", options.ID)
+}
+
+func (suite *WebhookServiceTestSuite) Test_PublishIgnoresRepositoryFailures() {
+	ctx := context.Background()
+	suite.MockRepo.On("ListEndpoints", ctx).Return([]*models.WebhookEndpoint{}, errors.New("test error"))
+
+	suite.ws.Publish(ctx, models.BillCreated, "bill id", &models.Bill{ID: "bill id"})
+
+	suite.MockRepo.AssertNotCalled(suite.T(), "CreateDelivery", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookServiceTestSuite) Test_RedeliverLogsNewDeliveryOfSameEvent() {
+	ctx := context.Background()
+	original := &models.WebhookDelivery{
+		ID:         "delivery id",
+		EndpointID: "endpoint id",
+		EventID:    "event id",
+		EventType:  models.BillClosed,
+		Payload:    `{"id":"event id"}`,
+		Status:     models.DeliveryFailed,
+		Attempts:   12,
+	}
+	suite.MockRepo.On("GetDeliveryByID", ctx, original.ID).Return(original, nil)
+	suite.MockRepo.On("GetEndpointByID", ctx, original.EndpointID).Return(&models.WebhookEndpoint{ID: original.EndpointID, Active: true}, nil)
+	suite.MockRepo.On("CreateDelivery", ctx, mock.Anything).Return(&models.WebhookDelivery{ID: "redelivery id"}, nil)
+	suite.TemporalClientMock.On("ExecuteWorkflow", context.Background(), mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+
+	_, err := suite.ws.Redeliver(ctx, original.ID)
+
+	suite.Require().Nil(err)
+	delivery := suite.MockRepo.Calls[2].Arguments.Get(1).(*models.WebhookDelivery)
+	suite.Require().NotEqual(original.ID, delivery.ID)
+	suite.Require().Equal(original.ID, delivery.RedeliveryOf)
+	suite.Require().Equal(original.EventID, delivery.EventID)
+	suite.Require().Equal(original.Payload, delivery.Payload)
+	suite.Require().Equal(models.DeliveryPending, delivery.Status)
+	suite.Require().Zero(delivery.Attempts)
+}
+
+func (suite *WebhookServiceTestSuite) Test_RedeliverFailsForDisabledEndpoint() {
+	ctx := context.Background()
+	suite.MockRepo.On("GetDeliveryByID", ctx, "delivery id").Return(&models.WebhookDelivery{ID: "delivery id", EndpointID: "endpoint id"}, nil)
+	suite.MockRepo.On("GetEndpointByID", ctx, "endpoint id").Return(&models.WebhookEndpoint{ID: "endpoint id"}, nil)
+
+	_, err := suite.ws.Redeliver(ctx, "delivery id")
+
+	suite.Require().ErrorIs(err, ce.WebhookEndpointDisabledError)
+	suite.MockRepo.AssertNotCalled(suite.T(), "CreateDelivery", mock.Anything, mock.Anything)
+}
+
+func TestWebhookServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookServiceTestSuite))
+}
@@ -3,79 +3,113 @@ package workflows
 import (
 	"context"
 	"errors"
-	"log"
+	"net/http"
 
+	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/asheet-bhaskar/billing-service/db"
 	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"go.temporal.io/sdk/activity"
 )
 
+// BillService is the part of the bill service that activities depend on. It is
+// declared here because the services package already imports workflows.
+type BillService interface {
+	Create(context.Context, *models.BillRequest) (*models.Bill, error)
+	AddLineItems(context.Context, *models.LineItem) (*models.LineItem, error)
+	Close(context.Context, string) (*models.Bill, error)
+}
+
+// UsageService rates metered usage into line items of a bill.
+type UsageService interface {
+	RateBill(context.Context, string) ([]*models.LineItem, error)
+}
+
 type Activities struct {
+	BillService  BillService
+	UsageService UsageService
+	// HTTPClient sends webhooks, http.DefaultClient when nil.
+	HTTPClient *http.Client
 }
 
 func (a *Activities) AddLineItemActivity(ctx context.Context, message LineItemSignal) error {
-	log.Printf("line item %s added, updating the bill amount\n", message.ItemID)
+	ctx = activityContext(ctx, "bill_id", message.BillID)
+	logging.From(ctx).Info("line item added, updating the bill amount", "line_item_id", message.ItemID)
+	return updateBillAmount(ctx, message.BillID)
+}
 
-	billRepository := repository.NewBillRepository(db.Clients.DB)
-	bill, err := billRepository.GetByID(ctx, message.BillID)
+func (a *Activities) RemoveLineItemActivity(ctx context.Context, message LineItemSignal) error {
+	ctx = activityContext(ctx, "bill_id", message.BillID)
+	logging.From(ctx).Info("line item removed, updating the bill amount", "line_item_id", message.ItemID)
+	return updateBillAmount(ctx, message.BillID)
+}
 
-	if err != nil {
-		log.Println("error occured while fetching the bill")
-		return errors.New("error occured while fetching the bill")
+// activityContext returns a copy of ctx whose logger adds args and, in an
+// activity, names its workflow, run and activity type, so the lines of an
+// activity join the lines of its workflow.
+func activityContext(ctx context.Context, args ...any) context.Context {
+	if activity.IsActivity(ctx) {
+		info := activity.GetInfo(ctx)
+		ctx = logging.With(ctx, "workflow_id", info.WorkflowExecution.ID, "run_id", info.WorkflowExecution.RunID, "activity", info.ActivityType.Name)
 	}
+	return logging.With(ctx, args...)
+}
 
-	if bill.Status == "closed" {
-		log.Println("already closed bill can not be updated")
-		return errors.New("already closed bill can not be updated")
-	}
+// updateBillAmount sets the total of the open bill to its unbilled balance in
+// the ledger, so running it again or out of order gives the same total. The
+// bill is locked meanwhile, so a bill closing concurrently keeps its total.
+func updateBillAmount(ctx context.Context, billID string) error {
+	billRepository := repository.NewBillRepository(db.Clients.DB)
+	ledgerRepository := repository.NewLedgerRepository(db.Clients.DB)
 
-	lineItem, err := billRepository.GetLineItemByID(ctx, message.ItemID)
+	return billRepository.Transaction(ctx, func(ctx context.Context) error {
+		bill, err := billRepository.GetByIDForUpdate(ctx, billID)
 
-	if err != nil {
-		log.Println("error occured while fetching the bill")
-		return errors.New("error occured while fetching the bill")
-	}
+		if err != nil {
+			logging.From(ctx).Error("error occurred while fetching the bill", "error", err)
+			return errors.New("error occured while fetching the bill")
+		}
 
-	updatedAmount := bill.TotalAmount + lineItem.Amount
+		if bill.Status == "closed" {
+			logging.From(ctx).Warn("already closed bill can not be updated")
+			return errors.New("already closed bill can not be updated")
+		}
 
-	err = billRepository.UpdateBillAmount(ctx, message.BillID, updatedAmount)
+		updatedAmount, err := ledgerRepository.BillBalance(ctx, billID, models.UnbilledAccount)
 
-	if err != nil {
-		log.Println("failed to update bill amount")
-		return errors.New("failed to update bill amount")
-	}
+		if err != nil {
+			logging.From(ctx).Error("error occurred while fetching the bill balance", "error", err)
+			return errors.New("error occured while fetching the bill balance")
+		}
 
-	return nil
-}
+		err = billRepository.UpdateBillAmount(ctx, billID, updatedAmount)
 
-func (a *Activities) RemoveLineItemActivity(ctx context.Context, message LineItemSignal) error {
-	log.Printf("line item removed %s, updating the bill amount\n", message.ItemID)
+		if err != nil {
+			logging.From(ctx).Error("failed to update bill amount", "error", err)
+			return errors.New("failed to update bill amount")
+		}
 
-	billRepository := repository.NewBillRepository(db.Clients.DB)
-	bill, err := billRepository.GetByID(ctx, message.BillID)
-	if err != nil {
-		log.Println("error occured while fetching the bill")
-		return errors.New("error occured while fetching the bill")
-	}
+		return nil
+	})
+}
 
-	if bill.Status == "closed" {
-		log.Println("already closed bill can not be updated")
-		return errors.New("already closed bill can not be updated")
-	}
+func (a *Activities) RateUsageActivity(ctx context.Context, billID string) error {
+	ctx = activityContext(ctx, "bill_id", billID)
+	logging.From(ctx).Info("rating usage for bill")
 
-	lineItem, err := billRepository.GetLineItemByID(ctx, message.ItemID)
+	lineItems, err := a.UsageService.RateBill(ctx, billID)
 
-	if err != nil {
-		log.Println("error occured while fetching the bill")
-		return errors.New("error occured while fetching the bill")
+	if errors.Is(err, ce.BillClosedError) {
+		logging.From(ctx).Warn("already closed bill can not be rated")
+		return nil
 	}
 
-	updatedAmount := bill.TotalAmount - lineItem.Amount
-
-	err = billRepository.UpdateBillAmount(ctx, message.BillID, updatedAmount)
-
 	if err != nil {
-		log.Println("failed to update bill amount")
-		return errors.New("failed to update bill amount")
+		logging.From(ctx).Error("failed to rate usage", "error", err)
+		return errors.New("failed to rate usage")
 	}
+
+	logging.From(ctx).Info("added usage line items to bill", "line_items", len(lineItems))
 	return nil
 }
@@ -20,9 +20,37 @@ func BillingWorkflow(ctx workflow.Context, bill *models.Bill) error {
 	addLineItemChan := workflow.GetSignalChannel(ctx, "ADD_BILL_ITEM_CHANNEL")
 	removeLineItemChan := workflow.GetSignalChannel(ctx, "REMOVE_BILL_ITEM_CHANNEL")
 
+	// usage is rated onto the bill once its period has ended. Executions started
+	// before the timer was added have no timer in their history, so they replay
+	// without it.
+	var periodEndTimer workflow.Future
+	version := workflow.GetVersion(ctx, "rate-usage-at-period-end", workflow.DefaultVersion, 1)
+	if version != workflow.DefaultVersion && !bill.PeriodEnd.IsZero() {
+		wait := bill.PeriodEnd.Sub(workflow.Now(ctx))
+		if wait < 0 {
+			wait = 0
+		}
+		periodEndTimer = workflow.NewTimer(ctx, wait)
+	}
+
 	for {
 		selector := workflow.NewSelector(ctx)
 
+		if periodEndTimer != nil {
+			selector.AddFuture(periodEndTimer, func(f workflow.Future) {
+				periodEndTimer = nil
+
+				ao := workflow.ActivityOptions{
+					StartToCloseTimeout: time.Minute,
+				}
+				ctx = workflow.WithActivityOptions(ctx, ao)
+				err := workflow.ExecuteActivity(ctx, a.RateUsageActivity, bill.ID).Get(ctx, nil)
+				if err != nil {
+					logger.Error("Error rating usage", "bill_id", bill.ID, "error", err)
+				}
+			})
+		}
+
 		selector.AddReceive(addLineItemChan, func(c workflow.ReceiveChannel, _ bool) {
 			var signal interface{}
 			c.Receive(ctx, &signal)
@@ -30,7 +58,7 @@ func BillingWorkflow(ctx workflow.Context, bill *models.Bill) error {
 			var message LineItemSignal
 			err := mapstructure.Decode(signal, &message)
 			if err != nil {
-				logger.Error("Invalid signal type %v", err)
+				logger.Error("Invalid signal type", "bill_id", bill.ID, "error", err)
 				return
 			}
 
@@ -40,7 +68,7 @@ func BillingWorkflow(ctx workflow.Context, bill *models.Bill) error {
 			ctx = workflow.WithActivityOptions(ctx, ao)
 			err = workflow.ExecuteActivity(ctx, a.AddLineItemActivity, message).Get(ctx, nil)
 			if err != nil {
-				logger.Error("Error adding bill item: %v", err)
+				logger.Error("Error adding bill item", "bill_id", bill.ID, "line_item_id", message.ItemID, "error", err)
 				return
 			}
 		})
@@ -52,7 +80,7 @@ func BillingWorkflow(ctx workflow.Context, bill *models.Bill) error {
 			var message LineItemSignal
 			err := mapstructure.Decode(signal, &message)
 			if err != nil {
-				logger.Error("Invalid signal type %v", err)
+				logger.Error("Invalid signal type", "bill_id", bill.ID, "error", err)
 				return
 			}
 
@@ -62,7 +90,7 @@ func BillingWorkflow(ctx workflow.Context, bill *models.Bill) error {
 			ctx = workflow.WithActivityOptions(ctx, ao)
 			err = workflow.ExecuteActivity(ctx, a.RemoveLineItemActivity, message).Get(ctx, nil)
 			if err != nil {
-				logger.Error("Error removing bill item: %v", err)
+				logger.Error("Error removing bill item", "bill_id", bill.ID, "line_item_id", message.ItemID, "error", err)
 				return
 			}
 		})
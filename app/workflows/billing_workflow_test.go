@@ -5,8 +5,10 @@ import (
 	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
 )
 
 type BillingWorkflowTestSuite struct {
@@ -79,6 +81,33 @@ func (s *BillingWorkflowTestSuite) Test_AddAndRemoveLineItems() {
 	s.True(s.env.IsWorkflowCompleted())
 }
 
+func (s *BillingWorkflowTestSuite) Test_RatesUsageAtPeriodEnd() {
+	var a *Activities
+	s.env.RegisterActivity(&Activities{})
+	s.env.OnActivity(a.RateUsageActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+
+	bill := models.Bill{
+		ID:          "bill-id-01",
+		PeriodStart: s.env.Now(),
+		PeriodEnd:   s.env.Now().Add(time.Hour * 24),
+	}
+
+	s.env.ExecuteWorkflow(BillingWorkflow, &bill)
+
+	s.True(s.env.IsWorkflowCompleted())
+}
+
+// The history is of a bill opened for a period and given a line item by a
+// worker that did not start the period end timer yet.
+func (s *BillingWorkflowTestSuite) Test_ReplaysHistoryRecordedBeforePeriodEndTimer() {
+	replayer := worker.NewWorkflowReplayer()
+	replayer.RegisterWorkflow(BillingWorkflow)
+
+	err := replayer.ReplayWorkflowHistoryFromJSONFile(nil, "testdata/billing_workflow_before_period_end.json")
+
+	s.Nil(err)
+}
+
 func TestBillingWorkflowTestSuite(t *testing.T) {
 	suite.Run(t, new(BillingWorkflowTestSuite))
 }
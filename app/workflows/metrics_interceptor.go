@@ -0,0 +1,49 @@
+package workflows
+
+import (
+	"context"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+)
+
+// metricsInterceptor times every attempt of the activities of the worker,
+// by activity and outcome, and counts the attempts after the first as
+// retries.
+type metricsInterceptor struct {
+	interceptor.WorkerInterceptorBase
+}
+
+func NewMetricsInterceptor() interceptor.WorkerInterceptor {
+	return &metricsInterceptor{}
+}
+
+func (mi *metricsInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &activityMetricsInterceptor{}
+	i.Next = next
+	return i
+}
+
+type activityMetricsInterceptor struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (ai *activityMetricsInterceptor) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	info := activity.GetInfo(ctx)
+	if info.Attempt > 1 {
+		metrics.ActivityRetries.Inc(info.ActivityType.Name)
+	}
+
+	start := time.Now()
+	result, err := ai.Next.ExecuteActivity(ctx, in)
+
+	outcome := metrics.OutcomeSuccess
+	if err != nil {
+		outcome = metrics.OutcomeFailure
+	}
+	metrics.ActivityDuration.Observe(time.Since(start).Seconds(), info.ActivityType.Name, outcome)
+
+	return result, err
+}
@@ -0,0 +1,50 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+)
+
+type MetricsInterceptorTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+}
+
+func (s *MetricsInterceptorTestSuite) Test_TimesActivityAttemptsAndCountsRetries() {
+	env := s.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{NewMetricsInterceptor()}})
+	// mocked activities skip interceptors, so the delivery is a registered
+	// activity failing its first two attempts
+	attempts := 0
+	env.RegisterActivityWithOptions(func(ctx context.Context, deliveryID string) error {
+		attempts++
+		if attempts <= 2 {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, activity.RegisterOptions{Name: "DeliverWebhookActivity"})
+
+	retries := metrics.ActivityRetries.Value("DeliverWebhookActivity")
+	failures := metrics.ActivityDuration.Count("DeliverWebhookActivity", metrics.OutcomeFailure)
+	successes := metrics.ActivityDuration.Count("DeliverWebhookActivity", metrics.OutcomeSuccess)
+
+	env.ExecuteWorkflow(WebhookDeliveryWorkflow, "delivery-id-01")
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	s.Equal(retries+2, metrics.ActivityRetries.Value("DeliverWebhookActivity"))
+	s.Equal(failures+2, metrics.ActivityDuration.Count("DeliverWebhookActivity", metrics.OutcomeFailure))
+	s.Equal(successes+1, metrics.ActivityDuration.Count("DeliverWebhookActivity", metrics.OutcomeSuccess))
+}
+
+func TestMetricsInterceptorTestSuite(t *testing.T) {
+	suite.Run(t, new(MetricsInterceptorTestSuite))
+}
@@ -0,0 +1,171 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+type SubscriptionPeriod struct {
+	SubscriptionID string
+	PeriodStart    time.Time
+	PeriodEnd      time.Time
+}
+
+// OpenSubscriptionBillActivity creates the bill for a subscription period,
+// charges the subscription items for the full period and returns its id. The
+// bill is saved on the subscription as soon as it is created, so retries for
+// the period charge the items still missing on that bill instead of opening
+// another one.
+func (a *Activities) OpenSubscriptionBillActivity(ctx context.Context, period SubscriptionPeriod) (string, error) {
+	ctx = activityContext(ctx, "subscription_id", period.SubscriptionID)
+	logging.From(ctx).Info("opening bill for subscription period", "period_start", period.PeriodStart, "period_end", period.PeriodEnd)
+
+	subscriptionRepository := repository.NewSubscriptionRepository(db.Clients.DB)
+	subscription, err := subscriptionRepository.GetByID(ctx, period.SubscriptionID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the subscription", "error", err)
+		return "", errors.New("error occured while fetching the subscription")
+	}
+
+	billID := subscription.CurrentBillID
+	if billID != "" && subscription.CurrentPeriodStart.Equal(period.PeriodStart) {
+		logging.From(ctx).Warn("bill already opened for subscription", "bill_id", billID)
+	} else {
+		billID, err = a.createSubscriptionBill(ctx, subscriptionRepository, subscription, period)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	billRepository := repository.NewBillRepository(db.Clients.DB)
+	lineItems, err := billRepository.GetLineItemsByBillID(ctx, billID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the line items of the bill", "bill_id", billID, "error", err)
+		return "", errors.New("error occured while fetching the line items of the bill")
+	}
+
+	// items charged by an earlier attempt are not charged again
+	charged := map[string]int{}
+	for _, lineItem := range lineItems {
+		charged[lineItem.PriceID]++
+	}
+
+	items, err := subscriptionRepository.ListItemsBySubscriptionID(ctx, subscription.ID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the subscription items", "error", err)
+		return "", errors.New("error occured while fetching the subscription items")
+	}
+
+	for _, item := range items {
+		if charged[item.PriceID] > 0 {
+			charged[item.PriceID]--
+			continue
+		}
+
+		_, err = a.BillService.AddLineItems(ctx, &models.LineItem{
+			BillID:   billID,
+			PriceID:  item.PriceID,
+			Quantity: item.Quantity,
+		})
+
+		if errors.Is(err, ce.PriceArchivedError) || errors.Is(err, ce.PlanArchivedError) || errors.Is(err, ce.ProductArchivedError) {
+			logging.From(ctx).Warn("skipping subscription item with archived price", "item_id", item.ID, "price_id", item.PriceID)
+			continue
+		}
+
+		if err != nil {
+			logging.From(ctx).Error("failed to charge subscription item", "item_id", item.ID, "error", err)
+			return "", errors.New("failed to charge subscription item")
+		}
+	}
+
+	return billID, nil
+}
+
+// createSubscriptionBill creates the bill of the period and saves it on the
+// subscription as its current bill.
+func (a *Activities) createSubscriptionBill(ctx context.Context, subscriptionRepository repository.SubscriptionRepository, subscription *models.Subscription, period SubscriptionPeriod) (string, error) {
+	currency, err := repository.NewCurrencyRepository(db.Clients.DB).GetByID(ctx, subscription.CurrencyID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the subscription currency", "error", err)
+		return "", errors.New("error occured while fetching the subscription currency")
+	}
+
+	bill, err := a.BillService.Create(ctx, &models.BillRequest{
+		Description:  subscription.Description,
+		CustomerID:   subscription.CustomerID,
+		CurrencyCode: currency.Code,
+		PeriodStart:  period.PeriodStart,
+		PeriodEnd:    period.PeriodEnd,
+	})
+
+	if err != nil {
+		logging.From(ctx).Error("failed to create subscription bill", "error", err)
+		return "", errors.New("failed to create subscription bill")
+	}
+
+	subscription.CurrentBillID = bill.ID
+	subscription.CurrentPeriodStart = period.PeriodStart
+	subscription.CurrentPeriodEnd = period.PeriodEnd
+
+	_, err = subscriptionRepository.Update(ctx, subscription)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to update subscription period", "bill_id", bill.ID, "error", err)
+		return "", errors.New("failed to update subscription period")
+	}
+
+	return bill.ID, nil
+}
+
+func (a *Activities) CloseSubscriptionBillActivity(ctx context.Context, billID string) error {
+	ctx = activityContext(ctx, "bill_id", billID)
+	logging.From(ctx).Info("closing subscription bill")
+
+	_, err := a.BillService.Close(ctx, billID)
+
+	if errors.Is(err, ce.BillClosedError) {
+		return nil
+	}
+
+	if err != nil {
+		logging.From(ctx).Error("failed to close subscription bill", "error", err)
+		return errors.New("failed to close subscription bill")
+	}
+
+	return nil
+}
+
+func (a *Activities) EndSubscriptionActivity(ctx context.Context, subscriptionID string) error {
+	ctx = activityContext(ctx, "subscription_id", subscriptionID)
+	logging.From(ctx).Info("ending subscription")
+
+	subscriptionRepository := repository.NewSubscriptionRepository(db.Clients.DB)
+	subscription, err := subscriptionRepository.GetByID(ctx, subscriptionID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the subscription", "error", err)
+		return errors.New("error occured while fetching the subscription")
+	}
+
+	subscription.Status = "cancelled"
+	_, err = subscriptionRepository.Update(ctx, subscription)
+
+	if err != nil {
+		logging.From(ctx).Error("failed to cancel subscription", "error", err)
+		return errors.New("failed to cancel subscription")
+	}
+
+	return nil
+}
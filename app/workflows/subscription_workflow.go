@@ -0,0 +1,121 @@
+package workflows
+
+import (
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// SubscriptionWorkflow runs one billing period of a subscription. It opens the
+// bill for the current period, rates usage onto and closes the bill of the
+// previous period and waits for the period to end before continuing as new with
+// the next period. Rating and closing are retried until they succeed, the
+// workflow fails rather than leave a bill of an ended period open.
+func SubscriptionWorkflow(ctx workflow.Context, subscription *models.Subscription) error {
+	logger := workflow.GetLogger(ctx)
+
+	var a *Activities
+	ao := workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    time.Second,
+			BackoffCoefficient: 2,
+			MaximumInterval:    time.Hour,
+		},
+	}
+	ctx = workflow.WithActivityOptions(ctx, ao)
+	cancelChan := workflow.GetSignalChannel(ctx, "CANCEL_SUBSCRIPTION_CHANNEL")
+
+	period := SubscriptionPeriod{
+		SubscriptionID: subscription.ID,
+		PeriodStart:    subscription.CurrentPeriodStart,
+		PeriodEnd:      subscription.CurrentPeriodEnd,
+	}
+
+	var billID string
+	err := workflow.ExecuteActivity(ctx, a.OpenSubscriptionBillActivity, period).Get(ctx, &billID)
+	if err != nil {
+		logger.Error("Error opening subscription bill", "error", err)
+		return err
+	}
+
+	if subscription.CurrentBillID != "" && subscription.CurrentBillID != billID {
+		err = workflow.ExecuteActivity(ctx, a.RateUsageActivity, subscription.CurrentBillID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Error rating usage of previous subscription bill", "error", err)
+			if failOnUnclosedBill(ctx) {
+				return err
+			}
+		}
+
+		err = workflow.ExecuteActivity(ctx, a.CloseSubscriptionBillActivity, subscription.CurrentBillID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Error closing previous subscription bill", "error", err)
+			if failOnUnclosedBill(ctx) {
+				return err
+			}
+		}
+	}
+
+	wait := subscription.CurrentPeriodEnd.Sub(workflow.Now(ctx))
+	if wait < 0 {
+		wait = 0
+	}
+	timer := workflow.NewTimer(ctx, wait)
+
+	periodEnded := false
+	for !periodEnded {
+		selector := workflow.NewSelector(ctx)
+
+		selector.AddFuture(timer, func(f workflow.Future) {
+			periodEnded = true
+		})
+
+		selector.AddReceive(cancelChan, func(c workflow.ReceiveChannel, _ bool) {
+			var signal interface{}
+			c.Receive(ctx, &signal)
+			subscription.CancelAtPeriodEnd = true
+		})
+
+		selector.Select(ctx)
+	}
+
+	var pending interface{}
+	for cancelChan.ReceiveAsync(&pending) {
+		subscription.CancelAtPeriodEnd = true
+	}
+
+	if subscription.CancelAtPeriodEnd {
+		err = workflow.ExecuteActivity(ctx, a.RateUsageActivity, billID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Error rating usage of subscription bill", "error", err)
+			if failOnUnclosedBill(ctx) {
+				return err
+			}
+		}
+
+		err = workflow.ExecuteActivity(ctx, a.CloseSubscriptionBillActivity, billID).Get(ctx, nil)
+		if err != nil {
+			logger.Error("Error closing subscription bill", "error", err)
+			return err
+		}
+
+		return workflow.ExecuteActivity(ctx, a.EndSubscriptionActivity, subscription.ID).Get(ctx, nil)
+	}
+
+	next := *subscription
+	next.CurrentBillID = billID
+	next.CurrentPeriodStart = subscription.CurrentPeriodEnd
+	next.CurrentPeriodEnd = subscription.PeriodEnd(subscription.CurrentPeriodEnd)
+
+	return workflow.NewContinueAsNewError(ctx, SubscriptionWorkflow, &next)
+}
+
+// failOnUnclosedBill reports whether the workflow fails when rating or closing
+// a bill fails. Executions that carried on after such a failure before the
+// workflow failed on it replay the same way.
+func failOnUnclosedBill(ctx workflow.Context) bool {
+	return workflow.GetVersion(ctx, "fail-on-unclosed-bill", workflow.DefaultVersion, 1) != workflow.DefaultVersion
+}
@@ -0,0 +1,114 @@
+package workflows
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type SubscriptionWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env          *testsuite.TestWorkflowEnvironment
+	subscription *models.Subscription
+}
+
+func (s *SubscriptionWorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.env.RegisterActivity(&Activities{})
+
+	start := s.env.Now()
+	s.subscription = &models.Subscription{
+		ID:                 "subscription-id-01",
+		Description:        "pro plan",
+		BillingInterval:    models.MonthlyInterval,
+		Status:             "active",
+		CurrentPeriodStart: start,
+		CurrentPeriodEnd:   start.AddDate(0, 1, 0),
+	}
+}
+
+func (s *SubscriptionWorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *SubscriptionWorkflowTestSuite) Test_ContinuesAsNewAtPeriodEnd() {
+	var a *Activities
+	s.env.OnActivity(a.OpenSubscriptionBillActivity, mock.Anything, mock.Anything).Return("bill-id-01", nil).Once()
+
+	s.env.ExecuteWorkflow(SubscriptionWorkflow, s.subscription)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.True(workflow.IsContinueAsNewError(s.env.GetWorkflowError()))
+}
+
+func (s *SubscriptionWorkflowTestSuite) Test_ClosesPreviousBillWhenNextPeriodOpens() {
+	var a *Activities
+	s.subscription.CurrentBillID = "bill-id-01"
+	s.env.OnActivity(a.OpenSubscriptionBillActivity, mock.Anything, mock.Anything).Return("bill-id-02", nil).Once()
+	s.env.OnActivity(a.RateUsageActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+	s.env.OnActivity(a.CloseSubscriptionBillActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(SubscriptionWorkflow, s.subscription)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.True(workflow.IsContinueAsNewError(s.env.GetWorkflowError()))
+}
+
+func (s *SubscriptionWorkflowTestSuite) Test_FailsWhenPreviousBillIsNotClosed() {
+	var a *Activities
+	s.subscription.CurrentBillID = "bill-id-01"
+	s.env.OnActivity(a.OpenSubscriptionBillActivity, mock.Anything, mock.Anything).Return("bill-id-02", nil).Once()
+	s.env.OnActivity(a.RateUsageActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+	s.env.OnActivity(a.CloseSubscriptionBillActivity, mock.Anything, "bill-id-01").
+		Return(temporal.NewNonRetryableApplicationError("failed to close subscription bill", "close", nil)).Once()
+
+	s.env.ExecuteWorkflow(SubscriptionWorkflow, s.subscription)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.Error(s.env.GetWorkflowError())
+	s.False(workflow.IsContinueAsNewError(s.env.GetWorkflowError()))
+}
+
+func (s *SubscriptionWorkflowTestSuite) Test_RetriesClosingPreviousBill() {
+	var a *Activities
+	s.subscription.CurrentBillID = "bill-id-01"
+	s.env.OnActivity(a.OpenSubscriptionBillActivity, mock.Anything, mock.Anything).Return("bill-id-02", nil).Once()
+	s.env.OnActivity(a.RateUsageActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+	s.env.OnActivity(a.CloseSubscriptionBillActivity, mock.Anything, "bill-id-01").Return(errors.New("failed to close subscription bill")).Twice()
+	s.env.OnActivity(a.CloseSubscriptionBillActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(SubscriptionWorkflow, s.subscription)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.True(workflow.IsContinueAsNewError(s.env.GetWorkflowError()))
+}
+
+func (s *SubscriptionWorkflowTestSuite) Test_CancelsAtPeriodEnd() {
+	var a *Activities
+	s.env.OnActivity(a.OpenSubscriptionBillActivity, mock.Anything, mock.Anything).Return("bill-id-01", nil).Once()
+	s.env.OnActivity(a.RateUsageActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+	s.env.OnActivity(a.CloseSubscriptionBillActivity, mock.Anything, "bill-id-01").Return(nil).Once()
+	s.env.OnActivity(a.EndSubscriptionActivity, mock.Anything, s.subscription.ID).Return(nil).Once()
+
+	s.env.RegisterDelayedCallback(func() {
+		s.env.SignalWorkflow("CANCEL_SUBSCRIPTION_CHANNEL", s.subscription.ID)
+	}, time.Hour)
+
+	s.env.ExecuteWorkflow(SubscriptionWorkflow, s.subscription)
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func TestSubscriptionWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionWorkflowTestSuite))
+}
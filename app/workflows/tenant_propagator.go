@@ -0,0 +1,78 @@
+package workflows
+
+import (
+	"context"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/workflow"
+)
+
+// tenantHeader is the Temporal header carrying the tenant.
+const tenantHeader = "tenant-id"
+
+type tenantWorkflowContextKey struct{}
+
+// tenantPropagator carries the tenant of the context a workflow is started
+// or signalled with to the workflow, and from the workflow to its activities,
+// so their queries are scoped to the tenant. Workflows started without a
+// tenant run as tenancy.DefaultTenant.
+type tenantPropagator struct{}
+
+func NewTenantPropagator() workflow.ContextPropagator {
+	return &tenantPropagator{}
+}
+
+func (tp *tenantPropagator) Inject(ctx context.Context, writer workflow.HeaderWriter) error {
+	tenantID, ok := tenancy.Lookup(ctx)
+	if !ok {
+		return nil
+	}
+	return setTenantHeader(writer, tenantID)
+}
+
+func (tp *tenantPropagator) Extract(ctx context.Context, reader workflow.HeaderReader) (context.Context, error) {
+	tenantID, ok, err := tenantFromHeader(reader)
+	if err != nil || !ok {
+		return ctx, err
+	}
+	return tenancy.WithTenant(ctx, tenantID), nil
+}
+
+func (tp *tenantPropagator) InjectFromWorkflow(ctx workflow.Context, writer workflow.HeaderWriter) error {
+	tenantID, ok := ctx.Value(tenantWorkflowContextKey{}).(string)
+	if !ok {
+		return nil
+	}
+	return setTenantHeader(writer, tenantID)
+}
+
+func (tp *tenantPropagator) ExtractToWorkflow(ctx workflow.Context, reader workflow.HeaderReader) (workflow.Context, error) {
+	tenantID, ok, err := tenantFromHeader(reader)
+	if err != nil || !ok {
+		return ctx, err
+	}
+	return workflow.WithValue(ctx, tenantWorkflowContextKey{}, tenantID), nil
+}
+
+func setTenantHeader(writer workflow.HeaderWriter, tenantID string) error {
+	payload, err := converter.GetDefaultDataConverter().ToPayload(tenantID)
+	if err != nil {
+		return err
+	}
+	writer.Set(tenantHeader, payload)
+	return nil
+}
+
+func tenantFromHeader(reader workflow.HeaderReader) (string, bool, error) {
+	payload, ok := reader.Get(tenantHeader)
+	if !ok {
+		return "", false, nil
+	}
+
+	var tenantID string
+	if err := converter.GetDefaultDataConverter().FromPayload(payload, &tenantID); err != nil {
+		return "", false, err
+	}
+	return tenantID, tenantID != "", nil
+}
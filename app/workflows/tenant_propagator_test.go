@@ -0,0 +1,64 @@
+package workflows
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type TenantPropagatorTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+}
+
+func (s *TenantPropagatorTestSuite) Test_ActivitiesRunInTenantOfWorkflow() {
+	s.SetContextPropagators([]workflow.ContextPropagator{NewTenantPropagator()})
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(&Activities{})
+
+	payload, err := converter.GetDefaultDataConverter().ToPayload("tenant-a")
+	s.Require().NoError(err)
+	env.SetHeader(&commonpb.Header{Fields: map[string]*commonpb.Payload{tenantHeader: payload}})
+
+	var tenantID string
+	var a *Activities
+	env.OnActivity(a.DeliverWebhookActivity, mock.Anything, "delivery-id-01").Return(func(ctx context.Context, deliveryID string) error {
+		tenantID = tenancy.From(ctx)
+		return nil
+	}).Once()
+
+	env.ExecuteWorkflow(WebhookDeliveryWorkflow, "delivery-id-01")
+
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	s.Equal("tenant-a", tenantID)
+}
+
+func (s *TenantPropagatorTestSuite) Test_ActivitiesOfWorkflowWithoutTenantRunInDefaultTenant() {
+	s.SetContextPropagators([]workflow.ContextPropagator{NewTenantPropagator()})
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterActivity(&Activities{})
+
+	var tenantID string
+	var a *Activities
+	env.OnActivity(a.DeliverWebhookActivity, mock.Anything, "delivery-id-01").Return(func(ctx context.Context, deliveryID string) error {
+		tenantID = tenancy.From(ctx)
+		return nil
+	}).Once()
+
+	env.ExecuteWorkflow(WebhookDeliveryWorkflow, "delivery-id-01")
+
+	s.NoError(env.GetWorkflowError())
+	s.Equal(tenancy.DefaultTenant, tenantID)
+}
+
+func TestTenantPropagatorTestSuite(t *testing.T) {
+	suite.Run(t, new(TenantPropagatorTestSuite))
+}
@@ -0,0 +1,133 @@
+package workflows
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/db/repository"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"go.temporal.io/sdk/temporal"
+)
+
+// DeliverWebhookActivity posts the payload of the delivery to its endpoint
+// and records the attempt. Failed attempts return an error so the workflow
+// retries them. Deliveries that already succeeded are not sent again.
+func (a *Activities) DeliverWebhookActivity(ctx context.Context, deliveryID string) error {
+	ctx = activityContext(ctx, "delivery_id", deliveryID)
+	webhookRepository := repository.NewWebhookRepository(db.Clients.DB)
+	delivery, err := webhookRepository.GetDeliveryByID(ctx, deliveryID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the webhook delivery", "error", err)
+		return errors.New("error occured while fetching the webhook delivery")
+	}
+
+	if delivery.Status == models.DeliverySucceeded {
+		logging.From(ctx).Warn("webhook delivery already succeeded")
+		return nil
+	}
+
+	endpoint, err := webhookRepository.GetEndpointByID(ctx, delivery.EndpointID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the webhook endpoint", "error", err)
+		return errors.New("error occured while fetching the webhook endpoint")
+	}
+
+	if !endpoint.Active {
+		logging.From(ctx).Warn("webhook endpoint is disabled", "endpoint_id", endpoint.ID)
+		return temporal.NewNonRetryableApplicationError("webhook endpoint is disabled", "WebhookEndpointDisabled", nil)
+	}
+
+	delivery.Attempts++
+	delivery.ResponseStatus, err = SendWebhook(ctx, a.httpClient(), endpoint, delivery, time.Now().UTC())
+	delivery.UpdatedAt = time.Now().UTC()
+	if err == nil {
+		delivery.Status = models.DeliverySucceeded
+		delivery.LastError = ""
+		deliveredAt := delivery.UpdatedAt
+		delivery.DeliveredAt = &deliveredAt
+	} else {
+		delivery.LastError = err.Error()
+	}
+
+	_, updateErr := webhookRepository.UpdateDelivery(ctx, delivery)
+	if updateErr != nil {
+		logging.From(ctx).Error("failed to record webhook delivery attempt", "error", updateErr)
+		return errors.New("failed to record webhook delivery attempt")
+	}
+
+	if err != nil {
+		logging.From(ctx).Error("webhook delivery attempt failed", "attempts", delivery.Attempts, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// FailWebhookDeliveryActivity marks the delivery failed after its last
+// attempt.
+func (a *Activities) FailWebhookDeliveryActivity(ctx context.Context, deliveryID string) error {
+	ctx = activityContext(ctx, "delivery_id", deliveryID)
+	webhookRepository := repository.NewWebhookRepository(db.Clients.DB)
+	delivery, err := webhookRepository.GetDeliveryByID(ctx, deliveryID)
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while fetching the webhook delivery", "error", err)
+		return errors.New("error occured while fetching the webhook delivery")
+	}
+
+	delivery.Status = models.DeliveryFailed
+	delivery.UpdatedAt = time.Now().UTC()
+
+	_, err = webhookRepository.UpdateDelivery(ctx, delivery)
+	if err != nil {
+		logging.From(ctx).Error("failed to mark webhook delivery failed", "error", err)
+		return errors.New("failed to mark webhook delivery failed")
+	}
+
+	return nil
+}
+
+func (a *Activities) httpClient() *http.Client {
+	if a.HTTPClient != nil {
+		return a.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// SendWebhook posts the payload of the delivery to the endpoint, signed with
+// the secret of the endpoint at now. It returns the response status, and an
+// error for responses outside of 2xx.
+func SendWebhook(ctx context.Context, client *http.Client, endpoint *models.WebhookEndpoint, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	payload := []byte(delivery.Payload)
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(models.WebhookEventHeader, delivery.EventType)
+	request.Header.Set(models.WebhookDeliveryHeader, delivery.ID)
+	request.Header.Set(models.WebhookSignatureHeader, models.SignWebhook(endpoint.Secret, now, payload))
+
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return response.StatusCode, fmt.Errorf("webhook endpoint responded with status %d", response.StatusCode)
+	}
+
+	return response.StatusCode, nil
+}
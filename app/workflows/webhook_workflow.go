@@ -0,0 +1,47 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Retries of a webhook delivery back off exponentially from
+// WebhookRetryInterval up to WebhookMaxRetryInterval between attempts, for
+// about twelve hours in total.
+const (
+	WebhookMaxAttempts        = 12
+	WebhookRetryInterval      = 30 * time.Second
+	WebhookMaxRetryInterval   = 4 * time.Hour
+	WebhookBackoffCoefficient = 2.0
+)
+
+// WebhookDeliveryWorkflow delivers a webhook, retrying failed attempts with
+// exponential backoff. The delivery is marked failed once its attempts run
+// out, and can then only be redelivered by hand.
+func WebhookDeliveryWorkflow(ctx workflow.Context, deliveryID string) error {
+	logger := workflow.GetLogger(ctx)
+
+	var a *Activities
+	deliverCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy: &temporal.RetryPolicy{
+			InitialInterval:    WebhookRetryInterval,
+			BackoffCoefficient: WebhookBackoffCoefficient,
+			MaximumInterval:    WebhookMaxRetryInterval,
+			MaximumAttempts:    WebhookMaxAttempts,
+		},
+	})
+
+	err := workflow.ExecuteActivity(deliverCtx, a.DeliverWebhookActivity, deliveryID).Get(ctx, nil)
+	if err == nil {
+		return nil
+	}
+	logger.Error("Error delivering webhook", "delivery_id", deliveryID, "error", err)
+
+	failCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+	})
+	return workflow.ExecuteActivity(failCtx, a.FailWebhookDeliveryActivity, deliveryID).Get(ctx, nil)
+}
@@ -0,0 +1,122 @@
+package workflows
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/testsuite"
+)
+
+type WebhookWorkflowTestSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env *testsuite.TestWorkflowEnvironment
+}
+
+func (s *WebhookWorkflowTestSuite) SetupTest() {
+	s.env = s.NewTestWorkflowEnvironment()
+	s.env.RegisterActivity(&Activities{})
+}
+
+func (s *WebhookWorkflowTestSuite) AfterTest(suiteName, testName string) {
+	s.env.AssertExpectations(s.T())
+}
+
+func (s *WebhookWorkflowTestSuite) Test_DeliversWebhook() {
+	var a *Activities
+	s.env.OnActivity(a.DeliverWebhookActivity, mock.Anything, "delivery-id-01").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(WebhookDeliveryWorkflow, "delivery-id-01")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WebhookWorkflowTestSuite) Test_RetriesFailedDelivery() {
+	var a *Activities
+	s.env.OnActivity(a.DeliverWebhookActivity, mock.Anything, "delivery-id-01").Return(errors.New("connection refused")).Times(2)
+	s.env.OnActivity(a.DeliverWebhookActivity, mock.Anything, "delivery-id-01").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(WebhookDeliveryWorkflow, "delivery-id-01")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func (s *WebhookWorkflowTestSuite) Test_MarksDeliveryFailedWhenAttemptsRunOut() {
+	var a *Activities
+	s.env.OnActivity(a.DeliverWebhookActivity, mock.Anything, "delivery-id-01").Return(errors.New("connection refused")).Times(WebhookMaxAttempts)
+	s.env.OnActivity(a.FailWebhookDeliveryActivity, mock.Anything, "delivery-id-01").Return(nil).Once()
+
+	s.env.ExecuteWorkflow(WebhookDeliveryWorkflow, "delivery-id-01")
+
+	s.True(s.env.IsWorkflowCompleted())
+	s.NoError(s.env.GetWorkflowError())
+}
+
+func TestWebhookWorkflowTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookWorkflowTestSuite))
+}
+
+type SendWebhookTestSuite struct {
+	suite.Suite
+	endpoint *models.WebhookEndpoint
+	delivery *models.WebhookDelivery
+}
+
+func (s *SendWebhookTestSuite) SetupTest() {
+	s.endpoint = &models.WebhookEndpoint{ID: "endpoint-id-01", Secret: "secret", Active: true}
+	s.delivery = &models.WebhookDelivery{
+		ID:        "delivery-id-01",
+		EventType: models.BillClosed,
+		Payload:   `{"id":"event-id-01","type":"bill.closed"}`,
+	}
+}
+
+func (s *SendWebhookTestSuite) Test_SendsSignedPayload() {
+	now := time.Now().UTC()
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	s.endpoint.URL = server.URL
+
+	status, err := SendWebhook(context.Background(), server.Client(), s.endpoint, s.delivery, now)
+
+	s.Nil(err)
+	s.Equal(http.StatusNoContent, status)
+	s.Equal(s.delivery.Payload, string(body))
+	s.Equal(models.BillClosed, request.Header.Get(models.WebhookEventHeader))
+	s.Equal("delivery-id-01", request.Header.Get(models.WebhookDeliveryHeader))
+	s.True(models.VerifyWebhook("secret", request.Header.Get(models.WebhookSignatureHeader), body, now, time.Minute))
+}
+
+func (s *SendWebhookTestSuite) Test_FailsOnErrorResponse() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	s.endpoint.URL = server.URL
+
+	status, err := SendWebhook(context.Background(), server.Client(), s.endpoint, s.delivery, time.Now().UTC())
+
+	s.NotNil(err)
+	s.Equal(http.StatusServiceUnavailable, status)
+}
+
+func TestSendWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(SendWebhookTestSuite))
+}
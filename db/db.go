@@ -2,8 +2,9 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -22,22 +23,28 @@ var Clients *DBClient
 
 func InitDBClient(host, port, user, password, name, migrationsPath string) (*DBClient, error) {
 
-	log.Printf("initialising db for %s", name)
+	slog.Info("initialising db", "host", host, "port", port, "user", user, "name", name)
 
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", host, port, user, password, name)
-	log.Println(dsn)
 	dbConn, err := sql.Open("postgres", dsn)
 
 	if err != nil {
-		log.Fatalf("failed to create database connection, error %s\n", err.Error())
+		slog.Error("failed to create database connection", "host", host, "name", name, "error", err)
+		return nil, fmt.Errorf("creating database connection: %w", err)
 	}
 
 	gormDB, err := gorm.Open(gPostgres.New(gPostgres.Config{
 		Conn: dbConn,
-	}), &gorm.Config{})
+	}), &gorm.Config{TranslateError: true})
 
 	if err != nil {
-		log.Fatalf("failed to initialise gorm db client, error %s\n", err.Error())
+		slog.Error("failed to initialise gorm db client", "error", err)
+		return nil, fmt.Errorf("initialising gorm db client: %w", err)
+	}
+
+	if err = gormDB.Use(QueryMetrics{}); err != nil {
+		slog.Error("failed to register query metrics", "error", err)
+		return nil, fmt.Errorf("registering query metrics: %w", err)
 	}
 
 	Clients = &DBClient{
@@ -45,14 +52,15 @@ func InitDBClient(host, port, user, password, name, migrationsPath string) (*DBC
 	}
 
 	if err = runDBSchemaMigrations(dbConn, migrationsPath); err != nil {
-		log.Fatalf("error occured while running database schema migrations, error %s\n", err.Error())
+		slog.Error("error occurred while running database schema migrations", "path", migrationsPath, "error", err)
+		return nil, fmt.Errorf("running database schema migrations: %w", err)
 	}
 
 	return Clients, nil
 }
 
 func runDBSchemaMigrations(dbConn *sql.DB, migrationsPath string) error {
-	log.Println("running database schema migrations")
+	slog.Info("running database schema migrations", "path", migrationsPath)
 	driver, err := postgres.WithInstance(dbConn, &postgres.Config{})
 
 	if err != nil {
@@ -64,8 +72,13 @@ func runDBSchemaMigrations(dbConn *sql.DB, migrationsPath string) error {
 	if err != nil {
 		return err
 	}
-	m.Up()
 
-	log.Println("successfully ran database schema migrations")
+	// a failed or dirty migration must stop the service, later migrations
+	// would never run
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+
+	slog.Info("successfully ran database schema migrations")
 	return nil
 }
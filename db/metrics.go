@@ -0,0 +1,58 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	"gorm.io/gorm"
+)
+
+// queryStartKey keeps the start of a query in its statement.
+const queryStartKey = "billing:query_start"
+
+// QueryMetrics is a gorm plugin timing the queries of the database into
+// metrics.DBQueryDuration, by operation and table.
+type QueryMetrics struct{}
+
+func (QueryMetrics) Name() string {
+	return "billing:query_metrics"
+}
+
+func (QueryMetrics) Initialize(db *gorm.DB) error {
+	callback := db.Callback()
+	return errors.Join(
+		callback.Create().Before("gorm:create").Register("billing:create_start", startQuery),
+		callback.Create().After("gorm:create").Register("billing:create_end", endQuery("create")),
+		callback.Query().Before("gorm:query").Register("billing:query_start", startQuery),
+		callback.Query().After("gorm:query").Register("billing:query_end", endQuery("query")),
+		callback.Update().Before("gorm:update").Register("billing:update_start", startQuery),
+		callback.Update().After("gorm:update").Register("billing:update_end", endQuery("update")),
+		callback.Delete().Before("gorm:delete").Register("billing:delete_start", startQuery),
+		callback.Delete().After("gorm:delete").Register("billing:delete_end", endQuery("delete")),
+		callback.Row().Before("gorm:row").Register("billing:row_start", startQuery),
+		callback.Row().After("gorm:row").Register("billing:row_end", endQuery("row")),
+		callback.Raw().Before("gorm:raw").Register("billing:raw_start", startQuery),
+		callback.Raw().After("gorm:raw").Register("billing:raw_end", endQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func endQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		start, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+
+		// raw queries have no table
+		table := db.Statement.Table
+		if table == "" {
+			table = "none"
+		}
+		metrics.DBQueryDuration.Observe(time.Since(start.(time.Time)).Seconds(), operation, table)
+	}
+}
@@ -0,0 +1,62 @@
+package db
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/metrics"
+	gPostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a gorm client building queries without sending them, so
+// no database is needed.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dbConn, err := sql.Open("postgres", "host=localhost dbname=none sslmode=disable")
+	if err != nil {
+		t.Fatalf("sql.Open() = %s", err)
+	}
+
+	gormDB, err := gorm.Open(gPostgres.New(gPostgres.Config{Conn: dbConn}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open() = %s", err)
+	}
+	if err = gormDB.Use(QueryMetrics{}); err != nil {
+		t.Fatalf("Use(QueryMetrics{}) = %s", err)
+	}
+	return gormDB
+}
+
+func TestQueryMetricsTimesQueriesByOperationAndTable(t *testing.T) {
+	gormDB := dryRunDB(t)
+	creates := metrics.DBQueryDuration.Count("create", "bills")
+	queries := metrics.DBQueryDuration.Count("query", "bills")
+	updates := metrics.DBQueryDuration.Count("update", "bills")
+
+	gormDB.Create(&models.Bill{ID: "bill-01"})
+	gormDB.Where("id = ?", "bill-01").First(&models.Bill{})
+	gormDB.Model(&models.Bill{ID: "bill-01"}).Update("status", "closed")
+
+	if got := metrics.DBQueryDuration.Count("create", "bills"); got != creates+1 {
+		t.Errorf("Count(create, bills) = %d, want %d", got, creates+1)
+	}
+	if got := metrics.DBQueryDuration.Count("query", "bills"); got != queries+1 {
+		t.Errorf("Count(query, bills) = %d, want %d", got, queries+1)
+	}
+	if got := metrics.DBQueryDuration.Count("update", "bills"); got != updates+1 {
+		t.Errorf("Count(update, bills) = %d, want %d", got, updates+1)
+	}
+}
+
+func TestQueryMetricsNamesRawQueriesWithoutTable(t *testing.T) {
+	gormDB := dryRunDB(t)
+	raws := metrics.DBQueryDuration.Count("raw", "none")
+
+	gormDB.Exec("SELECT 1")
+
+	if got := metrics.DBQueryDuration.Count("raw", "none"); got != raws+1 {
+		t.Errorf("Count(raw, none) = %d, want %d", got, raws+1)
+	}
+}
@@ -0,0 +1,10 @@
+CREATE TABLE meters (
+    id VARCHAR(36) PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    aggregation VARCHAR(10) NOT NULL CHECK (aggregation IN ('sum', 'max', 'last')),
+    plan_id VARCHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    FOREIGN KEY (plan_id) REFERENCES plans(id)
+);
\ No newline at end of file
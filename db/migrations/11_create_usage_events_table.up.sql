@@ -0,0 +1,14 @@
+CREATE TABLE usage_events (
+    id VARCHAR(36) PRIMARY KEY,
+    idempotency_id VARCHAR(100) NOT NULL UNIQUE,
+    customer_id VARCHAR(36) NOT NULL,
+    meter_id VARCHAR(36) NOT NULL,
+    quantity DECIMAL(18, 4) NOT NULL CHECK (quantity >= 0),
+    timestamp TIMESTAMP NOT NULL,
+    bill_id VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (meter_id) REFERENCES meters(id)
+);
+
+CREATE INDEX usage_events_customer_id_timestamp_idx ON usage_events (customer_id, timestamp) WHERE bill_id = '';
\ No newline at end of file
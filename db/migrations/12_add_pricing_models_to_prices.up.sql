@@ -0,0 +1,7 @@
+ALTER TABLE prices
+    ADD COLUMN pricing_model VARCHAR(16) NOT NULL DEFAULT 'per_unit' CHECK (pricing_model IN ('per_unit', 'graduated', 'volume', 'package', 'flat')),
+    ADD COLUMN package_size DECIMAL(18, 4) NOT NULL DEFAULT 0 CHECK (package_size >= 0),
+    ADD COLUMN tiers JSONB NOT NULL DEFAULT '[]';
+
+ALTER TABLE line_items
+    ADD COLUMN pricing_details TEXT NOT NULL DEFAULT '';
\ No newline at end of file
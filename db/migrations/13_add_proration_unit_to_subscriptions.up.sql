@@ -0,0 +1,2 @@
+ALTER TABLE subscriptions
+    ADD COLUMN proration_unit VARCHAR(10) NOT NULL DEFAULT 'day' CHECK (proration_unit IN ('day', 'second'));
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE subscription_items (
+    id VARCHAR(36) PRIMARY KEY,
+    subscription_id VARCHAR(36) NOT NULL,
+    price_id VARCHAR(36) NOT NULL,
+    quantity DECIMAL(18, 4) NOT NULL DEFAULT 1 CHECK (quantity >= 0),
+    removed BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    FOREIGN KEY (subscription_id) REFERENCES subscriptions(id),
+    FOREIGN KEY (price_id) REFERENCES prices(id)
+);
+
+CREATE INDEX subscription_items_subscription_id_idx ON subscription_items (subscription_id) WHERE NOT removed;
\ No newline at end of file
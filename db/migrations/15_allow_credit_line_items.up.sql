@@ -0,0 +1,2 @@
+-- proration credits are line items with a negative amount
+ALTER TABLE line_items DROP CONSTRAINT line_items_amount_check;
\ No newline at end of file
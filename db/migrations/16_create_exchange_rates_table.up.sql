@@ -0,0 +1,9 @@
+CREATE TABLE exchange_rates (
+    id VARCHAR(36) PRIMARY KEY,
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(24, 10) NOT NULL CHECK (rate > 0),
+    effective_date TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    UNIQUE (base_currency, quote_currency, effective_date)
+);
\ No newline at end of file
@@ -0,0 +1,6 @@
+ALTER TABLE currencies ADD COLUMN active BOOLEAN NOT NULL DEFAULT true;
+
+-- deleting a currency must not delete the bills charged in it
+ALTER TABLE bills
+    DROP CONSTRAINT bills_currency_id_fkey,
+    ADD CONSTRAINT bills_currency_id_fkey FOREIGN KEY (currency_id) REFERENCES currencies(id) ON DELETE RESTRICT;
\ No newline at end of file
@@ -0,0 +1,51 @@
+ALTER TABLE customers ADD COLUMN active BOOLEAN NOT NULL DEFAULT true;
+
+-- emails were unique only as typed until now. The oldest customer of an email
+-- with open bills or active subscriptions keeps it, else the oldest one. The
+-- others are archived and their email is kept here to resolve
+CREATE TABLE customer_email_conflicts (
+    customer_id VARCHAR(36) PRIMARY KEY REFERENCES customers(id) ON DELETE CASCADE,
+    email VARCHAR(100) NOT NULL,
+    kept_by_customer_id VARCHAR(36) NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW())
+);
+
+INSERT INTO customer_email_conflicts (customer_id, email, kept_by_customer_id)
+SELECT id, email, kept_by_customer_id
+FROM (
+    SELECT id, email,
+        FIRST_VALUE(id) OVER (PARTITION BY LOWER(email) ORDER BY in_use DESC, created_at, id) AS kept_by_customer_id
+    FROM (
+        SELECT customers.*,
+            EXISTS (SELECT 1 FROM bills WHERE bills.customer_id = customers.id AND bills.status = 'open')
+            OR EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.customer_id = customers.id AND subscriptions.status = 'active') AS in_use
+        FROM customers
+    ) AS customers_in_use
+) AS customers_by_email
+WHERE id <> kept_by_customer_id;
+
+-- customers in use are not archived, more than one of them sharing an email
+-- is left to resolve by hand before migrating
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(customer_id || ' (' || email || ', kept by ' || kept_by_customer_id || ')', ', ' ORDER BY customer_id)
+    INTO conflicts
+    FROM customer_email_conflicts
+    WHERE EXISTS (SELECT 1 FROM bills WHERE bills.customer_id = customer_email_conflicts.customer_id AND bills.status = 'open')
+        OR EXISTS (SELECT 1 FROM subscriptions WHERE subscriptions.customer_id = customer_email_conflicts.customer_id AND subscriptions.status = 'active');
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'customers sharing an email have open bills or active subscriptions: %', conflicts;
+    END IF;
+END $$;
+
+UPDATE customers
+SET active = false, email = customers.id || '@duplicate.invalid', updated_at = timezone('UTC', NOW())
+FROM customer_email_conflicts
+WHERE customer_email_conflicts.customer_id = customers.id;
+
+CREATE UNIQUE INDEX customers_email_key ON customers (LOWER(email) text_pattern_ops);
+CREATE INDEX customers_first_name_idx ON customers (LOWER(first_name) text_pattern_ops);
+CREATE INDEX customers_last_name_idx ON customers (LOWER(last_name) text_pattern_ops);
\ No newline at end of file
@@ -0,0 +1,24 @@
+ALTER TABLE customers ADD COLUMN deleted_at TIMESTAMP;
+
+-- deleted customers give up their email
+DROP INDEX customers_email_key;
+CREATE UNIQUE INDEX customers_email_key ON customers (LOWER(email) text_pattern_ops) WHERE deleted_at IS NULL;
+
+-- bills and line items are only ever removed by an explicit purge
+ALTER TABLE bills
+    DROP CONSTRAINT bills_customer_id_fkey,
+    ADD CONSTRAINT bills_customer_id_fkey FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE RESTRICT;
+
+ALTER TABLE line_items
+    DROP CONSTRAINT line_items_bill_id_fkey,
+    ADD CONSTRAINT line_items_bill_id_fkey FOREIGN KEY (bill_id) REFERENCES bills(id) ON DELETE RESTRICT;
+
+CREATE TABLE purges (
+    id VARCHAR(36) PRIMARY KEY,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(36) NOT NULL,
+    reason TEXT NOT NULL,
+    requested_by VARCHAR(100) NOT NULL,
+    details TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW())
+);
\ No newline at end of file
@@ -0,0 +1,7 @@
+ALTER TABLE customers
+    ADD COLUMN company_name VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN tax_id VARCHAR(50) NOT NULL DEFAULT '',
+    ADD COLUMN billing_address JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN shipping_address JSONB NOT NULL DEFAULT '{}',
+    ADD COLUMN locale VARCHAR(35) NOT NULL DEFAULT 'en-US',
+    ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
\ No newline at end of file
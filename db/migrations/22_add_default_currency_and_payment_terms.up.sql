@@ -0,0 +1,7 @@
+ALTER TABLE customers
+    ADD COLUMN default_currency VARCHAR(3) NOT NULL DEFAULT '',
+    ADD COLUMN payment_terms VARCHAR(20) NOT NULL DEFAULT 'due_on_receipt';
+
+ALTER TABLE bills
+    ADD COLUMN payment_terms VARCHAR(20) NOT NULL DEFAULT 'due_on_receipt',
+    ADD COLUMN due_date TIMESTAMP;
\ No newline at end of file
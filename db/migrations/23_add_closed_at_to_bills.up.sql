@@ -0,0 +1,5 @@
+ALTER TABLE bills ADD COLUMN closed_at TIMESTAMP;
+
+UPDATE bills SET closed_at = updated_at WHERE status = 'closed';
+
+CREATE INDEX bills_customer_id_closed_at_idx ON bills (customer_id, closed_at) WHERE status = 'closed';
\ No newline at end of file
@@ -0,0 +1,92 @@
+CREATE TABLE ledger_accounts (
+    id VARCHAR(36) PRIMARY KEY,
+    code VARCHAR(20) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('asset', 'income')),
+    -- empty for the accounts kept once for the whole ledger
+    customer_id VARCHAR(36) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    UNIQUE (code, customer_id)
+);
+
+CREATE TABLE journal_entries (
+    id VARCHAR(36) PRIMARY KEY,
+    kind VARCHAR(30) NOT NULL,
+    reference_id VARCHAR(100) NOT NULL,
+    bill_id VARCHAR(36) NOT NULL,
+    customer_id VARCHAR(36) NOT NULL,
+    description TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    UNIQUE (kind, reference_id),
+    FOREIGN KEY (bill_id) REFERENCES bills(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX journal_entries_bill_id_idx ON journal_entries (bill_id);
+CREATE INDEX journal_entries_customer_id_created_at_idx ON journal_entries (customer_id, created_at);
+
+CREATE TABLE postings (
+    id VARCHAR(36) PRIMARY KEY,
+    journal_entry_id VARCHAR(36) NOT NULL,
+    account_id VARCHAR(36) NOT NULL,
+    currency_code CHAR(3) NOT NULL,
+    debit DECIMAL(18, 4) NOT NULL DEFAULT 0 CHECK (debit >= 0),
+    credit DECIMAL(18, 4) NOT NULL DEFAULT 0 CHECK (credit >= 0),
+    CHECK ((debit > 0) <> (credit > 0)),
+    FOREIGN KEY (journal_entry_id) REFERENCES journal_entries(id) ON DELETE RESTRICT,
+    FOREIGN KEY (account_id) REFERENCES ledger_accounts(id) ON DELETE RESTRICT
+);
+
+CREATE INDEX postings_journal_entry_id_idx ON postings (journal_entry_id);
+CREATE INDEX postings_account_id_idx ON postings (account_id);
+
+-- backfill the ledger from the line items and closed bills recorded so far
+INSERT INTO ledger_accounts (id, code, type) VALUES
+    (gen_random_uuid()::text, 'cash', 'asset'),
+    (gen_random_uuid()::text, 'revenue', 'income');
+
+INSERT INTO ledger_accounts (id, code, type, customer_id)
+SELECT gen_random_uuid()::text, accounts.code, 'asset', customers.customer_id
+FROM (SELECT DISTINCT customer_id FROM bills) customers
+CROSS JOIN (VALUES ('receivable'), ('unbilled')) accounts(code);
+
+INSERT INTO journal_entries (id, kind, reference_id, bill_id, customer_id, description, created_at)
+SELECT gen_random_uuid()::text, 'line_item_added', li.id, b.id, b.customer_id, li.description, li.created_at
+FROM line_items li JOIN bills b ON b.id = li.bill_id
+WHERE li.amount <> 0;
+
+INSERT INTO journal_entries (id, kind, reference_id, bill_id, customer_id, description, created_at)
+SELECT gen_random_uuid()::text, 'line_item_removed', li.id, b.id, b.customer_id, li.description, li.created_at
+FROM line_items li JOIN bills b ON b.id = li.bill_id
+WHERE li.amount <> 0 AND li.removed;
+
+INSERT INTO journal_entries (id, kind, reference_id, bill_id, customer_id, description, created_at)
+SELECT gen_random_uuid()::text, 'bill_closed', b.id, b.id, b.customer_id, b.description, COALESCE(b.closed_at, b.updated_at)
+FROM bills b
+WHERE b.status = 'closed'
+    AND (SELECT COALESCE(SUM(li.amount), 0) FROM line_items li WHERE li.bill_id = b.id AND NOT li.removed) <> 0;
+
+-- every entry moves its amount from the credited account to the debited one,
+-- negative amounts the other way
+WITH transfers AS (
+    SELECT e.id AS journal_entry_id, e.customer_id, c.code AS currency_code,
+        CASE e.kind WHEN 'line_item_added' THEN 'unbilled' WHEN 'line_item_removed' THEN 'revenue' ELSE 'receivable' END AS debit_code,
+        CASE e.kind WHEN 'line_item_added' THEN 'revenue' ELSE 'unbilled' END AS credit_code,
+        CASE e.kind
+            WHEN 'bill_closed' THEN (SELECT SUM(li.amount) FROM line_items li WHERE li.bill_id = e.bill_id AND NOT li.removed)
+            ELSE (SELECT li.amount FROM line_items li WHERE li.id = e.reference_id)
+        END AS amount
+    FROM journal_entries e
+    JOIN bills b ON b.id = e.bill_id
+    JOIN currencies c ON c.id = b.currency_id
+),
+legs AS (
+    SELECT journal_entry_id, customer_id, currency_code, debit_code AS code, GREATEST(amount, 0) AS debit, GREATEST(-amount, 0) AS credit
+    FROM transfers
+    UNION ALL
+    SELECT journal_entry_id, customer_id, currency_code, credit_code, GREATEST(-amount, 0), GREATEST(amount, 0)
+    FROM transfers
+)
+INSERT INTO postings (id, journal_entry_id, account_id, currency_code, debit, credit)
+SELECT gen_random_uuid()::text, legs.journal_entry_id, a.id, legs.currency_code, legs.debit, legs.credit
+FROM legs
+JOIN ledger_accounts a ON a.code = legs.code
+    AND a.customer_id = CASE WHEN legs.code = 'revenue' THEN '' ELSE legs.customer_id END;
\ No newline at end of file
@@ -0,0 +1,25 @@
+ALTER TABLE line_items ADD COLUMN removed_at TIMESTAMP;
+
+CREATE TABLE audit_events (
+    id VARCHAR(36) PRIMARY KEY,
+    actor VARCHAR(100) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id VARCHAR(100) NOT NULL,
+    before TEXT NOT NULL DEFAULT '',
+    after TEXT NOT NULL DEFAULT '',
+    request_id VARCHAR(100) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW())
+);
+
+CREATE INDEX audit_events_entity_idx ON audit_events (entity_type, entity_id, created_at);
+
+-- the audit log is append-only
+CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events can not be changed';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
\ No newline at end of file
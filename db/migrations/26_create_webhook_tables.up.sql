@@ -0,0 +1,29 @@
+CREATE TABLE webhook_endpoints (
+    id VARCHAR(36) PRIMARY KEY,
+    url TEXT NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    events TEXT NOT NULL DEFAULT '[]',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW())
+);
+
+CREATE TABLE webhook_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    endpoint_id VARCHAR(36) NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event_id VARCHAR(36) NOT NULL,
+    event_type VARCHAR(50) NOT NULL,
+    bill_id VARCHAR(36) NOT NULL DEFAULT '',
+    payload TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    redelivery_of VARCHAR(36) NOT NULL DEFAULT '',
+    delivered_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW())
+);
+
+CREATE INDEX webhook_deliveries_endpoint_idx ON webhook_deliveries (endpoint_id, created_at);
+CREATE INDEX webhook_deliveries_bill_idx ON webhook_deliveries (bill_id);
\ No newline at end of file
@@ -0,0 +1,12 @@
+CREATE TABLE bill_summaries (
+    bill_id VARCHAR(36) PRIMARY KEY,
+    customer_id VARCHAR(36) NOT NULL,
+    currency_id VARCHAR(36) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    total_amount DECIMAL(18,4) NOT NULL DEFAULT 0,
+    due_date TIMESTAMP,
+    last_event_id VARCHAR(36) NOT NULL,
+    last_event_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX bill_summaries_customer_idx ON bill_summaries (customer_id);
\ No newline at end of file
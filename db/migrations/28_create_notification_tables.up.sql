@@ -0,0 +1,22 @@
+CREATE TABLE email_templates (
+    name VARCHAR(50) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    subject TEXT NOT NULL,
+    text TEXT NOT NULL,
+    html TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW()),
+    PRIMARY KEY (name, locale)
+);
+
+CREATE TABLE invoice_deliveries (
+    id VARCHAR(36) PRIMARY KEY,
+    bill_id VARCHAR(36) NOT NULL REFERENCES bills(id) ON DELETE CASCADE,
+    recipient VARCHAR(255) NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    subject TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW())
+);
+
+CREATE INDEX invoice_deliveries_bill_idx ON invoice_deliveries (bill_id, created_at);
\ No newline at end of file
@@ -0,0 +1,8 @@
+CREATE TABLE api_keys (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(12) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL DEFAULT timezone('UTC', NOW()),
+    revoked_at TIMESTAMP
+);
\ No newline at end of file
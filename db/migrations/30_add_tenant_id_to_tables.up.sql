@@ -0,0 +1,58 @@
+-- every row belongs to a tenant, the rows from before tenants to the default
+-- one. iso_currencies and exchange_rates are reference data shared by all
+-- tenants.
+ALTER TABLE customers ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE currencies ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE bills ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE line_items ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE subscriptions ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE subscription_items ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE products ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE plans ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE prices ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE meters ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE usage_events ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE purges ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE ledger_accounts ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE journal_entries ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE postings ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE audit_events ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE webhook_endpoints ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE webhook_deliveries ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE bill_summaries ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE email_templates ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE invoice_deliveries ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+ALTER TABLE api_keys ADD COLUMN tenant_id VARCHAR(36) NOT NULL DEFAULT 'default';
+
+-- codes, emails and references are unique within a tenant
+ALTER TABLE currencies
+    DROP CONSTRAINT currencies_code_key,
+    ADD CONSTRAINT currencies_tenant_id_code_key UNIQUE (tenant_id, code);
+
+DROP INDEX customers_email_key;
+CREATE UNIQUE INDEX customers_email_key ON customers (tenant_id, LOWER(email) text_pattern_ops) WHERE deleted_at IS NULL;
+
+ALTER TABLE meters
+    DROP CONSTRAINT meters_code_key,
+    ADD CONSTRAINT meters_tenant_id_code_key UNIQUE (tenant_id, code);
+
+ALTER TABLE usage_events
+    DROP CONSTRAINT usage_events_idempotency_id_key,
+    ADD CONSTRAINT usage_events_tenant_id_idempotency_id_key UNIQUE (tenant_id, idempotency_id);
+
+ALTER TABLE ledger_accounts
+    DROP CONSTRAINT ledger_accounts_code_customer_id_key,
+    ADD CONSTRAINT ledger_accounts_tenant_id_code_customer_id_key UNIQUE (tenant_id, code, customer_id);
+
+ALTER TABLE journal_entries
+    DROP CONSTRAINT journal_entries_kind_reference_id_key,
+    ADD CONSTRAINT journal_entries_tenant_id_kind_reference_id_key UNIQUE (tenant_id, kind, reference_id);
+
+ALTER TABLE email_templates
+    DROP CONSTRAINT email_templates_pkey,
+    ADD PRIMARY KEY (tenant_id, name, locale);
+
+CREATE INDEX customers_tenant_id_idx ON customers (tenant_id);
+CREATE INDEX bills_tenant_id_idx ON bills (tenant_id);
+CREATE INDEX audit_events_tenant_id_idx ON audit_events (tenant_id, entity_type, entity_id, created_at);
+CREATE INDEX api_keys_tenant_id_idx ON api_keys (tenant_id);
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- keys issued before roles keep the access they had
+ALTER TABLE api_keys ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'finance_admin' CHECK (role IN ('viewer', 'biller', 'finance_admin'));
+ALTER TABLE api_keys ALTER COLUMN role DROP DEFAULT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+-- buckets of the rate limits shared by every instance of the service. They
+-- are keyed by api key, whose ids are unique across tenants.
+CREATE TABLE rate_limit_buckets (
+    key VARCHAR(100) PRIMARY KEY,
+    tokens DOUBLE PRECISION NOT NULL,
+    refilled_at TIMESTAMP NOT NULL
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+-- periods of existing subscriptions keep the day they start on now
+ALTER TABLE subscriptions ADD COLUMN anchor_day INTEGER NOT NULL DEFAULT 0;
+UPDATE subscriptions SET anchor_day = EXTRACT(DAY FROM current_period_start);
\ No newline at end of file
@@ -0,0 +1,4 @@
+-- invoice deliveries of a bill are only ever removed by an explicit purge
+ALTER TABLE invoice_deliveries
+    DROP CONSTRAINT invoice_deliveries_bill_id_fkey,
+    ADD CONSTRAINT invoice_deliveries_bill_id_fkey FOREIGN KEY (bill_id) REFERENCES bills(id) ON DELETE RESTRICT;
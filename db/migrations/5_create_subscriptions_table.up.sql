@@ -0,0 +1,17 @@
+CREATE TABLE subscriptions (
+    id VARCHAR(36) PRIMARY KEY,
+    description VARCHAR(100) NOT NULL,
+    customer_id VARCHAR(36) NOT NULL,
+    currency_id VARCHAR(36) NOT NULL,
+    billing_interval VARCHAR(20) NOT NULL CHECK (billing_interval IN ('monthly', 'yearly', 'custom')),
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('active', 'cancelled')),
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT false,
+    current_bill_id VARCHAR(36),
+    current_period_start TIMESTAMP NOT NULL,
+    current_period_end TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    FOREIGN KEY (customer_id) REFERENCES customers(id),
+    FOREIGN KEY (currency_id) REFERENCES currencies(id)
+);
\ No newline at end of file
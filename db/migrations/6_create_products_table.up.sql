@@ -0,0 +1,8 @@
+CREATE TABLE products (
+    id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP DEFAULT timezone('UTC', NOW())
+);
\ No newline at end of file
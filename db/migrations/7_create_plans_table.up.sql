@@ -0,0 +1,11 @@
+CREATE TABLE plans (
+    id VARCHAR(36) PRIMARY KEY,
+    product_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    billing_interval VARCHAR(20) NOT NULL CHECK (billing_interval IN ('monthly', 'yearly', 'custom')),
+    interval_days INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    FOREIGN KEY (product_id) REFERENCES products(id)
+);
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE prices (
+    id VARCHAR(36) PRIMARY KEY,
+    plan_id VARCHAR(36) NOT NULL,
+    currency_id VARCHAR(36) NOT NULL,
+    unit_amount DECIMAL(18, 2) NOT NULL CHECK (unit_amount >= 0),
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    updated_at TIMESTAMP DEFAULT timezone('UTC', NOW()),
+    FOREIGN KEY (plan_id) REFERENCES plans(id),
+    FOREIGN KEY (currency_id) REFERENCES currencies(id)
+);
+
+CREATE UNIQUE INDEX prices_plan_id_currency_id_active_idx ON prices (plan_id, currency_id) WHERE active;
\ No newline at end of file
@@ -0,0 +1,5 @@
+ALTER TABLE line_items
+    ADD COLUMN price_id VARCHAR(36),
+    ADD COLUMN quantity DECIMAL(18, 4) NOT NULL DEFAULT 1 CHECK (quantity >= 0);
+
+CREATE INDEX line_items_price_id_idx ON line_items (price_id);
\ No newline at end of file
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+)
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+type APIKeyRepository interface {
+	Create(context.Context, *models.APIKey) (*models.APIKey, error)
+	GetByID(context.Context, string) (*models.APIKey, error)
+	GetByHash(context.Context, string) (*models.APIKey, error)
+	List(context.Context) ([]*models.APIKey, error)
+	Revoke(context.Context, string, time.Time) (*models.APIKey, error)
+}
+
+func NewAPIKeyRepository(dbClient *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{
+		db: dbClient,
+	}
+}
+
+func (ar *apiKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) (*models.APIKey, error) {
+	apiKey.TenantID = tenancy.From(ctx)
+	result := ar.db.Create(&apiKey)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating api key", "name", apiKey.Name, "error", result.Error)
+		return apiKey, fmt.Errorf("creating api key %s: %w", apiKey.Name, result.Error)
+	}
+
+	return apiKey, nil
+}
+
+func (ar *apiKeyRepository) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	apiKey := &models.APIKey{}
+	result := ar.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&apiKey)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("api key not found", "api_key_id", id)
+		return apiKey, ce.APIKeyNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying api key", "api_key_id", id, "error", result.Error)
+		return apiKey, fmt.Errorf("querying api key %s: %w", id, result.Error)
+	}
+
+	return apiKey, nil
+}
+
+// GetByHash returns the api key of the hash, revoked or not.
+func (ar *apiKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	apiKey := &models.APIKey{}
+	result := ar.db.Where("key_hash = ?", keyHash).First(&apiKey)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		return apiKey, ce.APIKeyNotFoundError
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying api key by hash", "error", result.Error)
+		return apiKey, fmt.Errorf("querying api key by hash: %w", result.Error)
+	}
+
+	return apiKey, nil
+}
+
+// List returns every api key, revoked ones included, oldest first.
+func (ar *apiKeyRepository) List(ctx context.Context) ([]*models.APIKey, error) {
+	apiKeys := []*models.APIKey{}
+	result := ar.db.Scopes(tenancy.Scope(ctx)).Order("created_at, id").Find(&apiKeys)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing api keys", "error", result.Error)
+		return apiKeys, fmt.Errorf("listing api keys: %w", result.Error)
+	}
+
+	return apiKeys, nil
+}
+
+// Revoke revokes the api key at revokedAt. Revoking a revoked key keeps the
+// time it was first revoked.
+func (ar *apiKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) (*models.APIKey, error) {
+	result := ar.db.Scopes(tenancy.Scope(ctx)).Model(&models.APIKey{}).Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", revokedAt)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while revoking api key", "api_key_id", id, "error", result.Error)
+		return &models.APIKey{}, fmt.Errorf("revoking api key %s: %w", id, result.Error)
+	}
+
+	return ar.GetByID(ctx, id)
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type APIKeyRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	ar       APIKeyRepository
+}
+
+func (suite *APIKeyRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.ar = NewAPIKeyRepository(dbClient.DB)
+}
+
+func (suite *APIKeyRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM api_keys")
+}
+
+func (suite *APIKeyRepositoryTestSuite) Test_GetByHashReturnsKey() {
+	ctx := context.Background()
+	key := models.APIKeyPrefix + utils.RandomString(32)
+	apiKey, err := suite.ar.Create(ctx, models.NewAPIKey(utils.GetNewUUID(), "billing cron", models.RoleBiller, key, time.Now().UTC()))
+	suite.Nil(err, "error should be nil")
+
+	found, err := suite.ar.GetByHash(ctx, models.HashAPIKey(key))
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(apiKey.ID, found.ID)
+	suite.Equal(models.RoleBiller, found.Role)
+	suite.Equal(key[:12], found.Prefix)
+	suite.Nil(found.RevokedAt)
+}
+
+func (suite *APIKeyRepositoryTestSuite) Test_GetByHashFailsForUnknownKey() {
+	_, err := suite.ar.GetByHash(context.Background(), models.HashAPIKey("bsk_unknown"))
+
+	suite.ErrorIs(err, ce.APIKeyNotFoundError)
+}
+
+func (suite *APIKeyRepositoryTestSuite) Test_RevokeKeepsFirstRevocation() {
+	ctx := context.Background()
+	key := models.APIKeyPrefix + utils.RandomString(32)
+	apiKey, err := suite.ar.Create(ctx, models.NewAPIKey(utils.GetNewUUID(), "ci", models.RoleViewer, key, time.Now().UTC()))
+	suite.Nil(err, "error should be nil")
+	revokedAt := time.Now().UTC().Truncate(time.Microsecond)
+
+	revoked, err := suite.ar.Revoke(ctx, apiKey.ID, revokedAt)
+	suite.Nil(err, "error should be nil")
+	suite.NotNil(revoked.RevokedAt)
+
+	revoked, err = suite.ar.Revoke(ctx, apiKey.ID, revokedAt.Add(time.Hour))
+	suite.Nil(err, "error should be nil")
+	suite.True(revokedAt.Equal(revoked.RevokedAt.UTC()))
+}
+
+func (suite *APIKeyRepositoryTestSuite) Test_RevokeFailsForUnknownKey() {
+	_, err := suite.ar.Revoke(context.Background(), utils.GetNewUUID(), time.Now().UTC())
+
+	suite.ErrorIs(err, ce.APIKeyNotFoundError)
+}
+
+func TestAPIKeyRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyRepositoryTestSuite))
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+)
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+type AuditRepository interface {
+	Create(context.Context, *models.AuditEvent) (*models.AuditEvent, error)
+	List(context.Context, *models.ListAuditEventsRequest) ([]*models.AuditEvent, int64, error)
+}
+
+func NewAuditRepository(dbClient *gorm.DB) AuditRepository {
+	return &auditRepository{
+		db: dbClient,
+	}
+}
+
+func (ar *auditRepository) Create(ctx context.Context, event *models.AuditEvent) (*models.AuditEvent, error) {
+	event.TenantID = tenancy.From(ctx)
+	result := ar.db.Create(&event)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating audit event", "event_id", event.ID, "error", result.Error)
+		return event, fmt.Errorf("creating audit event: %w", result.Error)
+	}
+
+	return event, nil
+}
+
+// List returns a page of the audit events of the request, newest first, with
+// the total number of matching events.
+func (ar *auditRepository) List(ctx context.Context, request *models.ListAuditEventsRequest) ([]*models.AuditEvent, int64, error) {
+	events := []*models.AuditEvent{}
+	query := ar.db.Scopes(tenancy.Scope(ctx)).Model(&models.AuditEvent{}).Where("entity_type = ?", request.EntityType)
+	if request.EntityID != "" {
+		query = query.Where("entity_id = ?", request.EntityID)
+	}
+
+	var total int64
+	result := query.Count(&total)
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while counting audit events", "entity_type", request.EntityType, "error", result.Error)
+		return events, 0, fmt.Errorf("counting audit events of %s: %w", request.EntityType, result.Error)
+	}
+
+	result = query.Order("created_at DESC, id").Limit(request.PageSize()).Offset(request.Offset).Find(&events)
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing audit events", "entity_type", request.EntityType, "error", result.Error)
+		return events, 0, fmt.Errorf("listing audit events of %s: %w", request.EntityType, result.Error)
+	}
+
+	return events, total, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+// Audit events are append-only, so the suite leaves them in place and keeps
+// its events apart by entity id.
+type AuditRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	ar       AuditRepository
+	entityID string
+}
+
+func (suite *AuditRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.ar = NewAuditRepository(dbClient.DB)
+	suite.entityID = utils.GetNewUUID()
+}
+
+func (suite *AuditRepositoryTestSuite) event(action string, createdAt time.Time) *models.AuditEvent {
+	return &models.AuditEvent{
+		ID:         utils.GetNewUUID(),
+		Actor:      "jane@billing",
+		Action:     action,
+		EntityType: models.LineItemEntity,
+		EntityID:   suite.entityID,
+		After:      `{"Removed":true}`,
+		RequestID:  "request-01",
+		CreatedAt:  createdAt,
+	}
+}
+
+func (suite *AuditRepositoryTestSuite) Test_ListReturnsEventsOfEntityNewestFirst() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	_, err := suite.ar.Create(ctx, suite.event(models.LineItemAdded, now.Add(-time.Minute)))
+	suite.Nil(err, "error should be nil")
+	_, err = suite.ar.Create(ctx, suite.event(models.LineItemRemoved, now))
+	suite.Nil(err, "error should be nil")
+
+	events, total, err := suite.ar.List(ctx, &models.ListAuditEventsRequest{EntityType: models.LineItemEntity, EntityID: suite.entityID})
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(2), total)
+	suite.Len(events, 2)
+	suite.Equal(models.LineItemRemoved, events[0].Action)
+	suite.Equal("jane@billing", events[0].Actor)
+}
+
+func (suite *AuditRepositoryTestSuite) Test_EventsCanNotBeChanged() {
+	ctx := context.Background()
+	event, err := suite.ar.Create(ctx, suite.event(models.LineItemAdded, time.Now().UTC()))
+	suite.Nil(err, "error should be nil")
+
+	result := suite.dbClient.Model(event).Update("actor", "someone else")
+	suite.NotNil(result.Error, "error should not be nil")
+
+	result = suite.dbClient.Delete(event)
+	suite.NotNil(result.Error, "error should not be nil")
+}
+
+func TestAuditRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
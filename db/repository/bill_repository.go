@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type billRepository struct {
@@ -16,12 +20,16 @@ type billRepository struct {
 type BillRepository interface {
 	Create(context.Context, *models.Bill) (*models.Bill, error)
 	GetByID(context.Context, string) (*models.Bill, error)
+	GetByIDForUpdate(context.Context, string) (*models.Bill, error)
 	AddLineItems(context.Context, *models.LineItem) (*models.LineItem, error)
 	RemoveLineItems(context.Context, *models.LineItem) (*models.LineItem, error)
 	GetLineItemsByBillID(context.Context, string) ([]*models.LineItem, error)
 	GetLineItemByID(context.Context, string) (*models.LineItem, error)
-	Close(context.Context, string) (*models.Bill, error)
+	Close(context.Context, string, time.Time, time.Time) (*models.Bill, error)
+	ListClosedByCustomerID(context.Context, string, time.Time) ([]*models.Bill, error)
 	UpdateBillAmount(context.Context, string, float64) error
+	CountOpen(context.Context) (int64, error)
+	Transaction(context.Context, func(context.Context) error) error
 }
 
 func NewBillRepository(dbClient *gorm.DB) BillRepository {
@@ -31,11 +39,12 @@ func NewBillRepository(dbClient *gorm.DB) BillRepository {
 }
 
 func (br *billRepository) Create(ctx context.Context, bill *models.Bill) (*models.Bill, error) {
-	result := br.db.Create(&bill)
+	bill.TenantID = tenancy.From(ctx)
+	result := conn(ctx, br.db).Create(&bill)
 
 	if result.Error != nil {
-		log.Printf("error occured while creating bill, %v. error is %s", bill, result.Error.Error())
-		return bill, result.Error
+		logging.From(ctx).Error("error occurred while creating bill", "bill_id", bill.ID, "error", result.Error)
+		return bill, fmt.Errorf("creating bill: %w", result.Error)
 	}
 
 	return bill, nil
@@ -43,71 +52,123 @@ func (br *billRepository) Create(ctx context.Context, bill *models.Bill) (*model
 
 func (br *billRepository) GetByID(ctx context.Context, id string) (*models.Bill, error) {
 	bill := &models.Bill{}
-	result := br.db.Where("id = ?", id).First(&bill)
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&bill)
 
 	if result.Error == gorm.ErrRecordNotFound {
-		log.Printf("bill does not exist for id, %s. error is %s", id, result.Error.Error())
-		return bill, ce.BillNotFoundError
+		logging.From(ctx).Warn("bill not found", "bill_id", id)
+		return bill, ce.BillNotFoundError.WithID(id)
 	}
 
 	if result.Error != nil {
-		log.Printf("error occured while querying bill, %s. error is %s", id, result.Error.Error())
-		return bill, result.Error
+		logging.From(ctx).Error("error occurred while querying bill", "bill_id", id, "error", result.Error)
+		return bill, fmt.Errorf("querying bill %s: %w", id, result.Error)
+	}
+
+	return bill, nil
+}
+
+// GetByIDForUpdate returns the bill, locking its row until the transaction of
+// the context ends, so changes to the bill and its ledger take turns.
+func (br *billRepository) GetByIDForUpdate(ctx context.Context, id string) (*models.Bill, error) {
+	bill := &models.Bill{}
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&bill)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("bill not found", "bill_id", id)
+		return bill, ce.BillNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while locking bill", "bill_id", id, "error", result.Error)
+		return bill, fmt.Errorf("locking bill %s: %w", id, result.Error)
 	}
 
 	return bill, nil
 }
 
 func (br *billRepository) AddLineItems(ctx context.Context, lineItem *models.LineItem) (*models.LineItem, error) {
-	result := br.db.Create(&lineItem)
+	lineItem.TenantID = tenancy.From(ctx)
+	result := conn(ctx, br.db).Create(&lineItem)
 
 	if result.Error != nil {
-		log.Printf("error occured while creating lineItem, %v. error is %s", lineItem, result.Error.Error())
-		return lineItem, result.Error
+		logging.From(ctx).Error("error occurred while creating line item", "line_item_id", lineItem.ID, "error", result.Error)
+		return lineItem, fmt.Errorf("creating line item: %w", result.Error)
 	}
 
 	return lineItem, nil
 }
 
 func (br *billRepository) RemoveLineItems(ctx context.Context, lineItem *models.LineItem) (*models.LineItem, error) {
+	removedAt := time.Now().UTC()
 	lineItem.Removed = true
-	log.Printf("removing line item %v\n", lineItem)
-	result := br.db.Model(&lineItem).Where("id = ?", lineItem.ID).Update("removed", true)
+	lineItem.RemovedAt = &removedAt
+	logging.From(ctx).Info("removing line item", "line_item_id", lineItem.ID)
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Model(&lineItem).Where("id = ?", lineItem.ID).Updates(map[string]interface{}{"removed": true, "removed_at": removedAt})
 
 	if result.Error != nil {
-		log.Printf("error occured while removing lineItem, %v. error is %s", lineItem, result.Error.Error())
-		return lineItem, result.Error
+		logging.From(ctx).Error("error occurred while removing line item", "line_item_id", lineItem.ID, "error", result.Error)
+		return lineItem, fmt.Errorf("removing line item: %w", result.Error)
 	}
 
 	return lineItem, nil
 }
 
-func (br *billRepository) Close(ctx context.Context, id string) (*models.Bill, error) {
+func (br *billRepository) Close(ctx context.Context, id string, closedAt, dueDate time.Time) (*models.Bill, error) {
 	bill, err := br.GetByID(ctx, id)
 
 	if err != nil {
-		log.Printf("error occured while fetching bill id, %s. error is %s", id, err.Error())
-		return bill, err
+		logging.From(ctx).Error("error occurred while fetching bill", "bill_id", id, "error", err)
+		return bill, fmt.Errorf("fetching bill id %s: %w", id, err)
 	}
 
 	bill.Status = "closed"
-	result := br.db.Save(bill)
+	bill.ClosedAt = &closedAt
+	bill.DueDate = &dueDate
+	result := conn(ctx, br.db).Save(bill)
 
 	if result.Error != nil {
-		log.Printf("error occured while closing the bill id, %s. error is %s", id, result.Error.Error())
-		return bill, result.Error
+		logging.From(ctx).Error("error occurred while closing bill", "bill_id", id, "error", result.Error)
+		return bill, fmt.Errorf("closing the bill id %s: %w", id, result.Error)
 	}
 
 	return bill, nil
 }
 
+// ListClosedByCustomerID returns the bills of the customer closed before
+// until, oldest first.
+func (br *billRepository) ListClosedByCustomerID(ctx context.Context, customerID string, until time.Time) ([]*models.Bill, error) {
+	bills := []*models.Bill{}
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Where("customer_id = ? AND status = ? AND closed_at < ?", customerID, "closed", until).
+		Order("closed_at, id").Find(&bills)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing closed bills of customer", "customer_id", customerID, "error", result.Error)
+		return bills, fmt.Errorf("listing closed bills of customer %s: %w", customerID, result.Error)
+	}
+
+	return bills, nil
+}
+
+// CountOpen counts the open bills of all tenants.
+func (br *billRepository) CountOpen(ctx context.Context) (int64, error) {
+	var count int64
+	result := conn(ctx, br.db).Model(&models.Bill{}).Where("status = ?", "open").Count(&count)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while counting open bills", "error", result.Error)
+		return 0, fmt.Errorf("counting open bills: %w", result.Error)
+	}
+
+	return count, nil
+}
+
 func (br *billRepository) GetLineItemsByBillID(ctx context.Context, billID string) ([]*models.LineItem, error) {
 	lineItems := []*models.LineItem{}
-	result := br.db.Where("bill_id = ?", billID).Find(&lineItems)
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Where("bill_id = ?", billID).Find(&lineItems)
 
 	if result.Error != nil {
-		log.Printf("error occured while fetching line items for bill id, %s. error is %s", billID, result.Error.Error())
-		return lineItems, result.Error
+		logging.From(ctx).Error("error occurred while fetching line items for bill", "bill_id", billID, "error", result.Error)
+		return lineItems, fmt.Errorf("fetching line items for bill id %s: %w", billID, result.Error)
 	}
 
 	return lineItems, nil
@@ -115,30 +176,36 @@ func (br *billRepository) GetLineItemsByBillID(ctx context.Context, billID strin
 
 func (br *billRepository) GetLineItemByID(ctx context.Context, id string) (*models.LineItem, error) {
 	lineItem := &models.LineItem{}
-	result := br.db.Where("id = ?", id).First(&lineItem)
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&lineItem)
 
 	if result.Error == gorm.ErrRecordNotFound {
-		log.Printf("line item does not exist for id, %s. error is %s", id, result.Error.Error())
-		return lineItem, ce.LineItemNotFoundError
+		logging.From(ctx).Warn("line item not found", "line_item_id", id)
+		return lineItem, ce.LineItemNotFoundError.WithID(id)
 	}
 
 	if result.Error != nil {
-		log.Printf("error occured while fetching line item for id, %s. error is %s", id, result.Error.Error())
-		return lineItem, result.Error
+		logging.From(ctx).Error("error occurred while fetching line item", "line_item_id", id, "error", result.Error)
+		return lineItem, fmt.Errorf("fetching line item for id %s: %w", id, result.Error)
 	}
 
 	return lineItem, nil
 }
 
 func (br *billRepository) UpdateBillAmount(ctx context.Context, billID string, amount float64) error {
-	log.Printf("updating bill amount %f for bill id %s\n", amount, billID)
+	logging.From(ctx).Info("updating bill amount for bill", "amount", amount, "bill_id", billID)
 	bill := &models.Bill{}
-	result := br.db.Model(bill).Where("id = ?", billID).Update("total_amount", amount)
+	result := conn(ctx, br.db).Scopes(tenancy.Scope(ctx)).Model(bill).Where("id = ?", billID).Update("total_amount", amount)
 
 	if result.Error != nil {
-		log.Printf("error occured while updating amount for bill %s\n", billID)
-		return result.Error
+		logging.From(ctx).Error("error occurred while updating amount for bill", "bill_id", billID, "error", result.Error)
+		return fmt.Errorf("updating amount of bill %s: %w", billID, result.Error)
 	}
 
 	return nil
 }
+
+// Transaction runs fn in a database transaction, bill and ledger repositories
+// called with the context given to fn take part in it.
+func (br *billRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return transaction(ctx, br.db, fn)
+}
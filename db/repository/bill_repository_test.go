@@ -2,12 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
@@ -44,7 +47,7 @@ func (suite *BillRepositoryTestSuite) SetupTest() {
 		ID:        utils.GetNewUUID(),
 		FirstName: "John",
 		LastName:  "Jacobs",
-		Email:     "john.jacon@mail.com",
+		Email:     fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
@@ -83,8 +86,8 @@ func (suite *BillRepositoryTestSuite) SetupTest() {
 
 func (suite *BillRepositoryTestSuite) TearDownSuite() {
 	fmt.Printf("cleaning up db records")
-	suite.dbClient.Exec("DELETE FROM bills")
 	suite.dbClient.Exec("DELETE FROM line_items")
+	suite.dbClient.Exec("DELETE FROM bills")
 	suite.dbClient.Exec("DELETE FROM currencies")
 	suite.dbClient.Exec("DELETE FROM customers")
 }
@@ -102,6 +105,34 @@ func (suite *BillRepositoryTestSuite) Test_GetByIDWhenSucceeds() {
 	suite.Nil(err, "error should be nil")
 }
 
+func (suite *BillRepositoryTestSuite) Test_TransactionRollsBackOnError() {
+	ctx := context.Background()
+	bill, err := suite.br.Create(ctx, suite.bill)
+	suite.Nil(err, "error should be nil")
+
+	lineItem := &models.LineItem{
+		ID:          utils.GetNewUUID(),
+		BillID:      bill.ID,
+		Description: "line item 01",
+		Amount:      12.50,
+		CreatedAt:   time.Now(),
+	}
+	testError := errors.New("test-error")
+	err = suite.br.Transaction(ctx, func(ctx context.Context) error {
+		locked, err := suite.br.GetByIDForUpdate(ctx, bill.ID)
+		suite.Nil(err, "error should be nil")
+		suite.Equal(bill.ID, locked.ID)
+
+		_, err = suite.br.AddLineItems(ctx, lineItem)
+		suite.Nil(err, "error should be nil")
+		return testError
+	})
+
+	suite.ErrorIs(err, testError)
+	_, err = suite.br.GetLineItemByID(ctx, lineItem.ID)
+	suite.ErrorIs(err, ce.LineItemNotFoundError)
+}
+
 func (suite *BillRepositoryTestSuite) Test_AddLineItemWhenSucceeds() {
 	ctx := context.Background()
 	bill := &models.Bill{
@@ -250,9 +281,93 @@ func (suite *BillRepositoryTestSuite) Test_CloseDWhenSucceeds() {
 	_, err := suite.br.Create(ctx, bill)
 	suite.Nil(err, "error should be nil")
 
-	closeBill, err := suite.br.Close(ctx, bill.ID)
+	dueDate := time.Now().UTC().AddDate(0, 0, 30)
+	closeBill, err := suite.br.Close(ctx, bill.ID, time.Now().UTC(), dueDate)
 	suite.Nil(err, "error should be nil")
 	suite.Equal("closed", closeBill.Status)
+	suite.Equal(dueDate, *closeBill.DueDate)
+}
+
+func (suite *BillRepositoryTestSuite) Test_ListClosedByCustomerIDSkipsOpenBills() {
+	ctx := context.Background()
+	_, err := suite.br.Create(ctx, suite.bill)
+	suite.Nil(err, "error should be nil")
+
+	closed := *suite.bill
+	closed.ID = utils.GetNewUUID()
+	_, err = suite.br.Create(ctx, &closed)
+	suite.Nil(err, "error should be nil")
+	_, err = suite.br.Close(ctx, closed.ID, time.Now().UTC(), time.Now().UTC())
+	suite.Nil(err, "error should be nil")
+
+	bills, err := suite.br.ListClosedByCustomerID(ctx, suite.customer.ID, time.Now().UTC().Add(time.Minute))
+	suite.Nil(err, "error should be nil")
+	suite.Len(bills, 1)
+	suite.Equal(closed.ID, bills[0].ID)
+}
+
+func (suite *BillRepositoryTestSuite) Test_CountOpenCountsOpenBillsOfAllTenants() {
+	ctx := context.Background()
+	before, err := suite.br.CountOpen(ctx)
+	suite.Nil(err, "error should be nil")
+
+	_, err = suite.br.Create(ctx, suite.bill)
+	suite.Nil(err, "error should be nil")
+
+	other := *suite.bill
+	other.ID = utils.GetNewUUID()
+	_, err = suite.br.Create(tenancy.WithTenant(ctx, "tenant-b"), &other)
+	suite.Nil(err, "error should be nil")
+
+	closed := *suite.bill
+	closed.ID = utils.GetNewUUID()
+	_, err = suite.br.Create(ctx, &closed)
+	suite.Nil(err, "error should be nil")
+	_, err = suite.br.Close(ctx, closed.ID, time.Now().UTC(), time.Now().UTC())
+	suite.Nil(err, "error should be nil")
+
+	count, err := suite.br.CountOpen(ctx)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(before+2, count)
+}
+
+func (suite *BillRepositoryTestSuite) Test_BillOfAnotherTenantIsNotFound() {
+	bill, err := suite.br.Create(context.Background(), suite.bill)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(tenancy.DefaultTenant, bill.TenantID)
+
+	lineItem := &models.LineItem{
+		ID:          utils.GetNewUUID(),
+		BillID:      bill.ID,
+		Description: "line item 01",
+		Amount:      12.50,
+		CreatedAt:   time.Now(),
+	}
+	_, err = suite.br.AddLineItems(context.Background(), lineItem)
+	suite.Nil(err, "error should be nil")
+
+	otherTenant := tenancy.WithTenant(context.Background(), "tenant-b")
+
+	_, err = suite.br.GetByID(otherTenant, bill.ID)
+	suite.ErrorIs(err, ce.BillNotFoundError)
+
+	_, err = suite.br.GetLineItemByID(otherTenant, lineItem.ID)
+	suite.ErrorIs(err, ce.LineItemNotFoundError)
+
+	lineItems, err := suite.br.GetLineItemsByBillID(otherTenant, bill.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Empty(lineItems)
+
+	_, err = suite.br.Close(otherTenant, bill.ID, time.Now().UTC(), time.Now().UTC())
+	suite.ErrorIs(err, ce.BillNotFoundError)
+
+	err = suite.br.UpdateBillAmount(otherTenant, bill.ID, 1)
+	suite.Nil(err, "error should be nil")
+
+	billRecord, err := suite.br.GetByID(context.Background(), bill.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal("open", billRecord.Status)
+	suite.Equal(100.00, billRecord.TotalAmount)
 }
 
 func TestBillRepositoryTestSuite(t *testing.T) {
@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+)
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+type CatalogRepository interface {
+	CreateProduct(context.Context, *models.Product) (*models.Product, error)
+	GetProductByID(context.Context, string) (*models.Product, error)
+	ListProducts(context.Context, bool) ([]*models.Product, error)
+	UpdateProduct(context.Context, *models.Product) (*models.Product, error)
+	CreatePlan(context.Context, *models.Plan) (*models.Plan, error)
+	GetPlanByID(context.Context, string) (*models.Plan, error)
+	ListPlansByProductID(context.Context, string) ([]*models.Plan, error)
+	UpdatePlan(context.Context, *models.Plan) (*models.Plan, error)
+	CreatePrice(context.Context, *models.Price) (*models.Price, error)
+	GetPriceByID(context.Context, string) (*models.Price, error)
+	ListPricesByPlanID(context.Context, string) ([]*models.Price, error)
+	UpdatePrice(context.Context, *models.Price) (*models.Price, error)
+}
+
+func NewCatalogRepository(dbClient *gorm.DB) CatalogRepository {
+	return &catalogRepository{
+		db: dbClient,
+	}
+}
+
+func (cr *catalogRepository) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	product.TenantID = tenancy.From(ctx)
+	result := cr.db.Create(&product)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating product", "product_id", product.ID, "error", result.Error)
+		return product, fmt.Errorf("creating product: %w", result.Error)
+	}
+
+	return product, nil
+}
+
+func (cr *catalogRepository) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	product := &models.Product{}
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&product)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("product not found", "product_id", id)
+		return product, ce.ProductNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying product", "product_id", id, "error", result.Error)
+		return product, fmt.Errorf("querying product %s: %w", id, result.Error)
+	}
+
+	return product, nil
+}
+
+func (cr *catalogRepository) ListProducts(ctx context.Context, includeArchived bool) ([]*models.Product, error) {
+	products := []*models.Product{}
+	query := cr.db.Scopes(tenancy.Scope(ctx)).Order("name")
+	if !includeArchived {
+		query = query.Where("active = ?", true)
+	}
+	result := query.Find(&products)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing products", "error", result.Error)
+		return products, fmt.Errorf("listing products: %w", result.Error)
+	}
+
+	return products, nil
+}
+
+func (cr *catalogRepository) UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	product.UpdatedAt = time.Now().UTC()
+	result := cr.db.Save(product)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating product", "product_id", product.ID, "error", result.Error)
+		return product, fmt.Errorf("updating product %s: %w", product.ID, result.Error)
+	}
+
+	return product, nil
+}
+
+func (cr *catalogRepository) CreatePlan(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	plan.TenantID = tenancy.From(ctx)
+	result := cr.db.Create(&plan)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating plan", "plan_id", plan.ID, "error", result.Error)
+		return plan, fmt.Errorf("creating plan: %w", result.Error)
+	}
+
+	return plan, nil
+}
+
+func (cr *catalogRepository) GetPlanByID(ctx context.Context, id string) (*models.Plan, error) {
+	plan := &models.Plan{}
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&plan)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("plan not found", "plan_id", id)
+		return plan, ce.PlanNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying plan", "plan_id", id, "error", result.Error)
+		return plan, fmt.Errorf("querying plan %s: %w", id, result.Error)
+	}
+
+	return plan, nil
+}
+
+func (cr *catalogRepository) ListPlansByProductID(ctx context.Context, productID string) ([]*models.Plan, error) {
+	plans := []*models.Plan{}
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("product_id = ?", productID).Order("name").Find(&plans)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing plans for product", "product_id", productID, "error", result.Error)
+		return plans, fmt.Errorf("listing plans for product id %s: %w", productID, result.Error)
+	}
+
+	return plans, nil
+}
+
+func (cr *catalogRepository) UpdatePlan(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	plan.UpdatedAt = time.Now().UTC()
+	result := cr.db.Save(plan)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating plan", "plan_id", plan.ID, "error", result.Error)
+		return plan, fmt.Errorf("updating plan %s: %w", plan.ID, result.Error)
+	}
+
+	return plan, nil
+}
+
+func (cr *catalogRepository) CreatePrice(ctx context.Context, price *models.Price) (*models.Price, error) {
+	price.TenantID = tenancy.From(ctx)
+	result := cr.db.Create(&price)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		logging.From(ctx).Warn("plan already has an active price in the currency", "plan_id", price.PlanID, "currency_id", price.CurrencyID)
+		return price, ce.PriceAlreadyExistError.WithID(price.PlanID)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating price", "price_id", price.ID, "error", result.Error)
+		return price, fmt.Errorf("creating price: %w", result.Error)
+	}
+
+	return price, nil
+}
+
+func (cr *catalogRepository) GetPriceByID(ctx context.Context, id string) (*models.Price, error) {
+	price := &models.Price{}
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&price)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("price not found", "price_id", id)
+		return price, ce.PriceNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying price", "price_id", id, "error", result.Error)
+		return price, fmt.Errorf("querying price %s: %w", id, result.Error)
+	}
+
+	return price, nil
+}
+
+func (cr *catalogRepository) ListPricesByPlanID(ctx context.Context, planID string) ([]*models.Price, error) {
+	prices := []*models.Price{}
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("plan_id = ?", planID).Find(&prices)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing prices for plan", "plan_id", planID, "error", result.Error)
+		return prices, fmt.Errorf("listing prices for plan id %s: %w", planID, result.Error)
+	}
+
+	return prices, nil
+}
+
+func (cr *catalogRepository) UpdatePrice(ctx context.Context, price *models.Price) (*models.Price, error) {
+	price.UpdatedAt = time.Now().UTC()
+	result := cr.db.Save(price)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		logging.From(ctx).Warn("plan already has an active price in the currency", "plan_id", price.PlanID, "currency_id", price.CurrencyID)
+		return price, ce.PriceAlreadyExistError.WithID(price.PlanID)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating price", "price_id", price.ID, "error", result.Error)
+		return price, fmt.Errorf("updating price %s: %w", price.ID, result.Error)
+	}
+
+	return price, nil
+}
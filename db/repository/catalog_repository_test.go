@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type CatalogRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	cr       CatalogRepository
+	currency *models.Currency
+	product  *models.Product
+	plan     *models.Plan
+}
+
+func (suite *CatalogRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.cr = NewCatalogRepository(dbClient.DB)
+
+	suite.currency = &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "United states dollar",
+		Symbol:    "$",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	_, err = NewCurrencyRepository(dbClient.DB).Create(context.Background(), suite.currency)
+	suite.Nil(err, "error should be nil")
+
+	suite.product = &models.Product{
+		ID:        utils.GetNewUUID(),
+		Name:      "API",
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	suite.plan = &models.Plan{
+		ID:              utils.GetNewUUID(),
+		ProductID:       suite.product.ID,
+		Name:            "pro",
+		BillingInterval: models.MonthlyInterval,
+		Active:          true,
+		CreatedAt:       time.Now().UTC(),
+		UpdatedAt:       time.Now().UTC(),
+	}
+}
+
+func (suite *CatalogRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM prices")
+	suite.dbClient.Exec("DELETE FROM plans")
+	suite.dbClient.Exec("DELETE FROM products")
+	suite.dbClient.Exec("DELETE FROM currencies")
+}
+
+func (suite *CatalogRepositoryTestSuite) Test_CreateAndGetProductWhenSucceeds() {
+	_, err := suite.cr.CreateProduct(context.Background(), suite.product)
+	suite.Nil(err, "error should be nil")
+
+	product, err := suite.cr.GetProductByID(context.Background(), suite.product.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal("API", product.Name)
+	suite.True(product.Active)
+}
+
+func (suite *CatalogRepositoryTestSuite) Test_ListProductsExcludesArchived() {
+	_, err := suite.cr.CreateProduct(context.Background(), suite.product)
+	suite.Nil(err, "error should be nil")
+
+	suite.product.Active = false
+	_, err = suite.cr.UpdateProduct(context.Background(), suite.product)
+	suite.Nil(err, "error should be nil")
+
+	products, err := suite.cr.ListProducts(context.Background(), false)
+	suite.Nil(err, "error should be nil")
+	for _, product := range products {
+		suite.NotEqual(suite.product.ID, product.ID)
+	}
+}
+
+func (suite *CatalogRepositoryTestSuite) Test_CreatePlanAndPriceWhenSucceeds() {
+	ctx := context.Background()
+	_, err := suite.cr.CreateProduct(ctx, suite.product)
+	suite.Nil(err, "error should be nil")
+
+	_, err = suite.cr.CreatePlan(ctx, suite.plan)
+	suite.Nil(err, "error should be nil")
+
+	price := &models.Price{
+		ID:         utils.GetNewUUID(),
+		PlanID:     suite.plan.ID,
+		CurrencyID: suite.currency.ID,
+		UnitAmount: 9.99,
+		Active:     true,
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	}
+	_, err = suite.cr.CreatePrice(ctx, price)
+	suite.Nil(err, "error should be nil")
+
+	plans, err := suite.cr.ListPlansByProductID(ctx, suite.product.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(plans))
+
+	prices, err := suite.cr.ListPricesByPlanID(ctx, suite.plan.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(prices))
+	suite.Equal(9.99, prices[0].UnitAmount)
+}
+
+func (suite *CatalogRepositoryTestSuite) Test_CreatePriceFailsWhenPlanHasActivePriceInCurrency() {
+	ctx := context.Background()
+	_, err := suite.cr.CreateProduct(ctx, suite.product)
+	suite.Nil(err, "error should be nil")
+
+	_, err = suite.cr.CreatePlan(ctx, suite.plan)
+	suite.Nil(err, "error should be nil")
+
+	for i := 0; i < 2; i++ {
+		price := &models.Price{
+			ID:         utils.GetNewUUID(),
+			PlanID:     suite.plan.ID,
+			CurrencyID: suite.currency.ID,
+			UnitAmount: 9.99,
+			Active:     true,
+			CreatedAt:  time.Now().UTC(),
+			UpdatedAt:  time.Now().UTC(),
+		}
+		_, err = suite.cr.CreatePrice(ctx, price)
+	}
+
+	suite.ErrorIs(err, ce.PriceAlreadyExistError)
+}
+
+func TestCatalogRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(CatalogRepositoryTestSuite))
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"gorm.io/gorm"
 )
 
@@ -17,6 +20,11 @@ type CurrencyRepository interface {
 	Create(context.Context, *models.Currency) (*models.Currency, error)
 	GetByID(context.Context, string) (*models.Currency, error)
 	GetByCode(context.Context, string) (*models.Currency, error)
+	GetISOCurrency(context.Context, string) (*models.ISOCurrency, error)
+	List(context.Context, bool) ([]*models.Currency, error)
+	Update(context.Context, *models.Currency) (*models.Currency, error)
+	IsReferenced(context.Context, *models.Currency) (bool, error)
+	Delete(context.Context, string) error
 }
 
 func NewCurrencyRepository(dbClient *gorm.DB) CurrencyRepository {
@@ -26,11 +34,12 @@ func NewCurrencyRepository(dbClient *gorm.DB) CurrencyRepository {
 }
 
 func (cr *currencyRepository) Create(ctx context.Context, currency *models.Currency) (*models.Currency, error) {
+	currency.TenantID = tenancy.From(ctx)
 	result := cr.db.Create(&currency)
 
 	if result.Error != nil {
-		log.Printf("error occured while creating currency, %v. error is %s", currency, result.Error.Error())
-		return currency, result.Error
+		logging.From(ctx).Error("error occurred while creating currency", "currency_id", currency.ID, "error", result.Error)
+		return currency, fmt.Errorf("creating currency: %w", result.Error)
 	}
 
 	return currency, nil
@@ -38,16 +47,16 @@ func (cr *currencyRepository) Create(ctx context.Context, currency *models.Curre
 
 func (cr *currencyRepository) GetByID(ctx context.Context, id string) (*models.Currency, error) {
 	currency := &models.Currency{}
-	result := cr.db.Where("id = ?", id).First(&currency)
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&currency)
 
 	if result.Error == gorm.ErrRecordNotFound {
-		log.Printf("currency not found for id %s\n", id)
-		return currency, ce.CurrencyNotFoundError
+		logging.From(ctx).Warn("currency not found", "currency_id", id)
+		return currency, ce.CurrencyNotFoundError.WithID(id)
 	}
 
 	if result.Error != nil {
-		log.Printf("error occured while querying currency, %s. error is %s", id, result.Error.Error())
-		return currency, result.Error
+		logging.From(ctx).Error("error occurred while querying currency", "currency_id", id, "error", result.Error)
+		return currency, fmt.Errorf("querying currency %s: %w", id, result.Error)
 	}
 
 	return currency, nil
@@ -55,17 +64,110 @@ func (cr *currencyRepository) GetByID(ctx context.Context, id string) (*models.C
 
 func (cr *currencyRepository) GetByCode(ctx context.Context, code string) (*models.Currency, error) {
 	currency := &models.Currency{}
-	result := cr.db.Where("code = ?", code).Find(&currency)
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("code = ?", code).First(&currency)
 
 	if result.Error == gorm.ErrRecordNotFound {
-		log.Printf("currency not found for code %s\n", code)
-		return currency, ce.CurrencyNotFoundError
+		logging.From(ctx).Warn("currency not found", "code", code)
+		return currency, ce.CurrencyNotFoundError.WithID(code)
 	}
 
 	if result.Error != nil {
-		log.Printf("error occured while querying currency, %s. error is %s", code, result.Error.Error())
-		return currency, result.Error
+		logging.From(ctx).Error("error occurred while querying currency", "code", code, "error", result.Error)
+		return currency, fmt.Errorf("querying currency %s: %w", code, result.Error)
 	}
 
 	return currency, nil
 }
+
+func (cr *currencyRepository) GetISOCurrency(ctx context.Context, code string) (*models.ISOCurrency, error) {
+	isoCurrency := &models.ISOCurrency{}
+	result := cr.db.Where("code = ?", code).First(&isoCurrency)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("iso currency not found", "code", code)
+		return isoCurrency, ce.ISOCurrencyNotFoundError.WithID(code)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying iso currency", "code", code, "error", result.Error)
+		return isoCurrency, fmt.Errorf("querying iso currency %s: %w", code, result.Error)
+	}
+
+	return isoCurrency, nil
+}
+
+func (cr *currencyRepository) List(ctx context.Context, includeInactive bool) ([]*models.Currency, error) {
+	currencies := []*models.Currency{}
+	query := cr.db.Scopes(tenancy.Scope(ctx)).Order("code")
+	if !includeInactive {
+		query = query.Where("active = ?", true)
+	}
+	result := query.Find(&currencies)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing currencies", "error", result.Error)
+		return currencies, fmt.Errorf("listing currencies: %w", result.Error)
+	}
+
+	return currencies, nil
+}
+
+func (cr *currencyRepository) Update(ctx context.Context, currency *models.Currency) (*models.Currency, error) {
+	currency.UpdatedAt = time.Now().UTC()
+	result := cr.db.Save(currency)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating currency", "currency_id", currency.ID, "error", result.Error)
+		return currency, fmt.Errorf("updating currency %s: %w", currency.ID, result.Error)
+	}
+
+	return currency, nil
+}
+
+// IsReferenced reports whether bills, subscriptions or prices are charged in
+// the currency or customers have it as their default currency. Customers
+// reference currencies by code, the others by id.
+func (cr *currencyRepository) IsReferenced(ctx context.Context, currency *models.Currency) (bool, error) {
+	references := []struct {
+		table string
+		query string
+		value string
+	}{
+		{"bills", "currency_id = ?", currency.ID},
+		{"subscriptions", "currency_id = ?", currency.ID},
+		{"prices", "currency_id = ?", currency.ID},
+		{"customers", "default_currency = ?", currency.Code},
+	}
+
+	for _, reference := range references {
+		var count int64
+		result := cr.db.Scopes(tenancy.Scope(ctx)).Table(reference.table).Where(reference.query, reference.value).Count(&count)
+
+		if result.Error != nil {
+			logging.From(ctx).Error("error occurred while counting references of currency", "table", reference.table, "currency_id", currency.ID, "error", result.Error)
+			return false, fmt.Errorf("counting %s of currency %s: %w", reference.table, currency.ID, result.Error)
+		}
+
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (cr *currencyRepository) Delete(ctx context.Context, id string) error {
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).Delete(&models.Currency{})
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while deleting currency", "currency_id", id, "error", result.Error)
+		return fmt.Errorf("deleting currency %s: %w", id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		logging.From(ctx).Warn("currency not found", "currency_id", id)
+		return ce.CurrencyNotFoundError.WithID(id)
+	}
+
+	return nil
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
@@ -37,6 +39,7 @@ func (suite *CurrencyRepositoryTestSuite) SetupTest() {
 
 func (suite *CurrencyRepositoryTestSuite) TearDownSuite() {
 	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM customers")
 	suite.dbClient.Exec("DELETE FROM currencies")
 }
 
@@ -96,6 +99,117 @@ func (suite *CurrencyRepositoryTestSuite) Test_GetCurrencyByCodeWhenSucceeds() {
 	suite.Equal("code03", currencyRecord.Symbol)
 }
 
+func (suite *CurrencyRepositoryTestSuite) Test_GetCurrencyByCodeFailsWhenNotFound() {
+	_, err := suite.cr.GetByCode(context.Background(), "000")
+
+	suite.ErrorIs(err, ce.CurrencyNotFoundError)
+}
+
+func (suite *CurrencyRepositoryTestSuite) Test_GetISOCurrencyReturnsSeededReferenceData() {
+	isoCurrency, err := suite.cr.GetISOCurrency(context.Background(), "JPY")
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal("392", isoCurrency.NumericCode)
+	suite.Equal(0, isoCurrency.MinorUnits)
+
+	_, err = suite.cr.GetISOCurrency(context.Background(), "XYZ")
+	suite.ErrorIs(err, ce.ISOCurrencyNotFoundError)
+}
+
+func (suite *CurrencyRepositoryTestSuite) Test_CurrencyOfAnotherTenantIsNotFound() {
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "code04",
+		Symbol:    "code04",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	currency, err := suite.cr.Create(context.Background(), currency)
+	suite.Nil(err, "error should be nil")
+
+	otherTenant := tenancy.WithTenant(context.Background(), "tenant-b")
+
+	_, err = suite.cr.GetByID(otherTenant, currency.ID)
+	suite.ErrorIs(err, ce.CurrencyNotFoundError)
+
+	_, err = suite.cr.GetByCode(otherTenant, currency.Code)
+	suite.ErrorIs(err, ce.CurrencyNotFoundError)
+
+	currencies, err := suite.cr.List(otherTenant, true)
+	suite.Nil(err, "error should be nil")
+	for _, c := range currencies {
+		suite.NotEqual(currency.ID, c.ID)
+	}
+
+	err = suite.cr.Delete(otherTenant, currency.ID)
+	suite.ErrorIs(err, ce.CurrencyNotFoundError)
+
+	_, err = suite.cr.GetByID(context.Background(), currency.ID)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *CurrencyRepositoryTestSuite) Test_CreateCurrencySucceedsWhenCodeExistsInAnotherTenant() {
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "code05",
+		Symbol:    "code05",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err := suite.cr.Create(context.Background(), currency)
+	suite.Nil(err, "error should be nil")
+
+	other := *currency
+	other.ID = utils.GetNewUUID()
+	_, err = suite.cr.Create(tenancy.WithTenant(context.Background(), "tenant-b"), &other)
+	suite.Nil(err, "error should be nil")
+
+	currencyRecord, err := suite.cr.GetByCode(tenancy.WithTenant(context.Background(), "tenant-b"), currency.Code)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(other.ID, currencyRecord.ID)
+}
+
+func (suite *CurrencyRepositoryTestSuite) Test_IsReferencedByDefaultCurrencyOfCustomers() {
+	ctx := context.Background()
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "code06",
+		Symbol:    "code06",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err := suite.cr.Create(ctx, currency)
+	suite.Nil(err, "error should be nil")
+
+	referenced, err := suite.cr.IsReferenced(ctx, currency)
+	suite.Nil(err, "error should be nil")
+	suite.False(referenced)
+
+	customer := &models.Customer{
+		ID:              utils.GetNewUUID(),
+		FirstName:       "John",
+		LastName:        "Jacobs",
+		Email:           fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
+		DefaultCurrency: currency.Code,
+		CreatedAt:       time.Now().UTC(),
+		UpdatedAt:       time.Now().UTC(),
+	}
+	_, err = NewCustomerRepository(suite.dbClient).Create(ctx, customer)
+	suite.Nil(err, "error should be nil")
+
+	referenced, err = suite.cr.IsReferenced(ctx, currency)
+	suite.Nil(err, "error should be nil")
+	suite.True(referenced)
+
+	referenced, err = suite.cr.IsReferenced(tenancy.WithTenant(ctx, "tenant-b"), currency)
+	suite.Nil(err, "error should be nil")
+	suite.False(referenced)
+}
+
 func TestCurrencyRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(CurrencyRepositoryTestSuite))
 }
@@ -2,10 +2,15 @@ package repository
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"gorm.io/gorm"
 )
 
@@ -16,6 +21,11 @@ type customerRepository struct {
 type CustomerRepository interface {
 	Create(context.Context, *models.Customer) (*models.Customer, error)
 	GetByID(context.Context, string) (*models.Customer, error)
+	GetByEmail(context.Context, string) (*models.Customer, error)
+	List(context.Context, *models.ListCustomersRequest) ([]*models.Customer, int64, error)
+	Update(context.Context, *models.Customer) (*models.Customer, error)
+	HasActiveSubscriptions(context.Context, string) (bool, error)
+	Purge(context.Context, *models.Purge) (*models.Purge, error)
 }
 
 func NewCustomerRepository(dbClient *gorm.DB) CustomerRepository {
@@ -25,11 +35,17 @@ func NewCustomerRepository(dbClient *gorm.DB) CustomerRepository {
 }
 
 func (cr *customerRepository) Create(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	customer.TenantID = tenancy.From(ctx)
 	result := cr.db.Create(&customer)
 
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		logging.From(ctx).Warn("customer already exists for email", "email", customer.Email)
+		return customer, ce.CustomerAlreadyExistError
+	}
+
 	if result.Error != nil {
-		log.Printf("error occured while creating customer, %v. error is %s", customer, result.Error.Error())
-		return customer, result.Error
+		logging.From(ctx).Error("error occurred while creating customer", "customer_id", customer.ID, "error", result.Error)
+		return customer, fmt.Errorf("creating customer: %w", result.Error)
 	}
 
 	return customer, nil
@@ -37,17 +53,200 @@ func (cr *customerRepository) Create(ctx context.Context, customer *models.Custo
 
 func (cr *customerRepository) GetByID(ctx context.Context, id string) (*models.Customer, error) {
 	customer := &models.Customer{}
-	result := cr.db.Where("id = ?", id).First(&customer)
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&customer)
 
 	if result.Error == gorm.ErrRecordNotFound {
-		log.Printf("customer not found for id %s\n", id)
+		logging.From(ctx).Warn("customer not found", "customer_id", id)
+		return customer, ce.CustomerNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying customer", "customer_id", id, "error", result.Error)
+		return customer, fmt.Errorf("querying customer %s: %w", id, result.Error)
+	}
+
+	return customer, nil
+}
+
+// GetByEmail finds the customer with the email, ignoring case. Deleted
+// customers are skipped.
+func (cr *customerRepository) GetByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	customer := &models.Customer{}
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Where("LOWER(email) = LOWER(?) AND deleted_at IS NULL", email).First(&customer)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("customer not found for email", "email", email)
 		return customer, ce.CustomerNotFoundError
 	}
 
 	if result.Error != nil {
-		log.Printf("error occured while querying customer, %s. error is %s", id, result.Error.Error())
-		return customer, result.Error
+		logging.From(ctx).Error("error occurred while querying customer by email", "email", email, "error", result.Error)
+		return customer, fmt.Errorf("querying customer by email %s: %w", email, result.Error)
 	}
 
 	return customer, nil
 }
+
+func (cr *customerRepository) List(ctx context.Context, request *models.ListCustomersRequest) ([]*models.Customer, int64, error) {
+	customers := []*models.Customer{}
+	query := cr.db.Scopes(tenancy.Scope(ctx)).Model(&models.Customer{}).Where("deleted_at IS NULL")
+	if !request.IncludeArchived {
+		query = query.Where("active = ?", true)
+	}
+	if request.Query != "" {
+		prefix := escapeLike(strings.ToLower(request.Query)) + "%"
+		query = query.Where("LOWER(email) LIKE ? OR LOWER(first_name) LIKE ? OR LOWER(last_name) LIKE ?", prefix, prefix, prefix)
+	}
+
+	var total int64
+	result := query.Count(&total)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while counting customers", "error", result.Error)
+		return customers, 0, fmt.Errorf("counting customers: %w", result.Error)
+	}
+
+	result = query.Order("created_at, id").Limit(request.PageSize()).Offset(request.Offset).Find(&customers)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing customers", "error", result.Error)
+		return customers, 0, fmt.Errorf("listing customers: %w", result.Error)
+	}
+
+	return customers, total, nil
+}
+
+func (cr *customerRepository) Update(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	customer.UpdatedAt = time.Now().UTC()
+	result := cr.db.Save(customer)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		logging.From(ctx).Warn("customer already exists for email", "email", customer.Email)
+		return customer, ce.CustomerAlreadyExistError
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating customer", "customer_id", customer.ID, "error", result.Error)
+		return customer, fmt.Errorf("updating customer %s: %w", customer.ID, result.Error)
+	}
+
+	return customer, nil
+}
+
+func (cr *customerRepository) HasActiveSubscriptions(ctx context.Context, id string) (bool, error) {
+	var count int64
+	result := cr.db.Scopes(tenancy.Scope(ctx)).Model(&models.Subscription{}).Where("customer_id = ? AND status = ?", id, "active").Count(&count)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while counting subscriptions of customer", "customer_id", id, "error", result.Error)
+		return false, fmt.Errorf("counting subscriptions of customer %s: %w", id, result.Error)
+	}
+
+	return count > 0, nil
+}
+
+// Purge permanently removes the customer with its open bills, their journal
+// entries, webhook and invoice deliveries and summaries, subscriptions and usage events
+// and records the purge, all in one transaction. Customers with closed bills are never purged.
+func (cr *customerRepository) Purge(ctx context.Context, purge *models.Purge) (*models.Purge, error) {
+	scope := tenancy.Scope(ctx)
+	err := cr.db.Transaction(func(tx *gorm.DB) error {
+		var closedBills int64
+		result := tx.Scopes(scope).Model(&models.Bill{}).Where("customer_id = ? AND status <> ?", purge.EntityID, "open").Count(&closedBills)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if closedBills > 0 {
+			logging.From(ctx).Warn("customer has closed bills", "entity_id", purge.EntityID, "closed_bills", closedBills)
+			return ce.CustomerHasClosedBillsError.WithID(purge.EntityID)
+		}
+
+		bills := tx.Scopes(scope).Model(&models.Bill{}).Select("id").Where("customer_id = ?", purge.EntityID)
+		subscriptions := tx.Scopes(scope).Model(&models.Subscription{}).Select("id").Where("customer_id = ?", purge.EntityID)
+
+		entries := tx.Scopes(scope).Model(&models.JournalEntry{}).Select("id").Where("customer_id = ?", purge.EntityID)
+		postings := tx.Scopes(scope).Where("journal_entry_id IN (?)", entries).Delete(&models.Posting{})
+		if postings.Error != nil {
+			return postings.Error
+		}
+
+		journalEntries := tx.Scopes(scope).Where("customer_id = ?", purge.EntityID).Delete(&models.JournalEntry{})
+		if journalEntries.Error != nil {
+			return journalEntries.Error
+		}
+
+		accounts := tx.Scopes(scope).Where("customer_id = ?", purge.EntityID).Delete(&models.LedgerAccount{})
+		if accounts.Error != nil {
+			return accounts.Error
+		}
+
+		deliveries := tx.Scopes(scope).Where("bill_id IN (?)", bills).Delete(&models.WebhookDelivery{})
+		if deliveries.Error != nil {
+			return deliveries.Error
+		}
+
+		invoiceDeliveries := tx.Scopes(scope).Where("bill_id IN (?)", bills).Delete(&models.InvoiceDelivery{})
+		if invoiceDeliveries.Error != nil {
+			return invoiceDeliveries.Error
+		}
+
+		summaries := tx.Scopes(scope).Where("customer_id = ?", purge.EntityID).Delete(&models.BillSummary{})
+		if summaries.Error != nil {
+			return summaries.Error
+		}
+
+		lineItems := tx.Scopes(scope).Where("bill_id IN (?)", bills).Delete(&models.LineItem{})
+		if lineItems.Error != nil {
+			return lineItems.Error
+		}
+
+		items := tx.Scopes(scope).Where("subscription_id IN (?)", subscriptions).Delete(&models.SubscriptionItem{})
+		if items.Error != nil {
+			return items.Error
+		}
+
+		subscriptionRows := tx.Scopes(scope).Where("customer_id = ?", purge.EntityID).Delete(&models.Subscription{})
+		if subscriptionRows.Error != nil {
+			return subscriptionRows.Error
+		}
+
+		events := tx.Scopes(scope).Where("customer_id = ?", purge.EntityID).Delete(&models.UsageEvent{})
+		if events.Error != nil {
+			return events.Error
+		}
+
+		billRows := tx.Scopes(scope).Where("customer_id = ?", purge.EntityID).Delete(&models.Bill{})
+		if billRows.Error != nil {
+			return billRows.Error
+		}
+
+		customer := tx.Scopes(scope).Where("id = ?", purge.EntityID).Delete(&models.Customer{})
+		if customer.Error != nil {
+			return customer.Error
+		}
+
+		if customer.RowsAffected == 0 {
+			return ce.CustomerNotFoundError.WithID(purge.EntityID)
+		}
+
+		purge.Details = fmt.Sprintf("removed %d open bills, %d line items, %d journal entries, %d webhook deliveries, %d invoice deliveries, %d subscriptions, %d subscription items and %d usage events",
+			billRows.RowsAffected, lineItems.RowsAffected, journalEntries.RowsAffected, deliveries.RowsAffected, invoiceDeliveries.RowsAffected,
+			subscriptionRows.RowsAffected, items.RowsAffected, events.RowsAffected)
+
+		purge.TenantID = tenancy.From(ctx)
+		return tx.Create(purge).Error
+	})
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while purging customer", "entity_id", purge.EntityID, "error", err)
+		return purge, fmt.Errorf("purging customer %s: %w", purge.EntityID, err)
+	}
+
+	return purge, nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
 	"github.com/asheet-bhaskar/billing-service/pkg/utils"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
@@ -39,7 +42,7 @@ func (suite *CustomerRepositoryTestSuite) SetupTest() {
 		ID:        utils.GetNewUUID(),
 		FirstName: "John",
 		LastName:  "Jacobs",
-		Email:     "john.jacon@mail.com",
+		Email:     fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 	}
@@ -47,6 +50,11 @@ func (suite *CustomerRepositoryTestSuite) SetupTest() {
 
 func (suite *CustomerRepositoryTestSuite) TearDownSuite() {
 	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM purges")
+	suite.dbClient.Exec("DELETE FROM invoice_deliveries")
+	suite.dbClient.Exec("DELETE FROM line_items")
+	suite.dbClient.Exec("DELETE FROM bills")
+	suite.dbClient.Exec("DELETE FROM currencies")
 	suite.dbClient.Exec("DELETE FROM customers")
 }
 
@@ -67,6 +75,174 @@ func (suite *CustomerRepositoryTestSuite) Test_GetCustomerByIDWhenSucceeds() {
 	suite.Equal(suite.customer.Email, customerRecord.Email)
 }
 
+func (suite *CustomerRepositoryTestSuite) Test_CreateCustomerFailsWhenEmailExists() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+
+	duplicate := *suite.customer
+	duplicate.ID = utils.GetNewUUID()
+	duplicate.Email = strings.ToUpper(suite.customer.Email)
+	_, err = suite.cr.Create(context.Background(), &duplicate)
+
+	suite.ErrorIs(err, ce.CustomerAlreadyExistError)
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_GetCustomerByEmailIgnoresCase() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+
+	customerRecord, err := suite.cr.GetByEmail(context.Background(), strings.ToUpper(suite.customer.Email))
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(suite.customer.ID, customerRecord.ID)
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_ListCustomersMatchesPrefixAndSkipsArchived() {
+	suite.customer.FirstName = "Zelda" + utils.RandomString(6)
+	suite.customer.Active = true
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+
+	archived := *suite.customer
+	archived.ID = utils.GetNewUUID()
+	archived.Email = fmt.Sprintf("zelda.%s@mail.com", utils.RandomString(8))
+	archived.Active = false
+	_, err = suite.cr.Create(context.Background(), &archived)
+	suite.Nil(err, "error should be nil")
+
+	request := &models.ListCustomersRequest{Query: strings.ToLower(suite.customer.FirstName[:8])}
+	customers, total, err := suite.cr.List(context.Background(), request)
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(1), total)
+	suite.Equal(suite.customer.ID, customers[0].ID)
+
+	request.IncludeArchived = true
+	request.Limit = 1
+	customers, total, err = suite.cr.List(context.Background(), request)
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(2), total)
+	suite.Len(customers, 1)
+}
+
+func (suite *CustomerRepositoryTestSuite) createBill(status string) *models.Bill {
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "United states dollar",
+		Symbol:    "$",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err := NewCurrencyRepository(suite.dbClient).Create(context.Background(), currency)
+	suite.Nil(err, "error should be nil")
+
+	bill := &models.Bill{
+		ID:          utils.GetNewUUID(),
+		Description: "Bill 01",
+		CustomerID:  suite.customer.ID,
+		CurrencyID:  currency.ID,
+		Status:      status,
+		PeriodStart: time.Now().UTC(),
+		PeriodEnd:   time.Now().UTC().Add(time.Hour * 100),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	_, err = NewBillRepository(suite.dbClient).Create(context.Background(), bill)
+	suite.Nil(err, "error should be nil")
+
+	return bill
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_PurgeRemovesOpenBillsAndRecordsPurge() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+	bill := suite.createBill("open")
+	_, err = NewNotificationRepository(suite.dbClient).CreateInvoiceDelivery(context.Background(), &models.InvoiceDelivery{
+		ID: utils.GetNewUUID(), BillID: bill.ID, Recipient: suite.customer.Email, Locale: "en", Subject: "Invoice", Status: models.InvoiceEmailSent,
+	})
+	suite.Nil(err, "error should be nil")
+
+	purge, err := suite.cr.Purge(context.Background(), &models.Purge{
+		ID:          utils.GetNewUUID(),
+		EntityType:  "customer",
+		EntityID:    suite.customer.ID,
+		Reason:      "gdpr request",
+		RequestedBy: "ops@mail.com",
+		CreatedAt:   time.Now().UTC(),
+	})
+
+	suite.Nil(err, "error should be nil")
+	suite.Contains(purge.Details, "removed 1 open bills")
+	suite.Contains(purge.Details, "1 invoice deliveries")
+
+	_, err = suite.cr.GetByID(context.Background(), suite.customer.ID)
+	suite.ErrorIs(err, ce.CustomerNotFoundError)
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_PurgeFailsWhenCustomerHasClosedBills() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+	suite.createBill("closed")
+
+	_, err = suite.cr.Purge(context.Background(), &models.Purge{
+		ID:          utils.GetNewUUID(),
+		EntityType:  "customer",
+		EntityID:    suite.customer.ID,
+		Reason:      "gdpr request",
+		RequestedBy: "ops@mail.com",
+		CreatedAt:   time.Now().UTC(),
+	})
+
+	suite.ErrorIs(err, ce.CustomerHasClosedBillsError)
+
+	_, err = suite.cr.GetByID(context.Background(), suite.customer.ID)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_DeletingCustomerWithBillsIsRestricted() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+	suite.createBill("closed")
+
+	result := suite.dbClient.Exec("DELETE FROM customers WHERE id = ?", suite.customer.ID)
+
+	suite.NotNil(result.Error, "error should not be nil")
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_CustomerOfAnotherTenantIsNotFound() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+
+	otherTenant := tenancy.WithTenant(context.Background(), "tenant-b")
+
+	_, err = suite.cr.GetByID(otherTenant, suite.customer.ID)
+	suite.ErrorIs(err, ce.CustomerNotFoundError)
+
+	_, err = suite.cr.GetByEmail(otherTenant, suite.customer.Email)
+	suite.ErrorIs(err, ce.CustomerNotFoundError)
+
+	customers, _, err := suite.cr.List(otherTenant, &models.ListCustomersRequest{Query: suite.customer.Email, IncludeArchived: true})
+	suite.Nil(err, "error should be nil")
+	suite.Empty(customers)
+
+	_, err = suite.cr.Purge(otherTenant, &models.Purge{ID: utils.GetNewUUID(), EntityType: models.CustomerEntity, EntityID: suite.customer.ID, Reason: "test", RequestedBy: "test"})
+	suite.ErrorIs(err, ce.CustomerNotFoundError)
+}
+
+func (suite *CustomerRepositoryTestSuite) Test_CreateCustomerSucceedsWhenEmailExistsInAnotherTenant() {
+	_, err := suite.cr.Create(context.Background(), suite.customer)
+	suite.Nil(err, "error should be nil")
+
+	other := *suite.customer
+	other.ID = utils.GetNewUUID()
+	customer, err := suite.cr.Create(tenancy.WithTenant(context.Background(), "tenant-b"), &other)
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal("tenant-b", customer.TenantID)
+}
+
 func TestCustomerRepositoryTestSuite(t *testing.T) {
 	suite.Run(t, new(CustomerRepositoryTestSuite))
 }
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type exchangeRateRepository struct {
+	db *gorm.DB
+}
+
+type ExchangeRateRepository interface {
+	Upsert(context.Context, *models.ExchangeRate) (*models.ExchangeRate, error)
+	GetEffective(context.Context, string, string, time.Time) (*models.ExchangeRate, error)
+}
+
+func NewExchangeRateRepository(dbClient *gorm.DB) ExchangeRateRepository {
+	return &exchangeRateRepository{
+		db: dbClient,
+	}
+}
+
+// Upsert stores the rate, replacing the rate of the same currency pair and
+// effective date if there is one, and returns the stored rate. A replaced rate
+// keeps its id and creation time.
+func (er *exchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	result := er.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate"}),
+	}).Create(&rate)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while storing exchange rate", "rate_id", rate.ID, "error", result.Error)
+		return rate, fmt.Errorf("storing exchange rate: %w", result.Error)
+	}
+
+	stored := &models.ExchangeRate{}
+	result = er.db.Where("base_currency = ? AND quote_currency = ? AND effective_date = ?", rate.BaseCurrency, rate.QuoteCurrency, rate.EffectiveDate).First(&stored)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying stored exchange rate", "base", rate.BaseCurrency, "quote", rate.QuoteCurrency, "error", result.Error)
+		return rate, fmt.Errorf("querying exchange rate %s/%s: %w", rate.BaseCurrency, rate.QuoteCurrency, result.Error)
+	}
+
+	return stored, nil
+}
+
+// GetEffective returns the latest rate of the currency pair effective at the
+// given time.
+func (er *exchangeRateRepository) GetEffective(ctx context.Context, base string, quote string, at time.Time) (*models.ExchangeRate, error) {
+	rate := &models.ExchangeRate{}
+	result := er.db.Where("base_currency = ? AND quote_currency = ? AND effective_date <= ?", base, quote, at).
+		Order("effective_date DESC").First(&rate)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("exchange rate not found", "base", base, "quote", quote, "at", at)
+		return rate, ce.ExchangeRateNotFoundError.WithID(fmt.Sprintf("%s/%s", base, quote))
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying exchange rate", "base", base, "quote", quote, "error", result.Error)
+		return rate, fmt.Errorf("querying exchange rate %s/%s: %w", base, quote, result.Error)
+	}
+
+	return rate, nil
+}
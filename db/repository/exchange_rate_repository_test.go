@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ExchangeRateRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	er       ExchangeRateRepository
+	base     string
+	quote    string
+}
+
+func (suite *ExchangeRateRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.er = NewExchangeRateRepository(dbClient.DB)
+	suite.base = utils.RandomString(3)
+	suite.quote = utils.RandomString(3)
+}
+
+func (suite *ExchangeRateRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM exchange_rates")
+}
+
+func (suite *ExchangeRateRepositoryTestSuite) rate(value float64, effectiveDate time.Time) *models.ExchangeRate {
+	return &models.ExchangeRate{
+		ID:            utils.GetNewUUID(),
+		BaseCurrency:  suite.base,
+		QuoteCurrency: suite.quote,
+		Rate:          value,
+		EffectiveDate: effectiveDate,
+		CreatedAt:     time.Now().UTC(),
+	}
+}
+
+func (suite *ExchangeRateRepositoryTestSuite) Test_GetEffectiveReturnsLatestRateBeforeDate() {
+	ctx := context.Background()
+	january := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := suite.er.Upsert(ctx, suite.rate(0.9, january))
+	suite.Nil(err, "error should be nil")
+	_, err = suite.er.Upsert(ctx, suite.rate(0.95, february))
+	suite.Nil(err, "error should be nil")
+
+	rate, err := suite.er.GetEffective(ctx, suite.base, suite.quote, january.AddDate(0, 0, 15))
+	suite.Nil(err, "error should be nil")
+	suite.Equal(0.9, rate.Rate)
+
+	rate, err = suite.er.GetEffective(ctx, suite.base, suite.quote, february.AddDate(0, 0, 15))
+	suite.Nil(err, "error should be nil")
+	suite.Equal(0.95, rate.Rate)
+}
+
+func (suite *ExchangeRateRepositoryTestSuite) Test_UpsertReplacesRateOfSameDate() {
+	ctx := context.Background()
+	march := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+
+	first, err := suite.er.Upsert(ctx, suite.rate(0.9, march))
+	suite.Nil(err, "error should be nil")
+	replaced, err := suite.er.Upsert(ctx, suite.rate(0.8, march))
+	suite.Nil(err, "error should be nil")
+	suite.Equal(first.ID, replaced.ID)
+	suite.Equal(0.8, replaced.Rate)
+
+	rate, err := suite.er.GetEffective(ctx, suite.base, suite.quote, march)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(0.8, rate.Rate)
+}
+
+func (suite *ExchangeRateRepositoryTestSuite) Test_GetEffectiveFailsBeforeFirstRate() {
+	_, err := suite.er.GetEffective(context.Background(), suite.base, suite.quote, time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC))
+	suite.ErrorIs(err, ce.ExchangeRateNotFoundError)
+}
+
+func TestExchangeRateRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ExchangeRateRepositoryTestSuite))
+}
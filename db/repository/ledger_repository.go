@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ledgerRepository struct {
+	db *gorm.DB
+}
+
+type LedgerRepository interface {
+	GetOrCreateAccount(context.Context, *models.LedgerAccount) (*models.LedgerAccount, error)
+	Post(context.Context, *models.JournalEntry) (*models.JournalEntry, error)
+	BillBalance(context.Context, string, string) (float64, error)
+	ListByBillID(context.Context, string) ([]*models.JournalEntry, error)
+	ListByCustomerID(context.Context, string, []string, time.Time) ([]*models.JournalEntry, error)
+	ListImbalances(context.Context) ([]*models.LedgerImbalance, error)
+}
+
+func NewLedgerRepository(dbClient *gorm.DB) LedgerRepository {
+	return &ledgerRepository{
+		db: dbClient,
+	}
+}
+
+// GetOrCreateAccount returns the account with the code and customer of the
+// given account, creating it on first use.
+func (lr *ledgerRepository) GetOrCreateAccount(ctx context.Context, account *models.LedgerAccount) (*models.LedgerAccount, error) {
+	account.TenantID = tenancy.From(ctx)
+	result := conn(ctx, lr.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "code"}, {Name: "customer_id"}},
+		DoNothing: true,
+	}).Create(&account)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating ledger account", "account_id", account.ID, "error", result.Error)
+		return account, fmt.Errorf("creating ledger account: %w", result.Error)
+	}
+
+	existing := &models.LedgerAccount{}
+	result = conn(ctx, lr.db).Scopes(tenancy.Scope(ctx)).Where("code = ? AND customer_id = ?", account.Code, account.CustomerID).First(&existing)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying ledger account", "account_id", account.ID, "error", result.Error)
+		return existing, fmt.Errorf("querying ledger account: %w", result.Error)
+	}
+
+	return existing, nil
+}
+
+// Post records the entry with its postings in one transaction, a savepoint
+// within the transaction of the context. An entry of the same kind and
+// reference posted before is returned instead when it records the same change,
+// and refused when it is for another bill or amount.
+func (lr *ledgerRepository) Post(ctx context.Context, entry *models.JournalEntry) (*models.JournalEntry, error) {
+	entry.TenantID = tenancy.From(ctx)
+	for _, posting := range entry.Postings {
+		posting.TenantID = entry.TenantID
+	}
+	// a failed insert aborts the transaction of the context, the savepoint
+	// keeps it usable to read the entry posted before
+	err := conn(ctx, lr.db).Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&entry).Error
+	})
+
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		logging.From(ctx).Warn("journal entry already posted", "kind", entry.Kind, "reference_id", entry.ReferenceID)
+		existing := &models.JournalEntry{}
+		result := conn(ctx, lr.db).Scopes(tenancy.Scope(ctx)).Preload("Postings").Where("kind = ? AND reference_id = ?", entry.Kind, entry.ReferenceID).First(&existing)
+		if result.Error != nil {
+			logging.From(ctx).Error("error occurred while querying journal entry", "kind", entry.Kind, "reference_id", entry.ReferenceID, "error", result.Error)
+			return entry, fmt.Errorf("querying journal entry %s %s: %w", entry.Kind, entry.ReferenceID, result.Error)
+		}
+		if !existing.SameChange(entry) {
+			logging.From(ctx).Warn("journal entry reference already used for another change", "kind", entry.Kind, "reference_id", entry.ReferenceID,
+				"bill_id", entry.BillID, "posted_bill_id", existing.BillID)
+			return entry, ce.JournalEntryAlreadyExistError.WithID(entry.ReferenceID)
+		}
+		return existing, nil
+	}
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while posting journal entry", "entry_id", entry.ID, "error", err)
+		return entry, fmt.Errorf("posting journal entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// BillBalance returns the debits less the credits posted for the bill to the
+// account with the code.
+func (lr *ledgerRepository) BillBalance(ctx context.Context, billID string, code string) (float64, error) {
+	var balance float64
+	result := conn(ctx, lr.db).Scopes(tenancy.Scope(ctx)).Table("postings").
+		Select("COALESCE(SUM(postings.debit - postings.credit), 0)").
+		Joins("JOIN journal_entries ON journal_entries.id = postings.journal_entry_id").
+		Joins("JOIN ledger_accounts ON ledger_accounts.id = postings.account_id").
+		Where("journal_entries.bill_id = ? AND ledger_accounts.code = ?", billID, code).
+		Scan(&balance)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying balance of bill", "code", code, "bill_id", billID, "error", result.Error)
+		return balance, fmt.Errorf("querying %s balance of bill %s: %w", code, billID, result.Error)
+	}
+
+	return balance, nil
+}
+
+// ListByBillID returns the journal entries of the bill, oldest first.
+func (lr *ledgerRepository) ListByBillID(ctx context.Context, billID string) ([]*models.JournalEntry, error) {
+	entries := []*models.JournalEntry{}
+	result := conn(ctx, lr.db).Scopes(tenancy.Scope(ctx)).Preload("Postings").Where("bill_id = ?", billID).Order("created_at, id").Find(&entries)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing journal entries of bill", "bill_id", billID, "error", result.Error)
+		return entries, fmt.Errorf("listing journal entries of bill %s: %w", billID, result.Error)
+	}
+
+	return entries, nil
+}
+
+// ListByCustomerID returns the journal entries of the given kinds posted for
+// the customer before until, oldest first.
+func (lr *ledgerRepository) ListByCustomerID(ctx context.Context, customerID string, kinds []string, until time.Time) ([]*models.JournalEntry, error) {
+	entries := []*models.JournalEntry{}
+	result := conn(ctx, lr.db).Scopes(tenancy.Scope(ctx)).Preload("Postings").Where("customer_id = ? AND kind IN ? AND created_at < ?", customerID, kinds, until).
+		Order("created_at, id").Find(&entries)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing journal entries of customer", "customer_id", customerID, "error", result.Error)
+		return entries, fmt.Errorf("listing journal entries of customer %s: %w", customerID, result.Error)
+	}
+
+	return entries, nil
+}
+
+// ListImbalances returns every journal entry whose debits and credits differ
+// in a currency. A healthy ledger has none.
+func (lr *ledgerRepository) ListImbalances(ctx context.Context) ([]*models.LedgerImbalance, error) {
+	imbalances := []*models.LedgerImbalance{}
+	result := conn(ctx, lr.db).Scopes(tenancy.Scope(ctx)).Table("postings").
+		Select("journal_entry_id, currency_code, SUM(debit) AS debit, SUM(credit) AS credit").
+		Group("journal_entry_id, currency_code").
+		Having("SUM(debit) <> SUM(credit)").
+		Order("journal_entry_id, currency_code").
+		Scan(&imbalances)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while checking the ledger balances", "error", result.Error)
+		return imbalances, fmt.Errorf("checking the ledger balances: %w", result.Error)
+	}
+
+	return imbalances, nil
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type LedgerRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	lr       LedgerRepository
+	bill     *models.Bill
+	unbilled *models.LedgerAccount
+	revenue  *models.LedgerAccount
+}
+
+func (suite *LedgerRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.lr = NewLedgerRepository(dbClient.DB)
+	ctx := context.Background()
+
+	customer := &models.Customer{
+		ID:        utils.GetNewUUID(),
+		FirstName: "John",
+		LastName:  "Jacobs",
+		Email:     fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err = NewCustomerRepository(dbClient.DB).Create(ctx, customer)
+	suite.Nil(err, "error should be nil")
+
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "United states dollar",
+		Symbol:    "$",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err = NewCurrencyRepository(dbClient.DB).Create(ctx, currency)
+	suite.Nil(err, "error should be nil")
+
+	suite.bill = &models.Bill{
+		ID:          utils.GetNewUUID(),
+		Description: "Bill 01",
+		CustomerID:  customer.ID,
+		CurrencyID:  currency.ID,
+		Status:      "open",
+		PeriodStart: time.Now().UTC(),
+		PeriodEnd:   time.Now().UTC().Add(time.Hour * 100),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	_, err = NewBillRepository(dbClient.DB).Create(ctx, suite.bill)
+	suite.Nil(err, "error should be nil")
+
+	suite.unbilled, err = suite.lr.GetOrCreateAccount(ctx, &models.LedgerAccount{
+		ID: utils.GetNewUUID(), Code: models.UnbilledAccount, Type: models.AssetAccountType, CustomerID: customer.ID,
+	})
+	suite.Nil(err, "error should be nil")
+
+	suite.revenue, err = suite.lr.GetOrCreateAccount(ctx, &models.LedgerAccount{
+		ID: utils.GetNewUUID(), Code: models.RevenueAccount, Type: models.IncomeAccountType,
+	})
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *LedgerRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM postings")
+	suite.dbClient.Exec("DELETE FROM journal_entries")
+	suite.dbClient.Exec("DELETE FROM ledger_accounts")
+	suite.dbClient.Exec("DELETE FROM bills")
+	suite.dbClient.Exec("DELETE FROM currencies")
+	suite.dbClient.Exec("DELETE FROM customers")
+}
+
+func (suite *LedgerRepositoryTestSuite) entry(kind string, amount float64) *models.JournalEntry {
+	transfer := &models.LedgerTransfer{
+		Kind:         kind,
+		ReferenceID:  utils.GetNewUUID(),
+		BillID:       suite.bill.ID,
+		CustomerID:   suite.bill.CustomerID,
+		Description:  "line item 01",
+		CurrencyCode: "USD",
+		Amount:       amount,
+	}
+	entry := transfer.JournalEntry(suite.unbilled, suite.revenue)
+	entry.ID = utils.GetNewUUID()
+	for _, posting := range entry.Postings {
+		posting.ID = utils.GetNewUUID()
+	}
+	return entry
+}
+
+func (suite *LedgerRepositoryTestSuite) Test_GetOrCreateAccountReturnsExistingAccount() {
+	account, err := suite.lr.GetOrCreateAccount(context.Background(), &models.LedgerAccount{
+		ID: utils.GetNewUUID(), Code: models.UnbilledAccount, Type: models.AssetAccountType, CustomerID: suite.bill.CustomerID,
+	})
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(suite.unbilled.ID, account.ID)
+}
+
+func (suite *LedgerRepositoryTestSuite) Test_PostIsIdempotentAndUpdatesBillBalance() {
+	ctx := context.Background()
+	entry := suite.entry(models.JournalLineItemAdded, 12.5)
+
+	_, err := suite.lr.Post(ctx, entry)
+	suite.Nil(err, "error should be nil")
+
+	again := suite.entry(models.JournalLineItemAdded, 12.5)
+	again.ReferenceID = entry.ReferenceID
+	posted, err := suite.lr.Post(ctx, again)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(entry.ID, posted.ID)
+	suite.Len(posted.Postings, 2)
+
+	balance, err := suite.lr.BillBalance(ctx, suite.bill.ID, models.UnbilledAccount)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(12.5, balance)
+
+	entries, err := suite.lr.ListByBillID(ctx, suite.bill.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Len(entries, 1)
+}
+
+func (suite *LedgerRepositoryTestSuite) Test_PostRefusesReferenceOfAnotherChange() {
+	ctx := context.Background()
+	entry := suite.entry(models.JournalLineItemAdded, 12.5)
+	_, err := suite.lr.Post(ctx, entry)
+	suite.Nil(err, "error should be nil")
+
+	again := suite.entry(models.JournalLineItemAdded, 20)
+	again.ReferenceID = entry.ReferenceID
+	_, err = suite.lr.Post(ctx, again)
+	suite.ErrorIs(err, ce.JournalEntryAlreadyExistError)
+
+	balance, err := suite.lr.BillBalance(ctx, suite.bill.ID, models.UnbilledAccount)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(12.5, balance)
+}
+
+func (suite *LedgerRepositoryTestSuite) Test_PostAgainInTransactionKeepsItUsable() {
+	ctx := context.Background()
+	entry := suite.entry(models.JournalLineItemAdded, 7.5)
+	_, err := suite.lr.Post(ctx, entry)
+	suite.Nil(err, "error should be nil")
+
+	err = NewBillRepository(suite.dbClient).Transaction(ctx, func(ctx context.Context) error {
+		again := suite.entry(models.JournalLineItemAdded, 7.5)
+		again.ReferenceID = entry.ReferenceID
+		posted, err := suite.lr.Post(ctx, again)
+		suite.Nil(err, "error should be nil")
+		suite.Equal(entry.ID, posted.ID)
+
+		_, err = suite.lr.BillBalance(ctx, suite.bill.ID, models.UnbilledAccount)
+		return err
+	})
+
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *LedgerRepositoryTestSuite) Test_ListImbalancesFindsUnbalancedEntries() {
+	ctx := context.Background()
+	_, err := suite.lr.Post(ctx, suite.entry(models.JournalLineItemAdded, 5))
+	suite.Nil(err, "error should be nil")
+
+	imbalances, err := suite.lr.ListImbalances(ctx)
+	suite.Nil(err, "error should be nil")
+	suite.Empty(imbalances)
+
+	unbalanced := suite.entry(models.JournalLineItemAdded, 5)
+	unbalanced.Postings = unbalanced.Postings[:1]
+	_, err = suite.lr.Post(ctx, unbalanced)
+	suite.Nil(err, "error should be nil")
+
+	imbalances, err = suite.lr.ListImbalances(ctx)
+	suite.Nil(err, "error should be nil")
+	suite.Len(imbalances, 1)
+	suite.Equal(unbalanced.ID, imbalances[0].JournalEntryID)
+}
+
+func TestLedgerRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(LedgerRepositoryTestSuite))
+}
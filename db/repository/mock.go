@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/models"
 	"github.com/stretchr/testify/mock"
@@ -29,6 +30,17 @@ func (m *MockBillRepository) GetByID(ctx context.Context, id string) (*models.Bi
 	return args.Get(0).(*models.Bill), args.Error(1)
 }
 
+func (m *MockBillRepository) GetByIDForUpdate(ctx context.Context, id string) (*models.Bill, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Bill), args.Error(1)
+}
+
+// Transaction runs fn with the context as it is, there is no database to
+// roll back.
+func (m *MockBillRepository) Transaction(ctx context.Context, fn func(context.Context) error) error {
+	return fn(ctx)
+}
+
 func (m *MockBillRepository) AddLineItems(ctx context.Context, lineItem *models.LineItem) (*models.LineItem, error) {
 	args := m.Called(ctx, lineItem)
 	return args.Get(0).(*models.LineItem), args.Error(1)
@@ -47,15 +59,24 @@ func (m *MockBillRepository) GetLineItemsByBillID(ctx context.Context, id string
 	args := m.Called(ctx, id)
 	return args.Get(0).([]*models.LineItem), args.Error(1)
 }
-func (m *MockBillRepository) Close(ctx context.Context, id string) (*models.Bill, error) {
-	args := m.Called(ctx, id)
+func (m *MockBillRepository) Close(ctx context.Context, id string, closedAt, dueDate time.Time) (*models.Bill, error) {
+	args := m.Called(ctx, id, closedAt, dueDate)
 	return args.Get(0).(*models.Bill), args.Error(1)
+}
 
+func (m *MockBillRepository) ListClosedByCustomerID(ctx context.Context, customerID string, until time.Time) ([]*models.Bill, error) {
+	args := m.Called(ctx, customerID, until)
+	return args.Get(0).([]*models.Bill), args.Error(1)
 }
 
 func (m *MockBillRepository) UpdateBillAmount(ctx context.Context, billID string, amount float64) error {
 	args := m.Called(ctx, billID, amount)
-	return args.Error(1)
+	return args.Error(0)
+}
+
+func (m *MockBillRepository) CountOpen(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
 }
 
 func (m *MockCurrencyRepository) Create(ctx context.Context, currency *models.Currency) (*models.Currency, error) {
@@ -73,6 +94,31 @@ func (m *MockCurrencyRepository) GetByCode(ctx context.Context, code string) (*m
 	return args.Get(0).(*models.Currency), args.Error(1)
 }
 
+func (m *MockCurrencyRepository) GetISOCurrency(ctx context.Context, code string) (*models.ISOCurrency, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(*models.ISOCurrency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) List(ctx context.Context, includeInactive bool) ([]*models.Currency, error) {
+	args := m.Called(ctx, includeInactive)
+	return args.Get(0).([]*models.Currency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) Update(ctx context.Context, currency *models.Currency) (*models.Currency, error) {
+	args := m.Called(ctx, currency)
+	return args.Get(0).(*models.Currency), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) IsReferenced(ctx context.Context, currency *models.Currency) (bool, error) {
+	args := m.Called(ctx, currency)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCurrencyRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func (m *MockCustomerRepository) Create(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
 	args := m.Called(ctx, customer)
 	return args.Get(0).(*models.Customer), args.Error(1)
@@ -82,3 +128,348 @@ func (m *MockCustomerRepository) GetByID(ctx context.Context, id string) (*model
 	args := m.Called(ctx, id)
 	return args.Get(0).(*models.Customer), args.Error(1)
 }
+
+func (m *MockCustomerRepository) GetByEmail(ctx context.Context, email string) (*models.Customer, error) {
+	args := m.Called(ctx, email)
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerRepository) List(ctx context.Context, request *models.ListCustomersRequest) ([]*models.Customer, int64, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).([]*models.Customer), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCustomerRepository) Update(ctx context.Context, customer *models.Customer) (*models.Customer, error) {
+	args := m.Called(ctx, customer)
+	return args.Get(0).(*models.Customer), args.Error(1)
+}
+
+func (m *MockCustomerRepository) HasActiveSubscriptions(ctx context.Context, id string) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCustomerRepository) Purge(ctx context.Context, purge *models.Purge) (*models.Purge, error) {
+	args := m.Called(ctx, purge)
+	return args.Get(0).(*models.Purge), args.Error(1)
+}
+
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) (*models.Subscription, error) {
+	args := m.Called(ctx, subscription)
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetByID(ctx context.Context, id string) (*models.Subscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) Update(ctx context.Context, subscription *models.Subscription) (*models.Subscription, error) {
+	args := m.Called(ctx, subscription)
+	return args.Get(0).(*models.Subscription), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) CreateItem(ctx context.Context, item *models.SubscriptionItem) (*models.SubscriptionItem, error) {
+	args := m.Called(ctx, item)
+	return args.Get(0).(*models.SubscriptionItem), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) GetItemByID(ctx context.Context, id string) (*models.SubscriptionItem, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.SubscriptionItem), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) ListItemsBySubscriptionID(ctx context.Context, subscriptionID string) ([]*models.SubscriptionItem, error) {
+	args := m.Called(ctx, subscriptionID)
+	return args.Get(0).([]*models.SubscriptionItem), args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) UpdateItem(ctx context.Context, item *models.SubscriptionItem) (*models.SubscriptionItem, error) {
+	args := m.Called(ctx, item)
+	return args.Get(0).(*models.SubscriptionItem), args.Error(1)
+}
+
+type MockCatalogRepository struct {
+	mock.Mock
+}
+
+func (m *MockCatalogRepository) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	args := m.Called(ctx, product)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetProductByID(ctx context.Context, id string) (*models.Product, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockCatalogRepository) ListProducts(ctx context.Context, includeArchived bool) ([]*models.Product, error) {
+	args := m.Called(ctx, includeArchived)
+	return args.Get(0).([]*models.Product), args.Error(1)
+}
+
+func (m *MockCatalogRepository) UpdateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	args := m.Called(ctx, product)
+	return args.Get(0).(*models.Product), args.Error(1)
+}
+
+func (m *MockCatalogRepository) CreatePlan(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetPlanByID(ctx context.Context, id string) (*models.Plan, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *MockCatalogRepository) ListPlansByProductID(ctx context.Context, productID string) ([]*models.Plan, error) {
+	args := m.Called(ctx, productID)
+	return args.Get(0).([]*models.Plan), args.Error(1)
+}
+
+func (m *MockCatalogRepository) UpdatePlan(ctx context.Context, plan *models.Plan) (*models.Plan, error) {
+	args := m.Called(ctx, plan)
+	return args.Get(0).(*models.Plan), args.Error(1)
+}
+
+func (m *MockCatalogRepository) CreatePrice(ctx context.Context, price *models.Price) (*models.Price, error) {
+	args := m.Called(ctx, price)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+func (m *MockCatalogRepository) GetPriceByID(ctx context.Context, id string) (*models.Price, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+func (m *MockCatalogRepository) ListPricesByPlanID(ctx context.Context, planID string) ([]*models.Price, error) {
+	args := m.Called(ctx, planID)
+	return args.Get(0).([]*models.Price), args.Error(1)
+}
+
+func (m *MockCatalogRepository) UpdatePrice(ctx context.Context, price *models.Price) (*models.Price, error) {
+	args := m.Called(ctx, price)
+	return args.Get(0).(*models.Price), args.Error(1)
+}
+
+type MockUsageRepository struct {
+	mock.Mock
+}
+
+func (m *MockUsageRepository) CreateMeter(ctx context.Context, meter *models.Meter) (*models.Meter, error) {
+	args := m.Called(ctx, meter)
+	return args.Get(0).(*models.Meter), args.Error(1)
+}
+
+func (m *MockUsageRepository) GetMeterByID(ctx context.Context, id string) (*models.Meter, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.Meter), args.Error(1)
+}
+
+func (m *MockUsageRepository) GetMeterByCode(ctx context.Context, code string) (*models.Meter, error) {
+	args := m.Called(ctx, code)
+	return args.Get(0).(*models.Meter), args.Error(1)
+}
+
+func (m *MockUsageRepository) CreateEvent(ctx context.Context, event *models.UsageEvent) (*models.UsageEvent, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(*models.UsageEvent), args.Error(1)
+}
+
+func (m *MockUsageRepository) GetEventByIdempotencyID(ctx context.Context, idempotencyID string) (*models.UsageEvent, error) {
+	args := m.Called(ctx, idempotencyID)
+	return args.Get(0).(*models.UsageEvent), args.Error(1)
+}
+
+func (m *MockUsageRepository) ClaimEvents(ctx context.Context, bill *models.Bill) ([]*models.UsageEvent, error) {
+	args := m.Called(ctx, bill)
+	return args.Get(0).([]*models.UsageEvent), args.Error(1)
+}
+
+func (m *MockUsageRepository) ReleaseEvents(ctx context.Context, ids []string) error {
+	args := m.Called(ctx, ids)
+	return args.Error(0)
+}
+
+type MockExchangeRateRepository struct {
+	mock.Mock
+}
+
+func (m *MockExchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, rate)
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+func (m *MockExchangeRateRepository) GetEffective(ctx context.Context, base string, quote string, at time.Time) (*models.ExchangeRate, error) {
+	args := m.Called(ctx, base, quote, at)
+	return args.Get(0).(*models.ExchangeRate), args.Error(1)
+}
+
+type MockLedgerRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerRepository) GetOrCreateAccount(ctx context.Context, account *models.LedgerAccount) (*models.LedgerAccount, error) {
+	args := m.Called(ctx, account)
+	return args.Get(0).(*models.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerRepository) Post(ctx context.Context, entry *models.JournalEntry) (*models.JournalEntry, error) {
+	args := m.Called(ctx, entry)
+	return args.Get(0).(*models.JournalEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) BillBalance(ctx context.Context, billID string, code string) (float64, error) {
+	args := m.Called(ctx, billID, code)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockLedgerRepository) ListByBillID(ctx context.Context, billID string) ([]*models.JournalEntry, error) {
+	args := m.Called(ctx, billID)
+	return args.Get(0).([]*models.JournalEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) ListByCustomerID(ctx context.Context, customerID string, kinds []string, until time.Time) ([]*models.JournalEntry, error) {
+	args := m.Called(ctx, customerID, kinds, until)
+	return args.Get(0).([]*models.JournalEntry), args.Error(1)
+}
+
+func (m *MockLedgerRepository) ListImbalances(ctx context.Context) ([]*models.LedgerImbalance, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.LedgerImbalance), args.Error(1)
+}
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, event *models.AuditEvent) (*models.AuditEvent, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).(*models.AuditEvent), args.Error(1)
+}
+
+func (m *MockAuditRepository) List(ctx context.Context, request *models.ListAuditEventsRequest) ([]*models.AuditEvent, int64, error) {
+	args := m.Called(ctx, request)
+	return args.Get(0).([]*models.AuditEvent), args.Get(1).(int64), args.Error(2)
+}
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, endpoint)
+	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetEndpointByID(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListEndpoints(ctx context.Context) ([]*models.WebhookEndpoint, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DisableEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, delivery)
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetDeliveryByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, endpointID string, request *models.ListWebhookDeliveriesRequest) ([]*models.WebhookDelivery, int64, error) {
+	args := m.Called(ctx, endpointID, request)
+	return args.Get(0).([]*models.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	args := m.Called(ctx, delivery)
+	return args.Get(0).(*models.WebhookDelivery), args.Error(1)
+}
+
+type MockProjectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectionRepository) UpsertBillSummary(ctx context.Context, summary *models.BillSummary) error {
+	args := m.Called(ctx, summary)
+	return args.Error(0)
+}
+
+func (m *MockProjectionRepository) ListBillSummaries(ctx context.Context, customerID string) ([]*models.BillSummary, error) {
+	args := m.Called(ctx, customerID)
+	return args.Get(0).([]*models.BillSummary), args.Error(1)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) ListTemplates(ctx context.Context, name string) ([]*models.EmailTemplate, error) {
+	args := m.Called(ctx, name)
+	return args.Get(0).([]*models.EmailTemplate), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpsertTemplate(ctx context.Context, template *models.EmailTemplate) (*models.EmailTemplate, error) {
+	args := m.Called(ctx, template)
+	return args.Get(0).(*models.EmailTemplate), args.Error(1)
+}
+
+func (m *MockNotificationRepository) DeleteTemplate(ctx context.Context, name string, locale string) error {
+	args := m.Called(ctx, name, locale)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) CreateInvoiceDelivery(ctx context.Context, delivery *models.InvoiceDelivery) (*models.InvoiceDelivery, error) {
+	args := m.Called(ctx, delivery)
+	return args.Get(0).(*models.InvoiceDelivery), args.Error(1)
+}
+
+func (m *MockNotificationRepository) ListInvoiceDeliveries(ctx context.Context, billID string) ([]*models.InvoiceDelivery, error) {
+	args := m.Called(ctx, billID)
+	return args.Get(0).([]*models.InvoiceDelivery), args.Error(1)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, apiKey *models.APIKey) (*models.APIKey, error) {
+	args := m.Called(ctx, apiKey)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByID(ctx context.Context, id string) (*models.APIKey, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*models.APIKey, error) {
+	args := m.Called(ctx, keyHash)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) List(ctx context.Context) ([]*models.APIKey, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]*models.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string, revokedAt time.Time) (*models.APIKey, error) {
+	args := m.Called(ctx, id, revokedAt)
+	return args.Get(0).(*models.APIKey), args.Error(1)
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+type NotificationRepository interface {
+	ListTemplates(context.Context, string) ([]*models.EmailTemplate, error)
+	UpsertTemplate(context.Context, *models.EmailTemplate) (*models.EmailTemplate, error)
+	DeleteTemplate(context.Context, string, string) error
+	CreateInvoiceDelivery(context.Context, *models.InvoiceDelivery) (*models.InvoiceDelivery, error)
+	ListInvoiceDeliveries(context.Context, string) ([]*models.InvoiceDelivery, error)
+}
+
+func NewNotificationRepository(dbClient *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: dbClient,
+	}
+}
+
+// ListTemplates returns the saved templates of the name in every locale.
+func (nr *notificationRepository) ListTemplates(ctx context.Context, name string) ([]*models.EmailTemplate, error) {
+	templates := []*models.EmailTemplate{}
+	result := nr.db.Scopes(tenancy.Scope(ctx)).Where("name = ?", name).Order("locale").Find(&templates)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing email templates", "name", name, "error", result.Error)
+		return templates, fmt.Errorf("listing email templates %s: %w", name, result.Error)
+	}
+
+	return templates, nil
+}
+
+// UpsertTemplate saves the template, replacing the one of its name and locale.
+func (nr *notificationRepository) UpsertTemplate(ctx context.Context, template *models.EmailTemplate) (*models.EmailTemplate, error) {
+	template.TenantID = tenancy.From(ctx)
+	result := nr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "tenant_id"}, {Name: "name"}, {Name: "locale"}},
+		DoUpdates: clause.AssignmentColumns([]string{"subject", "text", "html", "updated_at"}),
+	}).Create(&template)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while saving email template", "name", template.Name, "locale", template.Locale, "error", result.Error)
+		return template, fmt.Errorf("saving email template %s/%s: %w", template.Name, template.Locale, result.Error)
+	}
+
+	return template, nil
+}
+
+func (nr *notificationRepository) DeleteTemplate(ctx context.Context, name string, locale string) error {
+	result := nr.db.Scopes(tenancy.Scope(ctx)).Where("name = ? AND locale = ?", name, locale).Delete(&models.EmailTemplate{})
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while deleting email template", "name", name, "locale", locale, "error", result.Error)
+		return fmt.Errorf("deleting email template %s/%s: %w", name, locale, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		logging.From(ctx).Warn("email template not found", "name", name, "locale", locale)
+		return ce.EmailTemplateNotFoundError.WithID(fmt.Sprintf("%s/%s", name, locale))
+	}
+
+	return nil
+}
+
+func (nr *notificationRepository) CreateInvoiceDelivery(ctx context.Context, delivery *models.InvoiceDelivery) (*models.InvoiceDelivery, error) {
+	delivery.TenantID = tenancy.From(ctx)
+	result := nr.db.Create(&delivery)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating invoice delivery of bill", "bill_id", delivery.BillID, "error", result.Error)
+		return delivery, fmt.Errorf("creating invoice delivery of bill %s: %w", delivery.BillID, result.Error)
+	}
+
+	return delivery, nil
+}
+
+// ListInvoiceDeliveries returns the invoice deliveries of the bill, newest
+// first.
+func (nr *notificationRepository) ListInvoiceDeliveries(ctx context.Context, billID string) ([]*models.InvoiceDelivery, error) {
+	deliveries := []*models.InvoiceDelivery{}
+	result := nr.db.Scopes(tenancy.Scope(ctx)).Where("bill_id = ?", billID).Order("created_at DESC, id").Find(&deliveries)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing invoice deliveries of bill", "bill_id", billID, "error", result.Error)
+		return deliveries, fmt.Errorf("listing invoice deliveries of bill %s: %w", billID, result.Error)
+	}
+
+	return deliveries, nil
+}
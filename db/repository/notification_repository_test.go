@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type NotificationRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	nr       NotificationRepository
+	bill     *models.Bill
+}
+
+func (suite *NotificationRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.nr = NewNotificationRepository(dbClient.DB)
+	ctx := context.Background()
+
+	customer := &models.Customer{
+		ID:        utils.GetNewUUID(),
+		FirstName: "John",
+		LastName:  "Jacobs",
+		Email:     fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err = NewCustomerRepository(dbClient.DB).Create(ctx, customer)
+	suite.Nil(err, "error should be nil")
+
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "United states dollar",
+		Symbol:    "$",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err = NewCurrencyRepository(dbClient.DB).Create(ctx, currency)
+	suite.Nil(err, "error should be nil")
+
+	suite.bill = &models.Bill{
+		ID:          utils.GetNewUUID(),
+		Description: "Bill 01",
+		CustomerID:  customer.ID,
+		CurrencyID:  currency.ID,
+		Status:      "closed",
+		PeriodStart: time.Now().UTC(),
+		PeriodEnd:   time.Now().UTC().Add(time.Hour * 100),
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	_, err = NewBillRepository(dbClient.DB).Create(ctx, suite.bill)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *NotificationRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM invoice_deliveries")
+	suite.dbClient.Exec("DELETE FROM email_templates")
+}
+
+func (suite *NotificationRepositoryTestSuite) Test_UpsertTemplateReplacesTemplateOfLocale() {
+	ctx := context.Background()
+	name := utils.RandomString(10)
+
+	_, err := suite.nr.UpsertTemplate(ctx, &models.EmailTemplate{Name: name, Locale: "de", Subject: "Rechnung", Text: "Text", HTML: "<p>Text</p>", UpdatedAt: time.Now().UTC()})
+	suite.Nil(err, "error should be nil")
+	_, err = suite.nr.UpsertTemplate(ctx, &models.EmailTemplate{Name: name, Locale: "de", Subject: "Ihre Rechnung", Text: "Text", HTML: "<p>Text</p>", UpdatedAt: time.Now().UTC()})
+	suite.Nil(err, "error should be nil")
+	_, err = suite.nr.UpsertTemplate(ctx, &models.EmailTemplate{Name: name, Locale: "en-GB", Subject: "Invoice", Text: "Text", HTML: "<p>Text</p>", UpdatedAt: time.Now().UTC()})
+	suite.Nil(err, "error should be nil")
+
+	templates, err := suite.nr.ListTemplates(ctx, name)
+
+	suite.Nil(err, "error should be nil")
+	suite.Len(templates, 2)
+	suite.Equal("de", templates[0].Locale)
+	suite.Equal("Ihre Rechnung", templates[0].Subject)
+	suite.Equal("en-GB", templates[1].Locale)
+}
+
+func (suite *NotificationRepositoryTestSuite) Test_DeleteTemplate() {
+	ctx := context.Background()
+	name := utils.RandomString(10)
+	_, err := suite.nr.UpsertTemplate(ctx, &models.EmailTemplate{Name: name, Locale: "en", Subject: "Invoice", Text: "Text", HTML: "<p>Text</p>", UpdatedAt: time.Now().UTC()})
+	suite.Nil(err, "error should be nil")
+
+	suite.Nil(suite.nr.DeleteTemplate(ctx, name, "en"))
+	suite.ErrorIs(suite.nr.DeleteTemplate(ctx, name, "en"), ce.EmailTemplateNotFoundError)
+
+	templates, err := suite.nr.ListTemplates(ctx, name)
+	suite.Nil(err, "error should be nil")
+	suite.Empty(templates)
+}
+
+func (suite *NotificationRepositoryTestSuite) Test_ListInvoiceDeliveriesNewestFirst() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	for i, status := range []string{models.InvoiceEmailFailed, models.InvoiceEmailSent} {
+		_, err := suite.nr.CreateInvoiceDelivery(ctx, &models.InvoiceDelivery{
+			ID:        utils.GetNewUUID(),
+			BillID:    suite.bill.ID,
+			Recipient: "john@mail.com",
+			Locale:    "en",
+			Subject:   "Invoice",
+			Status:    status,
+			CreatedAt: now.Add(time.Duration(i) * time.Minute),
+		})
+		suite.Nil(err, "error should be nil")
+	}
+
+	deliveries, err := suite.nr.ListInvoiceDeliveries(ctx, suite.bill.ID)
+
+	suite.Nil(err, "error should be nil")
+	suite.Len(deliveries, 2)
+	suite.Equal(models.InvoiceEmailSent, deliveries[0].Status)
+	suite.Equal(models.InvoiceEmailFailed, deliveries[1].Status)
+}
+
+func TestNotificationRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationRepositoryTestSuite))
+}
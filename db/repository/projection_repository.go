@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type projectionRepository struct {
+	db *gorm.DB
+}
+
+type ProjectionRepository interface {
+	UpsertBillSummary(context.Context, *models.BillSummary) error
+	ListBillSummaries(context.Context, string) ([]*models.BillSummary, error)
+}
+
+func NewProjectionRepository(dbClient *gorm.DB) ProjectionRepository {
+	return &projectionRepository{
+		db: dbClient,
+	}
+}
+
+// UpsertBillSummary saves the summary unless the stored one is of a later
+// event, so events applied twice or out of order leave the latest summary.
+func (pr *projectionRepository) UpsertBillSummary(ctx context.Context, summary *models.BillSummary) error {
+	summary.TenantID = tenancy.From(ctx)
+	result := pr.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "bill_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "total_amount", "due_date", "last_event_id", "last_event_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "bill_summaries.last_event_at <= excluded.last_event_at"},
+		}},
+	}).Create(&summary)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while saving summary of bill", "bill_id", summary.BillID, "error", result.Error)
+		return fmt.Errorf("saving summary of bill %s: %w", summary.BillID, result.Error)
+	}
+
+	return nil
+}
+
+// ListBillSummaries returns the bill summaries of the customer, newest event
+// first.
+func (pr *projectionRepository) ListBillSummaries(ctx context.Context, customerID string) ([]*models.BillSummary, error) {
+	summaries := []*models.BillSummary{}
+	result := pr.db.Scopes(tenancy.Scope(ctx)).Where("customer_id = ?", customerID).Order("last_event_at DESC, bill_id").Find(&summaries)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing bill summaries of customer", "customer_id", customerID, "error", result.Error)
+		return summaries, fmt.Errorf("listing bill summaries of customer %s: %w", customerID, result.Error)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type ProjectionRepositoryTestSuite struct {
+	suite.Suite
+	dbClient   *gorm.DB
+	pr         ProjectionRepository
+	customerID string
+}
+
+func (suite *ProjectionRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.pr = NewProjectionRepository(dbClient.DB)
+	suite.customerID = utils.GetNewUUID()
+}
+
+func (suite *ProjectionRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM bill_summaries")
+}
+
+func (suite *ProjectionRepositoryTestSuite) summary(billID string, status string, eventAt time.Time) *models.BillSummary {
+	return &models.BillSummary{
+		BillID:      billID,
+		CustomerID:  suite.customerID,
+		CurrencyID:  utils.GetNewUUID(),
+		Status:      status,
+		LastEventID: utils.GetNewUUID(),
+		LastEventAt: eventAt,
+	}
+}
+
+func (suite *ProjectionRepositoryTestSuite) Test_UpsertBillSummaryKeepsLatestEvent() {
+	ctx := context.Background()
+	billID := utils.GetNewUUID()
+	now := time.Now().UTC()
+
+	suite.Nil(suite.pr.UpsertBillSummary(ctx, suite.summary(billID, "closed", now)))
+	suite.Nil(suite.pr.UpsertBillSummary(ctx, suite.summary(billID, "open", now.Add(-time.Minute))))
+
+	summaries, err := suite.pr.ListBillSummaries(ctx, suite.customerID)
+	suite.Nil(err, "error should be nil")
+	suite.Len(summaries, 1)
+	suite.Equal("closed", summaries[0].Status)
+}
+
+func TestProjectionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(ProjectionRepositoryTestSuite))
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/ratelimit"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type rateLimitRepository struct {
+	db *gorm.DB
+}
+
+// RateLimitRepository is a ratelimit.Limiter keeping the buckets in the
+// database, so the instances of the service share the limits.
+type RateLimitRepository interface {
+	Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error)
+}
+
+func NewRateLimitRepository(dbClient *gorm.DB) RateLimitRepository {
+	return &rateLimitRepository{
+		db: dbClient,
+	}
+}
+
+// Allow takes a token of the bucket of the key, locking its row so
+// concurrent requests of the key take turns. Buckets are refilled on the
+// clock of the database, the one clock all instances agree on.
+func (rr *rateLimitRepository) Allow(ctx context.Context, key string, limit ratelimit.Limit) (ratelimit.Result, error) {
+	if limit.IsUnlimited() {
+		return ratelimit.Result{Allowed: true}, nil
+	}
+
+	result := ratelimit.Result{}
+	err := rr.db.Transaction(func(tx *gorm.DB) error {
+		var now time.Time
+		clock := tx.Raw("SELECT timezone('UTC', NOW())").Scan(&now)
+		if clock.Error != nil {
+			return clock.Error
+		}
+
+		bucket := &models.RateLimitBucket{Key: key, Tokens: float64(limit.Burst), RefilledAt: now}
+		created := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(bucket)
+		if created.Error != nil {
+			return created.Error
+		}
+
+		locked := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(bucket)
+		if locked.Error != nil {
+			return locked.Error
+		}
+
+		state := ratelimit.Bucket{Tokens: bucket.Tokens, UpdatedAt: bucket.RefilledAt}
+		result = state.Take(limit, now)
+
+		return tx.Model(&models.RateLimitBucket{}).Where("key = ?", key).Updates(map[string]interface{}{
+			"tokens":      state.Tokens,
+			"refilled_at": state.UpdatedAt,
+		}).Error
+	})
+
+	if err != nil {
+		logging.From(ctx).Error("error occurred while taking rate limit token", "key", key, "error", err)
+		return ratelimit.Result{}, fmt.Errorf("taking rate limit token of %s: %w", key, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	database "github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/pkg/ratelimit"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type RateLimitRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	rr       RateLimitRepository
+}
+
+func (suite *RateLimitRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.rr = NewRateLimitRepository(dbClient.DB)
+}
+
+func (suite *RateLimitRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM rate_limit_buckets")
+}
+
+func (suite *RateLimitRepositoryTestSuite) Test_AllowRefusesOnceBurstIsTaken() {
+	ctx := context.Background()
+	key := utils.GetNewUUID()
+	limit := ratelimit.PerMinute(1, 2)
+
+	first, err := suite.rr.Allow(ctx, key, limit)
+	suite.Nil(err, "error should be nil")
+	suite.True(first.Allowed)
+	suite.Equal(1, first.Remaining)
+
+	second, err := suite.rr.Allow(ctx, key, limit)
+	suite.Nil(err, "error should be nil")
+	suite.True(second.Allowed)
+	suite.Equal(0, second.Remaining)
+
+	third, err := suite.rr.Allow(ctx, key, limit)
+	suite.Nil(err, "error should be nil")
+	suite.False(third.Allowed)
+	suite.Equal(2, third.Limit)
+	suite.True(third.RetryAfter > 0)
+}
+
+func (suite *RateLimitRepositoryTestSuite) Test_AllowKeepsABucketPerKey() {
+	ctx := context.Background()
+	limit := ratelimit.PerMinute(1, 1)
+
+	first, err := suite.rr.Allow(ctx, utils.GetNewUUID(), limit)
+	suite.Nil(err, "error should be nil")
+	suite.True(first.Allowed)
+
+	other, err := suite.rr.Allow(ctx, utils.GetNewUUID(), limit)
+	suite.Nil(err, "error should be nil")
+	suite.True(other.Allowed)
+}
+
+func TestRateLimitRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(RateLimitRepositoryTestSuite))
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+)
+
+type subscriptionRepository struct {
+	db *gorm.DB
+}
+
+type SubscriptionRepository interface {
+	Create(context.Context, *models.Subscription) (*models.Subscription, error)
+	GetByID(context.Context, string) (*models.Subscription, error)
+	Update(context.Context, *models.Subscription) (*models.Subscription, error)
+	CreateItem(context.Context, *models.SubscriptionItem) (*models.SubscriptionItem, error)
+	GetItemByID(context.Context, string) (*models.SubscriptionItem, error)
+	ListItemsBySubscriptionID(context.Context, string) ([]*models.SubscriptionItem, error)
+	UpdateItem(context.Context, *models.SubscriptionItem) (*models.SubscriptionItem, error)
+}
+
+func NewSubscriptionRepository(dbClient *gorm.DB) SubscriptionRepository {
+	return &subscriptionRepository{
+		db: dbClient,
+	}
+}
+
+func (sr *subscriptionRepository) Create(ctx context.Context, subscription *models.Subscription) (*models.Subscription, error) {
+	subscription.TenantID = tenancy.From(ctx)
+	result := sr.db.Create(&subscription)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating subscription", "subscription_id", subscription.ID, "error", result.Error)
+		return subscription, fmt.Errorf("creating subscription: %w", result.Error)
+	}
+
+	return subscription, nil
+}
+
+func (sr *subscriptionRepository) GetByID(ctx context.Context, id string) (*models.Subscription, error) {
+	subscription := &models.Subscription{}
+	result := sr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&subscription)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("subscription not found", "subscription_id", id)
+		return subscription, ce.SubscriptionNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying subscription", "subscription_id", id, "error", result.Error)
+		return subscription, fmt.Errorf("querying subscription %s: %w", id, result.Error)
+	}
+
+	return subscription, nil
+}
+
+func (sr *subscriptionRepository) Update(ctx context.Context, subscription *models.Subscription) (*models.Subscription, error) {
+	subscription.UpdatedAt = time.Now().UTC()
+	result := sr.db.Save(subscription)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating subscription", "subscription_id", subscription.ID, "error", result.Error)
+		return subscription, fmt.Errorf("updating subscription %s: %w", subscription.ID, result.Error)
+	}
+
+	return subscription, nil
+}
+
+func (sr *subscriptionRepository) CreateItem(ctx context.Context, item *models.SubscriptionItem) (*models.SubscriptionItem, error) {
+	item.TenantID = tenancy.From(ctx)
+	result := sr.db.Create(&item)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating subscription item", "item_id", item.ID, "error", result.Error)
+		return item, fmt.Errorf("creating subscription item: %w", result.Error)
+	}
+
+	return item, nil
+}
+
+func (sr *subscriptionRepository) GetItemByID(ctx context.Context, id string) (*models.SubscriptionItem, error) {
+	item := &models.SubscriptionItem{}
+	result := sr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&item)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("subscription item not found", "item_id", id)
+		return item, ce.SubscriptionItemNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying subscription item", "item_id", id, "error", result.Error)
+		return item, fmt.Errorf("querying subscription item %s: %w", id, result.Error)
+	}
+
+	return item, nil
+}
+
+// ListItemsBySubscriptionID returns the subscription items that are not removed.
+func (sr *subscriptionRepository) ListItemsBySubscriptionID(ctx context.Context, subscriptionID string) ([]*models.SubscriptionItem, error) {
+	items := []*models.SubscriptionItem{}
+	result := sr.db.Scopes(tenancy.Scope(ctx)).Where("subscription_id = ? AND removed = ?", subscriptionID, false).Order("created_at").Find(&items)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying items of subscription", "subscription_id", subscriptionID, "error", result.Error)
+		return items, fmt.Errorf("querying items of subscription %s: %w", subscriptionID, result.Error)
+	}
+
+	return items, nil
+}
+
+func (sr *subscriptionRepository) UpdateItem(ctx context.Context, item *models.SubscriptionItem) (*models.SubscriptionItem, error) {
+	item.UpdatedAt = time.Now().UTC()
+	result := sr.db.Save(item)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating subscription item", "item_id", item.ID, "error", result.Error)
+		return item, fmt.Errorf("updating subscription item %s: %w", item.ID, result.Error)
+	}
+
+	return item, nil
+}
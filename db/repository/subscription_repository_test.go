@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type SubscriptionRepositoryTestSuite struct {
+	suite.Suite
+	dbClient     *gorm.DB
+	sr           SubscriptionRepository
+	subscription *models.Subscription
+}
+
+func (suite *SubscriptionRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.sr = NewSubscriptionRepository(dbClient.DB)
+
+	customer := &models.Customer{
+		ID:        utils.GetNewUUID(),
+		FirstName: "John",
+		LastName:  "Jacobs",
+		Email:     fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	currency := &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "United states dollar",
+		Symbol:    "$",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+
+	_, err = NewCustomerRepository(dbClient.DB).Create(context.Background(), customer)
+	suite.Nil(err, "error should be nil")
+
+	_, err = NewCurrencyRepository(dbClient.DB).Create(context.Background(), currency)
+	suite.Nil(err, "error should be nil")
+
+	now := time.Now().UTC()
+	suite.subscription = &models.Subscription{
+		ID:                 utils.GetNewUUID(),
+		Description:        "pro plan",
+		CustomerID:         customer.ID,
+		CurrencyID:         currency.ID,
+		BillingInterval:    models.MonthlyInterval,
+		Status:             "active",
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now.AddDate(0, 1, 0),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+}
+
+func (suite *SubscriptionRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM subscription_items")
+	suite.dbClient.Exec("DELETE FROM subscriptions")
+	suite.dbClient.Exec("DELETE FROM prices")
+	suite.dbClient.Exec("DELETE FROM plans")
+	suite.dbClient.Exec("DELETE FROM products")
+	suite.dbClient.Exec("DELETE FROM currencies")
+	suite.dbClient.Exec("DELETE FROM customers")
+}
+
+func (suite *SubscriptionRepositoryTestSuite) Test_CreateSubscriptionWhenSucceeds() {
+	_, err := suite.sr.Create(context.Background(), suite.subscription)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *SubscriptionRepositoryTestSuite) Test_GetSubscriptionByIDWhenSucceeds() {
+	_, err := suite.sr.Create(context.Background(), suite.subscription)
+	suite.Nil(err, "error should be nil")
+
+	subscription, err := suite.sr.GetByID(context.Background(), suite.subscription.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(suite.subscription.Description, subscription.Description)
+	suite.Equal(models.MonthlyInterval, subscription.BillingInterval)
+}
+
+func (suite *SubscriptionRepositoryTestSuite) Test_UpdateSubscriptionWhenSucceeds() {
+	_, err := suite.sr.Create(context.Background(), suite.subscription)
+	suite.Nil(err, "error should be nil")
+
+	suite.subscription.CancelAtPeriodEnd = true
+	_, err = suite.sr.Update(context.Background(), suite.subscription)
+	suite.Nil(err, "error should be nil")
+
+	subscription, err := suite.sr.GetByID(context.Background(), suite.subscription.ID)
+	suite.Nil(err, "error should be nil")
+	suite.True(subscription.CancelAtPeriodEnd)
+}
+
+func (suite *SubscriptionRepositoryTestSuite) Test_ListItemsSkipsRemovedItems() {
+	_, err := suite.sr.Create(context.Background(), suite.subscription)
+	suite.Nil(err, "error should be nil")
+
+	product := &models.Product{ID: utils.GetNewUUID(), Name: "API", Active: true}
+	plan := &models.Plan{ID: utils.GetNewUUID(), ProductID: product.ID, Name: "pro", BillingInterval: models.MonthlyInterval, Active: true}
+	price := &models.Price{ID: utils.GetNewUUID(), PlanID: plan.ID, CurrencyID: suite.subscription.CurrencyID, PricingModel: models.PerUnitPricing, UnitAmount: 49, Active: true}
+	catalogRepository := NewCatalogRepository(suite.dbClient)
+	_, err = catalogRepository.CreateProduct(context.Background(), product)
+	suite.Nil(err, "error should be nil")
+	_, err = catalogRepository.CreatePlan(context.Background(), plan)
+	suite.Nil(err, "error should be nil")
+	_, err = catalogRepository.CreatePrice(context.Background(), price)
+	suite.Nil(err, "error should be nil")
+
+	kept := &models.SubscriptionItem{ID: utils.GetNewUUID(), SubscriptionID: suite.subscription.ID, PriceID: price.ID, Quantity: 1}
+	removed := &models.SubscriptionItem{ID: utils.GetNewUUID(), SubscriptionID: suite.subscription.ID, PriceID: price.ID, Quantity: 2}
+	_, err = suite.sr.CreateItem(context.Background(), kept)
+	suite.Nil(err, "error should be nil")
+	_, err = suite.sr.CreateItem(context.Background(), removed)
+	suite.Nil(err, "error should be nil")
+
+	removed.Removed = true
+	_, err = suite.sr.UpdateItem(context.Background(), removed)
+	suite.Nil(err, "error should be nil")
+
+	items, err := suite.sr.ListItemsBySubscriptionID(context.Background(), suite.subscription.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(items))
+	suite.Equal(kept.ID, items[0].ID)
+}
+
+func TestSubscriptionRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionRepositoryTestSuite))
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type transactionKey struct{}
+
+// transaction runs fn in a database transaction. Repositories called with the
+// context given to fn run their queries in the transaction, and a transaction
+// begun inside it becomes a savepoint of it.
+func transaction(ctx context.Context, db *gorm.DB, fn func(context.Context) error) error {
+	return conn(ctx, db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, transactionKey{}, tx))
+	})
+}
+
+// conn returns the transaction of the context, or db outside of one.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(transactionKey{}).(*gorm.DB); ok {
+		return tx
+	}
+	return db
+}
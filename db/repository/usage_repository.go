@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type usageRepository struct {
+	db *gorm.DB
+}
+
+type UsageRepository interface {
+	CreateMeter(context.Context, *models.Meter) (*models.Meter, error)
+	GetMeterByID(context.Context, string) (*models.Meter, error)
+	GetMeterByCode(context.Context, string) (*models.Meter, error)
+	CreateEvent(context.Context, *models.UsageEvent) (*models.UsageEvent, error)
+	GetEventByIdempotencyID(context.Context, string) (*models.UsageEvent, error)
+	ClaimEvents(context.Context, *models.Bill) ([]*models.UsageEvent, error)
+	ReleaseEvents(context.Context, []string) error
+}
+
+func NewUsageRepository(dbClient *gorm.DB) UsageRepository {
+	return &usageRepository{
+		db: dbClient,
+	}
+}
+
+func (ur *usageRepository) CreateMeter(ctx context.Context, meter *models.Meter) (*models.Meter, error) {
+	meter.TenantID = tenancy.From(ctx)
+	result := ur.db.Create(&meter)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating meter", "meter_id", meter.ID, "error", result.Error)
+		return meter, fmt.Errorf("creating meter: %w", result.Error)
+	}
+
+	return meter, nil
+}
+
+func (ur *usageRepository) GetMeterByID(ctx context.Context, id string) (*models.Meter, error) {
+	meter := &models.Meter{}
+	result := ur.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&meter)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("meter not found", "meter_id", id)
+		return meter, ce.MeterNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying meter", "meter_id", id, "error", result.Error)
+		return meter, fmt.Errorf("querying meter %s: %w", id, result.Error)
+	}
+
+	return meter, nil
+}
+
+func (ur *usageRepository) GetMeterByCode(ctx context.Context, code string) (*models.Meter, error) {
+	meter := &models.Meter{}
+	result := ur.db.Scopes(tenancy.Scope(ctx)).Where("code = ?", code).First(&meter)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("meter not found", "code", code)
+		return meter, ce.MeterNotFoundError.WithID(code)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying meter", "code", code, "error", result.Error)
+		return meter, fmt.Errorf("querying meter %s: %w", code, result.Error)
+	}
+
+	return meter, nil
+}
+
+// CreateEvent records the usage event. An event with the same idempotency id
+// recorded concurrently is returned instead.
+func (ur *usageRepository) CreateEvent(ctx context.Context, event *models.UsageEvent) (*models.UsageEvent, error) {
+	event.TenantID = tenancy.From(ctx)
+	result := ur.db.Create(&event)
+
+	if errors.Is(result.Error, gorm.ErrDuplicatedKey) {
+		logging.From(ctx).Warn("usage event already recorded", "idempotency_id", event.IdempotencyID)
+		return ur.GetEventByIdempotencyID(ctx, event.IdempotencyID)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating usage event", "event_id", event.ID, "error", result.Error)
+		return event, fmt.Errorf("creating usage event: %w", result.Error)
+	}
+
+	return event, nil
+}
+
+func (ur *usageRepository) GetEventByIdempotencyID(ctx context.Context, idempotencyID string) (*models.UsageEvent, error) {
+	event := &models.UsageEvent{}
+	result := ur.db.Scopes(tenancy.Scope(ctx)).Where("idempotency_id = ?", idempotencyID).First(&event)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		return event, ce.UsageEventNotFoundError.WithID(idempotencyID)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying usage event", "idempotency_id", idempotencyID, "error", result.Error)
+		return event, fmt.Errorf("querying usage event %s: %w", idempotencyID, result.Error)
+	}
+
+	return event, nil
+}
+
+// ClaimEvents assigns the unbilled usage events of the customer of the bill
+// before the end of its period to the bill and returns the claimed events.
+// Only events of meters whose plan has an active price in the currency of the
+// bill are claimed. Events arriving after the bill of their period was rated
+// have no lower bound to miss, they are claimed by the next bill. Claiming in a
+// single update keeps concurrent rating runs from billing an event twice.
+func (ur *usageRepository) ClaimEvents(ctx context.Context, bill *models.Bill) ([]*models.UsageEvent, error) {
+	scope := tenancy.Scope(ctx)
+	meters := ur.db.Scopes(scope).Model(&models.Meter{}).Select("meters.id").
+		Joins("JOIN prices ON prices.plan_id = meters.plan_id").
+		Where("prices.active AND prices.currency_id = ?", bill.CurrencyID)
+
+	events := []*models.UsageEvent{}
+	result := ur.db.Scopes(scope).Model(&events).Clauses(clause.Returning{}).
+		Where("customer_id = ? AND bill_id = '' AND timestamp < ? AND meter_id IN (?)", bill.CustomerID, bill.PeriodEnd, meters).
+		Update("bill_id", bill.ID)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while claiming usage events for bill", "bill_id", bill.ID, "error", result.Error)
+		return events, fmt.Errorf("claiming usage events for bill %s: %w", bill.ID, result.Error)
+	}
+
+	return events, nil
+}
+
+func (ur *usageRepository) ReleaseEvents(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := ur.db.Scopes(tenancy.Scope(ctx)).Model(&models.UsageEvent{}).Where("id IN ?", ids).Update("bill_id", "")
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while releasing usage events", "error", result.Error)
+		return fmt.Errorf("releasing usage events: %w", result.Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type UsageRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	ur       UsageRepository
+	customer *models.Customer
+	currency *models.Currency
+	plan     *models.Plan
+	meter    *models.Meter
+}
+
+func (suite *UsageRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.ur = NewUsageRepository(dbClient.DB)
+	ctx := context.Background()
+
+	suite.customer = &models.Customer{
+		ID:        utils.GetNewUUID(),
+		FirstName: "John",
+		LastName:  "Jacobs",
+		Email:     fmt.Sprintf("john.%s@mail.com", utils.RandomString(8)),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err = NewCustomerRepository(dbClient.DB).Create(ctx, suite.customer)
+	suite.Nil(err, "error should be nil")
+
+	catalogRepository := NewCatalogRepository(dbClient.DB)
+	product := &models.Product{ID: utils.GetNewUUID(), Name: "API", Active: true}
+	_, err = catalogRepository.CreateProduct(ctx, product)
+	suite.Nil(err, "error should be nil")
+
+	suite.plan = &models.Plan{ID: utils.GetNewUUID(), ProductID: product.ID, Name: "metered", BillingInterval: models.MonthlyInterval, Active: true}
+	_, err = catalogRepository.CreatePlan(ctx, suite.plan)
+	suite.Nil(err, "error should be nil")
+
+	suite.currency = &models.Currency{
+		ID:        utils.GetNewUUID(),
+		Code:      utils.RandomString(3),
+		Name:      "United states dollar",
+		Symbol:    "$",
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	_, err = NewCurrencyRepository(dbClient.DB).Create(ctx, suite.currency)
+	suite.Nil(err, "error should be nil")
+
+	price := &models.Price{ID: utils.GetNewUUID(), PlanID: suite.plan.ID, CurrencyID: suite.currency.ID, UnitAmount: 0.01, Active: true}
+	_, err = catalogRepository.CreatePrice(ctx, price)
+	suite.Nil(err, "error should be nil")
+
+	suite.meter = &models.Meter{
+		ID:          utils.GetNewUUID(),
+		Code:        utils.RandomString(10),
+		Name:        "API calls",
+		Aggregation: models.SumAggregation,
+		PlanID:      suite.plan.ID,
+		CreatedAt:   time.Now().UTC(),
+		UpdatedAt:   time.Now().UTC(),
+	}
+	_, err = suite.ur.CreateMeter(ctx, suite.meter)
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *UsageRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM usage_events")
+	suite.dbClient.Exec("DELETE FROM meters")
+	suite.dbClient.Exec("DELETE FROM prices")
+	suite.dbClient.Exec("DELETE FROM plans")
+	suite.dbClient.Exec("DELETE FROM products")
+	suite.dbClient.Exec("DELETE FROM currencies")
+	suite.dbClient.Exec("DELETE FROM customers")
+}
+
+func (suite *UsageRepositoryTestSuite) createEvent(meterID string, timestamp time.Time) *models.UsageEvent {
+	event := &models.UsageEvent{
+		ID:            utils.GetNewUUID(),
+		IdempotencyID: utils.RandomString(20),
+		CustomerID:    suite.customer.ID,
+		MeterID:       meterID,
+		Quantity:      10,
+		Timestamp:     timestamp,
+		CreatedAt:     time.Now().UTC(),
+	}
+	_, err := suite.ur.CreateEvent(context.Background(), event)
+	suite.Nil(err, "error should be nil")
+	return event
+}
+
+func (suite *UsageRepositoryTestSuite) bill(periodStart time.Time, periodEnd time.Time) *models.Bill {
+	return &models.Bill{
+		ID:          utils.GetNewUUID(),
+		CustomerID:  suite.customer.ID,
+		CurrencyID:  suite.currency.ID,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+	}
+}
+
+func (suite *UsageRepositoryTestSuite) Test_GetMeterByCodeWhenSucceeds() {
+	meter, err := suite.ur.GetMeterByCode(context.Background(), suite.meter.Code)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(suite.meter.ID, meter.ID)
+}
+
+func (suite *UsageRepositoryTestSuite) Test_CreateEventAndGetByIdempotencyIDWhenSucceeds() {
+	event := &models.UsageEvent{
+		ID:            utils.GetNewUUID(),
+		IdempotencyID: utils.RandomString(20),
+		CustomerID:    suite.customer.ID,
+		MeterID:       suite.meter.ID,
+		Quantity:      10,
+		Timestamp:     time.Now().UTC(),
+		CreatedAt:     time.Now().UTC(),
+	}
+	_, err := suite.ur.CreateEvent(context.Background(), event)
+	suite.Nil(err, "error should be nil")
+
+	eventRecord, err := suite.ur.GetEventByIdempotencyID(context.Background(), event.IdempotencyID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(event.ID, eventRecord.ID)
+}
+
+func (suite *UsageRepositoryTestSuite) Test_CreateEventReturnsEventWithSameIdempotencyID() {
+	event := &models.UsageEvent{
+		ID:            utils.GetNewUUID(),
+		IdempotencyID: utils.RandomString(20),
+		CustomerID:    suite.customer.ID,
+		MeterID:       suite.meter.ID,
+		Quantity:      10,
+		Timestamp:     time.Now().UTC(),
+		CreatedAt:     time.Now().UTC(),
+	}
+	_, err := suite.ur.CreateEvent(context.Background(), event)
+	suite.Nil(err, "error should be nil")
+
+	again := *event
+	again.ID = utils.GetNewUUID()
+	again.Quantity = 20
+	eventRecord, err := suite.ur.CreateEvent(context.Background(), &again)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(event.ID, eventRecord.ID)
+	suite.Equal(float64(10), eventRecord.Quantity)
+}
+
+func (suite *UsageRepositoryTestSuite) Test_ClaimEventsClaimsOnlyOnce() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	event := suite.createEvent(suite.meter.ID, now)
+
+	bill := suite.bill(now.Add(-time.Hour), now.Add(time.Hour))
+	events, err := suite.ur.ClaimEvents(ctx, bill)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(events))
+
+	events, err = suite.ur.ClaimEvents(ctx, bill)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(0, len(events))
+
+	err = suite.ur.ReleaseEvents(ctx, []string{event.ID})
+	suite.Nil(err, "error should be nil")
+
+	events, err = suite.ur.ClaimEvents(ctx, bill)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(1, len(events))
+}
+
+func (suite *UsageRepositoryTestSuite) Test_ClaimEventsClaimsLateEventsOfMetersPricedInBillCurrency() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	plan := &models.Plan{ID: utils.GetNewUUID(), ProductID: suite.plan.ProductID, Name: "storage", BillingInterval: models.MonthlyInterval, Active: true}
+	_, err := NewCatalogRepository(suite.dbClient).CreatePlan(ctx, plan)
+	suite.Nil(err, "error should be nil")
+
+	unpriced := &models.Meter{
+		ID:          utils.GetNewUUID(),
+		Code:        utils.RandomString(10),
+		Name:        "storage",
+		Aggregation: models.MaxAggregation,
+		PlanID:      plan.ID,
+	}
+	_, err = suite.ur.CreateMeter(ctx, unpriced)
+	suite.Nil(err, "error should be nil")
+
+	late := suite.createEvent(suite.meter.ID, now.AddDate(0, -2, 0))
+	suite.createEvent(unpriced.ID, now)
+	suite.createEvent(suite.meter.ID, now.Add(2*time.Hour))
+
+	events, err := suite.ur.ClaimEvents(ctx, suite.bill(now.Add(-time.Hour), now.Add(time.Hour)))
+	suite.Nil(err, "error should be nil")
+	suite.Require().Equal(1, len(events))
+	suite.Equal(late.ID, events[0].ID)
+}
+
+func TestUsageRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(UsageRepositoryTestSuite))
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+	"github.com/asheet-bhaskar/billing-service/pkg/tenancy"
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+type WebhookRepository interface {
+	CreateEndpoint(context.Context, *models.WebhookEndpoint) (*models.WebhookEndpoint, error)
+	GetEndpointByID(context.Context, string) (*models.WebhookEndpoint, error)
+	ListEndpoints(context.Context) ([]*models.WebhookEndpoint, error)
+	DisableEndpoint(context.Context, string) (*models.WebhookEndpoint, error)
+	CreateDelivery(context.Context, *models.WebhookDelivery) (*models.WebhookDelivery, error)
+	GetDeliveryByID(context.Context, string) (*models.WebhookDelivery, error)
+	ListDeliveries(context.Context, string, *models.ListWebhookDeliveriesRequest) ([]*models.WebhookDelivery, int64, error)
+	UpdateDelivery(context.Context, *models.WebhookDelivery) (*models.WebhookDelivery, error)
+}
+
+func NewWebhookRepository(dbClient *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: dbClient,
+	}
+}
+
+func (wr *webhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) (*models.WebhookEndpoint, error) {
+	endpoint.TenantID = tenancy.From(ctx)
+	result := wr.db.Create(&endpoint)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating webhook endpoint", "url", endpoint.URL, "error", result.Error)
+		return endpoint, fmt.Errorf("creating webhook endpoint %s: %w", endpoint.URL, result.Error)
+	}
+
+	return endpoint, nil
+}
+
+func (wr *webhookRepository) GetEndpointByID(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	endpoint := &models.WebhookEndpoint{}
+	result := wr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&endpoint)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("webhook endpoint not found", "endpoint_id", id)
+		return endpoint, ce.WebhookEndpointNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying webhook endpoint", "endpoint_id", id, "error", result.Error)
+		return endpoint, fmt.Errorf("querying webhook endpoint %s: %w", id, result.Error)
+	}
+
+	return endpoint, nil
+}
+
+// ListEndpoints returns the active webhook endpoints, oldest first.
+func (wr *webhookRepository) ListEndpoints(ctx context.Context) ([]*models.WebhookEndpoint, error) {
+	endpoints := []*models.WebhookEndpoint{}
+	result := wr.db.Scopes(tenancy.Scope(ctx)).Where("active = ?", true).Order("created_at, id").Find(&endpoints)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing webhook endpoints", "error", result.Error)
+		return endpoints, fmt.Errorf("listing webhook endpoints: %w", result.Error)
+	}
+
+	return endpoints, nil
+}
+
+// DisableEndpoint stops sending events to the endpoint. Its deliveries are
+// kept in the delivery log.
+func (wr *webhookRepository) DisableEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	endpoint := &models.WebhookEndpoint{}
+	result := wr.db.Scopes(tenancy.Scope(ctx)).Model(&endpoint).Where("id = ?", id).Updates(map[string]interface{}{"active": false, "updated_at": gorm.Expr("timezone('UTC', NOW())")})
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while disabling webhook endpoint", "endpoint_id", id, "error", result.Error)
+		return endpoint, fmt.Errorf("disabling webhook endpoint %s: %w", id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		logging.From(ctx).Warn("webhook endpoint not found", "endpoint_id", id)
+		return endpoint, ce.WebhookEndpointNotFoundError.WithID(id)
+	}
+
+	return wr.GetEndpointByID(ctx, id)
+}
+
+func (wr *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	delivery.TenantID = tenancy.From(ctx)
+	result := wr.db.Create(&delivery)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while creating webhook delivery of event to endpoint", "event_id", delivery.EventID, "endpoint_id", delivery.EndpointID, "error", result.Error)
+		return delivery, fmt.Errorf("creating webhook delivery of event %s to endpoint %s: %w", delivery.EventID, delivery.EndpointID, result.Error)
+	}
+
+	return delivery, nil
+}
+
+func (wr *webhookRepository) GetDeliveryByID(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	delivery := &models.WebhookDelivery{}
+	result := wr.db.Scopes(tenancy.Scope(ctx)).Where("id = ?", id).First(&delivery)
+
+	if result.Error == gorm.ErrRecordNotFound {
+		logging.From(ctx).Warn("webhook delivery not found", "delivery_id", id)
+		return delivery, ce.WebhookDeliveryNotFoundError.WithID(id)
+	}
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while querying webhook delivery", "delivery_id", id, "error", result.Error)
+		return delivery, fmt.Errorf("querying webhook delivery %s: %w", id, result.Error)
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries returns a page of the deliveries to the endpoint, newest
+// first, with the total number of its deliveries.
+func (wr *webhookRepository) ListDeliveries(ctx context.Context, endpointID string, request *models.ListWebhookDeliveriesRequest) ([]*models.WebhookDelivery, int64, error) {
+	deliveries := []*models.WebhookDelivery{}
+	query := wr.db.Scopes(tenancy.Scope(ctx)).Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+
+	var total int64
+	result := query.Count(&total)
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while counting deliveries of webhook endpoint", "endpoint_id", endpointID, "error", result.Error)
+		return deliveries, 0, fmt.Errorf("counting deliveries of webhook endpoint %s: %w", endpointID, result.Error)
+	}
+
+	result = query.Order("created_at DESC, id").Limit(request.PageSize()).Offset(request.Offset).Find(&deliveries)
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while listing deliveries of webhook endpoint", "endpoint_id", endpointID, "error", result.Error)
+		return deliveries, 0, fmt.Errorf("listing deliveries of webhook endpoint %s: %w", endpointID, result.Error)
+	}
+
+	return deliveries, total, nil
+}
+
+// UpdateDelivery saves the outcome of the latest attempt of the delivery.
+func (wr *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) (*models.WebhookDelivery, error) {
+	result := wr.db.Scopes(tenancy.Scope(ctx)).Model(&delivery).Select("status", "attempts", "response_status", "last_error", "delivered_at", "updated_at").Updates(delivery)
+
+	if result.Error != nil {
+		logging.From(ctx).Error("error occurred while updating webhook delivery", "delivery_id", delivery.ID, "error", result.Error)
+		return delivery, fmt.Errorf("updating webhook delivery %s: %w", delivery.ID, result.Error)
+	}
+
+	return delivery, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/app/models"
+	database "github.com/asheet-bhaskar/billing-service/db"
+	ce "github.com/asheet-bhaskar/billing-service/pkg/error"
+	"github.com/asheet-bhaskar/billing-service/pkg/utils"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type WebhookRepositoryTestSuite struct {
+	suite.Suite
+	dbClient *gorm.DB
+	wr       WebhookRepository
+	endpoint *models.WebhookEndpoint
+}
+
+func (suite *WebhookRepositoryTestSuite) SetupTest() {
+	host := "localhost"
+	port := "5434"
+	user := "billing_service_test"
+	password := "billing_service_test"
+	name := "billing_service_test"
+	migrationsPath := "../migrations"
+
+	dbClient, err := database.InitDBClient(host, port, user, password, name, migrationsPath)
+	suite.Nil(err, "error should be nil")
+
+	suite.dbClient = dbClient.DB
+	suite.wr = NewWebhookRepository(dbClient.DB)
+
+	suite.endpoint, err = suite.wr.CreateEndpoint(context.Background(), &models.WebhookEndpoint{
+		ID:        utils.GetNewUUID(),
+		URL:       "https://crm.example.com/hooks",
+		Secret:    "secret",
+		Events:    []string{models.BillClosed, models.InvoiceFinalized},
+		Active:    true,
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	})
+	suite.Nil(err, "error should be nil")
+}
+
+func (suite *WebhookRepositoryTestSuite) TearDownSuite() {
+	fmt.Printf("cleaning up db records")
+	suite.dbClient.Exec("DELETE FROM webhook_deliveries")
+	suite.dbClient.Exec("DELETE FROM webhook_endpoints")
+}
+
+func (suite *WebhookRepositoryTestSuite) delivery(createdAt time.Time) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:         utils.GetNewUUID(),
+		EndpointID: suite.endpoint.ID,
+		EventID:    utils.GetNewUUID(),
+		EventType:  models.BillClosed,
+		BillID:     utils.GetNewUUID(),
+		Payload:    `{"type":"bill.closed"}`,
+		Status:     models.DeliveryPending,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+}
+
+func (suite *WebhookRepositoryTestSuite) Test_GetEndpointByIDReturnsEvents() {
+	endpoint, err := suite.wr.GetEndpointByID(context.Background(), suite.endpoint.ID)
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal([]string{models.BillClosed, models.InvoiceFinalized}, endpoint.Events)
+	suite.True(endpoint.Active)
+}
+
+func (suite *WebhookRepositoryTestSuite) Test_GetEndpointByIDFailsForUnknownEndpoint() {
+	_, err := suite.wr.GetEndpointByID(context.Background(), utils.GetNewUUID())
+
+	suite.ErrorIs(err, ce.WebhookEndpointNotFoundError)
+}
+
+func (suite *WebhookRepositoryTestSuite) Test_DisabledEndpointsAreNotListed() {
+	ctx := context.Background()
+
+	endpoint, err := suite.wr.DisableEndpoint(ctx, suite.endpoint.ID)
+	suite.Nil(err, "error should be nil")
+	suite.False(endpoint.Active)
+
+	endpoints, err := suite.wr.ListEndpoints(ctx)
+	suite.Nil(err, "error should be nil")
+	for _, listed := range endpoints {
+		suite.NotEqual(suite.endpoint.ID, listed.ID)
+	}
+}
+
+func (suite *WebhookRepositoryTestSuite) Test_ListDeliveriesReturnsNewestFirst() {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	older, err := suite.wr.CreateDelivery(ctx, suite.delivery(now.Add(-time.Minute)))
+	suite.Nil(err, "error should be nil")
+	newer, err := suite.wr.CreateDelivery(ctx, suite.delivery(now))
+	suite.Nil(err, "error should be nil")
+
+	deliveries, total, err := suite.wr.ListDeliveries(ctx, suite.endpoint.ID, &models.ListWebhookDeliveriesRequest{Limit: 1})
+
+	suite.Nil(err, "error should be nil")
+	suite.Equal(int64(2), total)
+	suite.Len(deliveries, 1)
+	suite.Equal(newer.ID, deliveries[0].ID)
+
+	deliveries, _, err = suite.wr.ListDeliveries(ctx, suite.endpoint.ID, &models.ListWebhookDeliveriesRequest{Limit: 1, Offset: 1})
+	suite.Nil(err, "error should be nil")
+	suite.Equal(older.ID, deliveries[0].ID)
+}
+
+func (suite *WebhookRepositoryTestSuite) Test_UpdateDeliveryRecordsAttempt() {
+	ctx := context.Background()
+	delivery, err := suite.wr.CreateDelivery(ctx, suite.delivery(time.Now().UTC()))
+	suite.Nil(err, "error should be nil")
+
+	deliveredAt := time.Now().UTC()
+	delivery.Status = models.DeliverySucceeded
+	delivery.Attempts = 2
+	delivery.ResponseStatus = 204
+	delivery.DeliveredAt = &deliveredAt
+	_, err = suite.wr.UpdateDelivery(ctx, delivery)
+	suite.Nil(err, "error should be nil")
+
+	updated, err := suite.wr.GetDeliveryByID(ctx, delivery.ID)
+	suite.Nil(err, "error should be nil")
+	suite.Equal(models.DeliverySucceeded, updated.Status)
+	suite.Equal(2, updated.Attempts)
+	suite.Equal(204, updated.ResponseStatus)
+	suite.NotNil(updated.DeliveredAt)
+}
+
+func TestWebhookRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
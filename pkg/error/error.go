@@ -1,15 +1,118 @@
+// Package error holds the domain errors of the billing service. Every error
+// has a Code, telling the api how to answer it, a Reason clients can match
+// on, and the entity it is about.
 package error
 
-import "errors"
+import "fmt"
 
-var BillNotFoundError = errors.New("Bill not found")
-var BillClosedError = errors.New("Bill is closed")
-var LineItemNotFoundError = errors.New("Line item not found")
-var CustomerNotFoundError = errors.New("Customer not found")
-var CurrencyNotFoundError = errors.New("Currency not found")
+// Code classifies domain errors by how the api answers them.
+type Code string
 
-var BillAlreadyExistError = errors.New("Bill already exist")
-var LineItemAlreadyExistError = errors.New("Line item already exist")
-var LineItemAlreadyRemovedError = errors.New("Line item already exist")
-var CustomerAlreadyExistError = errors.New("Customer already exist")
-var CurrencyAlreadyExistError = errors.New("Currency already exist")
+const (
+	NotFound           Code = "not_found"
+	AlreadyExists      Code = "already_exists"
+	InvalidArgument    Code = "invalid_argument"
+	FailedPrecondition Code = "failed_precondition"
+	Unauthenticated    Code = "unauthenticated"
+	PermissionDenied   Code = "permission_denied"
+	Unavailable        Code = "unavailable"
+	Internal           Code = "internal"
+)
+
+// Error is a domain error. The errors below are sentinels, returned with the
+// ID of the entity they are about by WithID, so match them with errors.Is.
+type Error struct {
+	Code    Code
+	Reason  string
+	Message string
+	Entity  string
+	ID      string
+	// Err is the error causing this one, if any.
+	Err error
+}
+
+func newError(code Code, reason string, entity string, message string) *Error {
+	return &Error{Code: code, Reason: reason, Entity: entity, Message: message}
+}
+
+func (e *Error) Error() string {
+	if e.ID == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s, %s", e.Message, e.ID)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an Error of the same Reason, whatever the
+// entity it is about.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Reason == e.Reason
+}
+
+// WithID returns a copy of the error about the entity of id.
+func (e *Error) WithID(id string) *Error {
+	err := *e
+	err.ID = id
+	return &err
+}
+
+// Wrap returns a copy of the error caused by cause.
+func (e *Error) Wrap(cause error) *Error {
+	err := *e
+	err.Err = cause
+	return &err
+}
+
+var BillNotFoundError = newError(NotFound, "bill_not_found", "bill", "Bill not found")
+var BillClosedError = newError(FailedPrecondition, "bill_closed", "bill", "Bill is closed")
+var BillNotClosedError = newError(FailedPrecondition, "bill_not_closed", "bill", "Bill is not closed")
+var AmountExceedsBalanceDueError = newError(FailedPrecondition, "amount_exceeds_balance_due", "bill", "Amount exceeds the balance due on the bill")
+var JournalEntryUnbalancedError = newError(Internal, "journal_entry_unbalanced", "journal_entry", "Journal entry does not balance")
+var LineItemNotFoundError = newError(NotFound, "line_item_not_found", "line_item", "Line item not found")
+var CustomerNotFoundError = newError(NotFound, "customer_not_found", "customer", "Customer not found")
+var CurrencyNotFoundError = newError(NotFound, "currency_not_found", "currency", "Currency not found")
+var ISOCurrencyNotFoundError = newError(InvalidArgument, "iso_currency_not_found", "currency", "Currency code is not an ISO 4217 currency")
+var CurrencyInactiveError = newError(FailedPrecondition, "currency_inactive", "currency", "Currency is inactive")
+var CurrencyInUseError = newError(FailedPrecondition, "currency_in_use", "currency", "Currency is used by bills, subscriptions or prices, deactivate it instead")
+var BillCurrencyRequiredError = newError(InvalidArgument, "bill_currency_required", "customer", "Bill currency is required, the customer has no default currency")
+var SubscriptionNotFoundError = newError(NotFound, "subscription_not_found", "subscription", "Subscription not found")
+var SubscriptionCancelledError = newError(FailedPrecondition, "subscription_cancelled", "subscription", "Subscription is cancelled")
+var ProductNotFoundError = newError(NotFound, "product_not_found", "product", "Product not found")
+var PlanNotFoundError = newError(NotFound, "plan_not_found", "plan", "Plan not found")
+var PriceNotFoundError = newError(NotFound, "price_not_found", "price", "Price not found")
+var ProductArchivedError = newError(FailedPrecondition, "product_archived", "product", "Product is archived")
+var PlanArchivedError = newError(FailedPrecondition, "plan_archived", "plan", "Plan is archived")
+var PriceArchivedError = newError(FailedPrecondition, "price_archived", "price", "Price is archived")
+var PriceCurrencyMismatchError = newError(InvalidArgument, "price_currency_mismatch", "price", "Price currency does not match bill currency")
+var MeterNotFoundError = newError(NotFound, "meter_not_found", "meter", "Meter not found")
+var UsageEventNotFoundError = newError(NotFound, "usage_event_not_found", "usage_event", "Usage event not found")
+var SubscriptionItemNotFoundError = newError(NotFound, "subscription_item_not_found", "subscription_item", "Subscription item not found")
+var SubscriptionItemRemovedError = newError(FailedPrecondition, "subscription_item_removed", "subscription_item", "Subscription item is removed")
+var ExchangeRateNotFoundError = newError(NotFound, "exchange_rate_not_found", "exchange_rate", "Exchange rate not found")
+var ExchangeRateProviderNotConfiguredError = newError(FailedPrecondition, "exchange_rate_provider_not_configured", "exchange_rate", "Exchange rate provider is not configured")
+var WebhookEndpointNotFoundError = newError(NotFound, "webhook_endpoint_not_found", "webhook_endpoint", "Webhook endpoint not found")
+var WebhookDeliveryNotFoundError = newError(NotFound, "webhook_delivery_not_found", "webhook_delivery", "Webhook delivery not found")
+var WebhookEndpointDisabledError = newError(FailedPrecondition, "webhook_endpoint_disabled", "webhook_endpoint", "Webhook endpoint is disabled")
+var EmailTemplateNotFoundError = newError(NotFound, "email_template_not_found", "email_template", "Email template not found")
+var InvalidEmailTemplateError = newError(InvalidArgument, "email_template_invalid", "email_template", "Email template does not render, check its syntax and fields")
+var InvoiceEmailNotSentError = newError(Unavailable, "invoice_email_not_sent", "bill", "Invoice email could not be sent, the failed delivery is recorded")
+var APIKeyNotFoundError = newError(NotFound, "api_key_not_found", "api_key", "API key not found")
+var InvalidAPIKeyError = newError(Unauthenticated, "api_key_invalid", "api_key", "API key is invalid or revoked")
+var APIKeyTenantNotAllowedError = newError(PermissionDenied, "api_key_tenant_not_allowed", "api_key", "API key can not be issued for another tenant")
+
+var BillAlreadyExistError = newError(AlreadyExists, "bill_already_exists", "bill", "Bill already exist")
+var LineItemAlreadyExistError = newError(AlreadyExists, "line_item_already_exists", "line_item", "Line item already exist")
+var LineItemAlreadyRemovedError = newError(FailedPrecondition, "line_item_already_removed", "line_item", "Line item already removed")
+var CustomerAlreadyExistError = newError(AlreadyExists, "customer_already_exists", "customer", "Customer already exist")
+var CustomerArchivedError = newError(FailedPrecondition, "customer_archived", "customer", "Customer is archived")
+var CustomerNotDeletedError = newError(FailedPrecondition, "customer_not_deleted", "customer", "Customer must be deleted before it is purged")
+var CustomerHasActiveSubscriptionsError = newError(FailedPrecondition, "customer_has_active_subscriptions", "customer", "Customer has active subscriptions, cancel them first")
+var CustomerHasClosedBillsError = newError(FailedPrecondition, "customer_has_closed_bills", "customer", "Customer has closed bills")
+var CurrencyAlreadyExistError = newError(AlreadyExists, "currency_already_exists", "currency", "Currency already exist")
+var MeterAlreadyExistError = newError(AlreadyExists, "meter_already_exists", "meter", "Meter already exist")
+var PriceAlreadyExistError = newError(AlreadyExists, "price_already_exists", "price", "Plan already has an active price in the currency")
+var JournalEntryAlreadyExistError = newError(AlreadyExists, "journal_entry_already_exists", "journal_entry", "Reference already recorded for another bill or amount")
@@ -0,0 +1,56 @@
+package error
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestWithIDMatchesTheSentinel(t *testing.T) {
+	err := fmt.Errorf("closing bill: %w", BillNotFoundError.WithID("bill-01"))
+
+	if !errors.Is(err, BillNotFoundError) {
+		t.Errorf("errors.Is(%v, BillNotFoundError) = false, want true", err)
+	}
+	if errors.Is(err, BillClosedError) {
+		t.Errorf("errors.Is(%v, BillClosedError) = true, want false", err)
+	}
+	if BillNotFoundError.ID != "" {
+		t.Errorf("WithID changed the sentinel, ID = %q", BillNotFoundError.ID)
+	}
+
+	var domainErr *Error
+	if !errors.As(err, &domainErr) || domainErr.ID != "bill-01" || domainErr.Code != NotFound {
+		t.Errorf("errors.As(%v) = %+v, want bill-01 not found", err, domainErr)
+	}
+}
+
+func TestErrorNamesTheID(t *testing.T) {
+	if got := CustomerArchivedError.WithID("customer-01").Error(); got != "Customer is archived, customer-01" {
+		t.Errorf("Error() = %q, want %q", got, "Customer is archived, customer-01")
+	}
+	if got := CustomerArchivedError.Error(); got != "Customer is archived" {
+		t.Errorf("Error() = %q, want %q", got, "Customer is archived")
+	}
+}
+
+func TestWrapKeepsTheCause(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := InvoiceEmailNotSentError.WithID("bill-01").Wrap(cause)
+
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(%v, cause) = false, want true", err)
+	}
+	if !errors.Is(err, InvoiceEmailNotSentError) {
+		t.Errorf("errors.Is(%v, InvoiceEmailNotSentError) = false, want true", err)
+	}
+}
+
+func TestLineItemAlreadyRemovedError(t *testing.T) {
+	if LineItemAlreadyRemovedError.Error() != "Line item already removed" {
+		t.Errorf("Error() = %q", LineItemAlreadyRemovedError.Error())
+	}
+	if errors.Is(LineItemAlreadyRemovedError, LineItemAlreadyExistError) {
+		t.Errorf("LineItemAlreadyRemovedError is LineItemAlreadyExistError")
+	}
+}
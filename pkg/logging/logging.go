@@ -0,0 +1,84 @@
+// Package logging is the structured logger of the billing service. The logger
+// of a request travels in its context with the fields correlating its lines,
+// such as the request, tenant, bill and workflow ids, and redacts secrets and
+// personal data before they are written.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces the value of secret fields.
+const Redacted = "[REDACTED]"
+
+// secretKeys are the fields never written, whatever their value.
+var secretKeys = map[string]bool{
+	"password":      true,
+	"secret":        true,
+	"token":         true,
+	"api_key":       true,
+	"key_hash":      true,
+	"authorization": true,
+	"dsn":           true,
+}
+
+// emailKeys are the fields holding an email address, written masked.
+var emailKeys = map[string]bool{
+	"email": true,
+	"to":    true,
+	"from":  true,
+}
+
+type loggerContextKey struct{}
+
+// New returns a logger writing JSON lines to w, redacting secret fields and
+// masking email addresses.
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}))
+}
+
+// WithLogger returns a copy of ctx carrying logger.
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// From returns the logger of ctx, slog.Default() when it has none.
+func From(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// With returns a copy of ctx whose logger adds args to every line, such as
+// the id of the bill the work is about.
+func With(ctx context.Context, args ...any) context.Context {
+	return WithLogger(ctx, From(ctx).With(args...))
+}
+
+func redact(groups []string, attr slog.Attr) slog.Attr {
+	key := strings.ToLower(attr.Key)
+	switch {
+	case secretKeys[key] || strings.HasSuffix(key, "_password") || strings.HasSuffix(key, "_secret") || strings.HasSuffix(key, "_token"):
+		return slog.String(attr.Key, Redacted)
+	case emailKeys[key] || strings.HasSuffix(key, "_email"):
+		return slog.String(attr.Key, MaskEmail(attr.Value.String()))
+	}
+	return attr
+}
+
+// MaskEmail keeps the first letter of the local part of email and its
+// domain, enough to tell addresses apart without writing them.
+func MaskEmail(email string) string {
+	at := strings.LastIndex(email, "@")
+	if at < 1 {
+		return Redacted
+	}
+	return email[:1] + "***" + email[at:]
+}
@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	line := map[string]any{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("line %q is not json: %s", buf.String(), err)
+	}
+	return line
+}
+
+func TestLoggerRedactsSecrets(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(buf, slog.LevelInfo)
+
+	logger.Info("connecting", "password", "hunter2", "smtp_password", "hunter3", "dsn", "host=db password=hunter2", "host", "db")
+
+	line := decode(t, buf)
+	for _, key := range []string{"password", "smtp_password", "dsn"} {
+		if line[key] != Redacted {
+			t.Errorf("%s = %v, want %s", key, line[key], Redacted)
+		}
+	}
+	if line["host"] != "db" {
+		t.Errorf("host = %v, want db", line["host"])
+	}
+}
+
+func TestLoggerMasksEmails(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(buf, slog.LevelInfo)
+
+	logger.Info("sending invoice", "to", "jane.doe@example.com", "customer_email", "x")
+
+	line := decode(t, buf)
+	if line["to"] != "j***@example.com" {
+		t.Errorf("to = %v, want j***@example.com", line["to"])
+	}
+	if line["customer_email"] != Redacted {
+		t.Errorf("customer_email = %v, want %s", line["customer_email"], Redacted)
+	}
+}
+
+func TestLoggerTravelsInTheContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := WithLogger(context.Background(), New(buf, slog.LevelInfo))
+	ctx = With(ctx, "request_id", "req-01")
+	ctx = With(ctx, "bill_id", "bill-01")
+
+	From(ctx).Error("error occurred while closing bill", "error", errors.New("connection refused"))
+
+	line := decode(t, buf)
+	if line["request_id"] != "req-01" || line["bill_id"] != "bill-01" {
+		t.Errorf("line = %v, want request_id req-01 and bill_id bill-01", line)
+	}
+	if line["error"] != "connection refused" {
+		t.Errorf("error = %v, want connection refused", line["error"])
+	}
+}
+
+func TestFromDefaultsToTheDefaultLogger(t *testing.T) {
+	if From(context.Background()) != slog.Default() {
+		t.Errorf("From(context.Background()) is not slog.Default()")
+	}
+}
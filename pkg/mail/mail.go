@@ -0,0 +1,89 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/logging"
+)
+
+// Message is an email with a plain text and an HTML alternative of its body.
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Transport sends messages.
+type Transport interface {
+	Send(context.Context, *Message) error
+}
+
+// Bytes renders the message as a multipart/alternative MIME message, the text
+// part first so clients prefer the HTML part.
+func (m *Message) Bytes(date time.Time) ([]byte, error) {
+	if m.From == "" || m.To == "" {
+		return nil, errors.New("mail: message has no sender or recipient")
+	}
+
+	body := &bytes.Buffer{}
+	parts := multipart.NewWriter(body)
+
+	for _, part := range []struct {
+		contentType string
+		content     string
+	}{
+		{"text/plain; charset=UTF-8", m.Text},
+		{"text/html; charset=UTF-8", m.HTML},
+	} {
+		writer, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		encoder := quotedprintable.NewWriter(writer)
+		if _, err = encoder.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err = encoder.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	message := &bytes.Buffer{}
+	fmt.Fprintf(message, "From: %s\r\n", m.From)
+	fmt.Fprintf(message, "To: %s\r\n", m.To)
+	fmt.Fprintf(message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", m.Subject))
+	fmt.Fprintf(message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(message, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(message, "Content-Type: multipart/alternative; boundary=%s\r\n", parts.Boundary())
+	fmt.Fprintf(message, "\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+// LogTransport logs messages instead of sending them. It stands in for an SMTP
+// server in development.
+type LogTransport struct{}
+
+func (lt *LogTransport) Send(ctx context.Context, message *Message) error {
+	logging.From(ctx).Warn("mail not sent, no smtp server is configured", "to", message.To, "subject", message.Subject)
+	return nil
+}
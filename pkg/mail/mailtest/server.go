@@ -0,0 +1,134 @@
+// Package mailtest provides a fake SMTP server for testing mail transports.
+package mailtest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Message is a message received by the server, as sent by the client.
+type Message struct {
+	From string
+	To   []string
+	Data string
+}
+
+// Server is a local SMTP server keeping the messages it receives. It speaks
+// the commands net/smtp sends, without STARTTLS or AUTH.
+type Server struct {
+	// Addr is the host:port the server listens on.
+	Addr string
+	// Reject makes the server refuse messages after their data is sent.
+	Reject bool
+
+	listener net.Listener
+	mu       sync.Mutex
+	messages []Message
+	wg       sync.WaitGroup
+}
+
+// NewServer starts a server on a loopback port. Close it when done.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("mailtest: failed to listen on a port: %v", err))
+	}
+
+	server := &Server{Addr: listener.Addr().String(), listener: listener}
+	server.wg.Add(1)
+	go server.serve()
+
+	return server
+}
+
+// Messages returns the messages received so far.
+func (s *Server) Messages() []Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Message{}, s.messages...)
+}
+
+// Close stops the server and waits for open sessions to end.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.session(conn)
+		}()
+	}
+}
+
+func (s *Server) session(conn net.Conn) {
+	text := textproto.NewConn(conn)
+	defer text.Close()
+
+	message := Message{}
+	_ = text.PrintfLine("220 mailtest ESMTP")
+
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, argument, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			_ = text.PrintfLine("250-mailtest\r\n250 8BITMIME")
+		case "HELO", "NOOP":
+			_ = text.PrintfLine("250 OK")
+		case "MAIL":
+			message = Message{From: address(argument)}
+			_ = text.PrintfLine("250 OK")
+		case "RCPT":
+			message.To = append(message.To, address(argument))
+			_ = text.PrintfLine("250 OK")
+		case "DATA":
+			_ = text.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			if s.Reject {
+				_ = text.PrintfLine("554 message rejected")
+				continue
+			}
+			message.Data = string(data)
+			s.mu.Lock()
+			s.messages = append(s.messages, message)
+			s.mu.Unlock()
+			_ = text.PrintfLine("250 OK")
+		case "RSET":
+			message = Message{}
+			_ = text.PrintfLine("250 OK")
+		case "QUIT":
+			_ = text.PrintfLine("221 bye")
+			return
+		default:
+			_ = text.PrintfLine("502 command not implemented")
+		}
+	}
+}
+
+// address returns the address of a MAIL FROM:<...> or RCPT TO:<...> argument.
+func address(argument string) string {
+	_, path, _ := strings.Cut(argument, ":")
+	path, _, _ = strings.Cut(path, " ")
+	return strings.Trim(path, "<>")
+}
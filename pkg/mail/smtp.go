@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPTransport sends messages through the SMTP server at Addr, a host:port.
+// The connection is upgraded with STARTTLS when the server offers it, and
+// authenticated with PLAIN when Username is set.
+type SMTPTransport struct {
+	Addr     string
+	Username string
+	Password string
+	// Timeout bounds a send when the context has no deadline.
+	Timeout time.Duration
+}
+
+func NewSMTPTransport(addr, username, password string) *SMTPTransport {
+	return &SMTPTransport{
+		Addr:     addr,
+		Username: username,
+		Password: password,
+		Timeout:  30 * time.Second,
+	}
+}
+
+func (st *SMTPTransport) Send(ctx context.Context, message *Message) error {
+	data, err := message.Bytes(time.Now())
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(st.Addr)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := ctx.Deadline(); !ok && st.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, st.Timeout)
+		defer cancel()
+	}
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", st.Addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+
+	if st.Username != "" {
+		if err = client.Auth(smtp.PlainAuth("", st.Username, st.Password, host)); err != nil {
+			return err
+		}
+	}
+
+	from, err := netmail.ParseAddress(message.From)
+	if err != nil {
+		return err
+	}
+	to, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return err
+	}
+
+	if err = client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err = client.Rcpt(to.Address); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(data); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
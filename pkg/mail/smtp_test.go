@@ -0,0 +1,99 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/asheet-bhaskar/billing-service/pkg/mail/mailtest"
+)
+
+func testMessage() *Message {
+	return &Message{
+		From:    "Billing <billing@example.com>",
+		To:      "jane@example.com",
+		Subject: "Rechnung für März",
+		Text:    "Total: €9.99",
+		HTML:    "<p>Total: <strong>€9.99</strong></p>",
+	}
+}
+
+func Test_SMTPTransportSendsMultipartMessage(t *testing.T) {
+	server := mailtest.NewServer()
+	defer server.Close()
+
+	err := NewSMTPTransport(server.Addr, "", "").Send(context.Background(), testMessage())
+	if err != nil {
+		t.Fatalf("error should be nil, got %s", err.Error())
+	}
+
+	messages := server.Messages()
+	if len(messages) != 1 {
+		t.Fatalf("server should receive 1 message, got %d", len(messages))
+	}
+	if messages[0].From != "billing@example.com" {
+		t.Errorf("envelope sender should be billing@example.com, got %s", messages[0].From)
+	}
+	if len(messages[0].To) != 1 || messages[0].To[0] != "jane@example.com" {
+		t.Errorf("envelope recipient should be jane@example.com, got %v", messages[0].To)
+	}
+
+	received, err := netmail.ReadMessage(strings.NewReader(messages[0].Data))
+	if err != nil {
+		t.Fatalf("message should parse, got %s", err.Error())
+	}
+
+	decoded, err := new(mime.WordDecoder).DecodeHeader(received.Header.Get("Subject"))
+	if err != nil || decoded != "Rechnung für März" {
+		t.Errorf("subject should decode to Rechnung für März, got %q", decoded)
+	}
+	if !strings.HasPrefix(received.Header.Get("Content-Type"), "multipart/alternative; boundary=") {
+		t.Errorf("message should be multipart/alternative, got %s", received.Header.Get("Content-Type"))
+	}
+
+	body, _ := io.ReadAll(received.Body)
+	for _, part := range []string{"text/plain; charset=UTF-8", "text/html; charset=UTF-8", "Total: =E2=82=AC9.99"} {
+		if !strings.Contains(string(body), part) {
+			t.Errorf("body should contain %q", part)
+		}
+	}
+}
+
+func Test_SMTPTransportReturnsRejections(t *testing.T) {
+	server := mailtest.NewServer()
+	defer server.Close()
+	server.Reject = true
+
+	err := NewSMTPTransport(server.Addr, "", "").Send(context.Background(), testMessage())
+	if err == nil || !strings.Contains(err.Error(), "554") {
+		t.Errorf("error should be the rejection, got %v", err)
+	}
+	if len(server.Messages()) != 0 {
+		t.Error("server should keep no message")
+	}
+}
+
+func Test_SMTPTransportFailsWithoutServer(t *testing.T) {
+	server := mailtest.NewServer()
+	addr := server.Addr
+	server.Close()
+
+	transport := NewSMTPTransport(addr, "", "")
+	transport.Timeout = time.Second
+	if err := transport.Send(context.Background(), testMessage()); err == nil {
+		t.Error("error should not be nil")
+	}
+}
+
+func Test_MessageRequiresRecipient(t *testing.T) {
+	message := testMessage()
+	message.To = ""
+
+	if _, err := message.Bytes(time.Now()); err == nil {
+		t.Error("error should not be nil")
+	}
+}
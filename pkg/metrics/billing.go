@@ -0,0 +1,33 @@
+package metrics
+
+// Metrics of the billing service, in the Default registry. BillsOpen is
+// counted in the database of all tenants when the metrics are read, so it
+// holds across restarts of the service.
+var (
+	BillsCreated = Default.Counter("billing_bills_created_total",
+		"Bills created.")
+	BillsClosed = Default.Counter("billing_bills_closed_total",
+		"Bills closed.")
+	BillsOpen = Default.Gauge("billing_bills_open",
+		"Bills open.")
+	LineItemsAdded = Default.Counter("billing_line_items_added_total",
+		"Line items added to bills.")
+	LineItemsRemoved = Default.Counter("billing_line_items_removed_total",
+		"Line items removed from bills.")
+	WorkflowStartFailures = Default.Counter("billing_workflow_start_failures_total",
+		"Workflows the service failed to start, by workflow.", "workflow")
+	WorkflowSignalFailures = Default.Counter("billing_workflow_signal_failures_total",
+		"Signals the service failed to send to workflows, by signal.", "signal")
+	ActivityDuration = Default.Histogram("billing_activity_duration_seconds",
+		"Duration of Temporal activity attempts, by activity and outcome.", DefaultBuckets, "activity", "outcome")
+	ActivityRetries = Default.Counter("billing_activity_retries_total",
+		"Temporal activity attempts after the first, by activity.", "activity")
+	DBQueryDuration = Default.Histogram("billing_db_query_duration_seconds",
+		"Duration of database queries, by operation and table.", DefaultBuckets, "operation", "table")
+)
+
+// Outcomes of activities.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounterCountsPerLabelValues(t *testing.T) {
+	registry := NewRegistry()
+	failures := registry.Counter("signal_failures_total", "Signal failures.", "signal")
+
+	failures.Inc("ADD_BILL_ITEM_CHANNEL")
+	failures.Inc("ADD_BILL_ITEM_CHANNEL")
+	failures.Add(3, "REMOVE_BILL_ITEM_CHANNEL")
+
+	if got := failures.Value("ADD_BILL_ITEM_CHANNEL"); got != 2 {
+		t.Errorf("Value(ADD_BILL_ITEM_CHANNEL) = %v, want 2", got)
+	}
+	if got := failures.Value("REMOVE_BILL_ITEM_CHANNEL"); got != 3 {
+		t.Errorf("Value(REMOVE_BILL_ITEM_CHANNEL) = %v, want 3", got)
+	}
+	if got := failures.Value("CLOSE"); got != 0 {
+		t.Errorf("Value(CLOSE) = %v, want 0", got)
+	}
+}
+
+func TestHistogramCountsObservationsIntoBuckets(t *testing.T) {
+	registry := NewRegistry()
+	duration := registry.Histogram("query_duration_seconds", "Query duration.", []float64{0.1, 1}, "table")
+
+	duration.Observe(0.05, "bills")
+	duration.Observe(0.5, "bills")
+	duration.Observe(2, "bills")
+
+	if got := duration.Count("bills"); got != 3 {
+		t.Errorf("Count(bills) = %d, want 3", got)
+	}
+	if got := duration.Sum("bills"); got != 2.55 {
+		t.Errorf("Sum(bills) = %v, want 2.55", got)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := registry.WritePrometheus(buf); err != nil {
+		t.Fatalf("WritePrometheus() = %s", err)
+	}
+	for _, line := range []string{
+		`query_duration_seconds_bucket{table="bills",le="0.1"} 1`,
+		`query_duration_seconds_bucket{table="bills",le="1"} 2`,
+		`query_duration_seconds_bucket{table="bills",le="+Inf"} 3`,
+		`query_duration_seconds_sum{table="bills"} 2.55`,
+		`query_duration_seconds_count{table="bills"} 3`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Errorf("output has no line %s:\n%s", line, buf.String())
+		}
+	}
+}
+
+func TestWritePrometheusWritesCounters(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("bills_created_total", "Bills created.").Inc()
+	registry.Counter("retries_total", "Retries.", "activity").Inc(`Rate "usage"`)
+
+	buf := &bytes.Buffer{}
+	if err := registry.WritePrometheus(buf); err != nil {
+		t.Fatalf("WritePrometheus() = %s", err)
+	}
+
+	want := `# HELP bills_created_total Bills created.
+# TYPE bills_created_total counter
+bills_created_total 1
+# HELP retries_total Retries.
+# TYPE retries_total counter
+retries_total{activity="Rate \"usage\""} 1
+`
+	if buf.String() != want {
+		t.Errorf("WritePrometheus() wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestRegisteringAMetricTwicePanics(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("bills_created_total", "Bills created.")
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("registering bills_created_total twice did not panic")
+		}
+	}()
+	registry.Counter("bills_created_total", "Bills created.")
+}
+
+func TestGaugeIsSetToTheLastValue(t *testing.T) {
+	registry := NewRegistry()
+	open := registry.Gauge("bills_open", "Bills open.")
+
+	open.Set(3)
+	open.Set(2)
+
+	if got := open.Value(); got != 2 {
+		t.Errorf("Value() = %v, want 2", got)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := registry.WritePrometheus(buf); err != nil {
+		t.Fatalf("WritePrometheus() = %s", err)
+	}
+
+	want := `# HELP bills_open Bills open.
+# TYPE bills_open gauge
+bills_open 2
+`
+	if buf.String() != want {
+		t.Errorf("WritePrometheus() wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+}
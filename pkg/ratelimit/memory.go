@@ -0,0 +1,39 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryLimiter keeps the buckets in memory, so every instance of the
+// service limits the requests it serves on its own.
+type MemoryLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*Bucket
+	now     func() time.Time
+}
+
+func NewMemoryLimiter() *MemoryLimiter {
+	return &MemoryLimiter{
+		buckets: map[string]*Bucket{},
+		now:     time.Now,
+	}
+}
+
+func (ml *MemoryLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	if limit.IsUnlimited() {
+		return Result{Allowed: true}, nil
+	}
+
+	ml.mu.Lock()
+	defer ml.mu.Unlock()
+
+	bucket, ok := ml.buckets[key]
+	if !ok {
+		bucket = &Bucket{}
+		ml.buckets[key] = bucket
+	}
+
+	return bucket.Take(limit, ml.now()), nil
+}
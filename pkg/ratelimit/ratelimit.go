@@ -0,0 +1,91 @@
+// Package ratelimit limits how often a key, such as an api key, may do
+// something with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit is a token bucket holding up to Burst tokens, refilled with Rate
+// tokens a second. Every request takes a token, requests finding the bucket
+// empty are refused. A Limit without Burst is no limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// PerMinute returns the limit of requests a minute on average, allowing
+// bursts of up to burst requests.
+func PerMinute(requests int, burst int) Limit {
+	return Limit{Rate: float64(requests) / 60, Burst: burst}
+}
+
+func (l Limit) IsUnlimited() bool {
+	return l.Burst <= 0
+}
+
+// Result is the outcome of a request, described like the RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset and Retry-After HTTP headers.
+type Result struct {
+	Allowed bool
+	// Limit is the number of requests allowed at once, the Burst.
+	Limit int
+	// Remaining is the number of requests allowed right after this one.
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until a refused request may be retried, zero
+	// when it was allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter takes a token of the bucket of the key for every request.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Bucket is the state of a token bucket. A Bucket never updated is full.
+type Bucket struct {
+	Tokens    float64
+	UpdatedAt time.Time
+}
+
+// Take refills the bucket for the time elapsed up to now and takes a token
+// from it, if it has one.
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	burst := float64(limit.Burst)
+	if b.UpdatedAt.IsZero() {
+		b.Tokens = burst
+	} else if elapsed := now.Sub(b.UpdatedAt).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed*limit.Rate)
+	}
+	if now.After(b.UpdatedAt) {
+		b.UpdatedAt = now
+	}
+
+	result := Result{Limit: limit.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = refillTime(1-b.Tokens, limit.Rate)
+	}
+	result.Remaining = int(math.Floor(b.Tokens))
+	result.Reset = refillTime(burst-b.Tokens, limit.Rate)
+
+	return result
+}
+
+// refillTime returns the time refilling tokens takes at rate, rounded up to
+// the second.
+func refillTime(tokens float64, rate float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	if rate <= 0 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(math.Ceil(tokens/rate)) * time.Second
+}
@@ -0,0 +1,96 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestTakeAllowsBurstThenRefuses(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := PerMinute(60, 3)
+	bucket := &Bucket{}
+
+	for i := 2; i >= 0; i-- {
+		result := bucket.Take(limit, now)
+		if !result.Allowed || result.Remaining != i {
+			t.Fatalf("Take() = %+v, want allowed with %d remaining", result, i)
+		}
+	}
+
+	result := bucket.Take(limit, now)
+	if result.Allowed {
+		t.Fatalf("Take() of empty bucket = %+v, want refused", result)
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %s, want 1s", result.RetryAfter)
+	}
+	if result.Reset != 3*time.Second {
+		t.Errorf("Reset = %s, want 3s", result.Reset)
+	}
+	if result.Limit != 3 {
+		t.Errorf("Limit = %d, want 3", result.Limit)
+	}
+}
+
+func TestTakeRefillsUpToBurst(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := PerMinute(60, 3)
+	bucket := &Bucket{Tokens: 0, UpdatedAt: now}
+
+	result := bucket.Take(limit, now.Add(1500*time.Millisecond))
+	if !result.Allowed || result.Remaining != 0 {
+		t.Fatalf("Take() after 1.5s = %+v, want allowed with 0 remaining", result)
+	}
+
+	result = bucket.Take(limit, now.Add(time.Hour))
+	if !result.Allowed || result.Remaining != 2 {
+		t.Fatalf("Take() after an hour = %+v, want allowed with 2 remaining", result)
+	}
+}
+
+func TestTakeIgnoresClockGoingBackwards(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limit := PerMinute(60, 1)
+	bucket := &Bucket{Tokens: 0, UpdatedAt: now}
+
+	if result := bucket.Take(limit, now.Add(-time.Minute)); result.Allowed {
+		t.Fatalf("Take() before the last update = %+v, want refused", result)
+	}
+	if !bucket.UpdatedAt.Equal(now) {
+		t.Errorf("UpdatedAt = %s, want %s", bucket.UpdatedAt, now)
+	}
+}
+
+func TestMemoryLimiterKeepsABucketPerKey(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := NewMemoryLimiter()
+	limiter.now = func() time.Time { return now }
+	limit := PerMinute(60, 1)
+	ctx := context.Background()
+
+	if result, _ := limiter.Allow(ctx, "key-a", limit); !result.Allowed {
+		t.Fatalf("Allow() of key-a = %+v, want allowed", result)
+	}
+	if result, _ := limiter.Allow(ctx, "key-a", limit); result.Allowed {
+		t.Fatalf("Allow() of key-a again = %+v, want refused", result)
+	}
+	if result, _ := limiter.Allow(ctx, "key-b", limit); !result.Allowed {
+		t.Fatalf("Allow() of key-b = %+v, want allowed", result)
+	}
+
+	now = now.Add(time.Second)
+	if result, _ := limiter.Allow(ctx, "key-a", limit); !result.Allowed {
+		t.Fatalf("Allow() of key-a after a second = %+v, want allowed", result)
+	}
+}
+
+func TestMemoryLimiterAllowsUnlimited(t *testing.T) {
+	limiter := NewMemoryLimiter()
+
+	for i := 0; i < 10; i++ {
+		if result, _ := limiter.Allow(context.Background(), "key-a", Limit{}); !result.Allowed {
+			t.Fatalf("Allow() without limit = %+v, want allowed", result)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package tenancy carries the tenant of a request, the business unit whose
+// customers, currencies and bills it may see, and scopes queries to it.
+package tenancy
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultTenant owns the data created before tenants existed, and the changes
+// made without a tenant.
+const DefaultTenant = "default"
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenantID.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// Lookup returns the tenant of ctx, if it has one.
+func Lookup(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// From returns the tenant of ctx, DefaultTenant when it has none.
+func From(ctx context.Context) string {
+	if tenantID, ok := Lookup(ctx); ok {
+		return tenantID
+	}
+	return DefaultTenant
+}
+
+// Scope narrows a query to the rows of the tenant of ctx in the table of the
+// query.
+func Scope(ctx context.Context) func(*gorm.DB) *gorm.DB {
+	tenantID := From(ctx)
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "tenant_id"}, Value: tenantID})
+	}
+}
+
+// WorkflowID namespaces the workflow id of the tenant of ctx. Workflows of
+// the default tenant keep their id, so those started before tenants existed
+// are still found.
+func WorkflowID(ctx context.Context, id string) string {
+	tenantID := From(ctx)
+	if tenantID == DefaultTenant {
+		return id
+	}
+	return fmt.Sprintf("%s/%s", tenantID, id)
+}
+
+// Detach returns a background context carrying only the tenant of ctx, to
+// start work that outlives the request, such as workflows. It is
+// context.Background() when ctx has no tenant.
+func Detach(ctx context.Context) context.Context {
+	tenantID, ok := Lookup(ctx)
+	if !ok {
+		return context.Background()
+	}
+	return WithTenant(context.Background(), tenantID)
+}
@@ -0,0 +1,47 @@
+package tenancy
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFromDefaultsToDefaultTenant(t *testing.T) {
+	if got := From(context.Background()); got != DefaultTenant {
+		t.Errorf("From() = %q, want %q", got, DefaultTenant)
+	}
+
+	if got := From(WithTenant(context.Background(), "")); got != DefaultTenant {
+		t.Errorf("From() of empty tenant = %q, want %q", got, DefaultTenant)
+	}
+
+	if got := From(WithTenant(context.Background(), "acme")); got != "acme" {
+		t.Errorf("From() = %q, want %q", got, "acme")
+	}
+}
+
+func TestWorkflowIDIsNamespacedPerTenant(t *testing.T) {
+	if got := WorkflowID(context.Background(), "BILL-1"); got != "BILL-1" {
+		t.Errorf("WorkflowID() of default tenant = %q, want %q", got, "BILL-1")
+	}
+
+	if got := WorkflowID(WithTenant(context.Background(), "acme"), "BILL-1"); got != "acme/BILL-1" {
+		t.Errorf("WorkflowID() = %q, want %q", got, "acme/BILL-1")
+	}
+}
+
+func TestDetachKeepsOnlyTheTenant(t *testing.T) {
+	ctx, cancel := context.WithCancel(WithTenant(context.Background(), "acme"))
+	cancel()
+
+	detached := Detach(ctx)
+	if detached.Err() != nil {
+		t.Errorf("Detach() is cancelled with its parent")
+	}
+	if got := From(detached); got != "acme" {
+		t.Errorf("From(Detach()) = %q, want %q", got, "acme")
+	}
+
+	if Detach(context.Background()) != context.Background() {
+		t.Errorf("Detach() without tenant is not context.Background()")
+	}
+}
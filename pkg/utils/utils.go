@@ -3,6 +3,8 @@ package utils
 import (
 	"time"
 
+	cryptorand "crypto/rand"
+	"encoding/hex"
 	"math/rand"
 
 	"github.com/google/uuid"
@@ -26,3 +28,13 @@ func RandomString(length int) string {
 	}
 	return string(b)
 }
+
+// SecureRandomString returns a hex string of length random bytes from a
+// cryptographically secure source, for secrets and tokens.
+func SecureRandomString(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := cryptorand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
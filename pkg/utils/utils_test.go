@@ -27,3 +27,19 @@ func Test_RandomString(t *testing.T) {
 		t.Error("length should be 10")
 	}
 }
+
+func Test_SecureRandomString(t *testing.T) {
+	s, err := SecureRandomString(16)
+	if err != nil {
+		t.Error("error should be nil")
+	}
+
+	if len(s) != 32 {
+		t.Error("length should be 32")
+	}
+
+	other, _ := SecureRandomString(16)
+	if s == other {
+		t.Error("strings should differ")
+	}
+}
@@ -1,26 +1,45 @@
 package worker
 
 import (
-	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
 
 	"github.com/asheet-bhaskar/billing-service/app/workflows"
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
 	"go.temporal.io/sdk/worker"
 )
 
-func Start(temporalClient client.Client) {
+func Start(temporalClient client.Client, billService workflows.BillService, usageService workflows.UsageService) {
 
-	w := worker.New(temporalClient, "CREATE_BILL_QUEUE", worker.Options{})
+	w := worker.New(temporalClient, "CREATE_BILL_QUEUE", worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{workflows.NewMetricsInterceptor()},
+	})
 
-	a := &workflows.Activities{}
+	a := &workflows.Activities{
+		BillService:  billService,
+		UsageService: usageService,
+		HTTPClient:   &http.Client{Timeout: 30 * time.Second},
+	}
 
 	w.RegisterActivity(a.AddLineItemActivity)
 	w.RegisterActivity(a.RemoveLineItemActivity)
+	w.RegisterActivity(a.OpenSubscriptionBillActivity)
+	w.RegisterActivity(a.CloseSubscriptionBillActivity)
+	w.RegisterActivity(a.EndSubscriptionActivity)
+	w.RegisterActivity(a.RateUsageActivity)
+	w.RegisterActivity(a.DeliverWebhookActivity)
+	w.RegisterActivity(a.FailWebhookDeliveryActivity)
 
 	w.RegisterWorkflow(workflows.BillingWorkflow)
+	w.RegisterWorkflow(workflows.SubscriptionWorkflow)
+	w.RegisterWorkflow(workflows.WebhookDeliveryWorkflow)
 
 	err := w.Run(worker.InterruptCh())
 	if err != nil {
-		log.Fatalln("unable to start Worker", err)
+		slog.Error("unable to start worker", "error", err)
+		os.Exit(1)
 	}
 }